@@ -0,0 +1,106 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// AggregatedStats 同一标签值下多个熔断器的汇总统计，用于服务级 SLO 报表
+type AggregatedStats struct {
+	// Label 分组标签名
+	Label string
+	// Value 标签值
+	Value string
+	// Breakers 参与汇总的熔断器名称（已排序）
+	Breakers []string
+	// Open 处于打开状态的熔断器数量
+	Open int
+	// HalfOpen 处于半开状态的熔断器数量
+	HalfOpen int
+	// Counts 各熔断器当前窗口计数之和（连续计数字段取最大值）
+	Counts gobreaker.Counts
+	// FailureRate 汇总失败率
+	FailureRate float64
+	// InFlight 正在执行中的调用数之和
+	InFlight int64
+	// LatencyP99 各熔断器 P99 耗时的最大值
+	LatencyP99 time.Duration
+	// HealthScore 按请求数加权的平均健康评分，无请求时取算术平均
+	HealthScore float64
+}
+
+// AggregateStats 按标签汇总注册表内的熔断器统计，返回以标签值为键的结果；
+// 未设置该标签的熔断器不参与汇总
+func (r *Registry) AggregateStats(label string) map[string]AggregatedStats {
+	groups := make(map[string][]Stats)
+	for _, s := range r.Stats() {
+		if value, ok := s.Labels[label]; ok {
+			groups[value] = append(groups[value], s)
+		}
+	}
+
+	result := make(map[string]AggregatedStats, len(groups))
+	for value, stats := range groups {
+		result[value] = aggregate(label, value, stats)
+	}
+	return result
+}
+
+// aggregate 汇总一组统计快照
+func aggregate(label, value string, stats []Stats) AggregatedStats {
+	agg := AggregatedStats{Label: label, Value: value}
+	var weighted, plain float64
+	for _, s := range stats {
+		agg.Breakers = append(agg.Breakers, s.Name)
+		switch s.State {
+		case gobreaker.StateOpen:
+			agg.Open++
+		case gobreaker.StateHalfOpen:
+			agg.HalfOpen++
+		}
+
+		agg.Counts.Requests += s.Counts.Requests
+		agg.Counts.TotalSuccesses += s.Counts.TotalSuccesses
+		agg.Counts.TotalFailures += s.Counts.TotalFailures
+		if s.Counts.ConsecutiveSuccesses > agg.Counts.ConsecutiveSuccesses {
+			agg.Counts.ConsecutiveSuccesses = s.Counts.ConsecutiveSuccesses
+		}
+		if s.Counts.ConsecutiveFailures > agg.Counts.ConsecutiveFailures {
+			agg.Counts.ConsecutiveFailures = s.Counts.ConsecutiveFailures
+		}
+
+		agg.InFlight += s.InFlight
+		if s.LatencyP99 > agg.LatencyP99 {
+			agg.LatencyP99 = s.LatencyP99
+		}
+		weighted += s.HealthScore * float64(s.Counts.Requests)
+		plain += s.HealthScore
+	}
+	sort.Strings(agg.Breakers)
+
+	agg.FailureRate = failureRate(agg.Counts)
+	if agg.Counts.Requests > 0 {
+		agg.HealthScore = weighted / float64(agg.Counts.Requests)
+	} else if len(stats) > 0 {
+		agg.HealthScore = plain / float64(len(stats))
+	}
+	return agg
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestRegistry_AggregateStats(t *testing.T) {
+	r := NewRegistry()
+	settingsFor := func(service string) Settings {
+		s := DefaultSettings()
+		s.Labels = map[string]string{"service": service}
+		return s
+	}
+
+	charge := r.GetOrCreate("payments/charge", settingsFor("payments"))
+	refund := r.GetOrCreate("payments/refund", settingsFor("payments"))
+	r.GetOrCreate("orders/list", settingsFor("orders"))
+	r.GetOrCreate("unlabeled", DefaultSettings())
+
+	for i := 0; i < 3; i++ {
+		charge.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	refund.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	refund.OpenFor(time.Minute)
+
+	stats := r.AggregateStats("service")
+	if len(stats) != 2 {
+		t.Fatalf("len(AggregateStats()) = %v, want 2", len(stats))
+	}
+
+	payments := stats["payments"]
+	if want := []string{"payments/charge", "payments/refund"}; !reflect.DeepEqual(payments.Breakers, want) {
+		t.Errorf("Breakers = %v, want %v", payments.Breakers, want)
+	}
+	if payments.Counts.Requests != 4 || payments.Counts.TotalFailures != 1 {
+		t.Errorf("Counts = %+v, want 4 requests and 1 failure", payments.Counts)
+	}
+	if payments.FailureRate != 0.25 {
+		t.Errorf("FailureRate = %v, want 0.25", payments.FailureRate)
+	}
+	if payments.Open != 1 {
+		t.Errorf("Open = %v, want 1", payments.Open)
+	}
+
+	if orders := stats["orders"]; len(orders.Breakers) != 1 || orders.Counts.Requests != 0 {
+		t.Errorf("orders = %+v, want one idle breaker", orders)
+	}
+}
+
+func TestAggregate_ConsecutiveCountsTakeMax(t *testing.T) {
+	agg := aggregate("service", "x", []Stats{
+		{Name: "a", Counts: gobreaker.Counts{ConsecutiveFailures: 2}},
+		{Name: "b", Counts: gobreaker.Counts{ConsecutiveFailures: 5}},
+	})
+	if agg.Counts.ConsecutiveFailures != 5 {
+		t.Errorf("ConsecutiveFailures = %v, want 5", agg.Counts.ConsecutiveFailures)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// AnomalyEvent 失败率异常事件，在熔断阈值触发前作为预警信号
+type AnomalyEvent struct {
+	// Breaker 熔断器名称
+	Breaker string
+	// FailureRate 本次采样区间内的失败率
+	FailureRate float64
+	// Mean 失败率的指数加权均值（不含本次采样）
+	Mean float64
+	// StdDev 失败率的指数加权标准差（不含本次采样）
+	StdDev float64
+	// ZScore 本次失败率偏离均值的标准差倍数
+	ZScore float64
+	// At 检测时间
+	At time.Time
+}
+
+// AnomalySettings 异常检测配置
+type AnomalySettings struct {
+	// Interval 采样间隔，默认 10 秒
+	Interval time.Duration
+	// Alpha EWMA 平滑系数（0~1），越大越敏感，默认 0.3
+	Alpha float64
+	// Threshold 触发事件的 z-score 阈值，默认 3
+	Threshold float64
+	// MinRequests 采样区间内请求数低于该值时跳过本次采样，默认 10
+	MinRequests uint32
+	// Warmup 开始告警前所需的采样次数，默认 5
+	Warmup int
+	// MinStdDev 标准差下限，避免长期零失败时任意波动都被判为异常，默认 0.01
+	MinStdDev float64
+}
+
+// DefaultAnomalySettings 返回默认异常检测配置
+func DefaultAnomalySettings() AnomalySettings {
+	return AnomalySettings{
+		Interval:    10 * time.Second,
+		Alpha:       0.3,
+		Threshold:   3,
+		MinRequests: 10,
+		Warmup:      5,
+		MinStdDev:   0.01,
+	}
+}
+
+// ewma 单个熔断器的失败率统计
+type ewma struct {
+	requests, failures uint32
+	mean, variance     float64
+	samples            int
+}
+
+// AnomalyDetector 对注册表内各熔断器的失败率做 EWMA + z-score 异常检测，
+// 仅在熔断器处于关闭状态时告警，作为熔断前的预警
+type AnomalyDetector struct {
+	registry  *Registry
+	settings  AnomalySettings
+	onAnomaly func(AnomalyEvent)
+
+	mu    sync.Mutex
+	stats map[string]*ewma
+}
+
+// NewAnomalyDetector 创建异常检测器，onAnomaly 在检测协程中同步调用
+func NewAnomalyDetector(r *Registry, settings AnomalySettings, onAnomaly func(AnomalyEvent)) *AnomalyDetector {
+	def := DefaultAnomalySettings()
+	if settings.Interval <= 0 {
+		settings.Interval = def.Interval
+	}
+	if settings.Alpha <= 0 || settings.Alpha > 1 {
+		settings.Alpha = def.Alpha
+	}
+	if settings.Threshold <= 0 {
+		settings.Threshold = def.Threshold
+	}
+	if settings.MinRequests == 0 {
+		settings.MinRequests = def.MinRequests
+	}
+	if settings.Warmup <= 0 {
+		settings.Warmup = def.Warmup
+	}
+	if settings.MinStdDev <= 0 {
+		settings.MinStdDev = def.MinStdDev
+	}
+	return &AnomalyDetector{
+		registry:  r,
+		settings:  settings,
+		onAnomaly: onAnomaly,
+		stats:     make(map[string]*ewma),
+	}
+}
+
+// Run 按 Interval 周期检测，直至 ctx 结束
+func (d *AnomalyDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.settings.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.Check()
+		}
+	}
+}
+
+// Schedule 在共享调度器（如 Registry.Scheduler）中按 Interval 周期检测，代替 Run 的独立协程；
+// 返回的函数停止检测
+func (d *AnomalyDetector) Schedule(s *Scheduler) (cancel func()) {
+	return s.Every(d.settings.Interval, func(time.Time) { d.Check() })
+}
+
+// Check 执行一轮检测，Run 内部周期调用，也可由调用方自行调度
+func (d *AnomalyDetector) Check() {
+	now := time.Now()
+	var events []AnomalyEvent
+
+	d.mu.Lock()
+	names := d.registry.Names()
+	live := make(map[string]bool, len(names))
+	for _, name := range names {
+		live[name] = true
+		cb, ok := d.registry.Get(name)
+		if !ok {
+			continue
+		}
+		if e, ok := d.observe(name, cb.State(), cb.Counts(), now); ok {
+			events = append(events, e)
+		}
+	}
+	for name := range d.stats {
+		if !live[name] {
+			delete(d.stats, name)
+		}
+	}
+	d.mu.Unlock()
+
+	if d.onAnomaly != nil {
+		for _, e := range events {
+			d.onAnomaly(e)
+		}
+	}
+}
+
+// observe 更新单个熔断器的统计并判断是否异常，调用方需持有锁
+func (d *AnomalyDetector) observe(name string, state gobreaker.State, counts gobreaker.Counts, now time.Time) (AnomalyEvent, bool) {
+	s, ok := d.stats[name]
+	if !ok {
+		s = &ewma{}
+		d.stats[name] = s
+	}
+
+	// 计数在每个统计窗口开始时清零，此时以当前值作为增量
+	requests, failures := counts.Requests, counts.TotalFailures
+	if requests >= s.requests && failures >= s.failures {
+		requests, failures = requests-s.requests, failures-s.failures
+	}
+	s.requests, s.failures = counts.Requests, counts.TotalFailures
+	if requests == 0 || requests < d.settings.MinRequests {
+		return AnomalyEvent{}, false
+	}
+
+	rate := float64(failures) / float64(requests)
+	mean, std := s.mean, math.Max(math.Sqrt(s.variance), d.settings.MinStdDev)
+	z := (rate - mean) / std
+
+	if s.samples == 0 {
+		s.mean = rate
+	} else {
+		a := d.settings.Alpha
+		diff := rate - s.mean
+		s.mean += a * diff
+		s.variance = (1 - a) * (s.variance + a*diff*diff)
+	}
+	s.samples++
+
+	if s.samples <= d.settings.Warmup || state != gobreaker.StateClosed || z < d.settings.Threshold {
+		return AnomalyEvent{}, false
+	}
+	return AnomalyEvent{
+		Breaker:     name,
+		FailureRate: rate,
+		Mean:        mean,
+		StdDev:      std,
+		ZScore:      z,
+		At:          now,
+	}, true
+}
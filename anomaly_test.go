@@ -0,0 +1,67 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAnomalyDetector(t *testing.T) {
+	r := NewRegistry()
+	settings := DefaultSettings()
+	settings.ReadyToTrip = FailureRate(0.9)
+	settings.MinimumRequests = 1000
+	cb := r.GetOrCreate("payments", settings)
+
+	var events []AnomalyEvent
+	d := NewAnomalyDetector(r, AnomalySettings{MinRequests: 10, Warmup: 3}, func(e AnomalyEvent) {
+		events = append(events, e)
+	})
+
+	round := func(failures int) {
+		for i := 0; i < 20; i++ {
+			var err error
+			if i < failures {
+				err = errors.New("fail")
+			}
+			cb.Execute(func() (interface{}, error) { return nil, err })
+		}
+		d.Check()
+	}
+
+	for i := 0; i < 5; i++ {
+		round(1)
+	}
+	if len(events) != 0 {
+		t.Fatalf("events during baseline = %+v, want none", events)
+	}
+
+	round(8)
+	if len(events) != 1 {
+		t.Fatalf("len(events) = %v, want 1", len(events))
+	}
+	if e := events[0]; e.Breaker != "payments" || e.FailureRate != 0.4 || e.ZScore < 3 {
+		t.Errorf("event = %+v, want payments at 40%% with z >= 3", e)
+	}
+}
+
+func TestAnomalyDetector_SkipsLowVolume(t *testing.T) {
+	r := NewRegistry()
+	cb := r.GetOrCreate("svc", DefaultSettings())
+	d := NewAnomalyDetector(r, AnomalySettings{MinRequests: 10, Warmup: 0}, func(e AnomalyEvent) {
+		t.Errorf("unexpected event %+v", e)
+	})
+
+	for i := 0; i < 3; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+		d.Check()
+	}
+}
+
+func TestNewAnomalyDetector_Defaults(t *testing.T) {
+	d := NewAnomalyDetector(NewRegistry(), AnomalySettings{}, func(AnomalyEvent) {})
+	if got, want := d.settings, DefaultAnomalySettings(); got != want {
+		t.Errorf("settings = %+v, want %+v", got, want)
+	}
+}
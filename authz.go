@@ -0,0 +1,219 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrUnauthenticated 管理请求缺少有效凭证
+	ErrUnauthenticated = errors.New("circuitbreaker: unauthenticated")
+	// ErrPermissionDenied 管理请求无权执行该操作
+	ErrPermissionDenied = errors.New("circuitbreaker: permission denied")
+	// ErrReadOnly 只读模式下拒绝修改操作，可用 errors.Is 匹配 ErrPermissionDenied
+	ErrReadOnly = fmt.Errorf("%w: admin is read-only", ErrPermissionDenied)
+)
+
+// AdminOperation 管理操作
+type AdminOperation string
+
+const (
+	AdminList      AdminOperation = "list"
+	AdminGet       AdminOperation = "get"
+	AdminWatch     AdminOperation = "watch"
+	AdminReset     AdminOperation = "reset"
+	AdminForceOpen AdminOperation = "force_open"
+)
+
+// Mutating 是否为修改熔断器状态的操作
+func (op AdminOperation) Mutating() bool {
+	return op == AdminReset || op == AdminForceOpen
+}
+
+// AdminRequest 待授权的管理请求，由各管理接口（HTTP/gRPC）从传输层提取
+type AdminRequest struct {
+	// Operation 操作类型
+	Operation AdminOperation
+	// Breaker 目标熔断器名称，List/Watch 时为空
+	Breaker string
+	// Token 请求携带的 Bearer 令牌
+	Token string
+	// Subjects 已验证的客户端证书主题（CommonName 与 DNS SAN）
+	Subjects []string
+}
+
+// LeaseOwner 返回强制打开租约的持有者，持有者与请求已认证的身份绑定，调用方无法以他人名义续约或占用租约：
+// 有客户端证书时为 requested（须为证书主题之一，为空时取首个主题）；仅有令牌时为令牌指纹 "token:<hex>"，
+// requested 须为空或与之相同；两者皆无时返回 ErrUnauthenticated
+func (req AdminRequest) LeaseOwner(requested string) (string, error) {
+	if len(req.Subjects) > 0 {
+		if requested == "" {
+			return req.Subjects[0], nil
+		}
+		if slices.Contains(req.Subjects, requested) {
+			return requested, nil
+		}
+		return "", fmt.Errorf("%w: owner %q is not a client certificate subject", ErrPermissionDenied, requested)
+	}
+	if req.Token == "" {
+		return "", ErrUnauthenticated
+	}
+	sum := sha256.Sum256([]byte(req.Token))
+	owner := "token:" + hex.EncodeToString(sum[:8])
+	if requested != "" && requested != owner {
+		return "", fmt.Errorf("%w: owner %q does not match the bearer token", ErrPermissionDenied, requested)
+	}
+	return owner, nil
+}
+
+// Authorizer 管理请求授权函数，返回 ErrUnauthenticated、ErrPermissionDenied 或其包装表示拒绝；
+// 也可直接实现为 RBAC 回调
+type Authorizer func(ctx context.Context, req AdminRequest) error
+
+// TokenAuthorizer 要求请求携带任一指定令牌（常量时间比较）
+func TokenAuthorizer(tokens ...string) Authorizer {
+	return func(ctx context.Context, req AdminRequest) error {
+		if req.Token == "" {
+			return ErrUnauthenticated
+		}
+		for _, token := range tokens {
+			if subtle.ConstantTimeCompare([]byte(req.Token), []byte(token)) == 1 {
+				return nil
+			}
+		}
+		return ErrUnauthenticated
+	}
+}
+
+// SubjectAuthorizer 要求 mTLS 客户端证书主题在白名单内
+func SubjectAuthorizer(subjects ...string) Authorizer {
+	allowed := make(map[string]bool, len(subjects))
+	for _, s := range subjects {
+		allowed[s] = true
+	}
+	return func(ctx context.Context, req AdminRequest) error {
+		if len(req.Subjects) == 0 {
+			return ErrUnauthenticated
+		}
+		for _, s := range req.Subjects {
+			if allowed[s] {
+				return nil
+			}
+		}
+		return ErrPermissionDenied
+	}
+}
+
+// MutationAuthorizer 仅对修改操作应用 authz，查询操作直接放行
+func MutationAuthorizer(authz Authorizer) Authorizer {
+	return func(ctx context.Context, req AdminRequest) error {
+		if !req.Operation.Mutating() {
+			return nil
+		}
+		return authz(ctx, req)
+	}
+}
+
+// AllAuthorizers 依次应用所有授权函数，全部通过才放行
+func AllAuthorizers(authz ...Authorizer) Authorizer {
+	return func(ctx context.Context, req AdminRequest) error {
+		for _, fn := range authz {
+			if err := fn(ctx, req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Authorize 使用 authz 授权请求；authz 为空时仅放行查询操作，修改操作返回 ErrPermissionDenied，
+// 避免强制打开、重置等危险操作被匿名调用
+func (authz Authorizer) Authorize(ctx context.Context, req AdminRequest) error {
+	if authz == nil {
+		if req.Operation.Mutating() {
+			return ErrPermissionDenied
+		}
+		return nil
+	}
+	return authz(ctx, req)
+}
+
+// AdminMode 管理接口模式
+type AdminMode int
+
+const (
+	// AdminReadWrite 允许查询与修改操作（修改操作仍需通过授权）
+	AdminReadWrite AdminMode = iota
+	// AdminReadOnly 仅允许查询，修改操作返回 ErrReadOnly
+	AdminReadOnly
+)
+
+// AdminAuditEvent 管理操作审计事件，所有修改操作（无论是否放行）与被拒绝的查询都会产生
+type AdminAuditEvent struct {
+	// Operation 操作类型
+	Operation AdminOperation
+	// Breaker 目标熔断器名称
+	Breaker string
+	// Subjects 客户端证书主题，令牌不记录
+	Subjects []string
+	// Allowed 是否放行
+	Allowed bool
+	// Err 拒绝原因
+	Err error
+	// At 发生时间
+	At time.Time
+}
+
+// AdminPolicy 管理接口的访问策略，由 HTTP 与 gRPC 管理接口共用
+type AdminPolicy struct {
+	// Mode 管理接口模式，默认 AdminReadWrite
+	Mode AdminMode
+	// Authorizer 授权函数，为空时仅放行查询操作
+	Authorizer Authorizer
+	// OnAudit 审计回调，同步调用
+	OnAudit func(AdminAuditEvent)
+}
+
+// Check 按模式与授权函数检查请求并记录审计事件
+func (p AdminPolicy) Check(ctx context.Context, req AdminRequest) error {
+	var err error
+	if p.Mode == AdminReadOnly && req.Operation.Mutating() {
+		err = ErrReadOnly
+	} else {
+		err = p.Authorizer.Authorize(ctx, req)
+	}
+
+	if p.OnAudit != nil && (err != nil || req.Operation.Mutating()) {
+		p.OnAudit(AdminAuditEvent{
+			Operation: req.Operation,
+			Breaker:   req.Breaker,
+			Subjects:  req.Subjects,
+			Allowed:   err == nil,
+			Err:       err,
+			At:        time.Now(),
+		})
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAuthorizers(t *testing.T) {
+	ctx := context.Background()
+	reset := AdminRequest{Operation: AdminReset, Breaker: "payments"}
+	withToken := func(req AdminRequest, token string) AdminRequest {
+		req.Token = token
+		return req
+	}
+	withSubjects := func(req AdminRequest, subjects ...string) AdminRequest {
+		req.Subjects = subjects
+		return req
+	}
+
+	tests := []struct {
+		name  string
+		authz Authorizer
+		req   AdminRequest
+		want  error
+	}{
+		{"nil allows reads", nil, AdminRequest{Operation: AdminList}, nil},
+		{"nil denies mutations", nil, reset, ErrPermissionDenied},
+		{"token ok", TokenAuthorizer("s3cret"), withToken(reset, "s3cret"), nil},
+		{"token wrong", TokenAuthorizer("s3cret"), withToken(reset, "guess"), ErrUnauthenticated},
+		{"token missing", TokenAuthorizer("s3cret"), reset, ErrUnauthenticated},
+		{"subject ok", SubjectAuthorizer("oncall"), withSubjects(reset, "web", "oncall"), nil},
+		{"subject denied", SubjectAuthorizer("oncall"), withSubjects(reset, "web"), ErrPermissionDenied},
+		{"subject missing", SubjectAuthorizer("oncall"), reset, ErrUnauthenticated},
+		{"mutation only passes reads", MutationAuthorizer(TokenAuthorizer("s3cret")), AdminRequest{Operation: AdminGet}, nil},
+		{"mutation only checks writes", MutationAuthorizer(TokenAuthorizer("s3cret")), reset, ErrUnauthenticated},
+		{
+			"all requires every check",
+			AllAuthorizers(TokenAuthorizer("s3cret"), SubjectAuthorizer("oncall")),
+			withSubjects(withToken(reset, "s3cret"), "web"),
+			ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.authz.Authorize(ctx, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("Authorize() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminRequest_LeaseOwner(t *testing.T) {
+	cert := AdminRequest{Subjects: []string{"alice", "alice.ops.example.com"}}
+	if owner, err := cert.LeaseOwner(""); err != nil || owner != "alice" {
+		t.Errorf("LeaseOwner(\"\") = %q, %v, want first subject", owner, err)
+	}
+	if owner, err := cert.LeaseOwner("alice.ops.example.com"); err != nil || owner != "alice.ops.example.com" {
+		t.Errorf("LeaseOwner(SAN) = %q, %v, want SAN", owner, err)
+	}
+	if _, err := cert.LeaseOwner("bob"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("LeaseOwner(bob) error = %v, want ErrPermissionDenied", err)
+	}
+
+	token := AdminRequest{Token: "s3cret"}
+	owner, err := token.LeaseOwner("")
+	if err != nil || !strings.HasPrefix(owner, "token:") || strings.Contains(owner, "s3cret") {
+		t.Fatalf("LeaseOwner() = %q, %v, want token fingerprint", owner, err)
+	}
+	if again, _ := token.LeaseOwner(owner); again != owner {
+		t.Errorf("LeaseOwner(%q) = %q, want same owner", owner, again)
+	}
+	if other, _ := (AdminRequest{Token: "other"}).LeaseOwner(""); other == owner {
+		t.Errorf("different tokens share owner %q", owner)
+	}
+	if _, err := token.LeaseOwner("alice"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("LeaseOwner(alice) error = %v, want ErrPermissionDenied", err)
+	}
+	if _, err := (AdminRequest{}).LeaseOwner("alice"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("anonymous LeaseOwner() error = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestAdminPolicy_ReadOnly(t *testing.T) {
+	var events []AdminAuditEvent
+	p := AdminPolicy{
+		Mode:       AdminReadOnly,
+		Authorizer: TokenAuthorizer("s3cret"),
+		OnAudit:    func(e AdminAuditEvent) { events = append(events, e) },
+	}
+	ctx := context.Background()
+
+	if err := p.Check(ctx, AdminRequest{Operation: AdminGet, Token: "s3cret"}); err != nil {
+		t.Errorf("Check(get) = %v, want nil", err)
+	}
+	err := p.Check(ctx, AdminRequest{Operation: AdminForceOpen, Breaker: "payments", Token: "s3cret"})
+	if !errors.Is(err, ErrReadOnly) || !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Check(force_open) = %v, want %v", err, ErrReadOnly)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("len(events) = %v, want 1", len(events))
+	}
+	if e := events[0]; e.Operation != AdminForceOpen || e.Breaker != "payments" || e.Allowed {
+		t.Errorf("event = %+v, want denied force_open on payments", e)
+	}
+}
+
+func TestAdminPolicy_AuditsAllowedMutations(t *testing.T) {
+	var events []AdminAuditEvent
+	p := AdminPolicy{
+		Authorizer: TokenAuthorizer("s3cret"),
+		OnAudit:    func(e AdminAuditEvent) { events = append(events, e) },
+	}
+
+	if err := p.Check(context.Background(), AdminRequest{Operation: AdminReset, Token: "s3cret"}); err != nil {
+		t.Fatalf("Check(reset) = %v, want nil", err)
+	}
+	if len(events) != 1 || !events[0].Allowed {
+		t.Errorf("events = %+v, want one allowed reset", events)
+	}
+}
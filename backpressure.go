@@ -0,0 +1,100 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// OpenFor 使熔断器在 d 内保持打开，期间请求以 ReasonOpen 拒绝、State 返回 StateOpen
+// 用于遵循下游自身的背压信号（如 HTTP Retry-After、gRPC pushback），而不是固定的 Timeout；
+// 只会延长已有的保持时间，不会缩短。到期后恢复为底层熔断器的状态，不触发状态变更回调
+func (cb *CircuitBreaker) OpenFor(d time.Duration) {
+	if d < 0 && cb.strict.Load() {
+		cb.misuse("OpenFor", fmt.Sprintf("duration is negative (%v)", d))
+	}
+	if d <= 0 {
+		return
+	}
+	cb.holdUntil(cb.now().Add(d).UnixNano())
+}
+
+// holdUntil 将保持期延长至 until（UnixNano），不会缩短，返回是否由本次调用设置了截止时间
+func (cb *CircuitBreaker) holdUntil(until int64) bool {
+	for {
+		cur := cb.openUntil.Load()
+		if cur >= until {
+			return false
+		}
+		if cb.openUntil.CompareAndSwap(cur, until) {
+			return true
+		}
+	}
+}
+
+// NextProbeAt 返回打开状态预计结束、可再次探测的时间（OpenFor 保持期、强制打开租约与底层打开期取较晚者），
+// 未处于打开状态时返回零值
+func (cb *CircuitBreaker) NextProbeAt() time.Time {
+	state, until := cb.openState()
+	if state != gobreaker.StateOpen {
+		return time.Time{}
+	}
+	return until
+}
+
+// OpenUntil 返回 OpenFor 保持打开的截止时间，未保持时返回零值
+func (cb *CircuitBreaker) OpenUntil() time.Time {
+	until := cb.openUntil.Load()
+	if until == 0 || cb.now().UnixNano() >= until {
+		return time.Time{}
+	}
+	return time.Unix(0, until)
+}
+
+// Reset 手动将熔断器恢复为关闭状态并清空计数，同时清除 OpenFor 保持期与强制打开租约，用于运维介入
+func (cb *CircuitBreaker) Reset() {
+	cb.openUntil.Store(0)
+	cb.lease.Store(nil)
+	cb.reset(true)
+}
+
+// heldOpen 判断是否处于 OpenFor 保持期或强制打开租约期内
+func (cb *CircuitBreaker) heldOpen() bool {
+	return cb.now().UnixNano() < cb.openUntil.Load() || !cb.leaseUntil().IsZero()
+}
+
+// checkHeld 处于保持期时返回拒绝错误，强制打开时原因为 ReasonForced，调用方需持有读锁
+func (cb *CircuitBreaker) checkHeld() error {
+	if !cb.leaseUntil().IsZero() {
+		return cb.rejectOpen(ErrForcedOpen)
+	}
+	if cb.heldOpen() || cb.timeoutHeld() {
+		return cb.rejectOpen(gobreaker.ErrOpenState)
+	}
+	return nil
+}
+
+// state 返回考虑保持期后的状态，调用方需持有读锁
+func (cb *CircuitBreaker) state() gobreaker.State {
+	if cb.heldOpen() || cb.timeoutHeld() || cb.pendingHalfOpen.Load() {
+		return gobreaker.StateOpen
+	}
+	return cb.cb.State()
+}
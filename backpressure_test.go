@@ -0,0 +1,93 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestOpenFor(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+	cb.OpenFor(20 * time.Millisecond)
+	cb.OpenFor(time.Millisecond) // 不会缩短
+
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+	_, err := cb.Execute(func() (interface{}, error) { return nil, nil })
+	if ReasonOf(err) != ReasonOpen || !errors.Is(err, gobreaker.ErrOpenState) {
+		t.Errorf("Execute() error = %v, want %v", err, gobreaker.ErrOpenState)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+	if !cb.OpenUntil().IsZero() {
+		t.Errorf("OpenUntil() = %v, want zero", cb.OpenUntil())
+	}
+	if _, err := cb.Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("Execute() error = %v", err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	var transitions []gobreaker.State
+	settings.OnStateChange = func(name string, from, to gobreaker.State) {
+		transitions = append(transitions, to)
+	}
+	cb := NewCircuitBreaker("test", settings)
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	cb.OpenFor(time.Minute)
+	cb.Reset()
+
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+	if !cb.OpenUntil().IsZero() {
+		t.Errorf("OpenUntil() = %v, want zero", cb.OpenUntil())
+	}
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Requests = %v, want 0", got)
+	}
+	if want := []gobreaker.State{gobreaker.StateOpen, gobreaker.StateClosed}; !reflect.DeepEqual(transitions, want) {
+		t.Errorf("transitions = %v, want %v", transitions, want)
+	}
+}
+
+func TestNextProbeAt(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	settings.Timeout = 30 * time.Second
+	cb := NewCircuitBreaker("probe", settings)
+
+	if got := cb.NextProbeAt(); !got.IsZero() {
+		t.Errorf("NextProbeAt() closed = %v, want zero", got)
+	}
+
+	before := time.Now()
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("x") })
+	got := cb.NextProbeAt()
+	if got.Before(before.Add(settings.Timeout)) || got.After(time.Now().Add(settings.Timeout)) {
+		t.Errorf("NextProbeAt() = %v, want ~%v", got, before.Add(settings.Timeout))
+	}
+
+	cb.OpenFor(time.Hour)
+	if got := cb.NextProbeAt(); got.Before(before.Add(time.Hour)) {
+		t.Errorf("NextProbeAt() with hold = %v, want hold end", got)
+	}
+
+	_, err := cb.Execute(func() (interface{}, error) { return nil, nil })
+	d, ok := RetryAfter(err)
+	if !ok || d < 59*time.Minute || d > time.Hour {
+		t.Errorf("RetryAfter() = %v, %v, want ~1h", d, ok)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// 延迟基线参数
+const (
+	// baselineCompression t-digest 压缩参数
+	baselineCompression = 100
+	// baselineDecayAt 采样权重达到该值时整体减半，使基线跟随近期延迟变化
+	baselineDecayAt = 10000
+	// MinBaselineSamples 基线至少包含的采样数，不足时 LatencyQuantile 返回 false，DeadlineQuantile 不生效
+	MinBaselineSamples = 100
+)
+
+// LatencyBaseline 成功调用耗时基线的摘要
+type LatencyBaseline struct {
+	// Samples 基线当前的有效采样数（旧采样按衰减折算）
+	Samples uint64
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+}
+
+// latencyBaseline 按 Settings.LatencySampleRate 采样的成功调用耗时
+type latencyBaseline struct {
+	mu     sync.Mutex
+	digest *tdigest
+}
+
+// observe 按采样比例记录成功调用的耗时
+func (b *latencyBaseline) observe(rate float64, d time.Duration) {
+	if rate <= 0 || (rate < 1 && rand.Float64() >= rate) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.digest == nil {
+		b.digest = newTDigest(baselineCompression)
+	}
+	b.digest.add(float64(d))
+	if b.digest.count() >= baselineDecayAt {
+		b.digest.decay()
+	}
+}
+
+// quantile 返回分位数，采样不足 MinBaselineSamples 时返回 false
+func (b *latencyBaseline) quantile(q float64) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.digest == nil || b.digest.count() < MinBaselineSamples {
+		return 0, false
+	}
+	return time.Duration(b.digest.quantile(q)), true
+}
+
+// summary 返回基线摘要，没有采样时返回 false
+func (b *latencyBaseline) summary() (LatencyBaseline, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.digest == nil || b.digest.count() == 0 {
+		return LatencyBaseline{}, false
+	}
+	return LatencyBaseline{
+		Samples: uint64(b.digest.count()),
+		P50:     time.Duration(b.digest.quantile(0.5)),
+		P90:     time.Duration(b.digest.quantile(0.9)),
+		P99:     time.Duration(b.digest.quantile(0.99)),
+	}, true
+}
+
+// reset 清空基线
+func (b *latencyBaseline) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.digest != nil {
+		b.digest.reset()
+	}
+}
+
+// LatencyBaseline 返回成功调用耗时基线的摘要，未启用采样或尚无采样时返回 false
+func (cb *CircuitBreaker) LatencyBaseline() (LatencyBaseline, bool) {
+	return cb.baseline.summary()
+}
+
+// LatencyQuantile 返回成功调用耗时的分位数 q（0~1），可用于基于延迟的熔断策略或截止时间判断；
+// 采样不足 MinBaselineSamples 时返回 false
+func (cb *CircuitBreaker) LatencyQuantile(q float64) (time.Duration, bool) {
+	return cb.baseline.quantile(q)
+}
+
+// ResetLatencyBaseline 清空延迟基线，如依赖发布新版本后重新建立基线
+func (cb *CircuitBreaker) ResetLatencyBaseline() {
+	cb.baseline.reset()
+}
+
+// observeSuccess 成功调用时按采样比例记录耗时
+func (cb *CircuitBreaker) observeSuccess(settings *Settings, outcome Outcome, start time.Time) {
+	if outcome == OutcomeSuccess && settings.LatencySampleRate > 0 {
+		cb.baseline.observe(settings.LatencySampleRate, time.Since(start))
+	}
+}
+
+// checkDeadlineQuantile 剩余时间（扣除 DeadlineOverhead）低于成功调用耗时的 DeadlineQuantile 分位数时，
+// 调用大概率超时，直接返回 ErrDeadlineTooShort；调用方需持有读锁
+func (cb *CircuitBreaker) checkDeadlineQuantile(remaining time.Duration) error {
+	if cb.settings.DeadlineQuantile <= 0 {
+		return nil
+	}
+	if q, ok := cb.baseline.quantile(cb.settings.DeadlineQuantile); ok && remaining < q {
+		return reject(cb.name, ErrDeadlineTooShort)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLatencyBaseline_SamplesSuccesses(t *testing.T) {
+	settings := DefaultSettings()
+	settings.LatencySampleRate = 1
+	cb := NewCircuitBreaker("baseline", settings)
+
+	if _, ok := cb.LatencyBaseline(); ok {
+		t.Fatal("LatencyBaseline() before calls = ok, want none")
+	}
+	for i := 0; i < MinBaselineSamples; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("failures are not sampled") })
+
+	b, ok := cb.LatencyBaseline()
+	if !ok || b.Samples != MinBaselineSamples {
+		t.Fatalf("LatencyBaseline() = %+v, %v, want %d samples", b, ok, MinBaselineSamples)
+	}
+	if b.P50 > b.P90 || b.P90 > b.P99 {
+		t.Errorf("LatencyBaseline() = %+v, want non-decreasing quantiles", b)
+	}
+	if _, ok := cb.LatencyQuantile(0.99); !ok {
+		t.Error("LatencyQuantile(0.99) ok = false with enough samples")
+	}
+	if got := cb.Stats().LatencyBaseline; got == nil || got.Samples != b.Samples {
+		t.Errorf("Stats().LatencyBaseline = %+v, want %+v", got, b)
+	}
+
+	cb.ResetLatencyBaseline()
+	if _, ok := cb.LatencyBaseline(); ok {
+		t.Error("LatencyBaseline() after reset = ok, want none")
+	}
+}
+
+func TestLatencyBaseline_SampleRate(t *testing.T) {
+	settings := DefaultSettings()
+	settings.LatencySampleRate = 0.1
+	cb := NewCircuitBreaker("sampled", settings)
+	for i := 0; i < 2000; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	b, _ := cb.LatencyBaseline()
+	if b.Samples < 100 || b.Samples > 300 {
+		t.Errorf("Samples = %d, want about 10%% of 2000 calls", b.Samples)
+	}
+
+	cb = NewCircuitBreaker("disabled", DefaultSettings())
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	if _, ok := cb.LatencyBaseline(); ok || cb.Stats().LatencyBaseline != nil {
+		t.Error("baseline recorded with LatencySampleRate = 0")
+	}
+}
+
+func TestDeadlineQuantile_RejectsShortDeadlines(t *testing.T) {
+	settings := DefaultSettings()
+	settings.LatencySampleRate = 1
+	settings.DeadlineQuantile = 0.99
+	cb := NewCircuitBreaker("deadline", settings)
+
+	slow := func(context.Context) (interface{}, error) {
+		time.Sleep(2 * time.Millisecond)
+		return nil, nil
+	}
+	// 采样不足时不生效
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	if _, err := cb.ExecuteContext(ctx, func(context.Context) (interface{}, error) { return nil, nil }); errors.Is(err, ErrDeadlineTooShort) {
+		t.Error("ExecuteContext() rejected before the baseline was established")
+	}
+	cancel()
+
+	for i := 0; i < MinBaselineSamples; i++ {
+		cb.ExecuteContext(context.Background(), slow)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := cb.ExecuteContext(ctx, slow); !errors.Is(err, ErrDeadlineTooShort) || ReasonOf(err) == ReasonNone {
+		t.Errorf("ExecuteContext() with 1ms left error = %v, want ErrDeadlineTooShort", err)
+	}
+	if _, err := cb.ExecuteContext(context.Background(), slow); err != nil {
+		t.Errorf("ExecuteContext() without deadline error = %v", err)
+	}
+}
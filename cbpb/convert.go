@@ -0,0 +1,212 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package cbpb
+
+import (
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// State 枚举值的简写
+const (
+	StateUnspecified = State_STATE_UNSPECIFIED
+	StateClosed      = State_STATE_CLOSED
+	StateHalfOpen    = State_STATE_HALF_OPEN
+	StateOpen        = State_STATE_OPEN
+)
+
+// FromState 转换 gobreaker 状态
+func FromState(s gobreaker.State) State {
+	switch s {
+	case gobreaker.StateClosed:
+		return StateClosed
+	case gobreaker.StateHalfOpen:
+		return StateHalfOpen
+	case gobreaker.StateOpen:
+		return StateOpen
+	default:
+		return StateUnspecified
+	}
+}
+
+// ToState 转换为 gobreaker 状态，StateUnspecified 按关闭处理
+func ToState(s State) gobreaker.State {
+	switch s {
+	case StateHalfOpen:
+		return gobreaker.StateHalfOpen
+	case StateOpen:
+		return gobreaker.StateOpen
+	default:
+		return gobreaker.StateClosed
+	}
+}
+
+// FromSettings 转换配置，函数类型字段被忽略
+func FromSettings(s circuitbreaker.Settings) *Settings {
+	return &Settings{
+		MaxRequests:             s.MaxRequests,
+		IntervalMs:              s.Interval.Milliseconds(),
+		TimeoutMs:               s.Timeout.Milliseconds(),
+		CallTimeoutMs:           s.CallTimeout.Milliseconds(),
+		DeadlineOverheadMs:      s.DeadlineOverhead.Milliseconds(),
+		DeferTimeoutOutcome:     s.DeferTimeoutOutcome,
+		MaxPressure:             s.MaxPressure,
+		CountCallerCancellation: s.CountCallerCancellation,
+		Description:             s.Description,
+		RunbookUrl:              s.RunbookURL,
+		Tier:                    string(s.Tier),
+		CloseFailureRate:        s.CloseFailureRate,
+		MinimumRequests:         s.MinimumRequests,
+		Labels:                  copyLabels(s.Labels),
+	}
+}
+
+// ToSettings 转换为 Settings，函数类型字段保持零值，由调用方按需补充
+func ToSettings(s *Settings) circuitbreaker.Settings {
+	if s == nil {
+		return circuitbreaker.Settings{}
+	}
+	return circuitbreaker.Settings{
+		MaxRequests:             s.MaxRequests,
+		Interval:                time.Duration(s.IntervalMs) * time.Millisecond,
+		Timeout:                 time.Duration(s.TimeoutMs) * time.Millisecond,
+		CallTimeout:             time.Duration(s.CallTimeoutMs) * time.Millisecond,
+		DeadlineOverhead:        time.Duration(s.DeadlineOverheadMs) * time.Millisecond,
+		DeferTimeoutOutcome:     s.DeferTimeoutOutcome,
+		MaxPressure:             s.MaxPressure,
+		CountCallerCancellation: s.CountCallerCancellation,
+		Description:             s.Description,
+		RunbookURL:              s.RunbookUrl,
+		Tier:                    circuitbreaker.Tier(s.Tier),
+		CloseFailureRate:        s.CloseFailureRate,
+		MinimumRequests:         s.MinimumRequests,
+		Labels:                  copyLabels(s.Labels),
+	}
+}
+
+// fromRejections 转换按原因的拒绝数，键为 Reason 字符串
+func fromRejections(byReason map[circuitbreaker.Reason]uint64) map[string]uint64 {
+	if len(byReason) == 0 {
+		return nil
+	}
+	out := make(map[string]uint64, len(byReason))
+	for reason, n := range byReason {
+		out[string(reason)] = n
+	}
+	return out
+}
+
+// FromCounts 转换计数
+func FromCounts(c gobreaker.Counts) *Counts {
+	return &Counts{
+		Requests:             c.Requests,
+		TotalSuccesses:       c.TotalSuccesses,
+		TotalFailures:        c.TotalFailures,
+		ConsecutiveSuccesses: c.ConsecutiveSuccesses,
+		ConsecutiveFailures:  c.ConsecutiveFailures,
+	}
+}
+
+// FromStats 将统计快照转换为 Snapshot
+func FromStats(s circuitbreaker.Stats) *Snapshot {
+	return &Snapshot{
+		Name:           s.Name,
+		State:          FromState(s.State),
+		Counts:         FromCounts(s.Counts),
+		InFlight:       s.InFlight,
+		MaxInFlight:    s.MaxInFlight,
+		LatencyP99Ms:   s.LatencyP99.Milliseconds(),
+		HealthScore:    s.HealthScore,
+		Description:    s.Description,
+		RunbookUrl:     s.RunbookURL,
+		Tier:           string(s.Tier),
+		Labels:         copyLabels(s.Labels),
+		CountingPaused: s.CountingPaused,
+		ErrorSamples:   fromErrorSamples(s.ErrorSamples),
+		Lease:          FromLease(s.Lease),
+		LastTripCause:  FromTripCause(s.LastTripCause),
+
+		Rejections:         s.Rejections,
+		RejectionsByReason: fromRejections(s.RejectionsByReason),
+	}
+}
+
+// FromTripCause 转换打开原因，nil 返回 nil
+func FromTripCause(c *circuitbreaker.TripCause) *TripCause {
+	if c == nil {
+		return nil
+	}
+	out := &TripCause{
+		At:          timestamppb.New(c.At),
+		From:        FromState(c.From),
+		Policy:      c.Policy,
+		Counts:      FromCounts(c.Counts),
+		FailureRate: c.FailureRate,
+		Pressure:    c.Pressure,
+	}
+	if c.Error != nil {
+		out.Error = fromErrorSamples([]circuitbreaker.ErrorSample{*c.Error})[0]
+	}
+	return out
+}
+
+// FromLease 转换强制打开租约，nil 返回 nil
+func FromLease(l *circuitbreaker.Lease) *Lease {
+	if l == nil {
+		return nil
+	}
+	return &Lease{Owner: l.Owner, AcquiredAt: timestamppb.New(l.Acquired), ExpiresAt: timestamppb.New(l.Expires)}
+}
+
+// fromErrorSamples 转换错误样本
+func fromErrorSamples(samples []circuitbreaker.ErrorSample) []*ErrorSample {
+	if len(samples) == 0 {
+		return nil
+	}
+	out := make([]*ErrorSample, len(samples))
+	for i, s := range samples {
+		out[i] = &ErrorSample{Error: s.Error, StatusCode: int32(s.StatusCode), Peer: s.Peer, At: timestamppb.New(s.At)}
+	}
+	return out
+}
+
+// NewEvent 由状态变更回调参数构造 Event，可直接用于 Registry.Subscribe
+func NewEvent(name string, from, to gobreaker.State, tier circuitbreaker.Tier, at time.Time) *Event {
+	return &Event{
+		Breaker: name,
+		From:    FromState(from),
+		To:      FromState(to),
+		At:      timestamppb.New(at),
+		Tier:    string(tier),
+	}
+}
+
+// copyLabels 复制标签，避免与调用方共享 map
+func copyLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 zampo.
+
+package cbpb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// marshalJSON 以 protojson 编码并去除空白，protojson 的输出空白不稳定
+func marshalJSON(t *testing.T, m proto.Message) string {
+	t.Helper()
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestSettings_RoundTrip(t *testing.T) {
+	in := circuitbreaker.Settings{
+		MaxRequests:      5,
+		Interval:         time.Minute,
+		Timeout:          30 * time.Second,
+		CallTimeout:      250 * time.Millisecond,
+		Description:      "payments API",
+		RunbookURL:       "https://runbooks.example.com/payments",
+		Tier:             circuitbreaker.TierDegradedOK,
+		CloseFailureRate: 0.1,
+		MinimumRequests:  20,
+		Labels:           map[string]string{"service": "payments"},
+	}
+
+	data := marshalJSON(t, FromSettings(in))
+	if !strings.Contains(data, `"intervalMs":"60000"`) {
+		t.Errorf("json = %s, want proto3 int64 string encoding", data)
+	}
+
+	var decoded Settings
+	if err := protojson.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out := ToSettings(&decoded); !reflect.DeepEqual(out, in) {
+		t.Errorf("ToSettings() = %+v, want %+v", out, in)
+	}
+}
+
+func TestState_JSON(t *testing.T) {
+	if data := marshalJSON(t, &Event{From: FromState(gobreaker.StateHalfOpen)}); data != `{"from":"STATE_HALF_OPEN"}` {
+		t.Errorf("Marshal() = %s, want enum name", data)
+	}
+
+	for _, in := range []string{`"STATE_OPEN"`, `3`} {
+		var e Event
+		if err := protojson.Unmarshal([]byte(`{"from":`+in+`}`), &e); err != nil || e.From != StateOpen {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", in, e.From, err, StateOpen)
+		}
+	}
+	var e Event
+	if err := protojson.Unmarshal([]byte(`{"from":"BOGUS"}`), &e); err == nil {
+		t.Error("Unmarshal(BOGUS) error = nil, want error")
+	}
+}
+
+func TestFromStats(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ErrorSamples = 1
+	cb := circuitbreaker.NewCircuitBreaker("svc", settings)
+	cb.Execute(func() (interface{}, error) {
+		return nil, circuitbreaker.Annotate(errors.New("boom"), 502, "db:5432")
+	})
+	cb.OpenFor(time.Minute)
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+
+	snap := FromStats(cb.Stats())
+	if snap.Name != "svc" || snap.State != StateOpen || snap.Counts.Requests != 1 {
+		t.Errorf("FromStats() = %+v", snap)
+	}
+	if len(snap.ErrorSamples) != 1 || snap.ErrorSamples[0].StatusCode != 502 || snap.ErrorSamples[0].Peer != "db:5432" {
+		t.Errorf("ErrorSamples = %+v", snap.ErrorSamples)
+	}
+	if snap.Tier != string(circuitbreaker.TierCritical) {
+		t.Errorf("Tier = %q, want %q", snap.Tier, circuitbreaker.TierCritical)
+	}
+	if snap.Rejections != 1 || snap.RejectionsByReason["OPEN"] != 1 {
+		t.Errorf("Rejections = %d, %v, want 1 OPEN", snap.Rejections, snap.RejectionsByReason)
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	e := NewEvent("svc", gobreaker.StateClosed, gobreaker.StateOpen, circuitbreaker.TierBestEffort, at)
+
+	data := marshalJSON(t, e)
+	want := `{"breaker":"svc","from":"STATE_CLOSED","to":"STATE_OPEN","at":"2025-01-02T03:04:05Z","tier":"best-effort"}`
+	if data != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
+
+func TestFromTripCause(t *testing.T) {
+	if FromTripCause(nil) != nil {
+		t.Error("FromTripCause(nil) should be nil")
+	}
+	cause := &circuitbreaker.TripCause{
+		At:     time.Now(),
+		From:   gobreaker.StateClosed,
+		Policy: "failure_rate",
+		Counts: gobreaker.Counts{Requests: 10, TotalFailures: 6},
+		Error:  &circuitbreaker.ErrorSample{Error: "refused", StatusCode: 503},
+	}
+	got := FromTripCause(cause)
+	if got.Policy != "failure_rate" || got.From != StateClosed || got.Counts.TotalFailures != 6 || got.Error.StatusCode != 503 {
+		t.Errorf("FromTripCause() = %+v", got)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package cbpb 定义熔断器状态与事件的交换格式（见 circuitbreaker.proto）
+//
+// circuitbreaker.pb.go 由 protoc-gen-go 生成，修改 circuitbreaker.proto 后需重新生成；
+// JSON 编码请使用 protojson，以遵循 proto3 JSON 映射（lowerCamelCase 字段名、枚举名、int64 编码为字符串），
+// 与其他语言的 protojson 输出互通。convert.go 与 shared.go 提供与 circuitbreaker 包类型之间的转换
+package cbpb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative ../cbpb/circuitbreaker.proto
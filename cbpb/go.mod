@@ -0,0 +1,9 @@
+module github.com/go-anyway/framework-circuitbreaker/cbpb
+
+go 1.25.4
+
+require (
+	github.com/go-anyway/framework-circuitbreaker v0.0.0-20261016101936-22ab0cef4c5b
+	github.com/sony/gobreaker v1.0.0
+	google.golang.org/protobuf v1.34.2
+)
@@ -0,0 +1,167 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package cbpb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// SharedStateKeyPrefix 共享存储中 SharedState 的键前缀，与 Java 服务约定一致
+const SharedStateKeyPrefix = "circuitbreaker:v1:state:"
+
+// resilience4j CircuitBreaker.State 名称
+const (
+	R4jClosed      = "CLOSED"
+	R4jOpen        = "OPEN"
+	R4jHalfOpen    = "HALF_OPEN"
+	R4jForcedOpen  = "FORCED_OPEN"
+	R4jDisabled    = "DISABLED"
+	R4jMetricsOnly = "METRICS_ONLY"
+)
+
+// SharedStateKey 返回熔断器在共享存储中的键
+func SharedStateKey(name string) string {
+	return SharedStateKeyPrefix + name
+}
+
+// ToR4jState 转换为 resilience4j 状态名，forced 表示打开状态来自强制打开租约
+func ToR4jState(s State, forced bool) string {
+	switch s {
+	case StateOpen:
+		if forced {
+			return R4jForcedOpen
+		}
+		return R4jOpen
+	case StateHalfOpen:
+		return R4jHalfOpen
+	default:
+		return R4jClosed
+	}
+}
+
+// FromR4jState 解析 resilience4j 状态名：FORCED_OPEN 按打开处理，
+// DISABLED 与 METRICS_ONLY 不拒绝请求，按关闭处理
+func FromR4jState(name string) (State, error) {
+	switch name {
+	case R4jClosed, R4jDisabled, R4jMetricsOnly:
+		return StateClosed, nil
+	case R4jOpen, R4jForcedOpen:
+		return StateOpen, nil
+	case R4jHalfOpen:
+		return StateHalfOpen, nil
+	default:
+		return StateUnspecified, fmt.Errorf("cbpb: unknown resilience4j state %q", name)
+	}
+}
+
+// NewSharedState 生成熔断器当前的共享状态，origin 为写入方标识
+func NewSharedState(cb *circuitbreaker.CircuitBreaker, origin string) *SharedState {
+	now := time.Now()
+	stats := cb.Stats()
+	_, forced := cb.Lease()
+	out := &SharedState{
+		Name:        cb.Name(),
+		State:       FromState(stats.State),
+		R4JState:    ToR4jState(FromState(stats.State), forced),
+		UpdatedAt:   timestamppb.New(now),
+		FailureRate: failureRate(stats.Counts, cb.GetSettings().MinimumRequests),
+		Origin:      origin,
+	}
+	if until := cb.NextProbeAt(); !until.IsZero() {
+		out.OpenUntil = timestamppb.New(until)
+		out.RemainingMs = until.Sub(now).Milliseconds()
+	}
+	return out
+}
+
+// failureRate 返回失败率，样本数不足 minimum（至少 1）时与 resilience4j 一致返回 -1
+func failureRate(c gobreaker.Counts, minimum uint32) float64 {
+	if c.Requests == 0 || c.Requests < minimum {
+		return -1
+	}
+	return float64(c.TotalFailures) / float64(c.Requests)
+}
+
+// Effective 返回读取方应采用的状态：State 未设置时按 R4JState 解析，均无法识别时返回 StateUnspecified
+func (s *SharedState) Effective() State {
+	if s.State != StateUnspecified {
+		return s.State
+	}
+	state, _ := FromR4jState(s.R4JState)
+	return state
+}
+
+// Remaining 返回打开状态的剩余时长：优先使用与时钟无关的 RemainingMs，
+// 否则按 OpenUntil 与本地时钟计算；非打开状态或已过期时返回 0
+func (s *SharedState) Remaining(now time.Time) time.Duration {
+	if s.Effective() != StateOpen {
+		return 0
+	}
+	if s.RemainingMs > 0 {
+		return time.Duration(s.RemainingMs) * time.Millisecond
+	}
+	if s.OpenUntil != nil {
+		return max(s.OpenUntil.AsTime().Sub(now), 0)
+	}
+	return 0
+}
+
+// Apply 将共享状态应用到本地熔断器：打开状态使 cb 保持打开至剩余时长结束（以 maxHold 为上限，
+// 防止写入方时钟或配置异常使依赖长期无法恢复），其他状态不改变本地熔断器，返回是否应用
+//
+// 没有截止时间的打开状态（如 Java 侧 FORCED_OPEN 未写入 open_until）按 maxHold 保持，
+// 由写入方续写以维持打开
+func (s *SharedState) Apply(cb *circuitbreaker.CircuitBreaker, maxHold time.Duration) bool {
+	if s.Effective() != StateOpen || maxHold <= 0 {
+		return false
+	}
+	d := s.Remaining(time.Now())
+	if d <= 0 {
+		if s.RemainingMs > 0 || s.OpenUntil != nil {
+			return false
+		}
+		d = maxHold
+	}
+	cb.OpenFor(min(d, maxHold))
+	return true
+}
+
+// MarshalSharedState 编码为存储值（proto3 JSON）
+func MarshalSharedState(s *SharedState) ([]byte, error) {
+	return protojson.Marshal(s)
+}
+
+// UnmarshalSharedState 解析存储值，忽略其他语言新增的未知字段，状态无法识别时返回错误
+func UnmarshalSharedState(data []byte) (*SharedState, error) {
+	s := new(SharedState)
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("cbpb: shared state: %w", err)
+	}
+	if s.State == StateUnspecified {
+		if _, err := FromR4jState(s.R4JState); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
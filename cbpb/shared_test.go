@@ -0,0 +1,103 @@
+// Copyright 2025 zampo.
+
+package cbpb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestNewSharedState_Open(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	settings.Timeout = time.Minute
+	cb := circuitbreaker.NewCircuitBreaker("payments", settings)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+
+	s := NewSharedState(cb, "go:test")
+	if s.State != StateOpen || s.R4JState != R4jOpen {
+		t.Errorf("state = %v/%v, want STATE_OPEN/OPEN", s.State, s.R4JState)
+	}
+	if s.RemainingMs <= 0 || s.RemainingMs > time.Minute.Milliseconds() {
+		t.Errorf("RemainingMs = %v, want (0, 60000]", s.RemainingMs)
+	}
+
+	data, err := MarshalSharedState(s)
+	if err != nil {
+		t.Fatalf("MarshalSharedState() error = %v", err)
+	}
+	var compact bytes.Buffer
+	json.Compact(&compact, data)
+	for _, want := range []string{`"state":"STATE_OPEN"`, `"r4jState":"OPEN"`, `"remainingMs":"`} {
+		if !strings.Contains(compact.String(), want) {
+			t.Errorf("json = %s, want %s", data, want)
+		}
+	}
+	if got := SharedStateKey("payments"); got != "circuitbreaker:v1:state:payments" {
+		t.Errorf("SharedStateKey() = %q", got)
+	}
+}
+
+func TestNewSharedState_FailureRate(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("svc", circuitbreaker.DefaultSettings())
+	if s := NewSharedState(cb, ""); s.FailureRate != -1 || s.State != StateClosed {
+		t.Errorf("idle = %v/%v, want -1/STATE_CLOSED", s.FailureRate, s.State)
+	}
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	if s := NewSharedState(cb, ""); s.FailureRate != 0.5 {
+		t.Errorf("FailureRate = %v, want 0.5", s.FailureRate)
+	}
+}
+
+func TestUnmarshalSharedState_Resilience4j(t *testing.T) {
+	// Java 侧只写入 resilience4j 状态名
+	s, err := UnmarshalSharedState([]byte(`{"name":"payments","r4jState":"FORCED_OPEN","remainingMs":"30000","failureRate":0.62,"origin":"java:orders-0"}`))
+	if err != nil {
+		t.Fatalf("UnmarshalSharedState() error = %v", err)
+	}
+	if s.Effective() != StateOpen {
+		t.Errorf("Effective() = %v, want %v", s.Effective(), StateOpen)
+	}
+
+	cb := circuitbreaker.NewCircuitBreaker("payments", circuitbreaker.DefaultSettings())
+	if !s.Apply(cb, 10*time.Second) {
+		t.Fatal("Apply() = false, want true")
+	}
+	if cb.State() != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want open", cb.State())
+	}
+	if d := time.Until(cb.OpenUntil()); d > 10*time.Second {
+		t.Errorf("hold = %v, want capped at 10s", d)
+	}
+
+	if _, err := UnmarshalSharedState([]byte(`{"name":"payments","r4jState":"BOGUS"}`)); err == nil {
+		t.Error("UnmarshalSharedState(BOGUS) error = nil")
+	}
+}
+
+func TestSharedState_ApplyIgnoresExpiredAndClosed(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("svc", circuitbreaker.DefaultSettings())
+	past := time.Now().Add(-time.Second)
+	for _, s := range []*SharedState{
+		{State: StateOpen, OpenUntil: timestamppb.New(past)},
+		{R4JState: R4jDisabled},
+		{State: StateHalfOpen},
+	} {
+		if s.Apply(cb, time.Minute) {
+			t.Errorf("Apply(%+v) = true, want false", s)
+		}
+	}
+	if cb.State() != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want closed", cb.State())
+	}
+}
@@ -17,7 +17,9 @@
 package circuitbreaker
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sony/gobreaker"
@@ -25,22 +27,169 @@ import (
 
 // CircuitBreaker 熔断器包装
 type CircuitBreaker struct {
-	cb       *gobreaker.CircuitBreaker
+	cb       *gobreaker.TwoStepCircuitBreaker
 	name     string
 	settings Settings
 	mu       sync.RWMutex
+
+	listenerMu sync.RWMutex
+	listeners  map[uint64]func(name string, from, to gobreaker.State)
+	listenerID uint64
+
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+
+	leaked        atomic.Int64
+	lateSuccesses atomic.Uint64
+	lateFailures  atomic.Uint64
+	latePanics    atomic.Uint64
+
+	callTimeouts     atomic.Uint64
+	deadlineTimeouts atomic.Uint64
+
+	// openUntil OpenFor 保持打开的截止时间（UnixNano）
+	openUntil atomic.Int64
+	// openedAt 最近一次进入打开状态的时间（UnixNano）
+	openedAt atomic.Int64
+	// lease 人工强制打开租约，见 ForceOpen
+	lease atomic.Pointer[Lease]
+	// tier 当前配置的严重级别，供状态变更回调无锁读取
+	tier atomic.Value
+
+	probes         probeWindow
+	countingPaused atomic.Bool
+	faults         faults
+
+	latencies    latencyWindow
+	errorSamples errorSamples
+	// lastTrip 最近一次进入打开状态的原因，见 LastTripCause
+	lastTrip atomic.Pointer[TripCause]
+	// baseline 成功调用耗时的采样基线，见 Settings.LatencySampleRate
+	baseline latencyBaseline
+	// window 显式窗口模式下的计数窗口，见 Settings.WindowMode
+	window atomic.Pointer[countingWindow]
+
+	// clock 时间源（clockBox），见 Settings.Clock
+	clock atomic.Value
+	// timeoutUntil 设置 Clock 时按 Clock 计算的打开期截止时间（UnixNano）
+	timeoutUntil atomic.Int64
+	// pendingHalfOpen 打开期内被推迟的打开→半开通知，见 flushHalfOpen
+	pendingHalfOpen atomic.Bool
+	// states 供 State 无锁读取的状态缓存
+	states stateCache
+	// rejections 被拒绝、未实际执行的调用计数
+	rejections rejections
+	// probeSlots 从 Settings.ProbeBudget 占用的半开探测名额
+	probeSlots probeSlots
+	// spareProbes 半开状态下被忽略的探测归还的名额，见 spareProbes
+	spareProbes spareProbes
+	// forcingTrip 为 true 时任何失败都触发熔断，见 transitionTo
+	forcingTrip atomic.Bool
+
+	// slowCalls 超过 LatencyBudget 被计为失败的成功调用数
+	slowCalls atomic.Uint64
+	// oversizedResults 超过 MaxResultSize 被计为失败的成功调用数
+	oversizedResults atomic.Uint64
+
+	// strict 当前配置是否开启 Settings.Strict
+	strict atomic.Bool
+	// removed 已从注册表移除，严格模式下继续使用时 panic
+	removed atomic.Bool
 }
 
 // Settings 熔断器配置
 type Settings struct {
 	// MaxRequests 半开状态下允许的最大请求数
 	MaxRequests uint32
-	// Interval 关闭状态下的时间窗口（秒）
+	// Interval 关闭状态下的时间窗口（秒），含义由 WindowMode 决定
 	Interval time.Duration
+	// WindowMode 计数窗口模式，默认沿用 gobreaker 行为（Interval 为 0 时计数永不清零），
+	// 建议显式设置；容易误解的组合见 Warnings
+	WindowMode WindowMode
+	// WindowBuckets WindowRolling 的桶数，默认 DefaultWindowBuckets
+	WindowBuckets int
+	// WindowSize WindowCountBased 统计的最近调用数，默认 DefaultWindowSize
+	WindowSize int
 	// Timeout 打开状态下的超时时间（秒），之后尝试半开
 	Timeout time.Duration
 	// ReadyToTrip 自定义的熔断触发函数
 	ReadyToTrip func(counts gobreaker.Counts) bool
+	// TripPolicyName 返回 counts 下触发熔断的策略名称，记录在 LastTripCause 中，见 AnyOfNamed
+	TripPolicyName func(counts gobreaker.Counts) string
+	// OnStateChange 状态变更回调，在 gobreaker 内部锁中同步调用，不可阻塞；设置 Hooks 时改为在工作池中异步调用
+	OnStateChange func(name string, from, to gobreaker.State)
+	// Hooks 执行 OnStateChange 的回调工作池，为空时同步调用
+	Hooks *HookRunner
+	// ProbeBudget 与其他熔断器共享的半开探测名额，限制一组熔断器同时进行的探测总数，为空时不限制
+	ProbeBudget *ProbeBudget
+	// CallTimeout 单次调用超时时间，0 表示不限制，仅对 ExecuteContext 生效
+	CallTimeout time.Duration
+	// DeadlineOverhead 为外层预留的时间：派生调用 ctx 时截止时间取调用方截止时间减去该值，
+	// 使链式熔断（如 endpoint + host）中内层先于外层超时，外层仍有时间处理结果；
+	// 预算耗尽导致的超时视同调用方超时，见 CountCallerCancellation
+	DeadlineOverhead time.Duration
+	// DeferTimeoutOutcome 超时调用不立即计为失败，而是在被放弃的调用最终返回时按实际结果计数
+	DeferTimeoutOutcome bool
+	// PressureFunc 外部饱和度信号（0~1，如连接池占用率、队列深度比例），在每次放行与熔断判定时调用，须轻量且不可阻塞
+	PressureFunc func() float64
+	// MaxPressure 饱和度达到该值时直接触发熔断（关闭状态下放行前即检查，无需等待调用失败），0 表示不启用
+	MaxPressure float64
+	// HealthScorer 健康评分函数，为空时使用 DefaultHealthScorer
+	HealthScorer HealthScorer
+	// IsSuccessful 判断调用错误是否视为成功，默认 err == nil
+	IsSuccessful func(err error) bool
+	// IgnoreErrors 错误白名单，匹配的错误不计入熔断统计，在 IsSuccessful 之前应用
+	IgnoreErrors []ErrorMatcher
+	// FailErrors 错误黑名单，匹配的错误总是计为失败，优先于 IgnoreErrors 与 IsSuccessful
+	FailErrors []ErrorMatcher
+	// CountCallerCancellation 调用方自身 ctx 取消或超时导致的错误默认不计入熔断统计
+	// （被取消的半开探测归还名额，不计为成功），设置为 true 时按普通错误分类
+	CountCallerCancellation bool
+	// Description 依赖说明，随统计、事件与管理接口输出
+	Description string
+	// RunbookURL 熔断打开时的处置手册地址，便于值班人员快速定位处理步骤
+	RunbookURL string
+	// Tier 严重级别，控制告警路由并作为指标标签输出，默认 TierCritical
+	Tier Tier
+	// UnsafeFaultInjection 允许通过 InjectFailures/InjectLatency 注入故障，仅用于混沌测试，生产环境不应开启
+	UnsafeFaultInjection bool
+	// CloseFailureRate 半开探测窗口（MaxRequests 个请求）内失败率低于该值才关闭，否则重新打开；
+	// 与 ReadyToTrip 的开启阈值配合形成滞回（如 50% 失败率打开、低于 10% 才关闭）。
+	// 0 表示沿用 gobreaker 行为：半开期间任一失败即重新打开
+	CloseFailureRate float64
+	// MinimumRequests 当前窗口请求数达到该值前不评估 ReadyToTrip，
+	// 避免低流量时单次失败（1/1 = 100% 失败率）即打开熔断器；0 表示不限制
+	MinimumRequests uint32
+	// Labels 自定义标签（如 service=payments），用于 Registry.AggregateStats 按标签汇总
+	Labels map[string]string
+	// ErrorSamples 失败时保留的最近错误样本数，见 Stats.ErrorSamples 与 Annotate；0 表示不采集
+	ErrorSamples int
+	// LatencySampleRate 成功调用耗时的采样比例（0~1），采样值汇入 t-digest 形成延迟基线，
+	// 见 LatencyBaseline 与 LatencyQuantile；0 表示不采样
+	LatencySampleRate float64
+	// DeadlineQuantile 调用方剩余时间（扣除 DeadlineOverhead）低于基线的该分位数（如 0.99）时不发起调用，
+	// 直接返回 ErrDeadlineTooShort；需配合 LatencySampleRate，采样不足 MinBaselineSamples 时不生效
+	DeadlineQuantile float64
+	// LatencyBudget 软延迟预算：调用成功但耗时超过该值时仍按失败计数（调用方照常拿到结果），
+	// 应对返回慢但不报错的降级依赖；与 CallTimeout 不同，不会中断调用。0 表示不启用
+	LatencyBudget time.Duration
+	// MaxResultSize 结果大小上限（字节）：调用成功但结果超过该值时按失败计数，调用方照常拿到结果；0 表示不启用
+	MaxResultSize int64
+	// ResultSize 计算结果大小，返回负数表示未知；为空时使用 DefaultResultSize
+	ResultSize func(result interface{}) int64
+	// Interceptors 包装 Execute/ExecuteContext 的拦截器，第一个位于最外层；Allow/AllowContext 不经过拦截器
+	Interceptors []Interceptor
+	// Clock 时间源，为空时使用系统时间；设置后 Timeout 到期、Interval 窗口、OpenFor 保持期与
+	// 强制打开租约均按 Clock 判定，测试中推进 Clock 即可驱动打开→半开→关闭，无需等待
+	Clock Clock
+	// Strict 严格模式，用于开发与测试：误用（Execute 传入 nil 函数、负的时长配置、
+	// 使用已从注册表移除的熔断器）时以 *MisuseError panic，尽早暴露集成错误；
+	// 关闭时 nil 函数返回 *MisuseError，其余误用按原样容忍，见 MisuseError
+	Strict bool
+	// OnOutcome 每次调用结果计入统计后（或被拒绝时）调用，接收单次调用的耗时、错误、是否被拒绝、
+	// 是否为探测等信息，用于自定义指标与采样；在调用方协程（超时后补记时为后台协程）中同步调用，
+	// 不持有熔断器的锁，但应保持轻量。调用 panic 并向调用方重新抛出时不调用
+	OnOutcome func(info OutcomeInfo)
 }
 
 // DefaultSettings 返回默认配置
@@ -55,65 +204,326 @@ func DefaultSettings() Settings {
 
 // NewCircuitBreaker 创建新的熔断器
 func NewCircuitBreaker(name string, settings Settings) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		name:     name,
+		settings: settings,
+	}
+	cb.checkSettings("NewCircuitBreaker", settings)
+	cb.cb = gobreaker.NewTwoStepCircuitBreaker(cb.buildSettings(settings))
+	return cb
+}
+
+// buildSettings 将 Settings 转换为 gobreaker 配置
+func (cb *CircuitBreaker) buildSettings(settings Settings) gobreaker.Settings {
+	cb.tier.Store(settings.Tier.orDefault())
+	cb.strict.Store(settings.Strict)
+	cb.clock.Store(clockBox{settings.Clock})
+	onChange := settings.stateChangeHook()
+	window := cb.configureWindow(settings)
+	// 新建的底层熔断器总是从关闭状态开始
+	cb.states.store(gobreaker.StateClosed, 0)
+	cb.probeSlots.releaseAll()
 	cbSettings := gobreaker.Settings{
-		Name:        name,
+		Name:        cb.name,
 		MaxRequests: settings.MaxRequests,
-		Interval:    settings.Interval,
-		Timeout:     settings.Timeout,
+		Interval:    settings.backendInterval(),
+		Timeout:     settings.backendTimeout(),
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			cb.spareProbes.clear()
+			if window != nil {
+				window.reset()
+			}
+			cb.states.store(to, cb.now().Add(settings.openTimeout()).UnixNano())
+			if from == gobreaker.StateHalfOpen {
+				cb.probeSlots.releaseAll()
+			}
+			if cb.deferHalfOpen(from, to) {
+				return
+			}
+			if to == gobreaker.StateOpen {
+				now := cb.now()
+				cb.openedAt.Store(now.UnixNano())
+				if settings.Clock != nil {
+					cb.pendingHalfOpen.Store(false)
+					cb.timeoutUntil.Store(now.Add(settings.openTimeout()).UnixNano())
+				}
+				if from == gobreaker.StateHalfOpen {
+					cb.recordProbeTrip()
+				}
+			}
+			if to == gobreaker.StateClosed {
+				cb.errorSamples.clear()
+				cb.sealTrip()
+			}
+			if onChange != nil {
+				onChange(name, from, to)
+			}
+			cb.notifyListeners(name, from, to)
+		},
 	}
 
 	if settings.ReadyToTrip != nil {
 		cbSettings.ReadyToTrip = settings.ReadyToTrip
 	}
+	if settings.MinimumRequests > 0 {
+		cbSettings.ReadyToTrip = withMinimumRequests(cbSettings.ReadyToTrip, settings.MinimumRequests)
+	}
+	if settings.PressureFunc != nil && settings.MaxPressure > 0 {
+		cbSettings.ReadyToTrip = withPressure(cbSettings.ReadyToTrip, settings.PressureFunc, settings.MaxPressure)
+	}
+	if cbSettings.ReadyToTrip == nil {
+		cbSettings.ReadyToTrip = defaultReadyToTrip
+	}
+	cbSettings.ReadyToTrip = cb.withTripCause(cbSettings.ReadyToTrip, settings)
+	if window != nil {
+		readyToTrip := cbSettings.ReadyToTrip
+		cbSettings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+			return readyToTrip(window.counts(counts, cb.now()))
+		}
+	}
+	readyToTrip := cbSettings.ReadyToTrip
+	cbSettings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return cb.forcingTrip.Load() || readyToTrip(counts)
+	}
 
-	return &CircuitBreaker{
-		cb:       gobreaker.NewCircuitBreaker(cbSettings),
-		name:     name,
-		settings: settings,
+	return cbSettings
+}
+
+// stateChangeHook 返回实际调用的 OnStateChange，设置 Hooks 时经工作池异步执行
+func (s *Settings) stateChangeHook() func(name string, from, to gobreaker.State) {
+	if s.OnStateChange != nil && s.Hooks != nil {
+		return s.Hooks.StateChangeHook(s.OnStateChange)
+	}
+	return s.OnStateChange
+}
+
+// notifyListeners 分发状态变更到已注册的监听器
+// 调用时已持有 gobreaker 内部锁，因此不能获取 cb.mu
+func (cb *CircuitBreaker) notifyListeners(name string, from, to gobreaker.State) {
+	cb.listenerMu.RLock()
+	defer cb.listenerMu.RUnlock()
+	for _, fn := range cb.listeners {
+		fn(name, from, to)
+	}
+}
+
+// addListener 注册状态变更监听器，返回取消函数
+func (cb *CircuitBreaker) addListener(fn func(name string, from, to gobreaker.State)) func() {
+	cb.listenerMu.Lock()
+	defer cb.listenerMu.Unlock()
+
+	if cb.listeners == nil {
+		cb.listeners = make(map[uint64]func(name string, from, to gobreaker.State))
+	}
+	cb.listenerID++
+	id := cb.listenerID
+	cb.listeners[id] = fn
+
+	return func() {
+		cb.listenerMu.Lock()
+		defer cb.listenerMu.Unlock()
+		delete(cb.listeners, id)
 	}
 }
 
-// Execute 执行函数，带熔断保护
+// Name 返回熔断器名称
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// Execute 执行函数，带熔断保护；被拒绝时返回 *RejectionError，可用 ReasonOf 获取原因。
+// 配置了 Settings.Interceptors 时经拦截器执行，fn 不接收 ctx，拦截器对 ctx 的修改不会传入 fn
 func (cb *CircuitBreaker) Execute(fn func() (interface{}, error)) (interface{}, error) {
+	if err := cb.checkCall("Execute", fn == nil); err != nil {
+		return nil, err
+	}
+	if chain, ctx := cb.interceptorChain(context.Background()); chain != nil {
+		return chain(func(context.Context) (interface{}, error) { return cb.execute(fn) })(ctx)
+	}
+	return cb.execute(fn)
+}
+
+// execute 实现 Execute，不经过拦截器
+func (cb *CircuitBreaker) execute(fn func() (interface{}, error)) (interface{}, error) {
+	a, err := cb.admit(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start := cb.enter()
+	defer cb.exit(start)
+	defer func() {
+		if e := recover(); e != nil {
+			a.done(false)
+			panic(e)
+		}
+	}()
+
+	var result interface{}
+	if err = cb.faults.inject(context.Background()); err == nil {
+		result, err = fn()
+	}
+	outcome, sampled := cb.guardResult(&a.settings, a.settings.Classify(err), err, result, start)
+	reportOutcome(a.done, a.neutral, outcome)
+	cb.sample(&a.settings, outcome, sampled)
+	cb.observeSuccess(&a.settings, outcome, start)
+	cb.emitOutcome(&a, outcome, sampled, start)
+	return result, err
+}
+
+// Allow 两阶段调用：先检查是否放行，调用结束后通过 done 上报结果
+// 适用于无法用闭包包装调用的场景（如 HTTP/gRPC 适配器），done 仅第一次调用生效
+func (cb *CircuitBreaker) Allow() (done func(success bool), err error) {
+	if err := cb.checkCall("Allow", false); err != nil {
+		return nil, err
+	}
+	a, err := cb.admit(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start := cb.enter()
+	var once atomic.Bool
+	return func(success bool) {
+		if once.CompareAndSwap(false, true) {
+			cb.exit(start)
+			var sampled error
+			if success {
+				var outcome Outcome
+				outcome, sampled = cb.guardResult(&a.settings, OutcomeSuccess, nil, nil, start)
+				success = outcome == OutcomeSuccess
+			}
+			a.done(success)
+			if success {
+				cb.observeSuccess(&a.settings, OutcomeSuccess, start)
+				cb.emitOutcome(&a, OutcomeSuccess, nil, start)
+			} else {
+				cb.emitOutcome(&a, OutcomeFailure, sampled, start)
+			}
+		}
+	}, nil
+}
+
+// AllowContext 与 Allow 相同，但 done 接收调用错误并按 Settings 分类，
+// 调用方 ctx 已取消或超时导致的错误默认不计入统计；剩余时间不足 DeadlineOverhead 时返回 ErrDeadlineTooShort
+func (cb *CircuitBreaker) AllowContext(ctx context.Context) (done func(err error), err error) {
+	if err := cb.checkCall("AllowContext", false); err != nil {
+		return nil, err
+	}
+	a, err := cb.admit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := cb.enter()
+	var once atomic.Bool
+	return func(err error) {
+		if once.CompareAndSwap(false, true) {
+			cb.exit(start)
+			outcome, sampled := cb.guardResult(&a.settings, a.settings.classifyContext(ctx, err), err, nil, start)
+			reportOutcome(a.done, a.neutral, outcome)
+			cb.sample(&a.settings, outcome, sampled)
+			cb.observeSuccess(&a.settings, outcome, start)
+			cb.emitOutcome(&a, outcome, sampled, start)
+		}
+	}, nil
+}
+
+// admission 一次放行的快照：放行时的配置及上报函数
+type admission struct {
+	settings Settings
+	done     func(success bool)
+	// neutral 半开探测的结果被忽略时归还名额，非探测时为 nil，见 allow
+	neutral func()
+	// probe 放行时处于半开状态
+	probe bool
+}
+
+// admit 在读锁内完成放行检查并对实例与配置取快照，ctx 为 nil 时不检查剩余时间
+// 调用在锁外执行并按快照上报：耗时调用不会阻塞 UpdateSettings，
+// 结果总是按放行时的配置分类并上报给放行它的实例，不会与新配置混用
+func (cb *CircuitBreaker) admit(ctx context.Context) (admission, error) {
+	a, err := cb.tryAdmit(ctx)
+	if err != nil {
+		cb.rejections.record(ReasonOf(err))
+		cb.emitRejection(err)
+	}
+	return a, err
+}
+
+// tryAdmit 执行 admit 的放行检查
+func (cb *CircuitBreaker) tryAdmit(ctx context.Context) (admission, error) {
+	cb.flushHalfOpen()
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
-	return cb.cb.Execute(fn)
+
+	if ctx != nil {
+		if err := cb.checkBudget(ctx); err != nil {
+			return admission{}, err
+		}
+	}
+	if err := cb.checkHeld(); err != nil {
+		return admission{}, err
+	}
+	cb.tripOnPressure()
+	done, neutral, err := cb.allow()
+	if err != nil {
+		return admission{}, cb.rejectOpen(err)
+	}
+	return admission{settings: cb.settings, done: done, neutral: neutral, probe: neutral != nil}, nil
+}
+
+// enter 记录一次进入执行的调用并更新并发峰值，返回开始时间
+func (cb *CircuitBreaker) enter() time.Time {
+	n := cb.inFlight.Add(1)
+	for {
+		peak := cb.maxInFlight.Load()
+		if n <= peak || cb.maxInFlight.CompareAndSwap(peak, n) {
+			return time.Now()
+		}
+	}
 }
 
-// State 获取当前熔断器状态
+// exit 记录一次调用结束及其耗时
+func (cb *CircuitBreaker) exit(start time.Time) {
+	cb.inFlight.Add(-1)
+	cb.latencies.observe(time.Since(start))
+}
+
+// State 获取当前熔断器状态，通常只读取状态缓存，不获取任何锁
 func (cb *CircuitBreaker) State() gobreaker.State {
+	if state, ok := cb.cachedState(); ok {
+		return state
+	}
+	cb.flushHalfOpen()
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
-	return cb.cb.State()
+	return cb.state()
 }
 
 // Counts 获取统计信息
 func (cb *CircuitBreaker) Counts() gobreaker.Counts {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
-	return cb.cb.Counts()
+	return cb.windowed(cb.cb.Counts())
 }
 
 // UpdateSettings 更新熔断器配置（热更新）
-// 注意：这会重新创建内部的熔断器实例，会丢失当前状态
+// 注意：这会重新创建内部的熔断器实例，打开与半开状态会丢失；
+// 关闭状态下当前窗口的计数会移植到新实例，更新时尚未完成的请求不计入
 func (cb *CircuitBreaker) UpdateSettings(settings Settings) {
+	cb.checkSettings("UpdateSettings", settings)
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	cbSettings := gobreaker.Settings{
-		Name:        cb.name,
-		MaxRequests: settings.MaxRequests,
-		Interval:    settings.Interval,
-		Timeout:     settings.Timeout,
-	}
-
-	if settings.ReadyToTrip != nil {
-		cbSettings.ReadyToTrip = settings.ReadyToTrip
-	}
-
-	// 创建新的熔断器实例
-	cb.cb = gobreaker.NewCircuitBreaker(cbSettings)
+	// 创建新的熔断器实例；关闭状态下移植当前窗口计数，避免失败率策略在重载后从零开始
 	cb.settings = settings
+	cb.clearTimeoutHold()
+	if cb.cb.State() == gobreaker.StateClosed {
+		cb.cb = cb.newBackendWithCounts(settings, cb.cb.Counts())
+		return
+	}
+	cb.cb = gobreaker.NewTwoStepCircuitBreaker(cb.buildSettings(settings))
 }
 
 // GetSettings 获取当前配置
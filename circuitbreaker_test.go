@@ -3,6 +3,7 @@
 package circuitbreaker
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"sync/atomic"
@@ -302,3 +303,131 @@ func TestCircuitBreaker_Recovery(t *testing.T) {
 		t.Errorf("Expected at least 1 success after recovery, got %v", counts.TotalSuccesses)
 	}
 }
+
+func TestUpdateSettings_DoesNotWaitForInFlight(t *testing.T) {
+	cb := NewCircuitBreaker("inflight", DefaultSettings())
+
+	var v1, v2 atomic.Bool
+	settings := DefaultSettings()
+	settings.IsSuccessful = func(error) bool { v1.Store(true); return true }
+	cb.UpdateSettings(settings)
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	go cb.Execute(func() (interface{}, error) {
+		close(entered)
+		<-release
+		return nil, errors.New("classified by v1")
+	})
+	<-entered
+
+	updated := make(chan struct{})
+	go func() {
+		settings := DefaultSettings()
+		settings.IsSuccessful = func(error) bool { v2.Store(true); return true }
+		cb.UpdateSettings(settings)
+		close(updated)
+	}()
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		t.Fatal("UpdateSettings blocked by an in-flight Execute")
+	}
+
+	// 新调用立即使用新配置，且不被进行中的调用阻塞
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	if !v2.Load() || v1.Load() {
+		t.Errorf("after update: v1 used = %v, v2 used = %v, want only v2", v1.Load(), v2.Load())
+	}
+
+	// 进行中的调用按放行时的配置分类
+	v2.Store(false)
+	close(release)
+	waitFor(t, v1.Load)
+	if v2.Load() {
+		t.Error("in-flight call classified with settings installed after it was admitted")
+	}
+}
+
+// genError 记录对其分类的配置代数
+type genError struct{ gen atomic.Int64 }
+
+func (e *genError) Error() string { return "gen" }
+
+func TestUpdateSettings_ConcurrentExecute(t *testing.T) {
+	const (
+		workers     = 8
+		generations = 200
+	)
+
+	settingsFor := func(gen int64) Settings {
+		s := DefaultSettings()
+		s.MaxRequests = uint32(gen%5) + 1
+		s.CallTimeout = time.Duration(gen%2) * time.Second
+		s.IsSuccessful = func(err error) bool {
+			var ge *genError
+			if errors.As(err, &ge) {
+				ge.gen.Store(gen)
+			}
+			return true
+		}
+		return s
+	}
+
+	cb := NewCircuitBreaker("stress", settingsFor(1))
+	var published atomic.Int64
+	published.Store(1)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				before := published.Load()
+				ge := &genError{}
+				var err error
+				switch i % 3 {
+				case 0:
+					_, err = cb.Execute(func() (interface{}, error) { return nil, ge })
+				case 1:
+					_, err = cb.ExecuteContext(context.Background(), func(context.Context) (interface{}, error) { return nil, ge })
+				default:
+					var done func(error)
+					if done, err = cb.AllowContext(context.Background()); err == nil {
+						done(ge)
+						err = ge
+					}
+				}
+				if !errors.Is(err, ge) {
+					t.Errorf("worker %d: err = %v, want the call's own error", w, err)
+					return
+				}
+				if got := ge.gen.Load(); got < before {
+					t.Errorf("worker %d: call started after generation %d was classified by stale generation %d", w, before, got)
+					return
+				}
+			}
+		}(w)
+	}
+
+	for gen := int64(2); gen <= generations; gen++ {
+		cb.UpdateSettings(settingsFor(gen))
+		published.Store(gen)
+	}
+	close(stop)
+	wg.Wait()
+
+	if got := cb.GetSettings().MaxRequests; got != settingsFor(generations).MaxRequests {
+		t.Errorf("MaxRequests = %v, want %v", got, settingsFor(generations).MaxRequests)
+	}
+	if cb.State() != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want closed", cb.State())
+	}
+}
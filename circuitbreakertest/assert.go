@@ -0,0 +1,108 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreakertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// pollInterval AssertEventually 的轮询间隔
+const pollInterval = time.Millisecond
+
+// AssertState 断言熔断器当前处于 want 状态，失败时报告熔断器快照，返回是否通过
+func AssertState(t testing.TB, cb *circuitbreaker.CircuitBreaker, want gobreaker.State) bool {
+	t.Helper()
+	if got := cb.State(); got != want {
+		t.Errorf("breaker %q: state = %v, want %v\n%s", cb.Name(), got, want, describe(cb.Stats()))
+		return false
+	}
+	return true
+}
+
+// AssertEventually 断言熔断器在 within（真实时间）内进入 want 状态，适用于异步触发的状态变更；
+// 超时时报告最后观察到的状态与熔断器快照，返回是否通过
+func AssertEventually(t testing.TB, cb *circuitbreaker.CircuitBreaker, want gobreaker.State, within time.Duration) bool {
+	t.Helper()
+	deadline := time.Now().Add(within)
+	for {
+		got := cb.State()
+		if got == want {
+			return true
+		}
+		if !time.Now().Before(deadline) {
+			t.Errorf("breaker %q: state = %v after %v, want %v\n%s", cb.Name(), got, within, want, describe(cb.Stats()))
+			return false
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// AssertCounts 断言熔断器当前窗口的计数等于 want，失败时逐项列出不一致的字段，返回是否通过
+func AssertCounts(t testing.TB, cb *circuitbreaker.CircuitBreaker, want gobreaker.Counts) bool {
+	t.Helper()
+	got := cb.Counts()
+	if got == want {
+		return true
+	}
+	var diff []string
+	for _, f := range []struct {
+		name      string
+		got, want uint32
+	}{
+		{"Requests", got.Requests, want.Requests},
+		{"TotalSuccesses", got.TotalSuccesses, want.TotalSuccesses},
+		{"TotalFailures", got.TotalFailures, want.TotalFailures},
+		{"ConsecutiveSuccesses", got.ConsecutiveSuccesses, want.ConsecutiveSuccesses},
+		{"ConsecutiveFailures", got.ConsecutiveFailures, want.ConsecutiveFailures},
+	} {
+		if f.got != f.want {
+			diff = append(diff, fmt.Sprintf("  %s: got %d, want %d", f.name, f.got, f.want))
+		}
+	}
+	t.Errorf("breaker %q: counts mismatch\n%s\n%s", cb.Name(), strings.Join(diff, "\n"), describe(cb.Stats()))
+	return false
+}
+
+// describe 将快照格式化为多行文本，附在断言失败信息中帮助定位
+func describe(s circuitbreaker.Stats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "snapshot of %q:\n", s.Name)
+	fmt.Fprintf(&b, "  state: %v\n", s.State)
+	fmt.Fprintf(&b, "  counts: requests=%d successes=%d failures=%d consecutive_successes=%d consecutive_failures=%d\n",
+		s.Counts.Requests, s.Counts.TotalSuccesses, s.Counts.TotalFailures, s.Counts.ConsecutiveSuccesses, s.Counts.ConsecutiveFailures)
+	fmt.Fprintf(&b, "  in_flight: %d", s.InFlight)
+	if c := s.LastTripCause; c != nil {
+		fmt.Fprintf(&b, "\n  last_trip: policy=%s from=%v at=%s failure_rate=%.2f", c.Policy, c.From, c.At.Format(time.RFC3339Nano), c.FailureRate)
+		if c.Error != nil {
+			fmt.Fprintf(&b, " error=%q", c.Error.Error)
+		}
+	}
+	if s.Lease != nil {
+		fmt.Fprintf(&b, "\n  lease: owner=%s expires=%s", s.Lease.Owner, s.Lease.Expires.Format(time.RFC3339Nano))
+	}
+	for _, e := range s.ErrorSamples {
+		fmt.Fprintf(&b, "\n  error_sample: %s %q", e.At.Format(time.RFC3339Nano), e.Error)
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 zampo.
+
+package circuitbreakertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// recorder 记录断言失败信息而不让外层测试失败
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func trippedBreaker() *circuitbreaker.CircuitBreaker {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	settings.ErrorSamples = 1
+	cb := circuitbreaker.NewCircuitBreaker("payments", settings)
+	cb.Execute(fail)
+	return cb
+}
+
+func TestAssertState(t *testing.T) {
+	cb := trippedBreaker()
+	if !AssertState(t, cb, gobreaker.StateOpen) {
+		t.Fatal("AssertState(open) failed on an open breaker")
+	}
+
+	r := &recorder{TB: t}
+	if AssertState(r, cb, gobreaker.StateClosed) {
+		t.Fatal("AssertState(closed) passed on an open breaker")
+	}
+	msg := strings.Join(r.errors, "\n")
+	for _, want := range []string{`breaker "payments": state = open, want closed`, "policy=ready_to_trip", `error="boom"`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("failure message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestAssertEventually(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	cb := circuitbreaker.NewCircuitBreaker("async", settings)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cb.Execute(fail)
+	}()
+	if !AssertEventually(t, cb, gobreaker.StateOpen, time.Second) {
+		t.Fatal("AssertEventually(open) failed")
+	}
+
+	r := &recorder{TB: t}
+	start := time.Now()
+	if AssertEventually(r, cb, gobreaker.StateClosed, 20*time.Millisecond) {
+		t.Fatal("AssertEventually(closed) passed on an open breaker")
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("AssertEventually returned after %v, want to wait the full 20ms", elapsed)
+	}
+	if msg := strings.Join(r.errors, "\n"); !strings.Contains(msg, "state = open after 20ms, want closed") {
+		t.Errorf("failure message = %q", msg)
+	}
+}
+
+func TestAssertCounts(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("counts", circuitbreaker.DefaultSettings())
+	cb.Execute(succeed)
+	cb.Execute(fail)
+
+	if !AssertCounts(t, cb, gobreaker.Counts{Requests: 2, TotalSuccesses: 1, TotalFailures: 1, ConsecutiveFailures: 1}) {
+		t.Fatal("AssertCounts failed on matching counts")
+	}
+
+	r := &recorder{TB: t}
+	if AssertCounts(r, cb, gobreaker.Counts{Requests: 2, TotalSuccesses: 2, ConsecutiveSuccesses: 2}) {
+		t.Fatal("AssertCounts passed on different counts")
+	}
+	msg := strings.Join(r.errors, "\n")
+	for _, want := range []string{"TotalSuccesses: got 1, want 2", "TotalFailures: got 1, want 0", "ConsecutiveFailures: got 1, want 0"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("failure message missing %q:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "Requests: got") {
+		t.Errorf("failure message lists matching field Requests:\n%s", msg)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package circuitbreakertest 提供测试熔断处理逻辑的辅助工具：可手动推进的时间源等，
+// 使下游服务无需 sleep 即可测试打开→半开→关闭的处理
+package circuitbreakertest
+
+import (
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Clock 可由测试推进的时间源，实现 circuitbreaker.Clock，并发安全；
+// 赋值给 Settings.Clock 后，Timeout 到期、Interval 窗口、OpenFor 与强制打开租约均按其判定
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+var _ circuitbreaker.Clock = (*Clock)(nil)
+
+// NewClock 创建时间源，start 为零值时从 2025-01-01 00:00:00 UTC 开始，便于断言固定时间
+func NewClock(start time.Time) *Clock {
+	if start.IsZero() {
+		start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return &Clock{now: start}
+}
+
+// Now 返回当前时间
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance 将时间推进 d 并返回推进后的时间，d 为负时不回退
+func (c *Clock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+	return c.now
+}
+
+// Set 将时间设置为 t，早于当前时间时不回退
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 zampo.
+
+package circuitbreakertest
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// transitions 记录状态变更
+type transitions struct {
+	mu   sync.Mutex
+	seen []string
+}
+
+func (tr *transitions) record(_ string, from, to gobreaker.State) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.seen = append(tr.seen, from.String()+"->"+to.String())
+}
+
+func (tr *transitions) get() []string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return append([]string(nil), tr.seen...)
+}
+
+func newBreaker(clock *Clock, tr *transitions) *circuitbreaker.CircuitBreaker {
+	settings := circuitbreaker.DefaultSettings()
+	settings.Clock = clock
+	settings.Timeout = 30 * time.Second
+	settings.MaxRequests = 1
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	settings.OnStateChange = tr.record
+	return circuitbreaker.NewCircuitBreaker("clock", settings)
+}
+
+func fail() (interface{}, error) { return nil, errors.New("boom") }
+
+func succeed() (interface{}, error) { return "ok", nil }
+
+func TestClock_DrivesRecovery(t *testing.T) {
+	clock := NewClock(time.Time{})
+	tr := &transitions{}
+	cb := newBreaker(clock, tr)
+
+	cb.Execute(fail)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want open", got)
+	}
+	if got := cb.NextProbeAt(); !got.Equal(clock.Now().Add(30 * time.Second)) {
+		t.Errorf("NextProbeAt() = %v, want Timeout after the trip", got)
+	}
+
+	// 真实时间流逝不影响打开期
+	time.Sleep(2 * time.Millisecond)
+	clock.Advance(29 * time.Second)
+	if _, err := cb.Execute(succeed); circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Fatalf("Execute() before Timeout error = %v, want open rejection", err)
+	}
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Fatalf("State() before Timeout = %v, want open", got)
+	}
+
+	clock.Advance(time.Second)
+	if got := cb.State(); got != gobreaker.StateHalfOpen {
+		t.Fatalf("State() after Timeout = %v, want half-open", got)
+	}
+	if _, err := cb.Execute(succeed); err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Fatalf("State() after probe = %v, want closed", got)
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if got := tr.get(); !slices.Equal(got, want) {
+		t.Errorf("transitions = %v, want %v", got, want)
+	}
+}
+
+func TestClock_FailedProbeReopens(t *testing.T) {
+	clock := NewClock(time.Time{})
+	tr := &transitions{}
+	cb := newBreaker(clock, tr)
+
+	cb.Execute(fail)
+	clock.Advance(30 * time.Second)
+	cb.Execute(fail)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Fatalf("State() after failed probe = %v, want open", got)
+	}
+	clock.Advance(10 * time.Second)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Fatalf("State() within second Timeout = %v, want open", got)
+	}
+	clock.Advance(20 * time.Second)
+	if got := cb.State(); got != gobreaker.StateHalfOpen {
+		t.Fatalf("State() after second Timeout = %v, want half-open", got)
+	}
+}
+
+func TestClock_IntervalResetsCounts(t *testing.T) {
+	clock := NewClock(time.Time{})
+	settings := circuitbreaker.DefaultSettings()
+	settings.Clock = clock
+	settings.Interval = time.Minute
+	cb := circuitbreaker.NewCircuitBreaker("interval", settings)
+
+	cb.Execute(fail)
+	cb.Execute(succeed)
+	if got := cb.Counts(); got.Requests != 2 || got.TotalFailures != 1 {
+		t.Fatalf("Counts() = %+v, want 2 requests, 1 failure", got)
+	}
+	clock.Advance(time.Minute)
+	if got := cb.Counts(); got.Requests != 0 {
+		t.Errorf("Counts() after Interval = %+v, want cleared", got)
+	}
+}
+
+func TestClock_OpenForAndLease(t *testing.T) {
+	clock := NewClock(time.Time{})
+	settings := circuitbreaker.DefaultSettings()
+	settings.Clock = clock
+	cb := circuitbreaker.NewCircuitBreaker("hold", settings)
+
+	cb.OpenFor(time.Minute)
+	if _, err := cb.ForceOpen("oncall", 2*time.Minute); err != nil {
+		t.Fatalf("ForceOpen() error = %v", err)
+	}
+	clock.Advance(time.Minute)
+	if got := cb.OpenUntil(); !got.IsZero() {
+		t.Errorf("OpenUntil() after hold = %v, want zero", got)
+	}
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() with active lease = %v, want open", got)
+	}
+	clock.Advance(time.Minute)
+	if _, ok := cb.Lease(); ok {
+		t.Error("Lease() after ttl = active, want expired")
+	}
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() after lease = %v, want closed", got)
+	}
+}
+
+func TestClock_NeverGoesBackwards(t *testing.T) {
+	clock := NewClock(time.Time{})
+	start := clock.Now()
+	clock.Advance(-time.Second)
+	clock.Set(start.Add(-time.Hour))
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want %v", got, start)
+	}
+	clock.Set(start.Add(time.Hour))
+	if got := clock.Now(); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("Now() after Set = %v, want %v", got, start.Add(time.Hour))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreakertest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+	"github.com/go-anyway/framework-circuitbreaker/internal/testhook"
+)
+
+var (
+	// ErrInvalidState TransitionTo 的目标状态不是关闭、半开或打开
+	ErrInvalidState = errors.New("circuitbreakertest: invalid state")
+	// ErrNoClock 熔断器未设置 Settings.Clock，无法直接进入半开，应使用 NewBreaker 创建
+	ErrNoClock = errors.New("circuitbreakertest: half-open transition requires Settings.Clock")
+)
+
+// systemClock 真实时间源，使 NewBreaker 创建的熔断器按 Clock 判定打开期
+type systemClock struct{}
+
+// Now 返回 time.Now
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewBreaker 创建支持 TransitionTo 进入半开的熔断器：未设置 Settings.Clock 时使用真实时间源，
+// 其余行为与 circuitbreaker.NewCircuitBreaker 相同，供集成测试环境使用
+func NewBreaker(name string, settings circuitbreaker.Settings) *circuitbreaker.CircuitBreaker {
+	if settings.Clock == nil {
+		settings.Clock = systemClock{}
+	}
+	return circuitbreaker.NewCircuitBreaker(name, settings)
+}
+
+// TransitionTo 不产生真实失败，直接将熔断器切换到 state，用于在集成测试中构造打开/半开场景。
+// 熔断器先恢复为关闭（同 Reset），打开时按正常熔断记录打开时间并开始 Timeout 计时；
+// 进入半开要求熔断器设置了 Settings.Clock（见 NewBreaker）。状态变更回调依次收到每一步变更
+func TransitionTo(cb *circuitbreaker.CircuitBreaker, state gobreaker.State) error {
+	switch state {
+	case gobreaker.StateClosed, gobreaker.StateOpen:
+	case gobreaker.StateHalfOpen:
+		if cb.GetSettings().Clock == nil {
+			return ErrNoClock
+		}
+	default:
+		return fmt.Errorf("%w: %v", ErrInvalidState, state)
+	}
+	return testhook.TransitionTo(cb, state)
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 zampo.
+
+package circuitbreakertest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestTransitionTo(t *testing.T) {
+	tr := &transitions{}
+	settings := circuitbreaker.DefaultSettings()
+	settings.Timeout = time.Hour
+	settings.MaxRequests = 1
+	settings.OnStateChange = tr.record
+	cb := NewBreaker("integration", settings)
+
+	if err := TransitionTo(cb, gobreaker.StateOpen); err != nil {
+		t.Fatalf("TransitionTo(open) error = %v", err)
+	}
+	AssertState(t, cb, gobreaker.StateOpen)
+	if _, err := cb.Execute(succeed); circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Errorf("Execute() while open error = %v, want open rejection", err)
+	}
+	if cb.Stats().Counts.Requests != 0 {
+		t.Errorf("Counts() = %+v, want no recorded calls", cb.Stats().Counts)
+	}
+
+	if err := TransitionTo(cb, gobreaker.StateHalfOpen); err != nil {
+		t.Fatalf("TransitionTo(half-open) error = %v", err)
+	}
+	AssertState(t, cb, gobreaker.StateHalfOpen)
+	if _, err := cb.Execute(succeed); err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	AssertState(t, cb, gobreaker.StateClosed)
+
+	if err := TransitionTo(cb, gobreaker.StateOpen); err != nil {
+		t.Fatalf("TransitionTo(open) error = %v", err)
+	}
+	if err := TransitionTo(cb, gobreaker.StateClosed); err != nil {
+		t.Fatalf("TransitionTo(closed) error = %v", err)
+	}
+	AssertState(t, cb, gobreaker.StateClosed)
+
+	want := []string{
+		"closed->open",
+		"open->closed", "closed->open", "open->half-open",
+		"half-open->closed",
+		"closed->open",
+		"open->closed",
+	}
+	if got := tr.get(); !slices.Equal(got, want) {
+		t.Errorf("transitions = %v, want %v", got, want)
+	}
+}
+
+func TestTransitionTo_ManualClock(t *testing.T) {
+	clock := NewClock(time.Time{})
+	cb := newBreaker(clock, &transitions{})
+
+	if err := TransitionTo(cb, gobreaker.StateOpen); err != nil {
+		t.Fatalf("TransitionTo(open) error = %v", err)
+	}
+	clock.Advance(30 * time.Second)
+	AssertState(t, cb, gobreaker.StateHalfOpen)
+
+	if err := TransitionTo(cb, gobreaker.StateHalfOpen); err != nil {
+		t.Fatalf("TransitionTo(half-open) error = %v", err)
+	}
+	cb.Execute(fail)
+	AssertState(t, cb, gobreaker.StateOpen)
+}
+
+func TestTransitionTo_Errors(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("plain", circuitbreaker.DefaultSettings())
+	if err := TransitionTo(cb, gobreaker.StateHalfOpen); !errors.Is(err, ErrNoClock) {
+		t.Errorf("TransitionTo(half-open) without Clock error = %v, want ErrNoClock", err)
+	}
+	if err := TransitionTo(cb, gobreaker.State(42)); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("TransitionTo(42) error = %v, want ErrInvalidState", err)
+	}
+	if err := TransitionTo(cb, gobreaker.StateOpen); err != nil {
+		t.Errorf("TransitionTo(open) without Clock error = %v", err)
+	}
+	AssertState(t, cb, gobreaker.StateOpen)
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// Clock 时间源，见 Settings.Clock；测试中可使用 circuitbreakertest.Clock
+type Clock interface {
+	Now() time.Time
+}
+
+// clockBox 包装 Clock 以便存入 atomic.Value（不同实现类型不能直接存入同一个 atomic.Value）
+type clockBox struct {
+	clock Clock
+}
+
+// Now 返回熔断器时间源的当前时间，未设置 Clock 时为 time.Now；
+// 集成包按熔断器时间计算截止时间（如 OpenFor 的时长）时使用
+func (cb *CircuitBreaker) Now() time.Time {
+	return cb.now()
+}
+
+// now 返回熔断器时间源的当前时间，未设置 Clock 时为 time.Now
+func (cb *CircuitBreaker) now() time.Time {
+	if box, _ := cb.clock.Load().(clockBox); box.clock != nil {
+		return box.clock.Now()
+	}
+	return time.Now()
+}
+
+// openTimeout 返回生效的打开超时
+func (s Settings) openTimeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return defaultOpenTimeout
+}
+
+// backendTimeout 返回交给 gobreaker 的 Timeout：设置 Clock 时 gobreaker 立即允许进入半开，
+// 打开期改由 timeoutUntil 按 Clock 判定
+func (s Settings) backendTimeout() time.Duration {
+	if s.Clock != nil {
+		return time.Nanosecond
+	}
+	return s.Timeout
+}
+
+// timeoutHeld 是否处于按 Clock 计算的打开期内
+func (cb *CircuitBreaker) timeoutHeld() bool {
+	until := cb.timeoutUntil.Load()
+	return until != 0 && cb.now().UnixNano() < until
+}
+
+// deferHalfOpen 按 Clock 仍处于打开期时推迟 gobreaker 提前发出的打开→半开通知，返回是否已推迟；
+// 在 gobreaker 内部锁中调用，只使用原子操作
+func (cb *CircuitBreaker) deferHalfOpen(from, to gobreaker.State) bool {
+	if from != gobreaker.StateOpen || to != gobreaker.StateHalfOpen || !cb.timeoutHeld() {
+		return false
+	}
+	cb.pendingHalfOpen.Store(true)
+	return true
+}
+
+// flushHalfOpen 打开期按 Clock 结束后补发推迟的打开→半开通知；调用方不可持有 cb.mu
+func (cb *CircuitBreaker) flushHalfOpen() {
+	if !cb.pendingHalfOpen.Load() || cb.timeoutHeld() || !cb.pendingHalfOpen.CompareAndSwap(true, false) {
+		return
+	}
+	cb.mu.RLock()
+	onChange := cb.settings.stateChangeHook()
+	cb.mu.RUnlock()
+	if onChange != nil {
+		onChange(cb.name, gobreaker.StateOpen, gobreaker.StateHalfOpen)
+	}
+	cb.notifyListeners(cb.name, gobreaker.StateOpen, gobreaker.StateHalfOpen)
+}
+
+// clearTimeoutHold 清除按 Clock 计算的打开期与推迟的通知，在重建底层熔断器时调用
+func (cb *CircuitBreaker) clearTimeoutHold() {
+	cb.timeoutUntil.Store(0)
+	cb.pendingHalfOpen.Store(false)
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package cloudwatchbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// emfMaxMetrics 单条 EMF 日志允许的最大指标数
+const emfMaxMetrics = 100
+
+// EMFPublisher 以嵌入式指标格式（EMF）逐行写出 JSON 日志，
+// 由 CloudWatch Agent、Lambda 或 ECS/EKS 的日志驱动解析为指标，无需调用 API
+type EMFPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewEMFPublisher 创建 EMF 发布端，通常写入 os.Stdout
+func NewEMFPublisher(w io.Writer) *EMFPublisher {
+	return &EMFPublisher{w: w}
+}
+
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit,omitempty"`
+}
+
+type emfDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+// PutMetricData 将维度与时间相同的相邻数据点合并为一条日志，每条最多 100 个指标
+func (p *EMFPublisher) PutMetricData(ctx context.Context, namespace string, data []Datum) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	enc := json.NewEncoder(p.w)
+	for len(data) > 0 {
+		n := 1
+		for n < len(data) && n < emfMaxMetrics && sameGroup(data[0], data[n]) {
+			n++
+		}
+		if err := enc.Encode(emfDocument(namespace, data[:n])); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// sameGroup 两个数据点能否写入同一条 EMF 日志
+func sameGroup(a, b Datum) bool {
+	return a.Timestamp.Equal(b.Timestamp) && maps.Equal(a.Dimensions, b.Dimensions)
+}
+
+// emfDocument 构造一条 EMF 日志：_aws 元数据声明指标，维度与指标值作为顶层字段
+func emfDocument(namespace string, group []Datum) map[string]interface{} {
+	first := group[0]
+	keys := slices.Sorted(maps.Keys(first.Dimensions))
+	directive := emfDirective{
+		Namespace:  namespace,
+		Dimensions: [][]string{keys},
+		Metrics:    make([]emfMetric, 0, len(group)),
+	}
+
+	doc := make(map[string]interface{}, len(keys)+len(group)+1)
+	for k, v := range first.Dimensions {
+		doc[k] = v
+	}
+	for _, d := range group {
+		directive.Metrics = append(directive.Metrics, emfMetric{Name: d.MetricName, Unit: d.Unit})
+		doc[d.MetricName] = d.Value
+	}
+	ts := first.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	doc["_aws"] = emfMetadata{
+		Timestamp:         ts.UnixMilli(),
+		CloudWatchMetrics: []emfDirective{directive},
+	}
+	return doc
+}
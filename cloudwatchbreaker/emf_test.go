@@ -0,0 +1,73 @@
+// Copyright 2025 zampo.
+
+package cloudwatchbreaker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEMFPublisher(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	payments := map[string]string{"Breaker": "payments"}
+	orders := map[string]string{"Breaker": "orders"}
+	data := []Datum{
+		{MetricName: "State", Dimensions: payments, Value: 2, Unit: UnitNone, Timestamp: now},
+		{MetricName: "Requests", Dimensions: payments, Value: 7, Unit: UnitCount, Timestamp: now},
+		{MetricName: "State", Dimensions: orders, Value: 0, Unit: UnitNone, Timestamp: now},
+	}
+
+	var buf bytes.Buffer
+	if err := NewEMFPublisher(&buf).PutMetricData(context.Background(), "Checkout", data); err != nil {
+		t.Fatalf("PutMetricData() error = %v", err)
+	}
+
+	var docs []map[string]interface{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("documents = %d, want 2", len(docs))
+	}
+
+	first := docs[0]
+	if first["Breaker"] != "payments" || first["State"] != 2.0 || first["Requests"] != 7.0 {
+		t.Errorf("first document = %v", first)
+	}
+	meta := first["_aws"].(map[string]interface{})
+	if meta["Timestamp"] != float64(now.UnixMilli()) {
+		t.Errorf("Timestamp = %v, want %v", meta["Timestamp"], now.UnixMilli())
+	}
+	directive := meta["CloudWatchMetrics"].([]interface{})[0].(map[string]interface{})
+	if directive["Namespace"] != "Checkout" || len(directive["Metrics"].([]interface{})) != 2 {
+		t.Errorf("directive = %v", directive)
+	}
+	if dims := directive["Dimensions"].([]interface{})[0].([]interface{}); len(dims) != 1 || dims[0] != "Breaker" {
+		t.Errorf("Dimensions = %v, want [[Breaker]]", dims)
+	}
+}
+
+func TestEMFPublisher_SplitsLargeGroups(t *testing.T) {
+	dims := map[string]string{"Breaker": "payments"}
+	data := make([]Datum, emfMaxMetrics+1)
+	for i := range data {
+		data[i] = Datum{MetricName: "m" + string(rune('a'+i%26)) + string(rune('0'+i/26)), Dimensions: dims, Value: 1}
+	}
+
+	var buf bytes.Buffer
+	if err := NewEMFPublisher(&buf).PutMetricData(context.Background(), "ns", data); err != nil {
+		t.Fatalf("PutMetricData() error = %v", err)
+	}
+	if lines := bytes.Count(buf.Bytes(), []byte("\n")); lines != 2 {
+		t.Errorf("lines = %d, want 2", lines)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package cloudwatchbreaker 将熔断器状态与统计周期性上报到 Amazon CloudWatch，
+// 支持嵌入式指标格式（EMF）日志与批量 PutMetricData 两种方式，供无 Prometheus 的 AWS 部署使用
+package cloudwatchbreaker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// MaxBatchSize 单次 PutMetricData 允许的最大数据点数
+const MaxBatchSize = 1000
+
+// 上报的指标单位，取值与 CloudWatch StandardUnit 一致
+const (
+	UnitNone         = "None"
+	UnitCount        = "Count"
+	UnitMilliseconds = "Milliseconds"
+	UnitPercent      = "Percent"
+)
+
+// Datum 单个指标数据点
+type Datum struct {
+	MetricName string
+	Dimensions map[string]string
+	Value      float64
+	Unit       string
+	Timestamp  time.Time
+}
+
+// Publisher 指标发布端；使用 AWS SDK 时将 Datum 转换为 types.MetricDatum 后调用 PutMetricData
+type Publisher interface {
+	PutMetricData(ctx context.Context, namespace string, data []Datum) error
+}
+
+// PublisherFunc 函数形式的 Publisher
+type PublisherFunc func(ctx context.Context, namespace string, data []Datum) error
+
+// PutMetricData 调用 f
+func (f PublisherFunc) PutMetricData(ctx context.Context, namespace string, data []Datum) error {
+	return f(ctx, namespace, data)
+}
+
+// Option 上报器配置项
+type Option func(*Reporter)
+
+// WithNamespace 设置指标命名空间，默认 "CircuitBreaker"
+func WithNamespace(namespace string) Option {
+	return func(r *Reporter) { r.namespace = namespace }
+}
+
+// WithInterval 设置上报间隔，默认 60 秒（CloudWatch 标准分辨率）
+func WithInterval(d time.Duration) Option {
+	return func(r *Reporter) { r.interval = d }
+}
+
+// WithBatchSize 设置单次发布的最大数据点数，默认且最大为 MaxBatchSize
+func WithBatchSize(n int) Option {
+	return func(r *Reporter) { r.batchSize = n }
+}
+
+// WithDimensions 设置每个熔断器的维度，默认仅 Breaker=<名称>；
+// 维度组合越多 CloudWatch 计费的自定义指标越多，不建议加入高基数标签
+func WithDimensions(fn func(s circuitbreaker.Stats) map[string]string) Option {
+	return func(r *Reporter) { r.dimensions = fn }
+}
+
+// WithErrorHandler 设置 Run 中发布失败时的回调，默认忽略
+func WithErrorHandler(fn func(error)) Option {
+	return func(r *Reporter) { r.onError = fn }
+}
+
+// Reporter 周期性采集注册表内各熔断器的统计并发布到 CloudWatch
+type Reporter struct {
+	registry   *circuitbreaker.Registry
+	publisher  Publisher
+	namespace  string
+	interval   time.Duration
+	batchSize  int
+	dimensions func(s circuitbreaker.Stats) map[string]string
+	onError    func(error)
+}
+
+// New 创建上报器
+func New(r *circuitbreaker.Registry, publisher Publisher, opts ...Option) *Reporter {
+	rep := &Reporter{
+		registry:   r,
+		publisher:  publisher,
+		namespace:  "CircuitBreaker",
+		interval:   time.Minute,
+		batchSize:  MaxBatchSize,
+		dimensions: BreakerDimension,
+	}
+	for _, opt := range opts {
+		opt(rep)
+	}
+	if rep.interval <= 0 {
+		rep.interval = time.Minute
+	}
+	if rep.batchSize <= 0 || rep.batchSize > MaxBatchSize {
+		rep.batchSize = MaxBatchSize
+	}
+	return rep
+}
+
+// BreakerDimension 默认维度：Breaker=<名称>
+func BreakerDimension(s circuitbreaker.Stats) map[string]string {
+	return map[string]string{"Breaker": s.Name}
+}
+
+// Run 按 Interval 周期上报，直至 ctx 结束
+func (r *Reporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Report(ctx); err != nil && r.onError != nil {
+				r.onError(err)
+			}
+		}
+	}
+}
+
+// Report 执行一轮上报，按 BatchSize 分批发布；各批次独立发布，返回所有失败批次的错误
+func (r *Reporter) Report(ctx context.Context) error {
+	data := r.Collect(time.Now())
+	var errs []error
+	for len(data) > 0 {
+		n := min(len(data), r.batchSize)
+		if err := r.publisher.PutMetricData(ctx, r.namespace, data[:n]); err != nil {
+			errs = append(errs, err)
+		}
+		data = data[n:]
+	}
+	return errors.Join(errs...)
+}
+
+// Collect 采集当前所有熔断器的数据点（按熔断器名称排序）
+func (r *Reporter) Collect(now time.Time) []Datum {
+	stats := r.registry.Stats()
+	data := make([]Datum, 0, len(stats)*len(metrics))
+	for _, s := range stats {
+		dims := r.dimensions(s)
+		for _, m := range metrics {
+			data = append(data, Datum{
+				MetricName: m.name,
+				Dimensions: dims,
+				Value:      m.value(s),
+				Unit:       m.unit,
+				Timestamp:  now,
+			})
+		}
+	}
+	return data
+}
+
+// metric 单个熔断器上报的指标定义
+type metric struct {
+	name  string
+	unit  string
+	value func(s circuitbreaker.Stats) float64
+}
+
+// metrics 每个熔断器上报的指标；State 取值 0=closed、1=half-open、2=open，便于按最大值告警；
+// Rejections 为自创建起的累计值，按 DIFF 查看每周期的拒绝数
+var metrics = []metric{
+	{"State", UnitNone, func(s circuitbreaker.Stats) float64 { return stateValue(s.State) }},
+	{"Requests", UnitCount, func(s circuitbreaker.Stats) float64 { return float64(s.Counts.Requests) }},
+	{"Failures", UnitCount, func(s circuitbreaker.Stats) float64 { return float64(s.Counts.TotalFailures) }},
+	{"Rejections", UnitCount, func(s circuitbreaker.Stats) float64 { return float64(s.Rejections) }},
+	{"ConsecutiveFailures", UnitCount, func(s circuitbreaker.Stats) float64 { return float64(s.Counts.ConsecutiveFailures) }},
+	{"InFlight", UnitCount, func(s circuitbreaker.Stats) float64 { return float64(s.InFlight) }},
+	{"LatencyP99", UnitMilliseconds, func(s circuitbreaker.Stats) float64 {
+		return float64(s.LatencyP99) / float64(time.Millisecond)
+	}},
+	{"HealthScore", UnitPercent, func(s circuitbreaker.Stats) float64 { return s.HealthScore }},
+}
+
+// stateValue 将状态映射为便于告警的数值
+func stateValue(state gobreaker.State) float64 {
+	switch state {
+	case gobreaker.StateHalfOpen:
+		return 1
+	case gobreaker.StateOpen:
+		return 2
+	default:
+		return 0
+	}
+}
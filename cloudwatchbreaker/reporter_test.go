@@ -0,0 +1,108 @@
+// Copyright 2025 zampo.
+
+package cloudwatchbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestReporter_Report(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 }
+	r.GetOrCreate("orders", settings)
+	r.GetOrCreate("payments", settings).Execute(func() (interface{}, error) {
+		return nil, errors.New("fail")
+	})
+
+	var batches [][]Datum
+	pub := PublisherFunc(func(ctx context.Context, namespace string, data []Datum) error {
+		if namespace != "Checkout" {
+			t.Errorf("namespace = %q, want %q", namespace, "Checkout")
+		}
+		batches = append(batches, append([]Datum(nil), data...))
+		return nil
+	})
+	rep := New(r, pub, WithNamespace("Checkout"), WithBatchSize(5))
+	if err := rep.Report(context.Background()); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	total := 2 * len(metrics)
+	if want := (total + 4) / 5; len(batches) != want {
+		t.Fatalf("batches = %d, want %d", len(batches), want)
+	}
+	var all []Datum
+	for _, b := range batches {
+		if len(b) > 5 {
+			t.Errorf("batch size = %d, want <= 5", len(b))
+		}
+		all = append(all, b...)
+	}
+	if len(all) != total {
+		t.Fatalf("data points = %d, want %d", len(all), total)
+	}
+	for _, d := range all {
+		if d.MetricName == "State" && d.Dimensions["Breaker"] == "payments" && d.Value != 2 {
+			t.Errorf("payments State = %v, want 2 (open)", d.Value)
+		}
+		if d.MetricName == "State" && d.Dimensions["Breaker"] == "orders" && d.Value != 0 {
+			t.Errorf("orders State = %v, want 0 (closed)", d.Value)
+		}
+	}
+}
+
+func TestReporter_ReportJoinsBatchErrors(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	r.GetOrCreate("orders", circuitbreaker.DefaultSettings())
+	r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+
+	errThrottled := errors.New("throttled")
+	calls := 0
+	pub := PublisherFunc(func(ctx context.Context, namespace string, data []Datum) error {
+		calls++
+		if calls == 1 {
+			return errThrottled
+		}
+		return nil
+	})
+	err := New(r, pub, WithBatchSize(len(metrics))).Report(context.Background())
+	if !errors.Is(err, errThrottled) || calls != 2 {
+		t.Errorf("Report() = %v after %d calls, want throttled error and both batches attempted", err, calls)
+	}
+}
+
+func TestReporter_Run(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	r.GetOrCreate("orders", circuitbreaker.DefaultSettings())
+
+	published := make(chan struct{}, 1)
+	pub := PublisherFunc(func(ctx context.Context, namespace string, data []Datum) error {
+		select {
+		case published <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		New(r, pub, WithInterval(10*time.Millisecond)).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("no report within 1s")
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// cbconfig 熔断器配置文件工具
+//
+//	cbconfig validate FILE...   校验配置文件，有错误时以状态码 1 退出
+//	cbconfig migrate FILE       将配置文件迁移到当前格式版本并输出
+//	cbconfig schema             输出配置文件的 JSON Schema
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+const usage = `usage:
+  cbconfig validate FILE...
+  cbconfig migrate FILE
+  cbconfig schema
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 执行子命令并返回退出状态码
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "validate":
+		if len(args) < 2 {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		return validate(args[1:], stdout, stderr)
+	case "migrate":
+		if len(args) != 2 {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		return migrate(args[1], stdout, stderr)
+	case "schema":
+		stdout.Write(circuitbreaker.ConfigSchema)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n%s", args[0], usage)
+		return 2
+	}
+}
+
+// validate 校验每个文件并输出结果
+func validate(files []string, stdout, stderr io.Writer) int {
+	code := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			_, err = circuitbreaker.ParseConfig(data)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s:\n%s\n", file, err)
+			code = 1
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: ok\n", file)
+	}
+	return code
+}
+
+// migrate 输出迁移到当前版本的配置
+func migrate(file string, stdout, stderr io.Writer) int {
+	data, err := os.ReadFile(file)
+	if err == nil {
+		data, err = circuitbreaker.MigrateConfig(data)
+	}
+	if err == nil {
+		_, err = circuitbreaker.ParseConfig(data)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s:\n%s\n", file, err)
+		return 1
+	}
+	stdout.Write(data)
+	return 0
+}
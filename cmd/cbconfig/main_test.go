@@ -0,0 +1,59 @@
+// Copyright 2025 zampo.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_Validate(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(good, []byte(`{"version": 2, "breakers": {"payments": {"timeout": "30s"}}}`), 0o644)
+	os.WriteFile(bad, []byte(`{"version": 2, "breakers": {"payments": {"tier": "urgent"}}}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", good}, &stdout, &stderr); code != 0 {
+		t.Errorf("validate good = %v, stderr = %s", code, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := run([]string{"validate", good, bad}, &stdout, &stderr); code != 1 {
+		t.Errorf("validate bad = %v, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "breakers.payments.tier") {
+		t.Errorf("stderr = %q, want field path", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "good.json: ok") {
+		t.Errorf("stdout = %q, want good.json: ok", stdout.String())
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Errorf("run() = %v, want 2", code)
+	}
+	if code := run([]string{"schema"}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "$schema") {
+		t.Errorf("schema = %v, %q", code, stdout.String())
+	}
+}
+
+func TestRun_Migrate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "v1.json")
+	os.WriteFile(file, []byte(`{"version": 1, "breakers": {"payments": {"trip": {"failure_rate": 0.5}}}}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"migrate", file}, &stdout, &stderr); code != 0 {
+		t.Fatalf("migrate = %v, stderr = %s", code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, `"version": 2`) || !strings.Contains(out, `"type": "failure_rate"`) {
+		t.Errorf("stdout = %s, want v2 policies", out)
+	}
+}
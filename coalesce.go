@@ -0,0 +1,176 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CoalesceSettings 熔断期间的请求合并配置
+type CoalesceSettings struct {
+	// Window 同一键的降级结果被复用的时长，从首个调用方开始计算，默认 1 秒
+	Window time.Duration
+	// Fallback 被拒绝时的降级函数，err 为 *RejectionError；为空时直接返回拒绝错误。
+	// 合并执行时 ctx 不随首个调用方取消，超时由 FallbackTimeout 控制
+	Fallback func(ctx context.Context, key string, err error) (interface{}, error)
+	// FallbackTimeout 合并执行的降级函数的超时时间，默认 5 秒
+	FallbackTimeout time.Duration
+	// MaxKeys 同时缓存的键数上限，达到上限且无过期键可清理时不再合并，默认 1024
+	MaxKeys int
+}
+
+// CoalesceStats 请求合并统计
+type CoalesceStats struct {
+	// Fallbacks 实际执行降级（或返回拒绝）的次数
+	Fallbacks uint64
+	// Coalesced 复用了同一键已有结果的调用次数
+	Coalesced uint64
+}
+
+// coalesced 一个键的降级结果，done 关闭后 value 与 err 可读
+type coalesced struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	expires time.Time
+	// openedAt 产生结果时熔断器的打开时间，再次打开后不复用上一次打开期的结果
+	openedAt int64
+}
+
+// Coalescer 熔断打开期间按键合并被拒绝的调用：窗口内同一键的调用方共享同一次降级的结果，
+// 不再各自执行降级函数，避免大量请求同时落到降级路径（如回源缓存或备用服务）造成负载尖峰。
+// 熔断器放行的调用照常执行，不受影响
+type Coalescer struct {
+	breaker  *CircuitBreaker
+	settings CoalesceSettings
+
+	mu      sync.Mutex
+	entries map[string]*coalesced
+
+	fallbacks atomic.Uint64
+	coalesced atomic.Uint64
+}
+
+// NewCoalescer 创建请求合并器
+func NewCoalescer(cb *CircuitBreaker, settings CoalesceSettings) *Coalescer {
+	if settings.Window <= 0 {
+		settings.Window = time.Second
+	}
+	if settings.MaxKeys <= 0 {
+		settings.MaxKeys = 1024
+	}
+	if settings.FallbackTimeout <= 0 {
+		settings.FallbackTimeout = 5 * time.Second
+	}
+	return &Coalescer{breaker: cb, settings: settings, entries: make(map[string]*coalesced)}
+}
+
+// Execute 经熔断器执行 fn；被拒绝时返回 key 在窗口内已有的降级结果，没有时执行降级并缓存结果。
+// 等待其他调用方的降级结果时 ctx 结束则返回 ctx 的错误。
+// 因调用方剩余时间不足被拒绝时只与该调用方有关，直接以其 ctx 执行降级，不参与合并
+func (c *Coalescer) Execute(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	result, err := c.breaker.ExecuteContext(ctx, fn)
+	switch ReasonOf(err) {
+	case ReasonNone:
+		return result, err
+	case ReasonDeadlineTooShort:
+		c.fallbacks.Add(1)
+		if c.settings.Fallback == nil {
+			return nil, err
+		}
+		return c.settings.Fallback(ctx, key, err)
+	}
+
+	e, owner := c.acquire(key)
+	if !owner {
+		c.coalesced.Add(1)
+		select {
+		case <-e.done:
+			return e.value, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c.fallbacks.Add(1)
+	e.err = err
+	if c.settings.Fallback != nil {
+		// 结果会被其他调用方复用，不能因首个调用方取消而缓存 ctx 的错误
+		fctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.settings.FallbackTimeout)
+		defer cancel()
+		func() {
+			defer func() {
+				if p := recover(); p != nil {
+					e.value, e.err = nil, fmt.Errorf("circuitbreaker: fallback panic: %v", p)
+					close(e.done)
+					panic(p)
+				}
+			}()
+			e.value, e.err = c.settings.Fallback(fctx, key, err)
+		}()
+	}
+	close(e.done)
+	return e.value, e.err
+}
+
+// acquire 返回 key 可复用的结果，owner 为 true 时调用方负责填充结果并关闭 done；
+// 达到 MaxKeys 时返回不缓存的新结果
+func (c *Coalescer) acquire(key string) (e *coalesced, owner bool) {
+	now := c.breaker.now()
+	openedAt := c.breaker.openedAt.Load()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok && e.openedAt == openedAt && now.Before(e.expires) {
+		return e, false
+	}
+
+	e = &coalesced{done: make(chan struct{}), expires: now.Add(c.settings.Window), openedAt: openedAt}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.settings.MaxKeys {
+		c.sweep(now)
+		if len(c.entries) >= c.settings.MaxKeys {
+			return e, true
+		}
+	}
+	c.entries[key] = e
+	return e, true
+}
+
+// sweep 清理已过期且已完成的结果，调用时需持有 c.mu
+func (c *Coalescer) sweep(now time.Time) {
+	for key, e := range c.entries {
+		select {
+		case <-e.done:
+			if !now.Before(e.expires) {
+				delete(c.entries, key)
+			}
+		default:
+		}
+	}
+}
+
+// Stats 返回合并统计
+func (c *Coalescer) Stats() CoalesceStats {
+	return CoalesceStats{
+		Fallbacks: c.fallbacks.Load(),
+		Coalesced: c.coalesced.Load(),
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// openBreaker 返回已打开的熔断器
+func openBreaker(t *testing.T) *CircuitBreaker {
+	t.Helper()
+	settings := DefaultSettings()
+	settings.Timeout = time.Hour
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 }
+	cb := NewCircuitBreaker("test", settings)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	if cb.State() != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want open", cb.State())
+	}
+	return cb
+}
+
+func unreachable(ctx context.Context) (interface{}, error) {
+	return "unreachable", nil
+}
+
+func TestCoalescer_SharesFallbackWhileOpen(t *testing.T) {
+	cb := openBreaker(t)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := NewCoalescer(cb, CoalesceSettings{
+		Window: time.Minute,
+		Fallback: func(ctx context.Context, key string, err error) (interface{}, error) {
+			if ReasonOf(err) != ReasonOpen {
+				t.Errorf("fallback err = %v, want rejection", err)
+			}
+			calls.Add(1)
+			<-release
+			return "cached:" + key, nil
+		},
+	})
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]interface{}, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.Execute(context.Background(), "user:1", unreachable)
+		}(i)
+	}
+	for c.Stats().Fallbacks+c.Stats().Coalesced < callers {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("fallback calls = %d, want 1", got)
+	}
+	for i, r := range results {
+		if r != "cached:user:1" {
+			t.Errorf("results[%d] = %v, want cached:user:1", i, r)
+		}
+	}
+	if got := c.Stats(); got.Fallbacks != 1 || got.Coalesced != callers-1 {
+		t.Errorf("Stats() = %+v, want 1 fallback, %d coalesced", got, callers-1)
+	}
+
+	c.Execute(context.Background(), "user:2", unreachable)
+	if got := calls.Load(); got != 2 {
+		t.Errorf("fallback calls after another key = %d, want 2", got)
+	}
+}
+
+func TestCoalescer_WindowExpires(t *testing.T) {
+	cb := openBreaker(t)
+	var calls atomic.Int32
+	c := NewCoalescer(cb, CoalesceSettings{
+		Window: 20 * time.Millisecond,
+		Fallback: func(ctx context.Context, key string, err error) (interface{}, error) {
+			return calls.Add(1), nil
+		},
+	})
+
+	first, _ := c.Execute(context.Background(), "k", unreachable)
+	second, _ := c.Execute(context.Background(), "k", unreachable)
+	if first != second {
+		t.Errorf("results within window = %v, %v, want the same", first, second)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if third, _ := c.Execute(context.Background(), "k", unreachable); third == first {
+		t.Errorf("result after window = %v, want a fresh fallback", third)
+	}
+}
+
+func TestCoalescer_NoFallbackReturnsRejection(t *testing.T) {
+	cb := openBreaker(t)
+	c := NewCoalescer(cb, CoalesceSettings{})
+
+	_, err1 := c.Execute(context.Background(), "k", unreachable)
+	_, err2 := c.Execute(context.Background(), "k", unreachable)
+	if ReasonOf(err1) != ReasonOpen || err1 != err2 {
+		t.Errorf("errors = %v, %v, want the same rejection", err1, err2)
+	}
+	if got := c.Stats(); got.Coalesced != 1 {
+		t.Errorf("Coalesced = %d, want 1", got.Coalesced)
+	}
+}
+
+func TestCoalescer_AdmittedCallsRun(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+	c := NewCoalescer(cb, CoalesceSettings{
+		Fallback: func(ctx context.Context, key string, err error) (interface{}, error) {
+			t.Error("fallback called while closed")
+			return nil, nil
+		},
+	})
+
+	var calls int
+	for i := 0; i < 3; i++ {
+		c.Execute(context.Background(), "k", func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, nil
+		})
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestCoalescer_WaiterContextCanceled(t *testing.T) {
+	cb := openBreaker(t)
+	release := make(chan struct{})
+	defer close(release)
+	c := NewCoalescer(cb, CoalesceSettings{
+		Window: time.Minute,
+		Fallback: func(ctx context.Context, key string, err error) (interface{}, error) {
+			<-release
+			return nil, nil
+		},
+	})
+	go c.Execute(context.Background(), "k", unreachable)
+	for c.Stats().Fallbacks == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Execute(ctx, "k", unreachable); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Execute() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCoalescer_OwnerCancelDoesNotPoisonWaiters(t *testing.T) {
+	cb := openBreaker(t)
+	release := make(chan struct{})
+	c := NewCoalescer(cb, CoalesceSettings{
+		Window: time.Minute,
+		Fallback: func(ctx context.Context, key string, err error) (interface{}, error) {
+			<-release
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return "cached", nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	owner := make(chan error, 1)
+	go func() {
+		_, err := c.Execute(ctx, "k", unreachable)
+		owner <- err
+	}()
+	for c.Stats().Fallbacks == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	waiter := make(chan interface{}, 1)
+	go func() {
+		v, _ := c.Execute(context.Background(), "k", unreachable)
+		waiter <- v
+	}()
+	for c.Stats().Coalesced == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	if err := <-owner; err != nil {
+		t.Errorf("owner Execute() error = %v, want nil", err)
+	}
+	if v := <-waiter; v != "cached" {
+		t.Errorf("waiter Execute() = %v, want cached", v)
+	}
+}
+
+func TestCoalescer_DeadlineTooShortNotCoalesced(t *testing.T) {
+	settings := DefaultSettings()
+	settings.DeadlineOverhead = 50 * time.Millisecond
+	cb := NewCircuitBreaker("test", settings)
+	var calls atomic.Int32
+	c := NewCoalescer(cb, CoalesceSettings{
+		Window: time.Minute,
+		Fallback: func(ctx context.Context, key string, err error) (interface{}, error) {
+			if ReasonOf(err) != ReasonDeadlineTooShort {
+				t.Errorf("fallback err = %v, want %v", err, ErrDeadlineTooShort)
+			}
+			calls.Add(1)
+			return nil, err
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := c.Execute(ctx, "k", unreachable)
+		cancel()
+		if !errors.Is(err, ErrDeadlineTooShort) {
+			t.Errorf("Execute() error = %v, want %v", err, ErrDeadlineTooShort)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("fallback calls = %d, want 2", got)
+	}
+	if got := c.Stats(); got.Fallbacks != 2 || got.Coalesced != 0 {
+		t.Errorf("Stats() = %+v, want 2 fallbacks, none coalesced", got)
+	}
+}
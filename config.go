@@ -0,0 +1,392 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// ConfigVersion 当前配置文件格式版本，旧版本在解析时自动迁移，见 MigrateConfig
+const ConfigVersion = 2
+
+// ConfigSchema 配置文件的 JSON Schema（draft 2020-12），供 IDE 与 IaC 工具校验
+//
+//go:embed config.schema.json
+var ConfigSchema []byte
+
+// Duration 配置文件中的时长，JSON 编码为 Go 时长字符串（如 "30s"、"1m30s"）
+type Duration time.Duration
+
+// MarshalJSON 编码为时长字符串
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON 解析时长字符串
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %s", data)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// 熔断策略类型
+const (
+	// PolicyConsecutiveFailures 连续失败次数达到 Threshold 时熔断
+	PolicyConsecutiveFailures = "consecutive_failures"
+	// PolicyFailureRate 窗口失败率达到 Threshold（0~1）时熔断，通常配合 minimum_requests
+	PolicyFailureRate = "failure_rate"
+)
+
+// PolicyConfig 熔断策略配置
+type PolicyConfig struct {
+	// Type 策略类型，内置策略或 RegisterTripPolicy 注册的名称
+	Type string `json:"type"`
+	// Threshold 触发阈值，含义由 Type 决定，自定义策略可不使用
+	Threshold float64 `json:"threshold,omitempty"`
+	// Params 自定义策略的参数，原样交给 TripPolicyFactory
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// BreakerConfig 单个熔断器的配置，字段含义见 Settings
+type BreakerConfig struct {
+	// Preset 内置预设名称（见 Presets），先于同一层的其余字段应用，其余非零字段覆盖预设
+	Preset                  string            `json:"preset,omitempty"`
+	MaxRequests             uint32            `json:"max_requests,omitempty"`
+	Interval                Duration          `json:"interval,omitempty"`
+	WindowMode              WindowMode        `json:"window_mode,omitempty"`
+	WindowBuckets           int               `json:"window_buckets,omitempty"`
+	WindowSize              int               `json:"window_size,omitempty"`
+	Timeout                 Duration          `json:"timeout,omitempty"`
+	CallTimeout             Duration          `json:"call_timeout,omitempty"`
+	DeadlineOverhead        Duration          `json:"deadline_overhead,omitempty"`
+	DeferTimeoutOutcome     bool              `json:"defer_timeout_outcome,omitempty"`
+	MaxPressure             float64           `json:"max_pressure,omitempty"`
+	CountCallerCancellation bool              `json:"count_caller_cancellation,omitempty"`
+	Description             string            `json:"description,omitempty"`
+	RunbookURL              string            `json:"runbook_url,omitempty"`
+	Tier                    Tier              `json:"tier,omitempty"`
+	CloseFailureRate        float64           `json:"close_failure_rate,omitempty"`
+	MinimumRequests         uint32            `json:"minimum_requests,omitempty"`
+	Labels                  map[string]string `json:"labels,omitempty"`
+	ErrorSamples            int               `json:"error_samples,omitempty"`
+	LatencySampleRate       float64           `json:"latency_sample_rate,omitempty"`
+	DeadlineQuantile        float64           `json:"deadline_quantile,omitempty"`
+	LatencyBudget           Duration          `json:"latency_budget,omitempty"`
+	MaxResultSize           int64             `json:"max_result_size,omitempty"`
+	// Policies 熔断策略，满足任一即熔断；为空时沿用默认策略
+	Policies []PolicyConfig `json:"policies,omitempty"`
+	// Trip 声明式的内置熔断预设，与 Policies 二选一
+	Trip *TripConfig `json:"trip,omitempty"`
+}
+
+// Config 熔断器配置文件
+type Config struct {
+	// Version 格式版本，见 ConfigVersion
+	Version int `json:"version"`
+	// Defaults 所有熔断器的默认配置，各熔断器的非零字段覆盖默认值
+	Defaults BreakerConfig `json:"defaults"`
+	// Breakers 按名称配置的熔断器
+	Breakers map[string]BreakerConfig `json:"breakers"`
+}
+
+// ConfigError 配置校验错误
+type ConfigError struct {
+	// Path 出错字段的路径，如 breakers.payments.timeout
+	Path string
+	Msg  string
+}
+
+func (e *ConfigError) Error() string {
+	if e.Path == "" {
+		return e.Msg
+	}
+	return e.Path + ": " + e.Msg
+}
+
+// ParseConfig 解析并校验配置文件，旧版本先迁移到 ConfigVersion，未知字段视为错误
+func ParseConfig(data []byte) (*Config, error) {
+	data, err := MigrateConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var c Config
+	if err := dec.Decode(&c); err != nil {
+		return nil, &ConfigError{Msg: err.Error()}
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Validate 校验配置，返回所有问题（errors.Join 的 *ConfigError）
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Version != ConfigVersion {
+		errs = append(errs, &ConfigError{Path: "version", Msg: fmt.Sprintf("unsupported version %d, want %d", c.Version, ConfigVersion)})
+	}
+	errs = append(errs, c.Defaults.validate("defaults")...)
+
+	names := make([]string, 0, len(c.Breakers))
+	for name := range c.Breakers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := "breakers." + name
+		if name == "" {
+			errs = append(errs, &ConfigError{Path: "breakers", Msg: "breaker name must not be empty"})
+		}
+		errs = append(errs, c.Breakers[name].validate(path)...)
+	}
+	return errors.Join(errs...)
+}
+
+// validate 校验单个熔断器配置
+func (b BreakerConfig) validate(path string) []error {
+	var errs []error
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, &ConfigError{Path: path + "." + field, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	for field, d := range map[string]Duration{
+		"interval":          b.Interval,
+		"timeout":           b.Timeout,
+		"call_timeout":      b.CallTimeout,
+		"deadline_overhead": b.DeadlineOverhead,
+		"latency_budget":    b.LatencyBudget,
+	} {
+		if d < 0 {
+			fail(field, "must not be negative")
+		}
+	}
+	for field, v := range map[string]float64{
+		"max_pressure":        b.MaxPressure,
+		"close_failure_rate":  b.CloseFailureRate,
+		"latency_sample_rate": b.LatencySampleRate,
+		"deadline_quantile":   b.DeadlineQuantile,
+	} {
+		if v < 0 || v > 1 {
+			fail(field, "must be between 0 and 1, got %v", v)
+		}
+	}
+	for field, n := range map[string]int{
+		"error_samples":  b.ErrorSamples,
+		"window_buckets": b.WindowBuckets,
+		"window_size":    b.WindowSize,
+	} {
+		if n < 0 {
+			fail(field, "must not be negative")
+		}
+	}
+	if b.MaxResultSize < 0 {
+		fail("max_result_size", "must not be negative")
+	}
+	if _, ok := presets[b.Preset]; b.Preset != "" && !ok {
+		fail("preset", "unknown preset %q%s", b.Preset, presetHint(b.Preset))
+	}
+	if b.WindowMode != "" && !b.WindowMode.valid() {
+		fail("window_mode", "unknown window mode %q", b.WindowMode)
+	}
+	switch b.Tier {
+	case "", TierCritical, TierDegradedOK, TierBestEffort:
+	default:
+		fail("tier", "unknown tier %q", b.Tier)
+	}
+	if b.RunbookURL != "" {
+		if u, err := url.Parse(b.RunbookURL); err != nil || !u.IsAbs() {
+			fail("runbook_url", "must be an absolute URL")
+		}
+	}
+	for i, p := range b.Policies {
+		field := fmt.Sprintf("policies[%d]", i)
+		switch p.Type {
+		case PolicyConsecutiveFailures:
+			if p.Threshold < 1 || p.Threshold != float64(uint32(p.Threshold)) {
+				fail(field+".threshold", "must be a positive integer, got %v", p.Threshold)
+			}
+		case PolicyFailureRate:
+			if p.Threshold <= 0 || p.Threshold > 1 {
+				fail(field+".threshold", "must be in (0, 1], got %v", p.Threshold)
+			}
+		default:
+			if !tripPolicyRegistered(p.Type) {
+				fail(field+".type", "unknown policy %q", p.Type)
+			} else if _, err := buildTripPolicy(p); err != nil {
+				fail(field, "%v", err)
+			}
+		}
+	}
+	if b.Trip != nil {
+		if len(b.Policies) > 0 {
+			fail("trip", "cannot be combined with policies")
+		}
+		errs = append(errs, b.Trip.validate(path+".trip")...)
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// Warnings 返回各熔断器生效配置中容易误解的组合（见 Settings.Warnings），按熔断器名称排序，
+// 不影响配置生效
+func (c *Config) Warnings() []string {
+	names := make([]string, 0, len(c.Breakers))
+	for name := range c.Breakers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var warnings []string
+	for _, name := range names {
+		for _, w := range c.Settings(name).Warnings() {
+			warnings = append(warnings, "breakers."+name+": "+w)
+		}
+	}
+	return warnings
+}
+
+// Settings 返回指定熔断器的配置：DefaultSettings 之上依次应用 Defaults 与该熔断器的配置，
+// 未配置的熔断器仅应用 Defaults
+func (c *Config) Settings(name string) Settings {
+	settings := DefaultSettings()
+	c.Defaults.apply(&settings)
+	if b, ok := c.Breakers[name]; ok {
+		b.apply(&settings)
+	}
+	return settings
+}
+
+// Apply 按配置创建熔断器，已注册的同名熔断器通过 UpdateSettings 更新
+func (c *Config) Apply(r *Registry) {
+	for name := range c.Breakers {
+		settings := c.Settings(name)
+		if cb, ok := r.Get(name); ok {
+			cb.UpdateSettings(settings)
+			continue
+		}
+		r.GetOrCreate(name, settings)
+	}
+}
+
+// apply 将非零字段覆盖到 settings，设置了 Preset 时先应用预设
+func (b BreakerConfig) apply(s *Settings) {
+	if preset, ok := presets[b.Preset]; ok {
+		preset.apply(s)
+	}
+	if b.MaxRequests > 0 {
+		s.MaxRequests = b.MaxRequests
+	}
+	if b.Interval > 0 {
+		s.Interval = time.Duration(b.Interval)
+	}
+	if b.WindowMode != "" {
+		s.WindowMode = b.WindowMode
+	}
+	if b.WindowBuckets > 0 {
+		s.WindowBuckets = b.WindowBuckets
+	}
+	if b.WindowSize > 0 {
+		s.WindowSize = b.WindowSize
+	}
+	if b.Timeout > 0 {
+		s.Timeout = time.Duration(b.Timeout)
+	}
+	if b.CallTimeout > 0 {
+		s.CallTimeout = time.Duration(b.CallTimeout)
+	}
+	if b.DeadlineOverhead > 0 {
+		s.DeadlineOverhead = time.Duration(b.DeadlineOverhead)
+	}
+	if b.DeferTimeoutOutcome {
+		s.DeferTimeoutOutcome = true
+	}
+	if b.MaxPressure > 0 {
+		s.MaxPressure = b.MaxPressure
+	}
+	if b.CountCallerCancellation {
+		s.CountCallerCancellation = true
+	}
+	if b.Description != "" {
+		s.Description = b.Description
+	}
+	if b.RunbookURL != "" {
+		s.RunbookURL = b.RunbookURL
+	}
+	if b.Tier != "" {
+		s.Tier = b.Tier
+	}
+	if b.CloseFailureRate > 0 {
+		s.CloseFailureRate = b.CloseFailureRate
+	}
+	if b.MinimumRequests > 0 {
+		s.MinimumRequests = b.MinimumRequests
+	}
+	if len(b.Labels) > 0 {
+		labels := make(map[string]string, len(s.Labels)+len(b.Labels))
+		for k, v := range s.Labels {
+			labels[k] = v
+		}
+		for k, v := range b.Labels {
+			labels[k] = v
+		}
+		s.Labels = labels
+	}
+	if b.ErrorSamples > 0 {
+		s.ErrorSamples = b.ErrorSamples
+	}
+	if b.LatencySampleRate > 0 {
+		s.LatencySampleRate = b.LatencySampleRate
+	}
+	if b.DeadlineQuantile > 0 {
+		s.DeadlineQuantile = b.DeadlineQuantile
+	}
+	if b.LatencyBudget > 0 {
+		s.LatencyBudget = time.Duration(b.LatencyBudget)
+	}
+	if b.MaxResultSize > 0 {
+		s.MaxResultSize = b.MaxResultSize
+	}
+	if len(b.Policies) > 0 {
+		policies := make([]NamedPolicy, 0, len(b.Policies))
+		for _, p := range b.Policies {
+			if policy, err := buildTripPolicy(p); err == nil {
+				policies = append(policies, NamedPolicy{Name: p.Type, Policy: policy})
+			}
+		}
+		s.ReadyToTrip, s.TripPolicyName = AnyOfNamed(policies...)
+	}
+	if b.Trip != nil {
+		b.Trip.apply(s)
+	}
+}
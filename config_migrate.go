@@ -0,0 +1,130 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// configDoc 迁移时使用的通用 JSON 文档
+type configDoc = map[string]interface{}
+
+// configMigrations 按源版本索引的迁移函数，将文档从版本 v 升级到 v+1
+// 新增格式版本时追加迁移函数并递增 ConfigVersion，已发布的迁移不可修改
+var configMigrations = map[int]func(doc configDoc) error{
+	1: migrateConfigV1,
+}
+
+// MigrateConfig 将任意已支持版本的配置文件迁移到 ConfigVersion，已是当前版本时原样返回；
+// 可用于离线升级持久化的配置
+func MigrateConfig(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc configDoc
+	if err := dec.Decode(&doc); err != nil {
+		return nil, &ConfigError{Msg: err.Error()}
+	}
+
+	version, err := configDocVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if version == ConfigVersion {
+		return data, nil
+	}
+	if version > ConfigVersion {
+		return nil, &ConfigError{Path: "version", Msg: fmt.Sprintf("version %d is newer than supported version %d", version, ConfigVersion)}
+	}
+
+	for ; version < ConfigVersion; version++ {
+		migrate, ok := configMigrations[version]
+		if !ok {
+			return nil, &ConfigError{Path: "version", Msg: fmt.Sprintf("unsupported version %d", version)}
+		}
+		if err := migrate(doc); err != nil {
+			return nil, err
+		}
+	}
+	doc["version"] = ConfigVersion
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// configDocVersion 读取文档版本号
+func configDocVersion(doc configDoc) (int, error) {
+	n, ok := doc["version"].(json.Number)
+	if !ok {
+		return 0, &ConfigError{Path: "version", Msg: "missing or not a number"}
+	}
+	v, err := n.Int64()
+	if err != nil || v < 1 {
+		return 0, &ConfigError{Path: "version", Msg: fmt.Sprintf("invalid version %s", n)}
+	}
+	return int(v), nil
+}
+
+// migrateConfigV1 v1 -> v2：固定字段的 trip 对象改为可扩展的 policies 列表
+//
+//	"trip": {"consecutive_failures": 5, "failure_rate": 0.5}
+//	=> "policies": [{"type": "consecutive_failures", "threshold": 5}, {"type": "failure_rate", "threshold": 0.5}]
+func migrateConfigV1(doc configDoc) error {
+	return eachBreakerDoc(doc, func(path string, b configDoc) error {
+		raw, ok := b["trip"]
+		if !ok {
+			return nil
+		}
+		delete(b, "trip")
+
+		trip, ok := raw.(configDoc)
+		if !ok {
+			return &ConfigError{Path: path + ".trip", Msg: "must be an object"}
+		}
+		var policies []interface{}
+		for _, typ := range []string{PolicyConsecutiveFailures, PolicyFailureRate} {
+			if v, ok := trip[typ]; ok {
+				policies = append(policies, configDoc{"type": typ, "threshold": v})
+				delete(trip, typ)
+			}
+		}
+		if len(trip) > 0 {
+			return &ConfigError{Path: path + ".trip", Msg: "unknown fields"}
+		}
+		if len(policies) > 0 {
+			b["policies"] = policies
+		}
+		return nil
+	})
+}
+
+// eachBreakerDoc 对 defaults 与每个熔断器的配置对象调用 fn
+func eachBreakerDoc(doc configDoc, fn func(path string, b configDoc) error) error {
+	if b, ok := doc["defaults"].(configDoc); ok {
+		if err := fn("defaults", b); err != nil {
+			return err
+		}
+	}
+	breakers, _ := doc["breakers"].(configDoc)
+	for name, raw := range breakers {
+		if b, ok := raw.(configDoc); ok {
+			if err := fn("breakers."+name, b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
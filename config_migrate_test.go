@@ -0,0 +1,69 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestMigrateConfig_V1(t *testing.T) {
+	v1 := `{
+  "version": 1,
+  "defaults": {"trip": {"consecutive_failures": 3}},
+  "breakers": {
+    "payments": {"timeout": "30s", "trip": {"consecutive_failures": 10, "failure_rate": 0.5}}
+  }
+}`
+	c, err := ParseConfig([]byte(v1))
+	if err != nil {
+		t.Fatalf("ParseConfig(v1) error = %v", err)
+	}
+	if c.Version != ConfigVersion {
+		t.Errorf("Version = %v, want %v", c.Version, ConfigVersion)
+	}
+
+	want := []PolicyConfig{
+		{Type: PolicyConsecutiveFailures, Threshold: 10},
+		{Type: PolicyFailureRate, Threshold: 0.5},
+	}
+	if got := c.Breakers["payments"].Policies; !reflect.DeepEqual(got, want) {
+		t.Errorf("payments Policies = %+v, want %+v", got, want)
+	}
+	if got := c.Defaults.Policies; len(got) != 1 || got[0].Threshold != 3 {
+		t.Errorf("defaults Policies = %+v, want consecutive_failures 3", got)
+	}
+
+	trip := c.Settings("payments").ReadyToTrip
+	if !trip(gobreaker.Counts{Requests: 4, TotalFailures: 2}) {
+		t.Error("ReadyToTrip at 50% = false, want true")
+	}
+}
+
+func TestMigrateConfig_Current(t *testing.T) {
+	data := []byte(`{"version": 2, "breakers": {}}`)
+	out, err := MigrateConfig(data)
+	if err != nil || string(out) != string(data) {
+		t.Errorf("MigrateConfig(v2) = %s, %v, want unchanged", out, err)
+	}
+}
+
+func TestMigrateConfig_Errors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`{"breakers": {}}`, "version: missing"},
+		{`{"version": 0, "breakers": {}}`, "version: invalid"},
+		{`{"version": 9, "breakers": {}}`, "newer than supported"},
+		{`{"version": 1, "breakers": {"x": {"trip": {"p99": 1}}}}`, "breakers.x.trip: unknown fields"},
+	}
+	for _, tt := range tests {
+		if _, err := MigrateConfig([]byte(tt.in)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("MigrateConfig(%s) error = %v, want %q", tt.in, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+const testConfig = `{
+  "version": 2,
+  "defaults": {"timeout": "10s", "tier": "degraded-ok", "labels": {"team": "core"}},
+  "breakers": {
+    "payments": {
+      "timeout": "1m",
+      "tier": "critical",
+      "minimum_requests": 20,
+      "labels": {"service": "payments"},
+      "policies": [{"type": "failure_rate", "threshold": 0.5}]
+    },
+    "search": {}
+  }
+}`
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	payments := c.Settings("payments")
+	if payments.Timeout != time.Minute || payments.Tier != TierCritical || payments.MinimumRequests != 20 {
+		t.Errorf("payments = %+v", payments)
+	}
+	if want := map[string]string{"team": "core", "service": "payments"}; !reflect.DeepEqual(payments.Labels, want) {
+		t.Errorf("Labels = %v, want %v", payments.Labels, want)
+	}
+	if !payments.ReadyToTrip(gobreaker.Counts{Requests: 10, TotalFailures: 5}) {
+		t.Error("ReadyToTrip at 50% = false, want true")
+	}
+
+	search := c.Settings("search")
+	if search.Timeout != 10*time.Second || search.Tier != TierDegradedOK || search.MaxRequests != DefaultSettings().MaxRequests {
+		t.Errorf("search = %+v", search)
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	_, err := ParseConfig([]byte(`{
+  "version": 2,
+  "breakers": {
+    "payments": {
+      "timeout": "-1s", "tier": "urgent", "close_failure_rate": 1.5, "runbook_url": "wiki/payments",
+      "policies": [{"type": "consecutive_failures", "threshold": 0.5}, {"type": "latency", "threshold": 1}]
+    }
+  }
+}`))
+
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("ParseConfig() error = %v, want *ConfigError", err)
+	}
+	for _, path := range []string{
+		"breakers.payments.timeout",
+		"breakers.payments.tier",
+		"breakers.payments.close_failure_rate",
+		"breakers.payments.runbook_url",
+		"breakers.payments.policies[0].threshold",
+		"breakers.payments.policies[1].type",
+	} {
+		if !strings.Contains(err.Error(), path+":") {
+			t.Errorf("error %q does not mention %s", err, path)
+		}
+	}
+
+	if _, err := ParseConfig([]byte(`{"version": 2, "breakers": {"x": {"timeot": "1s"}}}`)); err == nil {
+		t.Error("ParseConfig() with unknown field error = nil, want error")
+	}
+	if _, err := ParseConfig([]byte(`{"version": 2, "breakers": {"x": {"timeout": 5}}}`)); err == nil {
+		t.Error("ParseConfig() with numeric duration error = nil, want error")
+	}
+}
+
+func TestConfig_Apply(t *testing.T) {
+	c, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	r := NewRegistry()
+	existing := r.GetOrCreate("search", DefaultSettings())
+
+	c.Apply(r)
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"payments", "search"}) {
+		t.Errorf("Names() = %v", got)
+	}
+	if got := existing.GetSettings().Timeout; got != 10*time.Second {
+		t.Errorf("search Timeout = %v, want 10s", got)
+	}
+}
+
+func TestConfigSchema_MatchesBreakerConfig(t *testing.T) {
+	var schema struct {
+		Defs struct {
+			Breaker struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"breaker"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(ConfigSchema, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	var fromSchema, fromStruct []string
+	for name := range schema.Defs.Breaker.Properties {
+		fromSchema = append(fromSchema, name)
+	}
+	typ := reflect.TypeOf(BreakerConfig{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		fromStruct = append(fromStruct, name)
+	}
+	sort.Strings(fromSchema)
+	sort.Strings(fromStruct)
+	if !reflect.DeepEqual(fromSchema, fromStruct) {
+		t.Errorf("schema properties = %v, BreakerConfig fields = %v", fromSchema, fromStruct)
+	}
+}
+
+func TestConfig_TripPolicyName(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{"version":2,"breakers":{"payments":{"policies":[
+		{"type":"failure_rate","threshold":0.5},
+		{"type":"consecutive_failures","threshold":3}
+	]}}}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	settings := cfg.Settings("payments")
+	if got := settings.TripPolicyName(gobreaker.Counts{Requests: 3, TotalFailures: 1, ConsecutiveFailures: 3}); got != PolicyConsecutiveFailures {
+		t.Errorf("TripPolicyName() = %q, want %q", got, PolicyConsecutiveFailures)
+	}
+}
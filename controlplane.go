@@ -0,0 +1,304 @@
+//go:build !cbnotelemetry
+
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 控制面协议（HTTP+JSON，类似 xDS 的版本化全量下发）：
+//
+//	GET <endpoint>?resource=<熔断器名称>&resource=...&wait=30s
+//	If-None-Match: "<客户端已见的 version_info>"
+//
+// 服务端返回 200 与 ConfigSnapshot（ETag 为其版本）；版本未变化时最长等待 wait 后返回 304。
+// resource 为空时下发全部熔断器，Defaults 总是下发。
+
+// ConfigSnapshot 控制面下发的一个配置版本
+type ConfigSnapshot struct {
+	// VersionInfo 配置版本，内容变化时必须变化
+	VersionInfo string `json:"version_info"`
+	// Config 配置文件内容，格式与 ParseConfig 相同
+	Config json.RawMessage `json:"config"`
+}
+
+// ConfigClientOptions 控制面客户端配置
+type ConfigClientOptions struct {
+	// Endpoint 控制面配置地址
+	Endpoint string
+	// Resources 订阅的熔断器名称，为空时订阅全部
+	Resources []string
+	// PollInterval 两次拉取的间隔，默认 30 秒；启用 Wait 时为出错或服务端未等待即返回后的重试间隔
+	PollInterval time.Duration
+	// Wait 长轮询等待时间：服务端在版本变化时立即返回，实现近似流式的推送；0 表示普通轮询
+	Wait time.Duration
+	// Header 每次请求附带的请求头（如鉴权）
+	Header http.Header
+	// Client HTTP 客户端，为空时使用 http.DefaultClient
+	Client *http.Client
+	// OnUpdate 新版本生效后调用
+	OnUpdate func(version string, cfg *Config)
+	// OnError 拉取失败或配置被拒绝时调用，默认忽略；被拒绝的版本不会重复下发，原配置继续生效
+	OnError func(error)
+}
+
+// ConfigClient 从控制面拉取多个服务的熔断配置并应用到注册表，供平台团队集中管理阈值
+type ConfigClient struct {
+	registry *Registry
+	opts     ConfigClientOptions
+
+	mu sync.Mutex
+	// seen 最近收到的版本（含被拒绝的版本），applied 最近生效的版本
+	seen    string
+	applied string
+}
+
+// NewConfigClient 创建控制面客户端
+func NewConfigClient(r *Registry, opts ConfigClientOptions) *ConfigClient {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 30 * time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &ConfigClient{registry: r, opts: opts}
+}
+
+// Version 返回当前生效的配置版本，尚未生效时为空
+func (c *ConfigClient) Version() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.applied
+}
+
+// Run 持续拉取并应用配置，直至 ctx 结束
+func (c *ConfigClient) Run(ctx context.Context) {
+	for {
+		start := time.Now()
+		updated, err := c.Fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && c.opts.OnError != nil {
+			c.opts.OnError(err)
+		}
+		// 收到新版本，或服务端等满 Wait 后才返回 304 时立即发起下一次长轮询；
+		// 不支持长轮询的服务端会立即返回 304，此时按 PollInterval 等待，避免连续请求
+		if err == nil && c.opts.Wait > 0 && (updated || time.Since(start) >= c.opts.Wait) {
+			continue
+		}
+		timer := time.NewTimer(c.opts.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Fetch 拉取一次配置，有新版本且校验通过时应用到注册表并返回 true；
+// 配置无效时返回错误且不应用，该版本不会再次下发
+func (c *ConfigClient) Fetch(ctx context.Context) (bool, error) {
+	c.mu.Lock()
+	seen := c.seen
+	c.mu.Unlock()
+
+	snap, err := c.request(ctx, seen)
+	if err != nil || snap == nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.seen = snap.VersionInfo
+	c.mu.Unlock()
+	cfg, err := ParseConfig(snap.Config)
+	if err != nil {
+		return false, fmt.Errorf("circuitbreaker: rejected config version %q: %w", snap.VersionInfo, err)
+	}
+	cfg.Apply(c.registry)
+
+	c.mu.Lock()
+	c.applied = snap.VersionInfo
+	c.mu.Unlock()
+	if c.opts.OnUpdate != nil {
+		c.opts.OnUpdate(snap.VersionInfo, cfg)
+	}
+	return true, nil
+}
+
+// request 执行一次请求，版本未变化时返回 nil
+func (c *ConfigClient) request(ctx context.Context, seen string) (*ConfigSnapshot, error) {
+	q := url.Values{}
+	for _, name := range c.opts.Resources {
+		q.Add("resource", name)
+	}
+	if c.opts.Wait > 0 {
+		q.Set("wait", c.opts.Wait.String())
+	}
+	u := c.opts.Endpoint
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.opts.Header {
+		req.Header[k] = v
+	}
+	if seen != "" {
+		req.Header.Set("If-None-Match", strconv.Quote(seen))
+	}
+	resp, err := c.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, nil
+	default:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("circuitbreaker: control plane returned %s", resp.Status)
+	}
+	var snap ConfigSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("circuitbreaker: decode config snapshot: %w", err)
+	}
+	if snap.VersionInfo == "" {
+		return nil, errors.New("circuitbreaker: config snapshot has no version_info")
+	}
+	return &snap, nil
+}
+
+// maxConfigWait ConfigServer 接受的最长等待时间
+const maxConfigWait = 5 * time.Minute
+
+// ConfigServer 控制面协议的服务端实现，持有当前配置并向长轮询的客户端推送新版本
+type ConfigServer struct {
+	mu      sync.RWMutex
+	version string
+	config  *Config
+	changed chan struct{}
+}
+
+// NewConfigServer 创建服务端，调用 Set 前返回 503
+func NewConfigServer() *ConfigServer {
+	return &ConfigServer{changed: make(chan struct{})}
+}
+
+// Set 发布新版本配置，唤醒所有等待中的客户端；配置无效时返回错误且不发布
+func (s *ConfigServer) Set(version string, cfg *Config) error {
+	if version == "" {
+		return errors.New("circuitbreaker: config version must not be empty")
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version, s.config = version, cfg
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return nil
+}
+
+// current 返回当前版本、配置与下次变更的通知通道
+func (s *ConfigServer) current() (string, *Config, <-chan struct{}) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version, s.config, s.changed
+}
+
+// ServeHTTP 实现控制面协议
+func (s *ConfigServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var wait time.Duration
+	if v := req.URL.Query().Get("wait"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			http.Error(w, "invalid wait", http.StatusBadRequest)
+			return
+		}
+		wait = min(d, maxConfigWait)
+	}
+	seen, _ := strconv.Unquote(req.Header.Get("If-None-Match"))
+
+	version, cfg, changed := s.current()
+	if version != "" && version == seen && wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-changed:
+			version, cfg, _ = s.current()
+		case <-timer.C:
+		case <-req.Context().Done():
+			return
+		}
+	}
+	switch {
+	case cfg == nil:
+		http.Error(w, "no config published", http.StatusServiceUnavailable)
+		return
+	case version == seen:
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	data, err := json.Marshal(cfg.subset(req.URL.Query()["resource"]))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("ETag", strconv.Quote(version))
+	_ = json.NewEncoder(w).Encode(ConfigSnapshot{VersionInfo: version, Config: data})
+}
+
+// subset 返回只包含指定熔断器的配置，names 为空时返回完整配置
+func (c *Config) subset(names []string) *Config {
+	if len(names) == 0 {
+		return c
+	}
+	out := &Config{Version: c.Version, Defaults: c.Defaults, Breakers: make(map[string]BreakerConfig, len(names))}
+	for _, name := range names {
+		if b, ok := c.Breakers[name]; ok {
+			out.Breakers[name] = b
+		}
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+//go:build !cbnotelemetry
+
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func mustParseConfig(t *testing.T, data string) *Config {
+	t.Helper()
+	cfg, err := ParseConfig([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	return cfg
+}
+
+func TestConfigClient_FetchAppliesSubscribedBreakers(t *testing.T) {
+	server := NewConfigServer()
+	if err := server.Set("v1", mustParseConfig(t, `{"version":2,"breakers":{
+		"payments":{"max_requests":7},
+		"inventory":{"max_requests":9}
+	}}`)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	r := NewRegistry()
+	client := NewConfigClient(r, ConfigClientOptions{Endpoint: srv.URL, Resources: []string{"payments"}})
+
+	changed, err := client.Fetch(context.Background())
+	if err != nil || !changed {
+		t.Fatalf("Fetch() = %v, %v, want true, nil", changed, err)
+	}
+	if got := r.Names(); len(got) != 1 || got[0] != "payments" {
+		t.Fatalf("Names() = %v, want [payments]", got)
+	}
+	if cb, _ := r.Get("payments"); cb.GetSettings().MaxRequests != 7 {
+		t.Errorf("MaxRequests = %v, want %v", cb.GetSettings().MaxRequests, 7)
+	}
+	if got := client.Version(); got != "v1" {
+		t.Errorf("Version() = %q, want v1", got)
+	}
+
+	if changed, err := client.Fetch(context.Background()); err != nil || changed {
+		t.Errorf("Fetch() unchanged = %v, %v, want false, nil", changed, err)
+	}
+}
+
+func TestConfigClient_RejectsInvalidConfig(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == `"bad"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"version_info":"bad","config":{"version":2,"breakers":{"payments":{"timeout":"-1s"}}}}`))
+	}))
+	defer srv.Close()
+
+	r := NewRegistry()
+	client := NewConfigClient(r, ConfigClientOptions{Endpoint: srv.URL})
+	if _, err := client.Fetch(context.Background()); err == nil {
+		t.Fatal("Fetch() error = nil, want rejection")
+	}
+	if got := r.Names(); len(got) != 0 {
+		t.Errorf("Names() = %v, want none", got)
+	}
+	if changed, err := client.Fetch(context.Background()); err != nil || changed {
+		t.Errorf("Fetch() after rejection = %v, %v, want false, nil", changed, err)
+	}
+	if client.Version() != "" {
+		t.Errorf("Version() = %q, want empty", client.Version())
+	}
+}
+
+func TestConfigClient_RunLongPoll(t *testing.T) {
+	server := NewConfigServer()
+	server.Set("v1", mustParseConfig(t, `{"version":2,"breakers":{"payments":{"max_requests":1}}}`))
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	versions := make(chan string, 2)
+	r := NewRegistry()
+	client := NewConfigClient(r, ConfigClientOptions{
+		Endpoint: srv.URL,
+		Wait:     time.Minute,
+		OnUpdate: func(version string, cfg *Config) { versions <- version },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go client.Run(ctx)
+
+	if got := <-versions; got != "v1" {
+		t.Fatalf("first version = %q, want v1", got)
+	}
+	server.Set("v2", mustParseConfig(t, `{"version":2,"breakers":{"payments":{"max_requests":2}}}`))
+	select {
+	case got := <-versions:
+		if got != "v2" {
+			t.Errorf("second version = %q, want v2", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("long poll did not deliver v2")
+	}
+	if cb, _ := r.Get("payments"); cb.GetSettings().MaxRequests != 2 {
+		t.Errorf("MaxRequests = %v, want %v", cb.GetSettings().MaxRequests, 2)
+	}
+}
+
+func TestConfigClient_RunWaitsAfterImmediateNotModified(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// 不支持长轮询的服务端忽略 wait 参数，立即返回 304
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	client := NewConfigClient(NewRegistry(), ConfigClientOptions{
+		Endpoint:     srv.URL,
+		Wait:         time.Minute,
+		PollInterval: time.Hour,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("requests = %v, want 1 before PollInterval", got)
+	}
+}
+
+func TestConfigServer_SetRejectsInvalid(t *testing.T) {
+	server := NewConfigServer()
+	if err := server.Set("", &Config{Version: ConfigVersion}); err == nil {
+		t.Error("Set() with empty version error = nil")
+	}
+	var cfgErr *ConfigError
+	if err := server.Set("v1", &Config{Version: 1}); !errors.As(err, &cfgErr) {
+		t.Errorf("Set() error = %v, want *ConfigError", err)
+	}
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusServiceUnavailable)
+	}
+}
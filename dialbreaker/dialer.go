@@ -0,0 +1,87 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package dialbreaker 提供按目标地址熔断的拨号器，
+// 可用于任何支持自定义 DialContext 的客户端（HTTP、gRPC、Redis 等）
+package dialbreaker
+
+import (
+	"context"
+	"net"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// ContextDialer 底层拨号器，*net.Dialer 实现了该接口
+type ContextDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Dialer 按地址熔断的拨号器，熔断打开的地址在发起 TCP 连接前即快速失败
+type Dialer struct {
+	dialer   ContextDialer
+	registry *circuitbreaker.Registry
+	settings circuitbreaker.Settings
+}
+
+// Option 拨号器配置项
+type Option func(*Dialer)
+
+// WithDialer 设置底层拨号器，默认 &net.Dialer{}
+func WithDialer(d ContextDialer) Option {
+	return func(dl *Dialer) { dl.dialer = d }
+}
+
+// WithRegistry 设置熔断器注册表，便于与健康检查、管理接口共享
+func WithRegistry(r *circuitbreaker.Registry) Option {
+	return func(dl *Dialer) { dl.registry = r }
+}
+
+// WithSettings 设置每个地址熔断器的配置
+func WithSettings(settings circuitbreaker.Settings) Option {
+	return func(dl *Dialer) { dl.settings = settings }
+}
+
+// New 创建拨号器
+func New(opts ...Option) *Dialer {
+	d := &Dialer{
+		dialer:   &net.Dialer{},
+		registry: circuitbreaker.NewRegistry(),
+		settings: circuitbreaker.DefaultSettings(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Breaker 返回指定地址的熔断器，注册表中命名为 "dial:<地址>"
+func (d *Dialer) Breaker(address string) *circuitbreaker.CircuitBreaker {
+	return d.registry.GetOrCreate("dial:"+address, d.settings)
+}
+
+// DialContext 连接指定地址，仅建立连接的结果计入熔断
+// 熔断器拒绝时返回 *net.OpError，其 Err 为 gobreaker.ErrOpenState 或 ErrTooManyRequests
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	done, err := d.Breaker(address).AllowContext(ctx)
+	if err != nil {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: err}
+	}
+
+	c, err := d.dialer.DialContext(ctx, network, address)
+	done(err)
+	return c, err
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 zampo.
+
+package dialbreaker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// countingDialer 记录实际拨号次数
+type countingDialer struct {
+	calls int
+	err   error
+}
+
+func (d *countingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.calls++
+	if d.err != nil {
+		return nil, d.err
+	}
+	c, _ := net.Pipe()
+	return c, nil
+}
+
+func TestDialer_FailsFastWhenOpen(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	base := &countingDialer{err: errors.New("connection refused")}
+	d := New(WithDialer(base), WithSettings(settings))
+
+	if _, err := d.DialContext(context.Background(), "tcp", "db:5432"); err == nil {
+		t.Fatal("DialContext() should fail")
+	}
+	_, err := d.DialContext(context.Background(), "tcp", "db:5432")
+	if !errors.Is(err, gobreaker.ErrOpenState) {
+		t.Errorf("DialContext() error = %v, want %v", err, gobreaker.ErrOpenState)
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		t.Errorf("DialContext() error = %T, want *net.OpError", err)
+	}
+	if base.calls != 1 {
+		t.Errorf("calls = %v, want 1", base.calls)
+	}
+
+	// 其他地址不受影响
+	base.err = nil
+	c, err := d.DialContext(context.Background(), "tcp", "cache:6379")
+	if err != nil {
+		t.Fatalf("DialContext() error = %v", err)
+	}
+	c.Close()
+}
+
+func TestDialer_SharedRegistry(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	d := New(WithDialer(&countingDialer{}), WithRegistry(r))
+
+	c, err := d.DialContext(context.Background(), "tcp", "api:443")
+	if err != nil {
+		t.Fatalf("DialContext() error = %v", err)
+	}
+	c.Close()
+	if _, ok := r.Get("dial:api:443"); !ok {
+		t.Error("breaker dial:api:443 not registered")
+	}
+}
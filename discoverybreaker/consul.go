@@ -0,0 +1,134 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package discoverybreaker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ConsulSource 通过 Consul 健康检查接口（/v1/health/service）的阻塞查询监听实例，只返回检查通过的实例
+type ConsulSource struct {
+	// Address Consul HTTP 地址，如 http://127.0.0.1:8500
+	Address string
+	// Token ACL 令牌，为空时不发送
+	Token string
+	// Datacenter 数据中心，为空时使用 Agent 所在数据中心
+	Datacenter string
+	// Wait 单次阻塞查询的最长等待时间，默认 5 分钟
+	Wait time.Duration
+	// Client HTTP 客户端，为空时使用 http.DefaultClient
+	Client *http.Client
+}
+
+// consulEntry /v1/health/service 的响应项
+type consulEntry struct {
+	Node struct {
+		Address string `json:"Address"`
+	} `json:"Node"`
+	Service struct {
+		ID      string            `json:"ID"`
+		Address string            `json:"Address"`
+		Port    int               `json:"Port"`
+		Meta    map[string]string `json:"Meta"`
+	} `json:"Service"`
+}
+
+// Watch 实现 Source，X-Consul-Index 未变化（等待超时）时不调用 update
+func (c *ConsulSource) Watch(ctx context.Context, service string, update func([]Instance)) error {
+	var index uint64
+	for {
+		entries, next, err := c.query(ctx, service, index)
+		if err != nil {
+			return err
+		}
+		// 索引回退（如 Consul 重启）时重新开始阻塞查询
+		if next < index {
+			next = 0
+		}
+		if next != index || index == 0 {
+			update(consulInstances(entries))
+		}
+		index = next
+	}
+}
+
+// query 执行一次阻塞查询，返回实例与 X-Consul-Index
+func (c *ConsulSource) query(ctx context.Context, service string, index uint64) ([]consulEntry, uint64, error) {
+	wait := c.Wait
+	if wait <= 0 {
+		wait = 5 * time.Minute
+	}
+	q := url.Values{"passing": {"1"}, "wait": {wait.String()}}
+	if index > 0 {
+		q.Set("index", strconv.FormatUint(index, 10))
+	}
+	if c.Datacenter != "" {
+		q.Set("dc", c.Datacenter)
+	}
+	u := c.Address + "/v1/health/service/" + url.PathEscape(service) + "?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("discoverybreaker: consul returned %s", resp.Status)
+	}
+
+	var entries []consulEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, 0, fmt.Errorf("discoverybreaker: decode consul response: %w", err)
+	}
+	next, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	return entries, next, nil
+}
+
+// consulInstances 转换为实例列表，服务未声明地址时使用节点地址
+func consulInstances(entries []consulEntry) []Instance {
+	instances := make([]Instance, 0, len(entries))
+	for _, e := range entries {
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		instances = append(instances, Instance{
+			ID:      e.Service.ID,
+			Address: net.JoinHostPort(host, strconv.Itoa(e.Service.Port)),
+			Meta:    e.Service.Meta,
+		})
+	}
+	return instances
+}
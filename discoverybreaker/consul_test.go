@@ -0,0 +1,52 @@
+// Copyright 2025 zampo.
+
+package discoverybreaker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConsulSource_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health/service/payments" || r.URL.Query().Get("passing") != "1" {
+			t.Errorf("unexpected request %v", r.URL)
+		}
+		if got := r.Header.Get("X-Consul-Token"); got != "secret" {
+			t.Errorf("X-Consul-Token = %q, want secret", got)
+		}
+		switch r.URL.Query().Get("index") {
+		case "":
+			w.Header().Set("X-Consul-Index", "7")
+			w.Write([]byte(`[
+				{"Node":{"Address":"10.0.0.1"},"Service":{"ID":"p1","Address":"","Port":8080}},
+				{"Node":{"Address":"10.0.0.9"},"Service":{"ID":"p2","Address":"10.0.0.2","Port":8080,"Meta":{"zone":"b"}}}
+			]`))
+		case "7":
+			w.Header().Set("X-Consul-Index", "7")
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("index = %q, want unchanged 7", r.URL.Query().Get("index"))
+		}
+		if r.URL.Query().Get("index") == "7" {
+			// 第二次阻塞查询索引未变化，结束 Watch
+			cancel()
+		}
+	}))
+	defer srv.Close()
+
+	var got [][]Instance
+	source := &ConsulSource{Address: srv.URL, Token: "secret"}
+	source.Watch(ctx, "payments", func(instances []Instance) { got = append(got, instances) })
+
+	if len(got) != 1 {
+		t.Fatalf("update called %d times, want 1 (unchanged index must not update)", len(got))
+	}
+	if len(got[0]) != 2 || got[0][0].Address != "10.0.0.1:8080" || got[0][1].Address != "10.0.0.2:8080" || got[0][1].Meta["zone"] != "b" {
+		t.Errorf("instances = %+v", got[0])
+	}
+}
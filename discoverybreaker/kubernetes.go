@@ -0,0 +1,182 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package discoverybreaker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// serviceAccountDir Pod 内服务账号凭据目录
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount/"
+
+// KubernetesSource 通过 Kubernetes API 的 watch 接口监听 Endpoints，只返回就绪地址；
+// 直接使用 REST 接口，不依赖 client-go，服务账号需有 endpoints 的 get/watch 权限
+type KubernetesSource struct {
+	// APIServer API 地址，如 https://kubernetes.default.svc
+	APIServer string
+	// Namespace Endpoints 所在命名空间
+	Namespace string
+	// Token Bearer 令牌，为空时不发送
+	Token string
+	// PortName 使用的端口名，为空时使用第一个端口
+	PortName string
+	// Client HTTP 客户端，为空时使用 http.DefaultClient
+	Client *http.Client
+}
+
+// InClusterKubernetes 使用 Pod 内的服务账号凭据创建数据源，namespace 为空时使用 Pod 所在命名空间
+func InClusterKubernetes(namespace string) (*KubernetesSource, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("discoverybreaker: not running in a kubernetes cluster")
+	}
+	token, err := os.ReadFile(serviceAccountDir + "token")
+	if err != nil {
+		return nil, err
+	}
+	ca, err := os.ReadFile(serviceAccountDir + "ca.crt")
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("discoverybreaker: invalid service account CA")
+	}
+	if namespace == "" {
+		ns, err := os.ReadFile(serviceAccountDir + "namespace")
+		if err != nil {
+			return nil, err
+		}
+		namespace = string(ns)
+	}
+	return &KubernetesSource{
+		APIServer: "https://" + net.JoinHostPort(host, port),
+		Namespace: namespace,
+		Token:     string(token),
+		Client: &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		}},
+	}, nil
+}
+
+// k8sEndpoints Endpoints 对象中用到的字段
+type k8sEndpoints struct {
+	Subsets []struct {
+		Addresses []struct {
+			IP        string `json:"ip"`
+			TargetRef *struct {
+				Name string `json:"name"`
+			} `json:"targetRef"`
+		} `json:"addresses"`
+		Ports []struct {
+			Name string `json:"name"`
+			Port int    `json:"port"`
+		} `json:"ports"`
+	} `json:"subsets"`
+}
+
+// k8sEvent watch 事件
+type k8sEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// Watch 实现 Source，service 为 Endpoints 名称（与 Service 同名）；
+// 服务端结束 watch 连接时返回 nil，由 Syncer.Run 重新监听
+func (k *KubernetesSource) Watch(ctx context.Context, service string, update func([]Instance)) error {
+	q := url.Values{"watch": {"1"}, "fieldSelector": {"metadata.name=" + service}}
+	u := k.APIServer + "/api/v1/namespaces/" + url.PathEscape(k.Namespace) + "/endpoints?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if k.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+k.Token)
+	}
+	client := k.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("discoverybreaker: kubernetes returned %s", resp.Status)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var ev k8sEvent
+		if err := dec.Decode(&ev); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		switch ev.Type {
+		case "ADDED", "MODIFIED":
+			var ep k8sEndpoints
+			if err := json.Unmarshal(ev.Object, &ep); err != nil {
+				return fmt.Errorf("discoverybreaker: decode endpoints: %w", err)
+			}
+			update(k.instances(ep))
+		case "DELETED":
+			update(nil)
+		case "ERROR":
+			return fmt.Errorf("discoverybreaker: kubernetes watch error: %s", ev.Object)
+		}
+	}
+}
+
+// instances 转换为实例列表，实例标识优先使用 Pod 名称
+func (k *KubernetesSource) instances(ep k8sEndpoints) []Instance {
+	var instances []Instance
+	for _, subset := range ep.Subsets {
+		port := -1
+		for _, p := range subset.Ports {
+			if k.PortName == "" || p.Name == k.PortName {
+				port = p.Port
+				break
+			}
+		}
+		if port < 0 {
+			continue
+		}
+		for _, addr := range subset.Addresses {
+			inst := Instance{Address: net.JoinHostPort(addr.IP, strconv.Itoa(port))}
+			if addr.TargetRef != nil && addr.TargetRef.Name != "" {
+				inst.ID = addr.TargetRef.Name
+			}
+			instances = append(instances, inst)
+		}
+	}
+	return instances
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 zampo.
+
+package discoverybreaker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKubernetesSource_Watch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/prod/endpoints" || r.URL.Query().Get("fieldSelector") != "metadata.name=payments" {
+			t.Errorf("unexpected request %v", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q, want Bearer token", got)
+		}
+		w.Write([]byte(`{"type":"ADDED","object":{"subsets":[{"addresses":[{"ip":"10.0.0.1","targetRef":{"name":"payments-0"}},{"ip":"10.0.0.2"}],"ports":[{"name":"metrics","port":9090},{"name":"http","port":8080}]}]}}
+{"type":"DELETED","object":{}}
+`))
+	}))
+	defer srv.Close()
+
+	var got [][]Instance
+	source := &KubernetesSource{APIServer: srv.URL, Namespace: "prod", Token: "token", PortName: "http"}
+	err := source.Watch(context.Background(), "payments", func(instances []Instance) { got = append(got, instances) })
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("update called %d times, want 2", len(got))
+	}
+	want := []Instance{{ID: "payments-0", Address: "10.0.0.1:8080"}, {Address: "10.0.0.2:8080"}}
+	if len(got[0]) != 2 || got[0][0].ID != want[0].ID || got[0][0].Address != want[0].Address || got[0][1].Address != want[1].Address {
+		t.Errorf("instances = %+v, want %+v", got[0], want)
+	}
+	if len(got[1]) != 0 {
+		t.Errorf("instances after DELETED = %+v, want none", got[1])
+	}
+}
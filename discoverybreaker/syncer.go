@@ -0,0 +1,189 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package discoverybreaker 监听服务发现（Consul 目录、Kubernetes Endpoints），
+// 随后端实例的上下线在注册表中预先创建、移除每个实例的熔断器
+package discoverybreaker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Instance 服务的一个后端实例
+type Instance struct {
+	// ID 实例标识，为空时使用 Address
+	ID string
+	// Address 实例地址，通常为 "host:port"
+	Address string
+	// Meta 服务发现附带的元数据
+	Meta map[string]string
+}
+
+// key 返回实例标识
+func (i Instance) key() string {
+	if i.ID != "" {
+		return i.ID
+	}
+	return i.Address
+}
+
+// Source 服务发现数据源
+type Source interface {
+	// Watch 持续监听 service 的实例列表，每次变化时以完整列表调用 update，直至 ctx 结束或出错
+	Watch(ctx context.Context, service string, update func([]Instance)) error
+}
+
+// SourceFunc 函数形式的 Source
+type SourceFunc func(ctx context.Context, service string, update func([]Instance)) error
+
+// Watch 调用 f
+func (f SourceFunc) Watch(ctx context.Context, service string, update func([]Instance)) error {
+	return f(ctx, service, update)
+}
+
+// Option 同步器配置项
+type Option func(*Syncer)
+
+// WithNaming 设置实例熔断器的命名，默认 "<服务名>/<实例标识>"
+func WithNaming(fn func(service string, inst Instance) string) Option {
+	return func(s *Syncer) { s.naming = fn }
+}
+
+// WithRetryInterval 设置 Watch 出错后的重试间隔，默认 5 秒
+func WithRetryInterval(d time.Duration) Option {
+	return func(s *Syncer) { s.retry = d }
+}
+
+// WithErrorHandler 设置 Watch 出错时的回调，默认忽略
+func WithErrorHandler(fn func(error)) Option {
+	return func(s *Syncer) { s.onError = fn }
+}
+
+// Syncer 将服务发现的实例列表同步为注册表中的熔断器：
+// 新实例上线时创建熔断器，实例下线时移除，只管理自己创建的熔断器
+type Syncer struct {
+	registry *circuitbreaker.Registry
+	service  string
+	source   Source
+	settings circuitbreaker.Settings
+	naming   func(service string, inst Instance) string
+	retry    time.Duration
+	onError  func(error)
+
+	mu        sync.RWMutex
+	instances map[string]Instance
+	breakers  map[string]*circuitbreaker.CircuitBreaker
+}
+
+// New 创建同步器，新建的熔断器使用 settings
+func New(r *circuitbreaker.Registry, service string, source Source, settings circuitbreaker.Settings, opts ...Option) *Syncer {
+	s := &Syncer{
+		registry:  r,
+		service:   service,
+		source:    source,
+		settings:  settings,
+		naming:    DefaultNaming,
+		retry:     5 * time.Second,
+		instances: make(map[string]Instance),
+		breakers:  make(map[string]*circuitbreaker.CircuitBreaker),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.retry <= 0 {
+		s.retry = 5 * time.Second
+	}
+	return s
+}
+
+// DefaultNaming 默认命名："<服务名>/<实例标识>"
+func DefaultNaming(service string, inst Instance) string {
+	return service + "/" + inst.key()
+}
+
+// Run 监听数据源并同步，出错后按重试间隔重新监听，直至 ctx 结束；
+// ctx 结束时保留已创建的熔断器，需要时调用 Close 移除
+func (s *Syncer) Run(ctx context.Context) {
+	for {
+		err := s.source.Watch(ctx, s.service, s.Sync)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && s.onError != nil {
+			s.onError(err)
+		}
+		timer := time.NewTimer(s.retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Sync 按完整的实例列表同步：为新实例创建熔断器，移除已下线实例的熔断器
+func (s *Syncer) Sync(instances []Instance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		key := inst.key()
+		seen[key] = true
+		s.instances[key] = inst
+		if _, ok := s.breakers[key]; !ok {
+			s.breakers[key] = s.registry.GetOrCreate(s.naming(s.service, inst), s.settings)
+		}
+	}
+	for key, cb := range s.breakers {
+		if !seen[key] {
+			s.registry.Remove(cb.Name())
+			delete(s.breakers, key)
+			delete(s.instances, key)
+		}
+	}
+}
+
+// Close 移除所有由本同步器创建的熔断器
+func (s *Syncer) Close() {
+	s.Sync(nil)
+}
+
+// Breaker 返回实例标识对应的熔断器
+func (s *Syncer) Breaker(id string) (*circuitbreaker.CircuitBreaker, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cb, ok := s.breakers[id]
+	return cb, ok
+}
+
+// Instances 返回当前实例列表（按标识排序）
+func (s *Syncer) Instances() []Instance {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Instance, 0, len(s.instances))
+	for _, inst := range s.instances {
+		out = append(out, inst)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].key() < out[j].key() })
+	return out
+}
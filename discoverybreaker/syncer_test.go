@@ -0,0 +1,92 @@
+// Copyright 2025 zampo.
+
+package discoverybreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestSyncer_CreatesAndRemovesBreakers(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	s := New(r, "payments", nil, circuitbreaker.DefaultSettings())
+
+	s.Sync([]Instance{{ID: "a", Address: "10.0.0.1:80"}, {Address: "10.0.0.2:80"}})
+	if got, want := r.Names(), []string{"payments/10.0.0.2:80", "payments/a"}; !equal(got, want) {
+		t.Fatalf("Names() = %v, want %v", got, want)
+	}
+	cb, ok := s.Breaker("a")
+	if !ok {
+		t.Fatal("Breaker(a) not found")
+	}
+
+	s.Sync([]Instance{{ID: "a", Address: "10.0.0.1:80"}, {ID: "c", Address: "10.0.0.3:80"}})
+	if got, want := r.Names(), []string{"payments/a", "payments/c"}; !equal(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if again, _ := s.Breaker("a"); again != cb {
+		t.Error("Sync() recreated the breaker of a surviving instance")
+	}
+	if got := len(s.Instances()); got != 2 {
+		t.Errorf("len(Instances()) = %v, want %v", got, 2)
+	}
+
+	s.Close()
+	if got := r.Names(); len(got) != 0 {
+		t.Errorf("Names() after Close = %v, want none", got)
+	}
+}
+
+func TestSyncer_RunRetriesWatch(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	calls := 0
+	errs := make(chan error, 1)
+	source := SourceFunc(func(ctx context.Context, service string, update func([]Instance)) error {
+		calls++
+		if calls == 1 {
+			return errors.New("unavailable")
+		}
+		update([]Instance{{Address: "10.0.0.1:80"}})
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s := New(r, "payments", source, circuitbreaker.DefaultSettings(),
+		WithRetryInterval(time.Millisecond),
+		WithErrorHandler(func(err error) { errs <- err }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	if err := <-errs; err == nil {
+		t.Error("error handler not called")
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(r.Names()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if _, ok := r.Get("payments/10.0.0.1:80"); !ok {
+		t.Errorf("Names() = %v, want payments/10.0.0.1:80", r.Names())
+	}
+	cancel()
+	<-done
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package dnsbreaker 提供按域名服务器熔断的 DNS 解析器
+package dnsbreaker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// ErrNoNameservers 没有可用的域名服务器（列表为空或全部熔断）
+var ErrNoNameservers = errors.New("dnsbreaker: no nameservers available")
+
+// Resolver 每个域名服务器独立熔断的解析器
+// 解析时按配置顺序选择第一个熔断器放行的服务器，熔断打开的服务器被直接跳过，
+// 避免解析器故障在拨号阶段造成长时间同步阻塞
+type Resolver struct {
+	servers  []string
+	breakers []*circuitbreaker.CircuitBreaker
+	dialer   net.Dialer
+	resolver *net.Resolver
+}
+
+// NewResolver 创建解析器，servers 为 "host:port" 形式的域名服务器地址，
+// 熔断器命名为 "dns:<地址>"
+func NewResolver(servers []string, settings circuitbreaker.Settings) *Resolver {
+	r := &Resolver{
+		servers:  servers,
+		breakers: make([]*circuitbreaker.CircuitBreaker, 0, len(servers)),
+	}
+	for _, server := range servers {
+		r.breakers = append(r.breakers, circuitbreaker.NewCircuitBreaker("dns:"+server, settings))
+	}
+	r.resolver = &net.Resolver{PreferGo: true, Dial: r.DialContext}
+	return r
+}
+
+// Resolver 返回使用本解析器拨号的 *net.Resolver，可赋给 net.Dialer.Resolver
+func (r *Resolver) Resolver() *net.Resolver {
+	return r.resolver
+}
+
+// Breakers 返回各域名服务器的熔断器（按配置顺序）
+func (r *Resolver) Breakers() []*circuitbreaker.CircuitBreaker {
+	return r.breakers
+}
+
+// DialContext 连接第一个可用的域名服务器，忽略 address（系统配置的服务器）
+// 查询结果在连接的首次读取时计入熔断：读到响应为成功，读取错误或超时为失败
+func (r *Resolver) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	lastErr := ErrNoNameservers
+	for i, server := range r.servers {
+		done, err := r.breakers[i].AllowContext(ctx)
+		if err != nil {
+			if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+				continue
+			}
+			return nil, err
+		}
+
+		c, err := r.dialer.DialContext(ctx, network, server)
+		if err != nil {
+			done(err)
+			lastErr = err
+			continue
+		}
+		return wrapConn(ctx, c, done), nil
+	}
+	return nil, lastErr
+}
+
+// conn 在首次读写结果确定时上报熔断结果
+type conn struct {
+	net.Conn
+	ctx  context.Context
+	once sync.Once
+	done func(err error)
+}
+
+func wrapConn(ctx context.Context, c net.Conn, done func(err error)) net.Conn {
+	wrapped := &conn{Conn: c, ctx: ctx, done: done}
+	// Go 解析器依据是否实现 net.PacketConn 决定 UDP/TCP 报文格式
+	if pc, ok := c.(net.PacketConn); ok {
+		return &packetConn{conn: wrapped, pc: pc}
+	}
+	return wrapped
+}
+
+func (c *conn) report(err error) {
+	c.once.Do(func() { c.done(err) })
+}
+
+// Read 实现 net.Conn
+func (c *conn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.report(err)
+	return n, err
+}
+
+// Write 实现 net.Conn
+func (c *conn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if err != nil {
+		c.report(err)
+	}
+	return n, err
+}
+
+// Close 实现 net.Conn，未产生结果的连接按调用方 ctx 状态上报
+func (c *conn) Close() error {
+	c.report(c.ctx.Err())
+	return c.Conn.Close()
+}
+
+// packetConn UDP 连接的包装，保留 net.PacketConn 接口
+type packetConn struct {
+	*conn
+	pc net.PacketConn
+}
+
+// ReadFrom 实现 net.PacketConn
+func (c *packetConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, addr, err := c.pc.ReadFrom(b)
+	c.report(err)
+	return n, addr, err
+}
+
+// WriteTo 实现 net.PacketConn
+func (c *packetConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	n, err := c.pc.WriteTo(b, addr)
+	if err != nil {
+		c.report(err)
+	}
+	return n, err
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 zampo.
+
+package dnsbreaker
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// fakeDNS 对 A 查询返回 127.0.0.1，其他查询返回空应答
+func fakeDNS(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			q := buf[:n]
+			end := 12
+			for end < n && q[end] != 0 {
+				end += int(q[end]) + 1
+			}
+			end += 5 // 根标签 + QTYPE + QCLASS
+			if end > n {
+				continue
+			}
+
+			resp := append([]byte(nil), q[:end]...)
+			resp[2] |= 0x80 // QR
+			binary.BigEndian.PutUint16(resp[10:], 0)
+			if binary.BigEndian.Uint16(q[end-4:]) == 1 {
+				binary.BigEndian.PutUint16(resp[6:], 1)
+				resp = append(resp, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 127, 0, 0, 1)
+			} else {
+				binary.BigEndian.PutUint16(resp[6:], 0)
+			}
+			pc.WriteTo(resp, addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestResolver_SkipsOpenNameserver(t *testing.T) {
+	good := fakeDNS(t)
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	r := NewResolver([]string{"127.0.0.1:1", good}, settings)
+	r.Breakers()[0].RecordFailure()
+
+	addrs, err := r.Resolver().LookupHost(context.Background(), "example.test")
+	if err != nil {
+		t.Fatalf("LookupHost() error = %v", err)
+	}
+	if len(addrs) != 1 || addrs[0] != "127.0.0.1" {
+		t.Errorf("LookupHost() = %v, want [127.0.0.1]", addrs)
+	}
+	if got := r.Breakers()[1].Counts().TotalSuccesses; got == 0 {
+		t.Errorf("TotalSuccesses = %v, want > 0", got)
+	}
+}
+
+func TestResolver_AllOpen(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	r := NewResolver([]string{"127.0.0.1:1"}, settings)
+	r.Breakers()[0].RecordFailure()
+
+	if _, err := r.DialContext(context.Background(), "udp", ""); !errors.Is(err, ErrNoNameservers) {
+		t.Errorf("DialContext() error = %v, want %v", err, ErrNoNameservers)
+	}
+}
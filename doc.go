@@ -0,0 +1,24 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package circuitbreaker 基于 gobreaker 的熔断器，核心包只依赖 gobreaker 与标准库。
+//
+// 协议适配、指标上报与通知等可选集成位于各自的子包（如 cloudwatchbreaker、webhookbreaker），
+// 不引用即不会链接；依赖较重的部分（cbpb、httpadmin、grpcbreaker、gossipbreaker、failsafebreaker、k8sbreaker）是独立的嵌套模块，不进入核心模块的依赖图。核心包内对外暴露网络接口的部分（MeshHandler 网格状态、
+// ConfigClient/ConfigServer 控制面）可通过构建标签去除，嵌入 CLI 与小工具时不链接 net/http：
+//
+//	go build -tags cbnotelemetry
+package circuitbreaker
@@ -0,0 +1,206 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errgroupbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// ErrQuorumNotMet 成功的目标数未达到 Quorum 要求
+var ErrQuorumNotMet = errors.New("errgroupbreaker: quorum not met")
+
+// Quorum FanOut 的结果合并策略，决定需要多少个目标成功
+type Quorum int
+
+const (
+	// FirstSuccess 任一目标成功即返回，取消其余目标
+	FirstSuccess Quorum = iota
+	// Majority 超过半数目标（含被跳过的目标）成功即返回
+	Majority
+	// All 所有目标都需成功，任一失败或被跳过即返回错误
+	All
+)
+
+// String 返回策略名称
+func (q Quorum) String() string {
+	switch q {
+	case FirstSuccess:
+		return "first-success"
+	case Majority:
+		return "majority"
+	case All:
+		return "all"
+	default:
+		return fmt.Sprintf("Quorum(%d)", int(q))
+	}
+}
+
+// required 返回 n 个目标时需要的成功数
+func (q Quorum) required(n int) int {
+	switch q {
+	case Majority:
+		return n/2 + 1
+	case All:
+		return n
+	default:
+		return min(1, n)
+	}
+}
+
+// Target FanOut 的一个目标，如一个区域的副本
+type Target[T any] struct {
+	// Breaker 目标的熔断器，打开时跳过该目标
+	Breaker circuitbreaker.Executor
+	// Call 对目标发起的调用，ctx 在达到或无法达到 Quorum 时取消
+	Call func(ctx context.Context) (T, error)
+}
+
+// Result 单个目标的结果，与 targets 按下标对应
+type Result[T any] struct {
+	// Name 目标熔断器名称
+	Name string
+	// Value 调用成功时的返回值
+	Value T
+	// Err 调用错误或拒绝错误；因提前返回而未发起的目标为 nil 且 Done 为 false
+	Err error
+	// Reason 被熔断器拒绝（跳过）时的原因
+	Reason circuitbreaker.Reason
+	// Done 调用已实际执行并返回
+	Done bool
+}
+
+// OK 调用是否成功
+func (r Result[T]) OK() bool {
+	return r.Done && r.Err == nil
+}
+
+// Results FanOut 的全部结果
+type Results[T any] []Result[T]
+
+// Values 返回成功目标的返回值（按目标顺序）
+func (rs Results[T]) Values() []T {
+	var out []T
+	for _, r := range rs {
+		if r.OK() {
+			out = append(out, r.Value)
+		}
+	}
+	return out
+}
+
+// FanOut 并发调用多个目标（最多 limit 个同时执行，limit <= 0 不限制），每个调用经目标自身的熔断器准入并计入其统计；
+// 熔断打开的目标直接跳过。达到 quorum 要求的成功数后取消其余调用并返回 nil，
+// 成功数已不可能达到时取消其余调用并返回包装了 ErrQuorumNotMet 与各目标错误的错误。
+// 返回前等待所有已发起的调用结束，被取消的调用默认不计为失败（见 Settings.CountCallerCancellation）
+func FanOut[T any](ctx context.Context, targets []Target[T], limit int, quorum Quorum) (Results[T], error) {
+	results := make(Results[T], len(targets))
+	for i, t := range targets {
+		results[i].Name = t.Breaker.Name()
+	}
+	required := quorum.required(len(targets))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu                sync.Mutex
+		wg                sync.WaitGroup
+		succeeded, failed int
+		sem               chan struct{}
+	)
+	if limit > 0 {
+		sem = make(chan struct{}, limit)
+	}
+	// settle 记录结果，达到或无法达到 quorum 时取消其余调用，返回是否已有定论
+	settle := func(i int, r Result[T]) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = r
+		if r.OK() {
+			succeeded++
+		} else {
+			failed++
+		}
+		if succeeded >= required || failed > len(targets)-required {
+			cancel()
+			return true
+		}
+		return false
+	}
+
+launch:
+	for i, t := range targets {
+		name := results[i].Name
+		if t.Breaker.State() == gobreaker.StateOpen {
+			rejection := &circuitbreaker.RejectionError{Breaker: name, Reason: circuitbreaker.ReasonOpen, Err: gobreaker.ErrOpenState}
+			if settle(i, Result[T]{Name: name, Err: rejection, Reason: circuitbreaker.ReasonOpen}) {
+				break
+			}
+			continue
+		}
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				break launch
+			}
+		}
+		if ctx.Err() != nil {
+			if sem != nil {
+				<-sem
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, t Target[T]) {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			v, err := t.Breaker.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+				return t.Call(ctx)
+			})
+			r := Result[T]{Name: name, Err: err, Reason: circuitbreaker.ReasonOf(err)}
+			if r.Reason == circuitbreaker.ReasonNone {
+				r.Value, _ = v.(T)
+				r.Done = true
+			}
+			settle(i, r)
+		}(i, t)
+	}
+	wg.Wait()
+
+	if succeeded >= required {
+		return results, nil
+	}
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Name, r.Err))
+		}
+	}
+	return results, fmt.Errorf("%w: %d of %d targets succeeded, %s requires %d: %w",
+		ErrQuorumNotMet, succeeded, len(targets), quorum, required, errors.Join(errs...))
+}
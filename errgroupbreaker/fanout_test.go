@@ -0,0 +1,110 @@
+// Copyright 2025 zampo.
+
+package errgroupbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func region(name string, value string, err error, delay time.Duration) Target[string] {
+	return Target[string]{
+		Breaker: circuitbreaker.NewCircuitBreaker(name, circuitbreaker.DefaultSettings()),
+		Call: func(ctx context.Context) (string, error) {
+			select {
+			case <-time.After(delay):
+				return value, err
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		},
+	}
+}
+
+func TestFanOut_FirstSuccessCancelsRest(t *testing.T) {
+	slow := region("eu", "eu", nil, time.Minute)
+	results, err := FanOut(context.Background(), []Target[string]{
+		region("us", "us", nil, 0),
+		slow,
+	}, 0, FirstSuccess)
+	if err != nil {
+		t.Fatalf("FanOut() error = %v", err)
+	}
+	if got := results.Values(); len(got) != 1 || got[0] != "us" {
+		t.Errorf("Values() = %v, want [us]", got)
+	}
+	if !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("slow target error = %v, want canceled", results[1].Err)
+	}
+	if got := slow.Breaker.Stats().Counts.TotalFailures; got != 0 {
+		t.Errorf("slow TotalFailures = %v, want cancellation not counted", got)
+	}
+}
+
+func TestFanOut_MajoritySkipsOpenTargets(t *testing.T) {
+	down := Target[string]{Breaker: openBreaker("ap"), Call: func(ctx context.Context) (string, error) {
+		t.Error("open target should be skipped")
+		return "", nil
+	}}
+	targets := []Target[string]{region("us", "us", nil, 0), region("eu", "eu", nil, 0), down}
+
+	results, err := FanOut(context.Background(), targets, 1, Majority)
+	if err != nil {
+		t.Fatalf("FanOut() error = %v", err)
+	}
+	if len(results.Values()) != 2 {
+		t.Errorf("Values() = %v, want two", results.Values())
+	}
+	if results[2].Reason != circuitbreaker.ReasonOpen || results[2].Done {
+		t.Errorf("open target result = %+v, want skipped", results[2])
+	}
+}
+
+func TestFanOut_AllFailsFast(t *testing.T) {
+	boom := errors.New("boom")
+	results, err := FanOut(context.Background(), []Target[string]{
+		region("us", "", boom, 0),
+		region("eu", "eu", nil, time.Minute),
+	}, 0, All)
+	if !errors.Is(err, ErrQuorumNotMet) || !errors.Is(err, boom) {
+		t.Fatalf("FanOut() error = %v, want quorum error wrapping boom", err)
+	}
+	if results[0].OK() || results[1].OK() {
+		t.Errorf("results = %+v, want no success", results)
+	}
+}
+
+func TestFanOut_Limit(t *testing.T) {
+	var running, peak atomic.Int32
+	targets := make([]Target[int], 6)
+	for i := range targets {
+		targets[i] = Target[int]{
+			Breaker: circuitbreaker.NewCircuitBreaker(fmt.Sprint("t", i), circuitbreaker.DefaultSettings()),
+			Call: func(ctx context.Context) (int, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+				return i, nil
+			},
+		}
+	}
+	results, err := FanOut(context.Background(), targets, 2, All)
+	if err != nil || len(results.Values()) != 6 {
+		t.Fatalf("FanOut() = %v, %v, want six values", results.Values(), err)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("peak concurrency = %v, want <= 2", peak.Load())
+	}
+}
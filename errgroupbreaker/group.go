@@ -0,0 +1,105 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package errgroupbreaker 提供 errgroup 语义的并发任务组与按 Quorum 合并结果的 FanOut，每个任务经熔断器准入：
+// 依赖熔断打开的任务被跳过，而不是让整个任务组失败
+package errgroupbreaker
+
+import (
+	"context"
+	"sync"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Skip 被熔断器拒绝而跳过的任务
+type Skip struct {
+	// Breaker 拒绝任务的熔断器名称
+	Breaker string
+	// Reason 拒绝原因
+	Reason circuitbreaker.Reason
+	// Err 拒绝错误
+	Err error
+}
+
+// Group 并发任务组，语义与 golang.org/x/sync/errgroup 一致：
+// 第一个任务错误会取消组 ctx 并由 Wait 返回；被熔断器拒绝的任务只记录在 Skipped 中
+// 零值可用，此时任务使用 context.Background()
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+
+	mu      sync.Mutex
+	skipped []Skip
+}
+
+// WithContext 创建任务组，返回的 ctx 在首个任务失败或 Wait 返回时取消
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{ctx: ctx, cancel: cancel}, ctx
+}
+
+// Go 在新协程中经 cb 执行 fn，fn 收到的 ctx 派生自组 ctx 并带有熔断器的调用超时
+func (g *Group) Go(cb circuitbreaker.Executor, fn func(ctx context.Context) error) {
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+			return nil, fn(ctx)
+		})
+		if err == nil {
+			return
+		}
+		if reason := circuitbreaker.ReasonOf(err); reason != circuitbreaker.ReasonNone {
+			g.mu.Lock()
+			g.skipped = append(g.skipped, Skip{Breaker: cb.Name(), Reason: reason, Err: err})
+			g.mu.Unlock()
+			return
+		}
+		g.errOnce.Do(func() {
+			g.err = err
+			if g.cancel != nil {
+				g.cancel(err)
+			}
+		})
+	}()
+}
+
+// Wait 等待所有任务结束，返回第一个非拒绝错误
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
+
+// Skipped 返回被熔断器拒绝而跳过的任务，应在 Wait 返回后调用
+func (g *Group) Skipped() []Skip {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Skip(nil), g.skipped...)
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 zampo.
+
+package errgroupbreaker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func openBreaker(name string) *circuitbreaker.CircuitBreaker {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	cb := circuitbreaker.NewCircuitBreaker(name, settings)
+	cb.RecordFailure()
+	return cb
+}
+
+func TestGroup_SkipsOpenBreakers(t *testing.T) {
+	healthy := circuitbreaker.NewCircuitBreaker("healthy", circuitbreaker.DefaultSettings())
+	down := openBreaker("down")
+
+	g, _ := WithContext(context.Background())
+	var ran atomic.Int32
+	for i := 0; i < 3; i++ {
+		g.Go(healthy, func(ctx context.Context) error {
+			ran.Add(1)
+			return nil
+		})
+	}
+	g.Go(down, func(ctx context.Context) error {
+		t.Error("task behind an open breaker should not run")
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if ran.Load() != 3 {
+		t.Errorf("ran = %v, want 3", ran.Load())
+	}
+	skipped := g.Skipped()
+	if len(skipped) != 1 || skipped[0].Breaker != "down" || skipped[0].Reason != circuitbreaker.ReasonOpen {
+		t.Errorf("Skipped() = %+v, want one down/OPEN", skipped)
+	}
+}
+
+func TestGroup_FirstErrorCancels(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+	boom := errors.New("boom")
+
+	g, ctx := WithContext(context.Background())
+	g.Go(cb, func(ctx context.Context) error {
+		return boom
+	})
+	g.Go(cb, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err := g.Wait(); !errors.Is(err, boom) {
+		t.Errorf("Wait() error = %v, want %v", err, boom)
+	}
+	if !errors.Is(context.Cause(ctx), boom) {
+		t.Errorf("Cause() = %v, want %v", context.Cause(ctx), boom)
+	}
+}
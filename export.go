@@ -0,0 +1,160 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// exportVersion 导出格式版本
+const exportVersion = 1
+
+// defaultOpenTimeout gobreaker 在 Timeout 为 0 时使用的打开时长
+const defaultOpenTimeout = 60 * time.Second
+
+// exportedRegistry 注册表导出格式
+type exportedRegistry struct {
+	Version int `json:"version"`
+	// ExportedAt 导出方的时钟时间，用于导入方估计时钟偏差
+	ExportedAt time.Time         `json:"exported_at,omitempty"`
+	Breakers   []exportedBreaker `json:"breakers"`
+}
+
+// exportedBreaker 单个熔断器的导出状态
+type exportedBreaker struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// OpenUntil 打开状态预计结束的时间（导出方时钟）
+	OpenUntil time.Time `json:"open_until,omitempty"`
+	// RemainingMs 导出时打开状态的剩余毫秒数，与时钟无关，导入时优先使用
+	RemainingMs int64 `json:"remaining_ms,omitempty"`
+}
+
+// ImportOptions 导入时对时钟偏差的容忍配置
+type ImportOptions struct {
+	// MaxHold 导入的打开状态最长保持时间，超过时截断，默认 10 分钟；
+	// 防止时钟偏快的实例导出的截止时间使整个集群的熔断器长期无法恢复
+	MaxHold time.Duration
+	// MaxClockSkew 仅有绝对时间（旧版导出）时可容忍的时钟偏差，默认 5 秒；
+	// 导出方与本地时钟相差超过该值时，按导出方自身时钟换算剩余时长
+	MaxClockSkew time.Duration
+}
+
+// DefaultImportOptions 返回默认导入配置
+func DefaultImportOptions() ImportOptions {
+	return ImportOptions{MaxHold: 10 * time.Minute, MaxClockSkew: 5 * time.Second}
+}
+
+// Export 导出所有熔断器的状态（按名称排序），供蓝绿发布时新实例通过 Import 继承，
+// 避免新实例上线后立即向已知故障的依赖发送大量流量
+func (r *Registry) Export() []byte {
+	names := r.Names()
+	now := time.Now()
+	out := exportedRegistry{
+		Version:    exportVersion,
+		ExportedAt: now.UTC(),
+		Breakers:   make([]exportedBreaker, 0, len(names)),
+	}
+	for _, name := range names {
+		cb, ok := r.Get(name)
+		if !ok {
+			continue
+		}
+		state, until := cb.openState()
+		entry := exportedBreaker{Name: name, State: state.String()}
+		if state == gobreaker.StateOpen {
+			entry.OpenUntil = until.UTC()
+			entry.RemainingMs = until.Sub(now).Milliseconds()
+		}
+		out.Breakers = append(out.Breakers, entry)
+	}
+
+	data, _ := json.Marshal(out)
+	return data
+}
+
+// Import 使用 DefaultImportOptions 导入 Export 的结果，见 ImportWithOptions
+func (r *Registry) Import(data []byte) error {
+	return r.ImportWithOptions(data, DefaultImportOptions())
+}
+
+// ImportWithOptions 导入 Export 的结果：对已注册的同名熔断器，原处于打开状态的保持打开至原定结束，
+// 未注册的熔断器与已过期的打开状态被忽略
+// 剩余时长按导出时的相对值计算，不依赖两端时钟一致，并以 MaxHold 为上限
+func (r *Registry) ImportWithOptions(data []byte, opts ImportOptions) error {
+	var in exportedRegistry
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("circuitbreaker: import: %w", err)
+	}
+	if in.Version != exportVersion {
+		return fmt.Errorf("circuitbreaker: import: unsupported version %d", in.Version)
+	}
+	if opts.MaxHold <= 0 {
+		opts.MaxHold = DefaultImportOptions().MaxHold
+	}
+	if opts.MaxClockSkew <= 0 {
+		opts.MaxClockSkew = DefaultImportOptions().MaxClockSkew
+	}
+
+	now := time.Now()
+	for _, entry := range in.Breakers {
+		cb, ok := r.Get(entry.Name)
+		if !ok || entry.State != gobreaker.StateOpen.String() {
+			continue
+		}
+		cb.OpenFor(min(in.remaining(entry, now, opts.MaxClockSkew), opts.MaxHold))
+	}
+	return nil
+}
+
+// remaining 计算导入后的剩余打开时长
+func (in exportedRegistry) remaining(entry exportedBreaker, now time.Time, maxSkew time.Duration) time.Duration {
+	if entry.RemainingMs > 0 {
+		return time.Duration(entry.RemainingMs) * time.Millisecond
+	}
+	if skew := now.Sub(in.ExportedAt); !in.ExportedAt.IsZero() && (skew > maxSkew || skew < -maxSkew) {
+		// 时钟偏差超出容忍范围，按导出方自身的时钟换算
+		return entry.OpenUntil.Sub(in.ExportedAt)
+	}
+	return entry.OpenUntil.Sub(now)
+}
+
+// openState 返回当前状态及打开状态预计结束的时间（OpenFor 保持期、强制打开租约与底层打开期取较晚者）
+func (cb *CircuitBreaker) openState() (gobreaker.State, time.Time) {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return cb.openStateLocked()
+}
+
+// openStateLocked 与 openState 相同，调用方需持有读锁
+func (cb *CircuitBreaker) openStateLocked() (gobreaker.State, time.Time) {
+	var until time.Time
+	if cb.timeoutHeld() || cb.pendingHalfOpen.Load() || cb.cb.State() == gobreaker.StateOpen {
+		until = time.Unix(0, cb.openedAt.Load()).Add(cb.settings.openTimeout())
+	}
+	if held := cb.OpenUntil(); held.After(until) {
+		until = held
+	}
+	if leased := cb.leaseUntil(); leased.After(until) {
+		until = leased
+	}
+	return cb.state(), until
+}
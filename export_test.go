@@ -0,0 +1,106 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestRegistry_ExportImport(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Timeout = time.Minute
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+
+	old := NewRegistry()
+	old.GetOrCreate("inventory", settings)
+	old.GetOrCreate("payments", settings).Execute(func() (interface{}, error) {
+		return nil, errors.New("fail")
+	})
+
+	data := old.Export()
+	var exported exportedRegistry
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Export() is not valid JSON: %v", err)
+	}
+	if len(exported.Breakers) != 2 || exported.Breakers[0].Name != "inventory" || exported.Breakers[1].Name != "payments" {
+		t.Errorf("Export() breakers = %+v, want sorted by name", exported.Breakers)
+	}
+
+	next := NewRegistry()
+	next.GetOrCreate("inventory", settings)
+	next.GetOrCreate("payments", settings)
+	if err := next.Import(data); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	payments, _ := next.Get("payments")
+	if got := payments.State(); got != gobreaker.StateOpen {
+		t.Errorf("payments State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+	if until := time.Until(payments.OpenUntil()); until <= 50*time.Second || until > time.Minute {
+		t.Errorf("payments OpenUntil() in %v, want about %v", until, time.Minute)
+	}
+	inventory, _ := next.Get("inventory")
+	if got := inventory.State(); got != gobreaker.StateClosed {
+		t.Errorf("inventory State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+}
+
+func TestRegistry_ImportInvalid(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Import([]byte("not json")); err == nil {
+		t.Error("Import() should fail on invalid data")
+	}
+	if err := r.Import([]byte(`{"version":99}`)); err == nil {
+		t.Error("Import() should fail on unsupported version")
+	}
+}
+
+func TestRegistry_ImportClockSkew(t *testing.T) {
+	settings := DefaultSettings()
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name string
+		data string
+		want time.Duration
+	}{
+		{
+			name: "relative remaining ignores skewed absolute time",
+			data: fmt.Sprintf(`{"version":1,"exported_at":%q,"breakers":[{"name":"svc","state":"open","open_until":%q,"remaining_ms":30000}]}`,
+				now.Add(24*time.Hour).Format(time.RFC3339Nano), now.Add(24*time.Hour+30*time.Second).Format(time.RFC3339Nano)),
+			want: 30 * time.Second,
+		},
+		{
+			name: "absolute time from skewed exporter uses exporter clock",
+			data: fmt.Sprintf(`{"version":1,"exported_at":%q,"breakers":[{"name":"svc","state":"open","open_until":%q}]}`,
+				now.Add(time.Hour).Format(time.RFC3339Nano), now.Add(time.Hour+20*time.Second).Format(time.RFC3339Nano)),
+			want: 20 * time.Second,
+		},
+		{
+			name: "hold capped by MaxHold",
+			data: `{"version":1,"breakers":[{"name":"svc","state":"open","remaining_ms":86400000}]}`,
+			want: DefaultImportOptions().MaxHold,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			cb := r.GetOrCreate("svc", settings)
+			if err := r.Import([]byte(tt.data)); err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if got := time.Until(cb.OpenUntil()); got > tt.want || got < tt.want-5*time.Second {
+				t.Errorf("held for %v, want about %v", got, tt.want)
+			}
+		})
+	}
+}
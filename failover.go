@@ -0,0 +1,149 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrFailoverExhausted 所有故障转移目标都被拒绝或失败
+var ErrFailoverExhausted = errors.New("circuitbreaker: all failover targets failed")
+
+// FailoverStep 故障转移的一个目标，如一个区域
+type FailoverStep struct {
+	// Breaker 目标的熔断器，拒绝时转移到下一个目标
+	Breaker Executor
+	// Call 对目标发起的调用
+	Call func(ctx context.Context) (interface{}, error)
+}
+
+// FailoverOptions 故障转移配置
+type FailoverOptions struct {
+	// Retryable 判断调用错误是否应转移到下一个目标，为空时除调用方 ctx 结束外的所有错误都转移；
+	// 熔断器拒绝总是转移
+	Retryable func(err error) bool
+	// OnFailover 从 from 转移到 to 时调用，err 为 from 的拒绝或调用错误，可用于上报指标或日志
+	OnFailover func(from, to string, err error)
+}
+
+// FailoverStats 故障转移统计，自创建起累计
+type FailoverStats struct {
+	// Calls Do 的调用次数
+	Calls uint64
+	// Failovers 转移到下一个目标的次数
+	Failovers uint64
+	// Exhausted 所有目标都失败的次数
+	Exhausted uint64
+	// ServedBy 按最终成功的目标统计的调用数
+	ServedBy map[string]uint64
+	// FailoversFrom 按被转移出的目标统计的次数
+	FailoversFrom map[string]uint64
+}
+
+// Failover 按顺序尝试一组目标的故障转移编排：目标熔断打开或调用以可重试错误失败时转移到下一个，
+// 并统计转移频率；同一个 Failover 可被并发使用，每次调用传入本次请求的目标列表
+type Failover struct {
+	opts FailoverOptions
+
+	calls     atomic.Uint64
+	failovers atomic.Uint64
+	exhausted atomic.Uint64
+
+	mu      sync.Mutex
+	served  map[string]uint64
+	movedBy map[string]uint64
+}
+
+// NewFailover 创建故障转移编排
+func NewFailover(opts FailoverOptions) *Failover {
+	return &Failover{
+		opts:    opts,
+		served:  make(map[string]uint64),
+		movedBy: make(map[string]uint64),
+	}
+}
+
+// Do 按顺序尝试 steps，返回第一个成功目标的结果；调用以不可重试错误失败时直接返回该错误，
+// 调用方 ctx 结束时返回 ctx 的错误；所有目标都失败时返回包装了 ErrFailoverExhausted 与各目标错误的错误
+func (f *Failover) Do(ctx context.Context, steps ...FailoverStep) (interface{}, error) {
+	f.calls.Add(1)
+	var errs []error
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		name := step.Breaker.Name()
+		v, err := step.Breaker.ExecuteContext(ctx, step.Call)
+		if err == nil {
+			f.mu.Lock()
+			f.served[name]++
+			f.mu.Unlock()
+			return v, nil
+		}
+		if ReasonOf(err) == ReasonNone && !f.retryable(ctx, err) {
+			return v, err
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		if i+1 < len(steps) {
+			f.failovers.Add(1)
+			f.mu.Lock()
+			f.movedBy[name]++
+			f.mu.Unlock()
+			if f.opts.OnFailover != nil {
+				f.opts.OnFailover(name, steps[i+1].Breaker.Name(), err)
+			}
+		}
+	}
+	f.exhausted.Add(1)
+	return nil, fmt.Errorf("%w: %w", ErrFailoverExhausted, errors.Join(errs...))
+}
+
+// retryable 判断调用错误是否转移，调用方 ctx 已结束时不转移
+func (f *Failover) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if f.opts.Retryable != nil {
+		return f.opts.Retryable(err)
+	}
+	return true
+}
+
+// Stats 返回统计快照
+func (f *Failover) Stats() FailoverStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stats := FailoverStats{
+		Calls:         f.calls.Load(),
+		Failovers:     f.failovers.Load(),
+		Exhausted:     f.exhausted.Load(),
+		ServedBy:      make(map[string]uint64, len(f.served)),
+		FailoversFrom: make(map[string]uint64, len(f.movedBy)),
+	}
+	for name, n := range f.served {
+		stats.ServedBy[name] = n
+	}
+	for name, n := range f.movedBy {
+		stats.FailoversFrom[name] = n
+	}
+	return stats
+}
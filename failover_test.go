@@ -0,0 +1,76 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func failoverStep(cb *CircuitBreaker, v interface{}, err error) FailoverStep {
+	return FailoverStep{Breaker: cb, Call: func(ctx context.Context) (interface{}, error) { return v, err }}
+}
+
+func TestFailover_SkipsOpenAndFailedTargets(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	us := NewCircuitBreaker("us", settings)
+	us.RecordFailure()
+	eu := NewCircuitBreaker("eu", settings)
+	ap := NewCircuitBreaker("ap", settings)
+
+	var moves []string
+	f := NewFailover(FailoverOptions{OnFailover: func(from, to string, err error) { moves = append(moves, from+">"+to) }})
+	v, err := f.Do(context.Background(),
+		failoverStep(us, "us", nil),
+		failoverStep(eu, nil, errors.New("unavailable")),
+		failoverStep(ap, "ap", nil),
+	)
+	if v != "ap" || err != nil {
+		t.Fatalf("Do() = %v, %v, want ap", v, err)
+	}
+	if len(moves) != 2 || moves[0] != "us>eu" || moves[1] != "eu>ap" {
+		t.Errorf("moves = %v", moves)
+	}
+	if eu.State() != gobreaker.StateOpen {
+		t.Errorf("eu State() = %v, want failure recorded and breaker open", eu.State())
+	}
+	stats := f.Stats()
+	if stats.Calls != 1 || stats.Failovers != 2 || stats.ServedBy["ap"] != 1 || stats.FailoversFrom["us"] != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestFailover_NonRetryableStops(t *testing.T) {
+	notFound := errors.New("not found")
+	f := NewFailover(FailoverOptions{Retryable: func(err error) bool { return !errors.Is(err, notFound) }})
+	called := false
+	_, err := f.Do(context.Background(),
+		failoverStep(NewCircuitBreaker("us", DefaultSettings()), nil, notFound),
+		FailoverStep{Breaker: NewCircuitBreaker("eu", DefaultSettings()), Call: func(ctx context.Context) (interface{}, error) {
+			called = true
+			return nil, nil
+		}},
+	)
+	if err != notFound || called {
+		t.Errorf("Do() error = %v, called = %v, want not found without failover", err, called)
+	}
+}
+
+func TestFailover_Exhausted(t *testing.T) {
+	boom := errors.New("boom")
+	f := NewFailover(FailoverOptions{})
+	_, err := f.Do(context.Background(),
+		failoverStep(NewCircuitBreaker("us", DefaultSettings()), nil, boom),
+		failoverStep(NewCircuitBreaker("eu", DefaultSettings()), nil, boom),
+	)
+	if !errors.Is(err, ErrFailoverExhausted) || !errors.Is(err, boom) {
+		t.Errorf("Do() error = %v, want exhausted wrapping boom", err)
+	}
+	if stats := f.Stats(); stats.Exhausted != 1 || stats.Failovers != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package failsafebreaker 在熔断器与 failsafe-go 之间互相适配：
+// Policy 让熔断器作为 failsafe.Policy 与重试、超时等策略组合，
+// Get/Run 在已有的 failsafe.Executor 内层经熔断器执行，Breaker 让 failsafe 的熔断器作为 circuitbreaker.Executor 使用
+package failsafebreaker
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go"
+	fcb "github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"github.com/failsafe-go/failsafe-go/common"
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Policy 将熔断器适配为 failsafe.Policy，如 failsafe.With[R](retry, failsafebreaker.NewPolicy[R](cb))。
+// 每次经过该策略的执行都经 cb 准入并计入 cb 的统计，被拒绝时结果错误为 *circuitbreaker.RejectionError；
+// 内层策略与调用仍使用 failsafe 执行的 ctx，CallTimeout 到期时熔断器返回超时但不取消内层执行
+type Policy[R any] struct {
+	cb *circuitbreaker.CircuitBreaker
+}
+
+var _ failsafe.Policy[any] = (*Policy[any])(nil)
+
+// NewPolicy 创建熔断器策略
+func NewPolicy[R any](cb *circuitbreaker.CircuitBreaker) *Policy[R] {
+	return &Policy[R]{cb: cb}
+}
+
+// ToExecutor 实现 failsafe.Policy，返回的执行器由 failsafe 组合进策略链
+func (p *Policy[R]) ToExecutor(_ R) any {
+	return p
+}
+
+// Apply 由 failsafe 调用，返回经熔断器执行 innerFn 的函数；错误结果标记为失败，供外层策略与监听器判断
+func (p *Policy[R]) Apply(innerFn func(failsafe.Execution[R]) *common.PolicyResult[R]) func(failsafe.Execution[R]) *common.PolicyResult[R] {
+	return func(exec failsafe.Execution[R]) *common.PolicyResult[R] {
+		// CallTimeout 到期后 innerFn 可能仍在执行，结果经原子指针传递
+		var inner atomic.Pointer[common.PolicyResult[R]]
+		v, err := p.cb.ExecuteContext(exec.Context(), func(context.Context) (interface{}, error) {
+			er := innerFn(exec)
+			inner.Store(er)
+			return er.Result, er.Error
+		})
+		r, _ := v.(R)
+		if err != nil {
+			return &common.PolicyResult[R]{Result: r, Error: err, Done: true}
+		}
+		er := inner.Load()
+		return &common.PolicyResult[R]{Result: r, Done: true, Success: true, SuccessAll: er == nil || er.SuccessAll}
+	}
+}
+
+// Get 经 executor 的策略执行 fn，每次尝试（含重试）都经 cb 准入并计入 cb 的统计，
+// 即熔断器位于 failsafe 策略链的最内层；被熔断器拒绝的尝试返回 *circuitbreaker.RejectionError，
+// 可配合 Rejected 让重试策略不再重试
+func Get[R any](executor failsafe.Executor[R], cb *circuitbreaker.CircuitBreaker, fn func(ctx context.Context) (R, error)) (R, error) {
+	return executor.GetWithExecution(func(exec failsafe.Execution[R]) (R, error) {
+		v, err := cb.ExecuteContext(exec.Context(), func(ctx context.Context) (interface{}, error) {
+			return fn(ctx)
+		})
+		r, _ := v.(R)
+		return r, err
+	})
+}
+
+// Run 与 Get 相同，用于无返回值的调用
+func Run(executor failsafe.Executor[any], cb *circuitbreaker.CircuitBreaker, fn func(ctx context.Context) error) error {
+	_, err := Get(executor, cb, func(ctx context.Context) (any, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// Rejected 判断尝试是否被熔断器拒绝，签名与 failsafe 策略的 AbortIf/HandleIf 条件一致，
+// 如 retrypolicy.NewBuilder[R]().AbortIf(failsafebreaker.Rejected[R])
+func Rejected[R any](_ R, err error) bool {
+	return circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonNone
+}
+
+// Breaker 将 failsafe-go 的熔断器适配为 circuitbreaker.Executor，
+// 可用于 errgroupbreaker、Registry 之外的统一执行入口等接收 Executor 的场景
+type Breaker struct {
+	name string
+	cb   fcb.CircuitBreaker[any]
+}
+
+var _ circuitbreaker.Executor = (*Breaker)(nil)
+
+// NewBreaker 包装 failsafe-go 熔断器
+func NewBreaker(name string, cb fcb.CircuitBreaker[any]) *Breaker {
+	return &Breaker{name: name, cb: cb}
+}
+
+// Name 返回名称
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Execute 经 failsafe 熔断器准入后执行 fn，拒绝时返回原因为 ReasonOpen 的 *circuitbreaker.RejectionError
+func (b *Breaker) Execute(fn func() (interface{}, error)) (interface{}, error) {
+	return b.ExecuteContext(context.Background(), func(context.Context) (interface{}, error) {
+		return fn()
+	})
+}
+
+// ExecuteContext 与 Execute 相同，ctx 已结束时不发起调用
+func (b *Breaker) ExecuteContext(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !b.cb.TryAcquirePermit() {
+		rejection := &circuitbreaker.RejectionError{Breaker: b.name, Reason: circuitbreaker.ReasonOpen, Err: fcb.ErrOpen}
+		if d := b.cb.RemainingDelay(); d > 0 {
+			rejection.RetryAt = time.Now().Add(d)
+		}
+		return nil, rejection
+	}
+
+	result, err := fn(ctx)
+	if err != nil {
+		b.cb.RecordError(err)
+	} else {
+		b.cb.RecordSuccess()
+	}
+	return result, err
+}
+
+// State 返回映射为 gobreaker 的状态
+func (b *Breaker) State() gobreaker.State {
+	switch {
+	case b.cb.IsOpen():
+		return gobreaker.StateOpen
+	case b.cb.IsHalfOpen():
+		return gobreaker.StateHalfOpen
+	default:
+		return gobreaker.StateClosed
+	}
+}
+
+// Stats 返回统计快照，计数取自 failsafe 熔断器当前状态下的指标
+func (b *Breaker) Stats() circuitbreaker.Stats {
+	m := b.cb.Metrics()
+	return circuitbreaker.Stats{
+		Name:  b.name,
+		State: b.State(),
+		Counts: gobreaker.Counts{
+			Requests:       uint32(m.Executions()),
+			TotalSuccesses: uint32(m.Successes()),
+			TotalFailures:  uint32(m.Failures()),
+		},
+	}
+}
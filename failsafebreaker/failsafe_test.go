@@ -0,0 +1,108 @@
+// Copyright 2025 zampo.
+
+package failsafebreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go"
+	fcb "github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"github.com/failsafe-go/failsafe-go/retrypolicy"
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestGet_RetriesThroughBreaker(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(2)
+	cb := circuitbreaker.NewCircuitBreaker("test", settings)
+	retry := retrypolicy.NewBuilder[any]().
+		WithMaxRetries(5).
+		AbortIf(Rejected[any]).
+		Build()
+
+	attempts := 0
+	err := Run(failsafe.With[any](retry), cb, func(ctx context.Context) error {
+		attempts++
+		return errors.New("unavailable")
+	})
+
+	if attempts != 2 {
+		t.Errorf("attempts = %v, want %v (retries stop once the breaker opens)", attempts, 2)
+	}
+	if circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Errorf("Run() error = %v, want rejection", err)
+	}
+	assertTripped(t, cb)
+}
+
+func TestPolicy_ComposesWithRetry(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(2)
+	cb := circuitbreaker.NewCircuitBreaker("test", settings)
+	retry := retrypolicy.NewBuilder[string]().
+		WithMaxRetries(5).
+		AbortIf(Rejected[string]).
+		Build()
+
+	attempts := 0
+	_, err := failsafe.With[string](retry, NewPolicy[string](cb)).Get(func() (string, error) {
+		attempts++
+		return "", errors.New("unavailable")
+	})
+
+	if attempts != 2 {
+		t.Errorf("attempts = %v, want %v (retries stop once the breaker opens)", attempts, 2)
+	}
+	if circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Errorf("Get() error = %v, want rejection", err)
+	}
+	assertTripped(t, cb)
+
+	cb.Reset()
+	got, err := failsafe.With[string](NewPolicy[string](cb)).Get(func() (string, error) {
+		return "ok", nil
+	})
+	if got != "ok" || err != nil {
+		t.Errorf("Get() = %q, %v, want ok", got, err)
+	}
+}
+
+// assertTripped 断言熔断器在两次失败后打开，并拒绝了随后的一次尝试
+// gobreaker 打开时会清零 Counts，因此从打开原因与拒绝计数中读取
+func assertTripped(t *testing.T, cb *circuitbreaker.CircuitBreaker) {
+	t.Helper()
+	stats := cb.Stats()
+	if stats.LastTripCause == nil || stats.LastTripCause.Counts.TotalFailures != 2 {
+		t.Errorf("LastTripCause = %+v, want trip after 2 failures", stats.LastTripCause)
+	}
+	if stats.Rejections != 1 {
+		t.Errorf("Rejections = %v, want %v", stats.Rejections, 1)
+	}
+}
+
+func TestBreaker_Executor(t *testing.T) {
+	b := NewBreaker("failsafe", fcb.NewBuilder[any]().
+		WithFailureThreshold(1).
+		WithDelay(time.Minute).
+		Build())
+
+	if _, err := b.Execute(func() (interface{}, error) { return nil, errors.New("fail") }); err == nil {
+		t.Fatal("Execute() error = nil")
+	}
+	if b.State() != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want open", b.State())
+	}
+
+	_, err := b.Execute(func() (interface{}, error) { return "ok", nil })
+	if circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen || !errors.Is(err, fcb.ErrOpen) {
+		t.Errorf("Execute() error = %v, want rejection wrapping ErrOpen", err)
+	}
+	if _, ok := circuitbreaker.RetryAfter(err); !ok {
+		t.Error("RetryAfter() = false, want remaining delay")
+	}
+}
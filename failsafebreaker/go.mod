@@ -0,0 +1,11 @@
+module github.com/go-anyway/framework-circuitbreaker/failsafebreaker
+
+go 1.25.4
+
+require (
+	github.com/failsafe-go/failsafe-go v0.9.7
+	github.com/go-anyway/framework-circuitbreaker v0.0.0-20261016101936-22ab0cef4c5b
+	github.com/sony/gobreaker v1.0.0
+)
+
+require github.com/bits-and-blooms/bitset v1.24.4 // indirect
@@ -0,0 +1,113 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrInjectedFailure 故障注入产生的失败
+	ErrInjectedFailure = errors.New("circuitbreaker: injected failure")
+	// ErrFaultInjectionDisabled 未设置 Settings.UnsafeFaultInjection 时调用故障注入
+	ErrFaultInjectionDisabled = errors.New("circuitbreaker: fault injection disabled")
+)
+
+// faults 故障注入状态
+type faults struct {
+	active atomic.Bool
+
+	mu          sync.Mutex
+	failRate    float64
+	failUntil   time.Time
+	latency     time.Duration
+	latencyRate float64
+}
+
+// InjectFailures 在 duration 内使 Execute/ExecuteContext 按 rate 比例直接返回 ErrInjectedFailure（不调用 fn），
+// 用于在预发环境验证降级路径；需设置 Settings.UnsafeFaultInjection，否则返回 ErrFaultInjectionDisabled
+func (cb *CircuitBreaker) InjectFailures(rate float64, duration time.Duration) error {
+	if !cb.GetSettings().UnsafeFaultInjection {
+		return ErrFaultInjectionDisabled
+	}
+	f := &cb.faults
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failRate = rate
+	f.failUntil = time.Now().Add(duration)
+	f.active.Store(true)
+	return nil
+}
+
+// InjectLatency 使 Execute/ExecuteContext 按 rate 比例在调用 fn 前额外等待 d，直至 ClearFaults；
+// 需设置 Settings.UnsafeFaultInjection，否则返回 ErrFaultInjectionDisabled
+func (cb *CircuitBreaker) InjectLatency(d time.Duration, rate float64) error {
+	if !cb.GetSettings().UnsafeFaultInjection {
+		return ErrFaultInjectionDisabled
+	}
+	f := &cb.faults
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latency = d
+	f.latencyRate = rate
+	f.active.Store(true)
+	return nil
+}
+
+// ClearFaults 清除所有故障注入
+func (cb *CircuitBreaker) ClearFaults() {
+	f := &cb.faults
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failRate, f.failUntil = 0, time.Time{}
+	f.latency, f.latencyRate = 0, 0
+	f.active.Store(false)
+}
+
+// inject 按当前故障注入配置扰动一次调用，返回非 nil 时不应再调用 fn
+func (f *faults) inject(ctx context.Context) error {
+	if !f.active.Load() {
+		return nil
+	}
+
+	f.mu.Lock()
+	fail := f.failRate > 0 && time.Now().Before(f.failUntil) && rand.Float64() < f.failRate
+	var delay time.Duration
+	if f.latency > 0 && rand.Float64() < f.latencyRate {
+		delay = f.latency
+	}
+	f.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ContextError(ctx, ctx.Err())
+		case <-timer.C:
+		}
+	}
+	if fail {
+		return ErrInjectedFailure
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFaultInjection_RequiresUnsafeOption(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+
+	if err := cb.InjectFailures(1, time.Minute); !errors.Is(err, ErrFaultInjectionDisabled) {
+		t.Errorf("InjectFailures() error = %v, want %v", err, ErrFaultInjectionDisabled)
+	}
+	if err := cb.InjectLatency(time.Second, 1); !errors.Is(err, ErrFaultInjectionDisabled) {
+		t.Errorf("InjectLatency() error = %v, want %v", err, ErrFaultInjectionDisabled)
+	}
+}
+
+func TestFaultInjection_Failures(t *testing.T) {
+	settings := DefaultSettings()
+	settings.UnsafeFaultInjection = true
+	cb := NewCircuitBreaker("test", settings)
+
+	if err := cb.InjectFailures(1, time.Minute); err != nil {
+		t.Fatalf("InjectFailures() error = %v", err)
+	}
+	called := false
+	_, err := cb.Execute(func() (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+	if !errors.Is(err, ErrInjectedFailure) || called {
+		t.Errorf("Execute() = %v, called = %v, want %v, false", err, called, ErrInjectedFailure)
+	}
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %v, want 1", got)
+	}
+
+	cb.ClearFaults()
+	if _, err := cb.Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("Execute() after ClearFaults error = %v", err)
+	}
+}
+
+func TestFaultInjection_Latency(t *testing.T) {
+	settings := DefaultSettings()
+	settings.UnsafeFaultInjection = true
+	settings.CallTimeout = 10 * time.Millisecond
+	cb := NewCircuitBreaker("test", settings)
+
+	if err := cb.InjectLatency(time.Second, 1); err != nil {
+		t.Fatalf("InjectLatency() error = %v", err)
+	}
+	_, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	})
+	if !errors.Is(err, ErrCallTimeout) {
+		t.Errorf("ExecuteContext() error = %v, want %v", err, ErrCallTimeout)
+	}
+}
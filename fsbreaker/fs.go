@@ -0,0 +1,118 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package fsbreaker 提供文件系统操作的熔断器包装，适用于 NFS 等可能变慢或失效的挂载点
+package fsbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// IsFailure 默认失败判定：EIO、句柄失效（ESTALE）、超时计为失败，
+// 文件不存在、权限不足等与挂载点健康无关的错误不计为失败
+func IsFailure(err error) bool {
+	return errors.Is(err, syscall.EIO) ||
+		errors.Is(err, syscall.ESTALE) ||
+		errors.Is(err, syscall.ETIMEDOUT) ||
+		errors.Is(err, os.ErrDeadlineExceeded) ||
+		errors.Is(err, circuitbreaker.ErrCallTimeout) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// DefaultSettings 返回以 IsFailure 判定成败的熔断器配置：文件不存在等错误计为成功；
+// 操作的原始错误会交给熔断器分类，可在此基础上设置 IgnoreErrors、FailErrors 或替换 IsSuccessful
+func DefaultSettings() circuitbreaker.Settings {
+	settings := circuitbreaker.DefaultSettings()
+	settings.IsSuccessful = func(err error) bool {
+		return !IsFailure(err)
+	}
+	return settings
+}
+
+// FS 带熔断保护的目录，所有路径均相对于根目录，经 os.Root 访问，符号链接同样不能逃出根目录
+// 操作通过 ExecuteContext 执行，挂起的系统调用在 CallTimeout 到期后被放弃并计为失败；
+// 熔断器建议使用 DefaultSettings 创建
+type FS struct {
+	root string
+	cb   *circuitbreaker.CircuitBreaker
+}
+
+// New 使用熔断器包装根目录 root，根目录在每次操作时打开，挂载点恢复后无需重新创建
+func New(root string, cb *circuitbreaker.CircuitBreaker) *FS {
+	return &FS{root: root, cb: cb}
+}
+
+// Breaker 返回熔断器
+func (f *FS) Breaker() *circuitbreaker.CircuitBreaker {
+	return f.cb
+}
+
+// ReadFile 读取文件内容
+func (f *FS) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	return do(f, ctx, "read", name, func(root *os.Root) ([]byte, error) {
+		return root.ReadFile(name)
+	})
+}
+
+// WriteFile 写入文件内容
+func (f *FS) WriteFile(ctx context.Context, name string, data []byte, perm fs.FileMode) error {
+	_, err := do(f, ctx, "write", name, func(root *os.Root) (struct{}, error) {
+		return struct{}{}, root.WriteFile(name, data, perm)
+	})
+	return err
+}
+
+// Stat 返回文件信息
+func (f *FS) Stat(ctx context.Context, name string) (fs.FileInfo, error) {
+	return do(f, ctx, "stat", name, func(root *os.Root) (fs.FileInfo, error) {
+		return root.Stat(name)
+	})
+}
+
+// do 在熔断器保护下打开根目录并执行 op，name 必须是根目录内的相对路径；
+// op 的错误原样返回给熔断器，由 Settings 的 FailErrors、IgnoreErrors 与 IsSuccessful 分类
+func do[T any](f *FS, ctx context.Context, op, name string, fn func(root *os.Root) (T, error)) (T, error) {
+	var zero T
+	if !filepath.IsLocal(name) {
+		return zero, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	v, err := f.cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		root, err := os.OpenRoot(f.root)
+		if err != nil {
+			return nil, err
+		}
+		defer root.Close()
+		return fn(root)
+	})
+	if err != nil {
+		return zero, err
+	}
+	// 拦截器可能替换结果，此时无法还原操作的返回值
+	value, ok := v.(T)
+	if !ok {
+		return zero, &fs.PathError{Op: op, Path: name, Err: fmt.Errorf("fsbreaker: unexpected result %T", v)}
+	}
+	return value, nil
+}
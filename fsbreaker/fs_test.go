@@ -0,0 +1,145 @@
+// Copyright 2025 zampo.
+
+package fsbreaker
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestFS_ReadWriteStat(t *testing.T) {
+	f := New(t.TempDir(), circuitbreaker.NewCircuitBreaker("nfs", circuitbreaker.DefaultSettings()))
+	ctx := context.Background()
+
+	if err := f.WriteFile(ctx, "a.txt", []byte("hello"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	data, err := f.ReadFile(ctx, "a.txt")
+	if err != nil || string(data) != "hello" {
+		t.Errorf("ReadFile() = %q, %v, want hello, nil", data, err)
+	}
+	info, err := f.Stat(ctx, "a.txt")
+	if err != nil || info.Size() != 5 {
+		t.Errorf("Stat() = %v, %v, want size 5", info, err)
+	}
+}
+
+func TestFS_NotExistIsNotFailure(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	f := New(t.TempDir(), circuitbreaker.NewCircuitBreaker("nfs", settings))
+
+	for i := 0; i < 3; i++ {
+		if _, err := f.Stat(context.Background(), "missing"); !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("Stat() error = %v, want %v", err, fs.ErrNotExist)
+		}
+	}
+	if got := f.Breaker().State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+}
+
+func TestFS_RejectsEscape(t *testing.T) {
+	f := New(t.TempDir(), circuitbreaker.NewCircuitBreaker("nfs", circuitbreaker.DefaultSettings()))
+
+	if _, err := f.ReadFile(context.Background(), "../etc/passwd"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("ReadFile() error = %v, want %v", err, fs.ErrInvalid)
+	}
+}
+
+func TestFS_ClassifiesUnderlyingError(t *testing.T) {
+	settings := DefaultSettings()
+	settings.IgnoreErrors = []circuitbreaker.ErrorMatcher{circuitbreaker.MatchErrors(fs.ErrNotExist)}
+	settings.FailErrors = []circuitbreaker.ErrorMatcher{circuitbreaker.MatchErrors(fs.ErrExist)}
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	f := New(t.TempDir(), circuitbreaker.NewCircuitBreaker("nfs", settings))
+	ctx := context.Background()
+
+	if _, err := f.Stat(ctx, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Stat() error = %v, want %v", err, fs.ErrNotExist)
+	}
+	if counts := f.Breaker().Counts(); counts.TotalSuccesses != 0 || counts.TotalFailures != 0 {
+		t.Errorf("Counts = %+v, want the ignored error not counted", counts)
+	}
+
+	// IsFailure 不把 EEXIST 计为失败，FailErrors 优先
+	if err := os.Mkdir(filepath.Join(f.root, "dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := do(f, ctx, "mkdir", "dir", func(root *os.Root) (struct{}, error) {
+		return struct{}{}, root.Mkdir("dir", 0o755)
+	}); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("mkdir error = %v, want %v", err, fs.ErrExist)
+	}
+	if got := f.Breaker().State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+}
+
+func TestFS_SymlinkCannotEscape(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Skipf("symlink: %v", err)
+	}
+	f := New(dir, circuitbreaker.NewCircuitBreaker("nfs", DefaultSettings()))
+
+	if data, err := f.ReadFile(context.Background(), "link"); err == nil {
+		t.Errorf("ReadFile() = %q, want an error for a symlink outside the root", data)
+	}
+}
+
+func TestFS_UnexpectedResult(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.Interceptors = []circuitbreaker.Interceptor{
+		func(next circuitbreaker.ExecFunc) circuitbreaker.ExecFunc {
+			return func(ctx context.Context) (interface{}, error) {
+				next(ctx)
+				return "cached", nil
+			}
+		},
+	}
+	f := New(t.TempDir(), circuitbreaker.NewCircuitBreaker("nfs", settings))
+
+	var pathErr *fs.PathError
+	if _, err := f.Stat(context.Background(), "missing"); !errors.As(err, &pathErr) || pathErr.Op != "stat" {
+		t.Errorf("Stat() error = %v, want a stat PathError", err)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&fs.PathError{Op: "read", Path: "x", Err: syscall.EIO}, true},
+		{&fs.PathError{Op: "stat", Path: "x", Err: syscall.ESTALE}, true},
+		{os.ErrDeadlineExceeded, true},
+		{circuitbreaker.ErrCallTimeout, true},
+		{&fs.PathError{Op: "open", Path: "x", Err: syscall.ENOENT}, false},
+		{&fs.PathError{Op: "open", Path: "x", Err: syscall.EACCES}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsFailure(tt.err); got != tt.want {
+			t.Errorf("IsFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
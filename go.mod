@@ -2,6 +2,4 @@ module github.com/go-anyway/framework-circuitbreaker
 
 go 1.25.4
 
-require (
-	github.com/sony/gobreaker v1.0.0
-)
+require github.com/sony/gobreaker v1.0.0
@@ -0,0 +1,281 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package gossipbreaker 基于 memberlist 在实例间交换熔断器状态摘要，
+// 适用于无法部署 Redis 等集中式存储的场景
+package gossipbreaker
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Summary 单个实例上某熔断器的状态摘要
+type Summary struct {
+	State    gobreaker.State `json:"state"`
+	Requests uint32          `json:"requests"`
+	Failures uint32          `json:"failures"`
+}
+
+// nodeState 推拉同步时交换的完整节点状态
+type nodeState struct {
+	Node     string             `json:"node"`
+	Breakers map[string]Summary `json:"breakers"`
+}
+
+// message 状态变更时广播的增量消息
+type message struct {
+	Node    string  `json:"node"`
+	Name    string  `json:"name"`
+	Summary Summary `json:"summary"`
+}
+
+// remoteState 远端节点状态，receivedAt 使用本地时钟，避免依赖对端时间
+type remoteState struct {
+	breakers   map[string]Summary
+	receivedAt time.Time
+}
+
+// FleetView 集群视角下某依赖的健康状况（包含本实例）
+type FleetView struct {
+	Name string
+	// Nodes 上报该熔断器的实例数
+	Nodes int
+	// Open 处于打开状态的实例数
+	Open int
+	// HalfOpen 处于半开状态的实例数
+	HalfOpen int
+	// Requests 各实例当前窗口请求数之和
+	Requests uint64
+	// Failures 各实例当前窗口失败数之和
+	Failures uint64
+}
+
+// FailureRate 集群整体失败率，无请求时返回 0
+func (v FleetView) FailureRate() float64 {
+	if v.Requests == 0 {
+		return 0
+	}
+	return float64(v.Failures) / float64(v.Requests)
+}
+
+// Gossip 实现 memberlist.Delegate，交换注册表内熔断器的状态摘要
+type Gossip struct {
+	registry   *circuitbreaker.Registry
+	node       string
+	staleAfter time.Duration
+	queue      *memberlist.TransmitLimitedQueue
+
+	mu     sync.RWMutex
+	remote map[string]*remoteState
+
+	unsubscribe func()
+}
+
+// Option Gossip 配置项
+type Option func(*Gossip)
+
+// WithStaleAfter 设置远端状态的过期时间，超过该时间未收到更新的实例不再参与汇总，默认 1 分钟
+func WithStaleAfter(d time.Duration) Option {
+	return func(g *Gossip) {
+		g.staleAfter = d
+	}
+}
+
+// New 创建 Gossip，node 须与 memberlist 节点名一致
+// 使用方式：将返回值设置为 memberlist.Config.Delegate，创建集群后调用 SetNumNodes(list.NumMembers)
+func New(registry *circuitbreaker.Registry, node string, opts ...Option) *Gossip {
+	g := &Gossip{
+		registry:   registry,
+		node:       node,
+		staleAfter: time.Minute,
+		remote:     make(map[string]*remoteState),
+	}
+	g.queue = &memberlist.TransmitLimitedQueue{
+		NumNodes:       func() int { return 1 },
+		RetransmitMult: 3,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.unsubscribe = registry.Subscribe(func(name string, from, to gobreaker.State) {
+		// 状态切换时 gobreaker 已清空计数，广播中计数为 0 即为准确值
+		msg, err := json.Marshal(message{Node: g.node, Name: name, Summary: Summary{State: to}})
+		if err != nil {
+			return
+		}
+		g.queue.QueueBroadcast(&broadcast{name: name, msg: msg})
+	})
+	return g
+}
+
+// SetNumNodes 设置集群规模函数，用于计算广播重传次数
+func (g *Gossip) SetNumNodes(fn func() int) {
+	g.queue.NumNodes = fn
+}
+
+// Close 停止监听注册表
+func (g *Gossip) Close() {
+	g.unsubscribe()
+}
+
+// View 返回某依赖在集群内的汇总视图
+func (g *Gossip) View(name string) FleetView {
+	view := FleetView{Name: name}
+
+	if cb, ok := g.registry.Get(name); ok {
+		stats := cb.Stats()
+		view.add(Summary{
+			State:    stats.State,
+			Requests: stats.Counts.Requests,
+			Failures: stats.Counts.TotalFailures,
+		})
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	cutoff := time.Now().Add(-g.staleAfter)
+	for _, rs := range g.remote {
+		if rs.receivedAt.Before(cutoff) {
+			continue
+		}
+		if s, ok := rs.breakers[name]; ok {
+			view.add(s)
+		}
+	}
+	return view
+}
+
+// RemoteCounts 汇总其他实例上某熔断器的请求数与失败数，不包含本实例，
+// 可作为 circuitbreaker.FleetCountsFunc 传给 circuitbreaker.FleetFailureRate
+func (g *Gossip) RemoteCounts(name string) (requests, failures uint64) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	cutoff := time.Now().Add(-g.staleAfter)
+	for _, rs := range g.remote {
+		if rs.receivedAt.Before(cutoff) {
+			continue
+		}
+		if s, ok := rs.breakers[name]; ok {
+			requests += uint64(s.Requests)
+			failures += uint64(s.Failures)
+		}
+	}
+	return requests, failures
+}
+
+func (v *FleetView) add(s Summary) {
+	v.Nodes++
+	switch s.State {
+	case gobreaker.StateOpen:
+		v.Open++
+	case gobreaker.StateHalfOpen:
+		v.HalfOpen++
+	}
+	v.Requests += uint64(s.Requests)
+	v.Failures += uint64(s.Failures)
+}
+
+// NodeMeta 实现 memberlist.Delegate
+func (g *Gossip) NodeMeta(limit int) []byte {
+	return nil
+}
+
+// NotifyMsg 实现 memberlist.Delegate，处理状态变更广播
+func (g *Gossip) NotifyMsg(buf []byte) {
+	var msg message
+	if err := json.Unmarshal(buf, &msg); err != nil || msg.Node == g.node {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	rs, ok := g.remote[msg.Node]
+	if !ok {
+		rs = &remoteState{breakers: make(map[string]Summary)}
+		g.remote[msg.Node] = rs
+	}
+	rs.breakers[msg.Name] = msg.Summary
+	rs.receivedAt = time.Now()
+}
+
+// GetBroadcasts 实现 memberlist.Delegate
+func (g *Gossip) GetBroadcasts(overhead, limit int) [][]byte {
+	return g.queue.GetBroadcasts(overhead, limit)
+}
+
+// LocalState 实现 memberlist.Delegate，返回本实例全部熔断器摘要
+func (g *Gossip) LocalState(join bool) []byte {
+	state := nodeState{Node: g.node, Breakers: make(map[string]Summary)}
+	for _, stats := range g.registry.Stats() {
+		state.Breakers[stats.Name] = Summary{
+			State:    stats.State,
+			Requests: stats.Counts.Requests,
+			Failures: stats.Counts.TotalFailures,
+		}
+	}
+
+	buf, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+	return buf
+}
+
+// MergeRemoteState 实现 memberlist.Delegate，合并远端实例的完整状态
+func (g *Gossip) MergeRemoteState(buf []byte, join bool) {
+	var state nodeState
+	if err := json.Unmarshal(buf, &state); err != nil || state.Node == g.node {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.remote[state.Node] = &remoteState{breakers: state.Breakers, receivedAt: time.Now()}
+
+	// 顺带清理长期未更新的实例
+	cutoff := time.Now().Add(-g.staleAfter)
+	for node, rs := range g.remote {
+		if rs.receivedAt.Before(cutoff) {
+			delete(g.remote, node)
+		}
+	}
+}
+
+// broadcast 单个熔断器的状态变更广播，同一熔断器的新消息覆盖旧消息
+type broadcast struct {
+	name string
+	msg  []byte
+}
+
+func (b *broadcast) Invalidates(other memberlist.Broadcast) bool {
+	o, ok := other.(*broadcast)
+	return ok && o.name == b.name
+}
+
+func (b *broadcast) Message() []byte {
+	return b.msg
+}
+
+func (b *broadcast) Finished() {}
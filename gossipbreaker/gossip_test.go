@@ -0,0 +1,93 @@
+// Copyright 2025 zampo.
+
+package gossipbreaker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func newNode(t *testing.T, node string) (*Gossip, *circuitbreaker.CircuitBreaker) {
+	t.Helper()
+	r := circuitbreaker.NewRegistry()
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 2
+	}
+	cb := r.GetOrCreate("payments", settings)
+	g := New(r, node)
+	t.Cleanup(g.Close)
+	return g, cb
+}
+
+func TestGossip_PushPull(t *testing.T) {
+	a, cbA := newNode(t, "a")
+	b, cbB := newNode(t, "b")
+
+	cbA.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	cbB.Execute(func() (interface{}, error) { return nil, nil })
+
+	b.MergeRemoteState(a.LocalState(false), false)
+
+	view := b.View("payments")
+	if view.Nodes != 2 {
+		t.Errorf("Nodes = %v, want %v", view.Nodes, 2)
+	}
+	if view.Requests != 2 || view.Failures != 1 {
+		t.Errorf("Requests, Failures = %v, %v, want 2, 1", view.Requests, view.Failures)
+	}
+	if got := view.FailureRate(); got != 0.5 {
+		t.Errorf("FailureRate() = %v, want %v", got, 0.5)
+	}
+}
+
+func TestGossip_BroadcastStateChange(t *testing.T) {
+	a, cbA := newNode(t, "a")
+	b, _ := newNode(t, "b")
+
+	for i := 0; i < 2; i++ {
+		cbA.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	}
+
+	for _, msg := range a.GetBroadcasts(0, 1024) {
+		b.NotifyMsg(msg)
+	}
+
+	if view := b.View("payments"); view.Open != 1 {
+		t.Errorf("Open = %v, want %v", view.Open, 1)
+	}
+}
+
+func TestGossip_IgnoresOwnState(t *testing.T) {
+	a, _ := newNode(t, "a")
+
+	a.MergeRemoteState(a.LocalState(false), false)
+
+	if view := a.View("payments"); view.Nodes != 1 {
+		t.Errorf("Nodes = %v, want %v", view.Nodes, 1)
+	}
+}
+
+func TestGossip_RemoteCountsDrivesFleetPolicy(t *testing.T) {
+	a, cbA := newNode(t, "a")
+	b, _ := newNode(t, "b")
+
+	for i := 0; i < 10; i++ {
+		cbA.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	b.MergeRemoteState(a.LocalState(false), false)
+
+	requests, failures := b.RemoteCounts("payments")
+	if requests != 10 || failures != 0 {
+		t.Fatalf("RemoteCounts() = %v, %v, want 10, 0", requests, failures)
+	}
+
+	trip := circuitbreaker.FleetFailureRate("payments", b.RemoteCounts, 5, 0.5)
+	if trip(gobreaker.Counts{Requests: 1, TotalFailures: 1}) {
+		t.Error("a single local failure should not trip a healthy fleet")
+	}
+}
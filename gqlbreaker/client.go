@@ -0,0 +1,264 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package gqlbreaker 提供按操作名熔断的 GraphQL over HTTP 客户端
+package gqlbreaker
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Request GraphQL 请求
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Error GraphQL 响应中的错误
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Response GraphQL 响应，Data 与 Errors 可同时存在（部分结果）
+type Response struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []Error         `json:"errors,omitempty"`
+	// Stale 为 true 表示熔断或传输失败时返回的缓存结果
+	Stale bool `json:"-"`
+}
+
+// Partial 是否为带错误的部分结果
+func (r *Response) Partial() bool {
+	return len(r.Errors) > 0 && len(r.Data) > 0 && string(r.Data) != "null"
+}
+
+// StatusError 非 2xx 的 HTTP 响应
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("gqlbreaker: unexpected status %d", e.StatusCode)
+}
+
+// DefaultIsFailure 默认失败判定：传输错误、5xx 与无法解析的响应计为失败；
+// GraphQL 错误（包括部分结果中的字段错误）说明服务仍在正常处理请求，不计为失败
+func DefaultIsFailure(resp *Response, err error) bool {
+	if err == nil {
+		return false
+	}
+	if se, ok := err.(*StatusError); ok {
+		return se.StatusCode >= 500 || se.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// Option 客户端配置项
+type Option func(*Client)
+
+// WithHTTPClient 设置 HTTP 客户端，默认 http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithRegistry 设置熔断器注册表，便于与健康检查、管理接口共享
+func WithRegistry(r *circuitbreaker.Registry) Option {
+	return func(c *Client) { c.registry = r }
+}
+
+// WithSettings 设置每个操作熔断器的配置
+func WithSettings(settings circuitbreaker.Settings) Option {
+	return func(c *Client) { c.settings = settings }
+}
+
+// WithIsFailure 设置失败判定，可将特定的部分错误（如 extensions.code 为下游不可用）计为失败
+func WithIsFailure(fn func(resp *Response, err error) bool) Option {
+	return func(c *Client) { c.isFailure = fn }
+}
+
+// WithFallbackCache 缓存最近 maxEntries 个（操作名 + 变量）的成功或部分结果，
+// 熔断拒绝或传输失败时返回缓存结果（Stale 为 true）而不是错误
+func WithFallbackCache(maxEntries int) Option {
+	return func(c *Client) { c.cache = newLRU(maxEntries) }
+}
+
+// Client 按操作名熔断的 GraphQL 客户端，熔断器在注册表中命名为 "graphql:<操作名>"，
+// 匿名操作共用 "graphql:anonymous"
+type Client struct {
+	endpoint  string
+	http      *http.Client
+	registry  *circuitbreaker.Registry
+	settings  circuitbreaker.Settings
+	isFailure func(resp *Response, err error) bool
+	cache     *lru
+}
+
+// New 创建客户端
+func New(endpoint string, opts ...Option) *Client {
+	c := &Client{
+		endpoint:  endpoint,
+		http:      http.DefaultClient,
+		registry:  circuitbreaker.NewRegistry(),
+		settings:  circuitbreaker.DefaultSettings(),
+		isFailure: DefaultIsFailure,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Breaker 返回指定操作的熔断器
+func (c *Client) Breaker(operation string) *circuitbreaker.CircuitBreaker {
+	if operation == "" {
+		operation = "anonymous"
+	}
+	return c.registry.GetOrCreate("graphql:"+operation, c.settings)
+}
+
+// Do 执行请求；GraphQL 错误通过 Response.Errors 返回，error 仅表示熔断拒绝、传输或 HTTP 错误，
+// 4xx 响应同时返回已解析的 Response 以便读取请求错误
+func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	done, err := c.Breaker(req.OperationName).AllowContext(ctx)
+	if err != nil {
+		return c.fallback(req, err)
+	}
+
+	resp, err := c.do(ctx, req)
+	if c.isFailure(resp, err) {
+		done(err)
+		return c.fallback(req, err)
+	}
+	done(nil)
+	if err != nil {
+		return resp, err
+	}
+	if c.cache != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		c.cache.put(cacheKey(req), resp)
+	}
+	return resp, nil
+}
+
+// do 发送请求并解析响应
+func (c *Client) do(ctx context.Context, req Request) (*Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/graphql-response+json, application/json")
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	// application/graphql-response+json 在请求错误时可能返回 4xx 并附带 errors，优先解析
+	var resp Response
+	decodeErr := json.NewDecoder(httpResp.Body).Decode(&resp)
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		io.Copy(io.Discard, httpResp.Body)
+		return &resp, &StatusError{StatusCode: httpResp.StatusCode}
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("gqlbreaker: decode response: %w", decodeErr)
+	}
+	return &resp, nil
+}
+
+// fallback 有缓存时返回缓存结果，否则返回原错误
+func (c *Client) fallback(req Request, err error) (*Response, error) {
+	if c.cache == nil {
+		return nil, err
+	}
+	cached, ok := c.cache.get(cacheKey(req))
+	if !ok {
+		return nil, err
+	}
+	stale := *cached
+	stale.Stale = true
+	return &stale, nil
+}
+
+// cacheKey 以操作名与变量作为缓存键（json.Marshal 对 map 键排序，结果稳定）
+func cacheKey(req Request) string {
+	vars, _ := json.Marshal(req.Variables)
+	return req.OperationName + "\x00" + req.Query + "\x00" + string(vars)
+}
+
+// lru 固定容量的最近最少使用缓存
+type lru struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key  string
+	resp *Response
+}
+
+func newLRU(max int) *lru {
+	if max <= 0 {
+		max = 1
+	}
+	return &lru{max: max, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (l *lru) get(key string) (*Response, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(*lruEntry).resp, true
+}
+
+func (l *lru) put(key string, resp *Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.entries[key]; ok {
+		el.Value.(*lruEntry).resp = resp
+		l.order.MoveToFront(el)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, resp: resp})
+	if l.order.Len() > l.max {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
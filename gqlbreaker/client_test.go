@@ -0,0 +1,119 @@
+// Copyright 2025 zampo.
+
+package gqlbreaker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func tripOnFirstFailure() circuitbreaker.Settings {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	return settings
+}
+
+func TestClient_PartialErrorsNotCounted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":{"user":{"name":"ann","orders":null}},"errors":[{"message":"orders unavailable","path":["user","orders"]}]}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithSettings(tripOnFirstFailure()))
+	resp, err := c.Do(context.Background(), Request{Query: "query User { user { name orders } }", OperationName: "User"})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if !resp.Partial() || resp.Errors[0].Message != "orders unavailable" {
+		t.Errorf("resp = %+v, want partial result", resp)
+	}
+	if got := c.Breaker("User").State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+}
+
+func TestClient_PerOperationBreakersAndCachedFallback(t *testing.T) {
+	var down atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		io.WriteString(w, `{"data":{"feed":[1,2,3]}}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithSettings(tripOnFirstFailure()), WithFallbackCache(10))
+	ctx := context.Background()
+	feed := Request{Query: "query Feed { feed }", OperationName: "Feed"}
+
+	if _, err := c.Do(ctx, feed); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	down.Store(true)
+	resp, err := c.Do(ctx, feed)
+	if err != nil || !resp.Stale || string(resp.Data) != `{"feed":[1,2,3]}` {
+		t.Fatalf("Do() while failing = %+v, %v, want stale cached data", resp, err)
+	}
+	if got := c.Breaker("Feed").State(); got != gobreaker.StateOpen {
+		t.Errorf("Feed State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+	if got := c.Breaker("Profile").State(); got != gobreaker.StateClosed {
+		t.Errorf("Profile State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+
+	resp, err = c.Do(ctx, feed)
+	if err != nil || !resp.Stale {
+		t.Errorf("Do() while open = %+v, %v, want stale cached data", resp, err)
+	}
+
+	_, err = c.Do(ctx, Request{Query: "query Feed { feed }", OperationName: "Feed", Variables: map[string]interface{}{"page": 2}})
+	if circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Errorf("Do() uncached variables error = %v, want open rejection", err)
+	}
+}
+
+func TestClient_ClientErrorsNotCounted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/graphql-response+json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"errors":[{"message":"Cannot query field \"nope\""}]}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithSettings(tripOnFirstFailure()))
+	resp, err := c.Do(context.Background(), Request{Query: "{ nope }"})
+	if se, ok := err.(*StatusError); !ok || se.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Do() error = %v, want *StatusError 400", err)
+	}
+	if resp == nil || len(resp.Errors) != 1 {
+		t.Errorf("resp = %+v, want request error", resp)
+	}
+	if got := c.Breaker("").State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+}
+
+func TestLRU_Evicts(t *testing.T) {
+	l := newLRU(2)
+	l.put("a", &Response{})
+	l.put("b", &Response{})
+	l.get("a")
+	l.put("c", &Response{})
+
+	if _, ok := l.get("b"); ok {
+		t.Error("b should be evicted")
+	}
+	if _, ok := l.get("a"); !ok {
+		t.Error("a should be retained")
+	}
+}
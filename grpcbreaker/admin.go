@@ -0,0 +1,270 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package grpcbreaker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+	"github.com/go-anyway/framework-circuitbreaker/cbpb"
+)
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative ../grpcbreaker/admin.proto
+
+// AdminServiceName circuitbreaker.v1.AdminService 的完整服务名
+const AdminServiceName = "circuitbreaker.v1.AdminService"
+
+// AdminServer 基于熔断器注册表实现 circuitbreaker.v1.AdminService（见 admin.proto）
+// 未配置 WithAuthorizer 时仅允许查询操作，Reset/ForceOpen 返回 PermissionDenied
+type AdminServer struct {
+	UnimplementedAdminServiceServer
+
+	registry    *circuitbreaker.Registry
+	eventBuffer int
+	policy      circuitbreaker.AdminPolicy
+}
+
+// AdminOption 管理服务配置项
+type AdminOption func(*AdminServer)
+
+// WithEventBuffer 设置 WatchEvents 每个订阅的事件缓冲大小，默认 64
+// 状态变更回调不可阻塞，缓冲已满时新事件被丢弃
+func WithEventBuffer(n int) AdminOption {
+	return func(s *AdminServer) {
+		s.eventBuffer = n
+	}
+}
+
+// WithAuthorizer 设置管理请求授权函数
+// 令牌取自 authorization 元数据（Bearer 方案），证书主题取自 mTLS 对端证书
+func WithAuthorizer(authz circuitbreaker.Authorizer) AdminOption {
+	return func(s *AdminServer) {
+		s.policy.Authorizer = authz
+	}
+}
+
+// WithAdminMode 设置管理接口模式，只读模式下 Reset/ForceOpen 返回 PermissionDenied 并产生审计事件
+func WithAdminMode(mode circuitbreaker.AdminMode) AdminOption {
+	return func(s *AdminServer) {
+		s.policy.Mode = mode
+	}
+}
+
+// WithAudit 设置管理操作审计回调
+func WithAudit(fn func(circuitbreaker.AdminAuditEvent)) AdminOption {
+	return func(s *AdminServer) {
+		s.policy.OnAudit = fn
+	}
+}
+
+// NewAdminServer 创建管理服务
+func NewAdminServer(registry *circuitbreaker.Registry, opts ...AdminOption) *AdminServer {
+	s := &AdminServer{
+		registry:    registry,
+		eventBuffer: 64,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// List 列出所有熔断器快照（按名称排序）
+func (s *AdminServer) List(ctx context.Context, req *cbpb.ListRequest) (*cbpb.ListResponse, error) {
+	if err := s.authorize(ctx, circuitbreaker.AdminList, ""); err != nil {
+		return nil, err
+	}
+	stats := s.registry.Stats()
+	resp := &cbpb.ListResponse{Breakers: make([]*cbpb.Snapshot, 0, len(stats))}
+	for _, st := range stats {
+		resp.Breakers = append(resp.Breakers, cbpb.FromStats(st))
+	}
+	return resp, nil
+}
+
+// Get 获取单个熔断器快照，未注册时返回 NotFound
+func (s *AdminServer) Get(ctx context.Context, req *cbpb.GetRequest) (*cbpb.Snapshot, error) {
+	cb, err := s.lookup(ctx, circuitbreaker.AdminGet, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return cbpb.FromStats(cb.Stats()), nil
+}
+
+// Reset 手动将熔断器恢复为关闭状态
+func (s *AdminServer) Reset(ctx context.Context, req *cbpb.ResetRequest) (*cbpb.Snapshot, error) {
+	cb, err := s.lookup(ctx, circuitbreaker.AdminReset, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	cb.Reset()
+	return cbpb.FromStats(cb.Stats()), nil
+}
+
+// ForceOpen 获取或续约 DurationMs 的强制打开租约，先授权再校验参数。
+// 持有者与调用方身份绑定（见 circuitbreaker.AdminRequest.LeaseOwner），Owner 只能为空或调用方的证书主题之一，
+// 否则返回 PermissionDenied；租约由其他持有者持有时返回 FailedPrecondition，
+// 有效期超过 circuitbreaker.MaxLeaseTTL 时返回 InvalidArgument
+func (s *AdminServer) ForceOpen(ctx context.Context, req *cbpb.ForceOpenRequest) (*cbpb.Snapshot, error) {
+	cb, err := s.lookup(ctx, circuitbreaker.AdminForceOpen, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	ttl := time.Duration(req.DurationMs) * time.Millisecond
+	if ttl <= 0 || ttl > circuitbreaker.MaxLeaseTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "duration_ms must be in (0, %d]", circuitbreaker.MaxLeaseTTL.Milliseconds())
+	}
+	owner, err := circuitbreaker.AdminRequest{Token: bearerToken(ctx), Subjects: peerSubjects(ctx)}.LeaseOwner(req.Owner)
+	if err != nil {
+		return nil, authError(err)
+	}
+	if lease, err := cb.ForceOpen(owner, ttl); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v: %s until %s", err, lease.Owner, lease.Expires.UTC().Format(time.RFC3339))
+	}
+	return cbpb.FromStats(cb.Stats()), nil
+}
+
+// transition 待推送的状态变更
+type transition struct {
+	name     string
+	from, to gobreaker.State
+	at       time.Time
+}
+
+// WatchEvents 推送状态变更事件，Names 为空时推送所有熔断器的事件，客户端取消后返回 ctx 的错误
+func (s *AdminServer) WatchEvents(req *cbpb.WatchEventsRequest, stream AdminService_WatchEventsServer) error {
+	if err := s.authorize(stream.Context(), circuitbreaker.AdminWatch, ""); err != nil {
+		return err
+	}
+	var names map[string]bool
+	if len(req.Names) > 0 {
+		names = make(map[string]bool, len(req.Names))
+		for _, name := range req.Names {
+			names[name] = true
+		}
+	}
+
+	events := make(chan transition, s.eventBuffer)
+	unsubscribe := s.registry.Subscribe(func(name string, from, to gobreaker.State) {
+		if names != nil && !names[name] {
+			return
+		}
+		select {
+		case events <- transition{name: name, from: from, to: to, at: time.Now()}:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case e := <-events:
+			// 在回调之外查询级别，避免在 gobreaker 内部锁中访问注册表
+			tier := circuitbreaker.TierCritical
+			if cb, ok := s.registry.Get(e.name); ok {
+				tier = cb.Tier()
+			}
+			if err := stream.Send(cbpb.NewEvent(e.name, e.from, e.to, tier, e.at)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// lookup 授权后按名称查找熔断器
+func (s *AdminServer) lookup(ctx context.Context, op circuitbreaker.AdminOperation, name string) (*circuitbreaker.CircuitBreaker, error) {
+	if err := s.authorize(ctx, op, name); err != nil {
+		return nil, err
+	}
+	cb, ok := s.registry.Get(name)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "circuit breaker %q not found", name)
+	}
+	return cb, nil
+}
+
+// authorize 从 ctx 提取凭证并授权，拒绝时返回 Unauthenticated 或 PermissionDenied
+func (s *AdminServer) authorize(ctx context.Context, op circuitbreaker.AdminOperation, name string) error {
+	req := circuitbreaker.AdminRequest{
+		Operation: op,
+		Breaker:   name,
+		Token:     bearerToken(ctx),
+		Subjects:  peerSubjects(ctx),
+	}
+	if err := s.policy.Check(ctx, req); err != nil {
+		return authError(err)
+	}
+	return nil
+}
+
+// authError 将 ErrUnauthenticated 转换为 Unauthenticated，其余授权错误转换为 PermissionDenied
+func authError(err error) error {
+	if errors.Is(err, circuitbreaker.ErrUnauthenticated) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return status.Error(codes.PermissionDenied, err.Error())
+}
+
+// bearerToken 从 authorization 元数据提取 Bearer 令牌
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, v := range md.Get("authorization") {
+		if scheme, token, ok := strings.Cut(v, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// peerSubjects 提取 mTLS 对端证书的 CommonName 与 DNS SAN
+func peerSubjects(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := info.State.PeerCertificates[0]
+	var subjects []string
+	if cert.Subject.CommonName != "" {
+		subjects = append(subjects, cert.Subject.CommonName)
+	}
+	return append(subjects, cert.DNSNames...)
+}
+
+// RegisterAdminServer 在 gRPC 服务上注册管理服务
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv *AdminServer) {
+	RegisterAdminServiceServer(s, srv)
+}
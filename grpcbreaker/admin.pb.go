@@ -0,0 +1,131 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// 熔断器 gRPC 管理服务，消息定义见 cbpb/circuitbreaker.proto
+// 与消息共用 circuitbreaker.v1 包，完整服务名为 circuitbreaker.v1.AdminService
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: grpcbreaker/admin.proto
+
+package grpcbreaker
+
+import (
+	cbpb "github.com/go-anyway/framework-circuitbreaker/cbpb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_grpcbreaker_admin_proto protoreflect.FileDescriptor
+
+var file_grpcbreaker_admin_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2f, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x63, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x1a, 0x19, 0x63, 0x62,
+	0x70, 0x62, 0x2f, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x82, 0x03, 0x0a, 0x0c, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x1e, 0x2e, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1f, 0x2e, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x41, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x1d, 0x2e, 0x63, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69,
+	0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x12, 0x45, 0x0a, 0x05, 0x52, 0x65, 0x73, 0x65, 0x74, 0x12, 0x1f, 0x2e,
+	0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x4d, 0x0a, 0x09, 0x46,
+	0x6f, 0x72, 0x63, 0x65, 0x4f, 0x70, 0x65, 0x6e, 0x12, 0x23, 0x2e, 0x63, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x6f, 0x72,
+	0x63, 0x65, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
+	0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x50, 0x0a, 0x0b, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x63, 0x69, 0x72, 0x63,
+	0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x18, 0x2e, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x3b, 0x5a, 0x39,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x2d, 0x61, 0x6e,
+	0x79, 0x77, 0x61, 0x79, 0x2f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x2d, 0x63,
+	0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2f, 0x67, 0x72,
+	0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var file_grpcbreaker_admin_proto_goTypes = []any{
+	(*cbpb.ListRequest)(nil),        // 0: circuitbreaker.v1.ListRequest
+	(*cbpb.GetRequest)(nil),         // 1: circuitbreaker.v1.GetRequest
+	(*cbpb.ResetRequest)(nil),       // 2: circuitbreaker.v1.ResetRequest
+	(*cbpb.ForceOpenRequest)(nil),   // 3: circuitbreaker.v1.ForceOpenRequest
+	(*cbpb.WatchEventsRequest)(nil), // 4: circuitbreaker.v1.WatchEventsRequest
+	(*cbpb.ListResponse)(nil),       // 5: circuitbreaker.v1.ListResponse
+	(*cbpb.Snapshot)(nil),           // 6: circuitbreaker.v1.Snapshot
+	(*cbpb.Event)(nil),              // 7: circuitbreaker.v1.Event
+}
+var file_grpcbreaker_admin_proto_depIdxs = []int32{
+	0, // 0: circuitbreaker.v1.AdminService.List:input_type -> circuitbreaker.v1.ListRequest
+	1, // 1: circuitbreaker.v1.AdminService.Get:input_type -> circuitbreaker.v1.GetRequest
+	2, // 2: circuitbreaker.v1.AdminService.Reset:input_type -> circuitbreaker.v1.ResetRequest
+	3, // 3: circuitbreaker.v1.AdminService.ForceOpen:input_type -> circuitbreaker.v1.ForceOpenRequest
+	4, // 4: circuitbreaker.v1.AdminService.WatchEvents:input_type -> circuitbreaker.v1.WatchEventsRequest
+	5, // 5: circuitbreaker.v1.AdminService.List:output_type -> circuitbreaker.v1.ListResponse
+	6, // 6: circuitbreaker.v1.AdminService.Get:output_type -> circuitbreaker.v1.Snapshot
+	6, // 7: circuitbreaker.v1.AdminService.Reset:output_type -> circuitbreaker.v1.Snapshot
+	6, // 8: circuitbreaker.v1.AdminService.ForceOpen:output_type -> circuitbreaker.v1.Snapshot
+	7, // 9: circuitbreaker.v1.AdminService.WatchEvents:output_type -> circuitbreaker.v1.Event
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_grpcbreaker_admin_proto_init() }
+func file_grpcbreaker_admin_proto_init() {
+	if File_grpcbreaker_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_grpcbreaker_admin_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_grpcbreaker_admin_proto_goTypes,
+		DependencyIndexes: file_grpcbreaker_admin_proto_depIdxs,
+	}.Build()
+	File_grpcbreaker_admin_proto = out.File
+	file_grpcbreaker_admin_proto_rawDesc = nil
+	file_grpcbreaker_admin_proto_goTypes = nil
+	file_grpcbreaker_admin_proto_depIdxs = nil
+}
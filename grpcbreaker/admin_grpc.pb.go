@@ -0,0 +1,311 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// 熔断器 gRPC 管理服务，消息定义见 cbpb/circuitbreaker.proto
+// 与消息共用 circuitbreaker.v1 包，完整服务名为 circuitbreaker.v1.AdminService
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: grpcbreaker/admin.proto
+
+package grpcbreaker
+
+import (
+	context "context"
+	cbpb "github.com/go-anyway/framework-circuitbreaker/cbpb"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_List_FullMethodName        = "/circuitbreaker.v1.AdminService/List"
+	AdminService_Get_FullMethodName         = "/circuitbreaker.v1.AdminService/Get"
+	AdminService_Reset_FullMethodName       = "/circuitbreaker.v1.AdminService/Reset"
+	AdminService_ForceOpen_FullMethodName   = "/circuitbreaker.v1.AdminService/ForceOpen"
+	AdminService_WatchEvents_FullMethodName = "/circuitbreaker.v1.AdminService/WatchEvents"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService 熔断器管理服务
+type AdminServiceClient interface {
+	// List 列出所有熔断器快照（按名称排序）
+	List(ctx context.Context, in *cbpb.ListRequest, opts ...grpc.CallOption) (*cbpb.ListResponse, error)
+	// Get 获取单个熔断器快照
+	Get(ctx context.Context, in *cbpb.GetRequest, opts ...grpc.CallOption) (*cbpb.Snapshot, error)
+	// Reset 手动恢复为关闭状态
+	Reset(ctx context.Context, in *cbpb.ResetRequest, opts ...grpc.CallOption) (*cbpb.Snapshot, error)
+	// ForceOpen 在指定时长内强制打开
+	ForceOpen(ctx context.Context, in *cbpb.ForceOpenRequest, opts ...grpc.CallOption) (*cbpb.Snapshot, error)
+	// WatchEvents 推送状态变更事件
+	WatchEvents(ctx context.Context, in *cbpb.WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[cbpb.Event], error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) List(ctx context.Context, in *cbpb.ListRequest, opts ...grpc.CallOption) (*cbpb.ListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(cbpb.ListResponse)
+	err := c.cc.Invoke(ctx, AdminService_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Get(ctx context.Context, in *cbpb.GetRequest, opts ...grpc.CallOption) (*cbpb.Snapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(cbpb.Snapshot)
+	err := c.cc.Invoke(ctx, AdminService_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Reset(ctx context.Context, in *cbpb.ResetRequest, opts ...grpc.CallOption) (*cbpb.Snapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(cbpb.Snapshot)
+	err := c.cc.Invoke(ctx, AdminService_Reset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ForceOpen(ctx context.Context, in *cbpb.ForceOpenRequest, opts ...grpc.CallOption) (*cbpb.Snapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(cbpb.Snapshot)
+	err := c.cc.Invoke(ctx, AdminService_ForceOpen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) WatchEvents(ctx context.Context, in *cbpb.WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[cbpb.Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[cbpb.WatchEventsRequest, cbpb.Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_WatchEventsClient = grpc.ServerStreamingClient[cbpb.Event]
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService 熔断器管理服务
+type AdminServiceServer interface {
+	// List 列出所有熔断器快照（按名称排序）
+	List(context.Context, *cbpb.ListRequest) (*cbpb.ListResponse, error)
+	// Get 获取单个熔断器快照
+	Get(context.Context, *cbpb.GetRequest) (*cbpb.Snapshot, error)
+	// Reset 手动恢复为关闭状态
+	Reset(context.Context, *cbpb.ResetRequest) (*cbpb.Snapshot, error)
+	// ForceOpen 在指定时长内强制打开
+	ForceOpen(context.Context, *cbpb.ForceOpenRequest) (*cbpb.Snapshot, error)
+	// WatchEvents 推送状态变更事件
+	WatchEvents(*cbpb.WatchEventsRequest, grpc.ServerStreamingServer[cbpb.Event]) error
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) List(context.Context, *cbpb.ListRequest) (*cbpb.ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedAdminServiceServer) Get(context.Context, *cbpb.GetRequest) (*cbpb.Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedAdminServiceServer) Reset(context.Context, *cbpb.ResetRequest) (*cbpb.Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reset not implemented")
+}
+func (UnimplementedAdminServiceServer) ForceOpen(context.Context, *cbpb.ForceOpenRequest) (*cbpb.Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceOpen not implemented")
+}
+func (UnimplementedAdminServiceServer) WatchEvents(*cbpb.WatchEventsRequest, grpc.ServerStreamingServer[cbpb.Event]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(cbpb.ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).List(ctx, req.(*cbpb.ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(cbpb.GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Get(ctx, req.(*cbpb.GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(cbpb.ResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Reset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Reset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Reset(ctx, req.(*cbpb.ResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ForceOpen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(cbpb.ForceOpenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ForceOpen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ForceOpen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ForceOpen(ctx, req.(*cbpb.ForceOpenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(cbpb.WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).WatchEvents(m, &grpc.GenericServerStream[cbpb.WatchEventsRequest, cbpb.Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_WatchEventsServer = grpc.ServerStreamingServer[cbpb.Event]
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "circuitbreaker.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _AdminService_List_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _AdminService_Get_Handler,
+		},
+		{
+			MethodName: "Reset",
+			Handler:    _AdminService_Reset_Handler,
+		},
+		{
+			MethodName: "ForceOpen",
+			Handler:    _AdminService_ForceOpen_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _AdminService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "grpcbreaker/admin.proto",
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 zampo.
+
+package grpcbreaker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+	"github.com/go-anyway/framework-circuitbreaker/cbpb"
+)
+
+func TestAdminServer_Unary(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	cb := r.GetOrCreate("payments", tripOnFirstFailure())
+	r.GetOrCreate("orders", circuitbreaker.DefaultSettings())
+	s := NewAdminServer(r, WithAuthorizer(circuitbreaker.MutationAuthorizer(circuitbreaker.TokenAuthorizer("s3cret"))))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer s3cret"))
+
+	list, err := s.List(ctx, &cbpb.ListRequest{})
+	if err != nil || len(list.Breakers) != 2 || list.Breakers[0].Name != "orders" {
+		t.Fatalf("List() = %+v, %v, want orders and payments", list, err)
+	}
+
+	snap, err := s.ForceOpen(ctx, &cbpb.ForceOpenRequest{Name: "payments", DurationMs: 60000})
+	if err != nil || snap.State != cbpb.StateOpen {
+		t.Fatalf("ForceOpen() = %+v, %v, want open", snap, err)
+	}
+
+	snap, err = s.Reset(ctx, &cbpb.ResetRequest{Name: "payments"})
+	if err != nil || snap.State != cbpb.StateClosed {
+		t.Fatalf("Reset() = %+v, %v, want closed", snap, err)
+	}
+	if cb.State() != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", cb.State(), gobreaker.StateClosed)
+	}
+
+	if _, err := s.Get(ctx, &cbpb.GetRequest{Name: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Get(missing) code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+	if _, err := s.ForceOpen(ctx, &cbpb.ForceOpenRequest{Name: "payments"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ForceOpen(0) code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
+
+func TestAdminServer_Client(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+
+	var method string
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method = info.FullMethod
+		return handler(ctx, req)
+	}))
+	RegisterAdminServer(srv, NewAdminServer(r))
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	snap, err := NewAdminServiceClient(conn).Get(context.Background(), &cbpb.GetRequest{Name: "payments"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if snap.Name != "payments" {
+		t.Errorf("Name = %q, want %q", snap.Name, "payments")
+	}
+	if want := "/" + AdminServiceName + "/Get"; method != want {
+		t.Errorf("FullMethod = %q, want %q", method, want)
+	}
+}
+
+type fakeEventStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *cbpb.Event
+}
+
+func (f *fakeEventStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeEventStream) Send(e *cbpb.Event) error {
+	f.sent <- e
+	return nil
+}
+
+func TestAdminServer_WatchEvents(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	payments := r.GetOrCreate("payments", tripOnFirstFailure())
+	orders := r.GetOrCreate("orders", tripOnFirstFailure())
+	s := NewAdminServer(r)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeEventStream{ctx: ctx, sent: make(chan *cbpb.Event, 4)}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WatchEvents(&cbpb.WatchEventsRequest{Names: []string{"payments"}}, stream)
+	}()
+
+	// 等待订阅生效：重复触发直到收到事件
+	deadline := time.After(time.Second)
+	var e *cbpb.Event
+	for e == nil {
+		fail(orders)
+		fail(payments)
+		select {
+		case e = <-stream.sent:
+		case <-time.After(10 * time.Millisecond):
+			payments.Reset()
+			orders.Reset()
+		case <-deadline:
+			t.Fatal("no event within 1s")
+		}
+	}
+	if e.Breaker != "payments" || e.To != cbpb.StateOpen && e.To != cbpb.StateClosed {
+		t.Errorf("event = %+v, want payments transition", e)
+	}
+
+	data, _ := protojson.Marshal(e)
+	var decoded cbpb.Event
+	if err := protojson.Unmarshal(data, &decoded); err != nil || decoded.Breaker != "payments" {
+		t.Errorf("round trip = %v, %v", &decoded, err)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("WatchEvents() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestAdminServer_Authorization(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+	ctx := context.Background()
+
+	anonymous := NewAdminServer(r)
+	if _, err := anonymous.Get(ctx, &cbpb.GetRequest{Name: "payments"}); err != nil {
+		t.Errorf("Get() without authorizer error = %v, want nil", err)
+	}
+	if _, err := anonymous.Reset(ctx, &cbpb.ResetRequest{Name: "payments"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Reset() without authorizer code = %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+
+	s := NewAdminServer(r, WithAuthorizer(circuitbreaker.TokenAuthorizer("s3cret")))
+	if _, err := s.List(ctx, &cbpb.ListRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("List() without token code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+
+	bad := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer guess"))
+	if _, err := s.ForceOpen(bad, &cbpb.ForceOpenRequest{Name: "payments", DurationMs: 1000}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ForceOpen() with wrong token code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+}
+
+func TestPeerSubjects(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "oncall"}, DNSNames: []string{"ops.internal"}}
+	info := credentials.TLSInfo{State: tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+
+	if got, want := peerSubjects(ctx), []string{"oncall", "ops.internal"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("peerSubjects() = %v, want %v", got, want)
+	}
+
+	info.State.VerifiedChains = nil
+	ctx = peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+	if got := peerSubjects(ctx); got != nil {
+		t.Errorf("peerSubjects() with unverified chain = %v, want nil", got)
+	}
+}
+
+func TestAdminServer_ReadOnly(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	cb := r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+	var audited []circuitbreaker.AdminAuditEvent
+	s := NewAdminServer(r,
+		WithAdminMode(circuitbreaker.AdminReadOnly),
+		WithAuthorizer(circuitbreaker.MutationAuthorizer(circuitbreaker.TokenAuthorizer("s3cret"))),
+		WithAudit(func(e circuitbreaker.AdminAuditEvent) { audited = append(audited, e) }),
+	)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer s3cret"))
+
+	if _, err := s.Get(ctx, &cbpb.GetRequest{Name: "payments"}); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+	_, err := s.ForceOpen(ctx, &cbpb.ForceOpenRequest{Name: "payments", DurationMs: 1000})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ForceOpen() code = %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+	if cb.State() != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", cb.State(), gobreaker.StateClosed)
+	}
+	if len(audited) != 1 || audited[0].Allowed || audited[0].Operation != circuitbreaker.AdminForceOpen {
+		t.Errorf("audited = %+v, want one denied force_open", audited)
+	}
+}
+
+func TestAdminServer_ForceOpenLease(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+	s := NewAdminServer(r, WithAuthorizer(circuitbreaker.TokenAuthorizer("alice-token", "bob-token")))
+	aliceCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer alice-token"))
+	bobCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer bob-token"))
+	alice, _ := circuitbreaker.AdminRequest{Token: "alice-token"}.LeaseOwner("")
+
+	// 持有者与凭证绑定，不能以他人名义获取租约
+	_, err := s.ForceOpen(bobCtx, &cbpb.ForceOpenRequest{Name: "payments", DurationMs: 60000, Owner: "alice"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ForceOpen() as another owner code = %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+	snap, err := s.ForceOpen(aliceCtx, &cbpb.ForceOpenRequest{Name: "payments", DurationMs: 60000})
+	if err != nil || snap.Lease == nil || snap.Lease.Owner != alice {
+		t.Fatalf("ForceOpen(alice) = %v, %v, want lease held by %s", snap, err, alice)
+	}
+	_, err = s.ForceOpen(bobCtx, &cbpb.ForceOpenRequest{Name: "payments", DurationMs: 60000, Owner: alice})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("renew by bob as alice code = %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+	_, err = s.ForceOpen(bobCtx, &cbpb.ForceOpenRequest{Name: "payments", DurationMs: 60000})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ForceOpen(bob) code = %v, want %v", status.Code(err), codes.FailedPrecondition)
+	}
+}
+
+func TestAdminServer_ForceOpenAuthorizesFirst(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+	var audited []circuitbreaker.AdminAuditEvent
+	s := NewAdminServer(r,
+		WithAuthorizer(circuitbreaker.TokenAuthorizer("s3cret")),
+		WithAudit(func(e circuitbreaker.AdminAuditEvent) { audited = append(audited, e) }),
+	)
+
+	// 未授权的调用方即使参数非法也得到鉴权错误，并产生审计事件
+	_, err := s.ForceOpen(context.Background(), &cbpb.ForceOpenRequest{Name: "payments", DurationMs: -1})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("anonymous ForceOpen(-1) code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+	if len(audited) != 1 || audited[0].Allowed {
+		t.Errorf("audited = %+v, want one denied force_open", audited)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer s3cret"))
+	_, err = s.ForceOpen(ctx, &cbpb.ForceOpenRequest{Name: "payments", DurationMs: -1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ForceOpen(-1) code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
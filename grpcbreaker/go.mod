@@ -0,0 +1,18 @@
+module github.com/go-anyway/framework-circuitbreaker/grpcbreaker
+
+go 1.25.4
+
+require (
+	github.com/go-anyway/framework-circuitbreaker v0.0.0-20261016101936-22ab0cef4c5b
+	github.com/sony/gobreaker v1.0.0
+	google.golang.org/grpc v1.67.1
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/go-anyway/framework-circuitbreaker/cbpb v0.0.0-20261016101936-22ab0cef4c5b
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+)
@@ -0,0 +1,138 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package grpcbreaker 提供熔断器的 gRPC 集成
+package grpcbreaker
+
+import (
+	"context"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// HealthServer 基于熔断器注册表实现 grpc.health.v1.Health 服务
+// 服务名即熔断器名称，空服务名表示进程整体状态，始终返回 SERVING
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+
+	registry       *circuitbreaker.Registry
+	halfOpenStatus healthpb.HealthCheckResponse_ServingStatus
+	watchInterval  time.Duration
+}
+
+// HealthOption 健康服务配置项
+type HealthOption func(*HealthServer)
+
+// WithHalfOpenStatus 设置半开状态对应的服务状态，默认 SERVING（允许探测流量）
+func WithHalfOpenStatus(st healthpb.HealthCheckResponse_ServingStatus) HealthOption {
+	return func(s *HealthServer) {
+		s.halfOpenStatus = st
+	}
+}
+
+// WithWatchInterval 设置 Watch 的兜底轮询间隔，默认 5 秒
+// gobreaker 的 open -> half-open 转换是惰性的，需要轮询才能及时感知
+func WithWatchInterval(d time.Duration) HealthOption {
+	return func(s *HealthServer) {
+		s.watchInterval = d
+	}
+}
+
+// NewHealthServer 创建健康检查服务
+func NewHealthServer(registry *circuitbreaker.Registry, opts ...HealthOption) *HealthServer {
+	s := &HealthServer{
+		registry:       registry,
+		halfOpenStatus: healthpb.HealthCheckResponse_SERVING,
+		watchInterval:  5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Check 返回指定依赖的服务状态，未注册的依赖返回 NotFound
+func (s *HealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st := s.servingStatus(req.GetService())
+	if st == healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch 推送指定依赖的服务状态，状态变化时发送新状态
+func (s *HealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	service := req.GetService()
+
+	changed := make(chan struct{}, 1)
+	unsubscribe := s.registry.Subscribe(func(name string, from, to gobreaker.State) {
+		if name != service {
+			return
+		}
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		if st := s.servingStatus(service); st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return status.Error(codes.Canceled, "Stream has ended.")
+			}
+			last = st
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "Stream has ended.")
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// servingStatus 将熔断器状态映射为服务状态
+func (s *HealthServer) servingStatus(service string) healthpb.HealthCheckResponse_ServingStatus {
+	if service == "" {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+
+	cb, ok := s.registry.Get(service)
+	if !ok {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+
+	switch cb.State() {
+	case gobreaker.StateClosed:
+		return healthpb.HealthCheckResponse_SERVING
+	case gobreaker.StateHalfOpen:
+		return s.halfOpenStatus
+	default:
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+}
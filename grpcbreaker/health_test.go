@@ -0,0 +1,119 @@
+// Copyright 2025 zampo.
+
+package grpcbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func tripOnFirstFailure() circuitbreaker.Settings {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	return settings
+}
+
+func fail(cb *circuitbreaker.CircuitBreaker) {
+	cb.Execute(func() (interface{}, error) {
+		return nil, errors.New("fail")
+	})
+}
+
+func TestHealthServer_Check(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	cb := r.GetOrCreate("payments", tripOnFirstFailure())
+	s := NewHealthServer(r)
+
+	tests := []struct {
+		service string
+		before  func()
+		want    healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{service: "", want: healthpb.HealthCheckResponse_SERVING},
+		{service: "payments", want: healthpb.HealthCheckResponse_SERVING},
+		{service: "payments", before: func() { fail(cb) }, want: healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+
+	for _, tt := range tests {
+		if tt.before != nil {
+			tt.before()
+		}
+		resp, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: tt.service})
+		if err != nil {
+			t.Fatalf("Check(%q) error = %v", tt.service, err)
+		}
+		if resp.GetStatus() != tt.want {
+			t.Errorf("Check(%q) = %v, want %v", tt.service, resp.GetStatus(), tt.want)
+		}
+	}
+}
+
+func TestHealthServer_CheckUnknown(t *testing.T) {
+	s := NewHealthServer(circuitbreaker.NewRegistry())
+
+	_, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Check() code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
+
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (f *fakeWatchStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeWatchStream) Send(resp *healthpb.HealthCheckResponse) error {
+	f.sent <- resp.GetStatus()
+	return nil
+}
+
+func TestHealthServer_Watch(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	cb := r.GetOrCreate("payments", tripOnFirstFailure())
+	s := NewHealthServer(r, WithWatchInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeWatchStream{ctx: ctx, sent: make(chan healthpb.HealthCheckResponse_ServingStatus, 4)}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Watch(&healthpb.HealthCheckRequest{Service: "payments"}, stream)
+	}()
+
+	if got := <-stream.sent; got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("initial status = %v, want %v", got, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	fail(cb)
+	select {
+	case got := <-stream.sent:
+		if got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("status after trip = %v, want %v", got, healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no status sent after trip")
+	}
+
+	cancel()
+	if err := <-done; status.Code(err) != codes.Canceled {
+		t.Errorf("Watch() code = %v, want %v", status.Code(err), codes.Canceled)
+	}
+}
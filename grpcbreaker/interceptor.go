@@ -0,0 +1,86 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package grpcbreaker
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// DefaultIsFailure 默认失败判定：仅服务端或链路问题计为失败，
+// 参数错误、未找到等业务错误不影响熔断
+func DefaultIsFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted,
+		codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnaryClientInterceptor 返回带熔断保护的一元客户端拦截器
+// 单次调用的超时取 min(熔断器 CallTimeout, ctx 剩余时间)，实际触发的上限记录在 Stats 中；
+// 调用方 ctx 取消或超时默认不计为失败；熔断拒绝时返回 codes.Unavailable
+func UnaryClientInterceptor(cb *circuitbreaker.CircuitBreaker, opts ...InterceptorOption) grpc.UnaryClientInterceptor {
+	o := interceptorOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		done, err := cb.AllowContext(ctx)
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+
+		callCtx, cancel, _ := cb.CallContext(ctx)
+		defer cancel()
+
+		var trailer metadata.MD
+		if o.pushback {
+			opts = append(opts, grpc.Trailer(&trailer))
+		}
+		err = invoker(callCtx, method, req, reply, cc, opts...)
+		if err != nil {
+			cb.RecordTimeout(circuitbreaker.TimeoutBoundOf(callCtx))
+		}
+		done(circuitbreaker.ContextError(callCtx, outcomeError(ctx, err)))
+		if o.pushback {
+			o.honorPushback(cb, trailer)
+		}
+		return err
+	}
+}
+
+// outcomeError 将 gRPC 错误转换为用于熔断分类的错误
+// status 错误不会匹配 context 错误，调用方 ctx 结束时需换成 ctx.Err() 以便核心包识别
+func outcomeError(ctx context.Context, err error) error {
+	if !DefaultIsFailure(err) {
+		return nil
+	}
+	if code := status.Code(err); ctx.Err() != nil && (code == codes.Canceled || code == codes.DeadlineExceeded) {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 zampo.
+
+package grpcbreaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestUnaryClientInterceptor_Classification(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+	interceptor := UnaryClientInterceptor(cb)
+
+	for _, code := range []codes.Code{codes.OK, codes.NotFound, codes.Unavailable} {
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return status.Error(code, "")
+		}
+		interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+	}
+
+	counts := cb.Counts()
+	if counts.TotalSuccesses != 2 || counts.TotalFailures != 1 {
+		t.Errorf("successes, failures = %v, %v, want 2, 1", counts.TotalSuccesses, counts.TotalFailures)
+	}
+}
+
+func TestUnaryClientInterceptor_CallTimeout(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.CallTimeout = 10 * time.Millisecond
+	cb := circuitbreaker.NewCircuitBreaker("test", settings)
+	interceptor := UnaryClientInterceptor(cb)
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		<-ctx.Done()
+		return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+	}
+	err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.DeadlineExceeded)
+	}
+	if got := cb.Stats().CallTimeouts; got != 1 {
+		t.Errorf("CallTimeouts = %v, want %v", got, 1)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package grpcbreaker
+
+import (
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// PushbackKey 服务端重试推回的 trailer 键（gRFC A6），值为毫秒数
+const PushbackKey = "grpc-retry-pushback-ms"
+
+// InterceptorOption 客户端拦截器配置项
+type InterceptorOption func(*interceptorOptions)
+
+type interceptorOptions struct {
+	pushback    bool
+	maxPushback time.Duration
+}
+
+// WithPushback 遵循服务端 grpc-retry-pushback-ms：收到正值时按其时长保持熔断器打开，
+// max 为时长上限，0 表示不限制；负值或无法解析的值表示服务端不希望重试，忽略
+func WithPushback(max time.Duration) InterceptorOption {
+	return func(o *interceptorOptions) {
+		o.pushback = true
+		o.maxPushback = max
+	}
+}
+
+// ParsePushback 解析 trailer 中的推回时长，不存在、为负或无法解析时返回 false
+func ParsePushback(md metadata.MD) (time.Duration, bool) {
+	values := md.Get(PushbackKey)
+	if len(values) != 1 {
+		return 0, false
+	}
+	ms, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// honorPushback 按服务端推回时长保持熔断器打开
+func (o *interceptorOptions) honorPushback(cb *circuitbreaker.CircuitBreaker, trailer metadata.MD) {
+	d, ok := ParsePushback(trailer)
+	if !ok {
+		return
+	}
+	if o.maxPushback > 0 && d > o.maxPushback {
+		d = o.maxPushback
+	}
+	cb.OpenFor(d)
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 zampo.
+
+package grpcbreaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestParsePushback(t *testing.T) {
+	tests := []struct {
+		md   metadata.MD
+		want time.Duration
+		ok   bool
+	}{
+		{metadata.Pairs(PushbackKey, "1500"), 1500 * time.Millisecond, true},
+		{metadata.Pairs(PushbackKey, "-1"), 0, false},
+		{metadata.Pairs(PushbackKey, "soon"), 0, false},
+		{metadata.Pairs(PushbackKey, "1", PushbackKey, "2"), 0, false},
+		{nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParsePushback(tt.md)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParsePushback(%v) = %v, %v, want %v, %v", tt.md, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestUnaryClientInterceptor_Pushback(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+	interceptor := UnaryClientInterceptor(cb, WithPushback(time.Minute))
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		for _, opt := range opts {
+			if o, ok := opt.(grpc.TrailerCallOption); ok {
+				*o.TrailerAddr = metadata.Pairs(PushbackKey, "3600000")
+			}
+		}
+		return status.Error(codes.ResourceExhausted, "slow down")
+	}
+	interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+	if until := time.Until(cb.OpenUntil()); until <= 0 || until > time.Minute {
+		t.Errorf("OpenUntil() in %v, want capped at %v", until, time.Minute)
+	}
+	err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.Unavailable)
+	}
+}
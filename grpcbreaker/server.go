@@ -0,0 +1,59 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package grpcbreaker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// UnaryServerInterceptor 返回带熔断保护的一元服务端拦截器，按 DefaultIsFailure 判定处理器的错误
+// 熔断拒绝时返回 codes.Unavailable，并在 trailer 中以 grpc-retry-pushback-ms 告知熔断器预计半开的时间，
+// 客户端使用 WithPushback 时会据此暂停发送
+func UnaryServerInterceptor(cb *circuitbreaker.CircuitBreaker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		done, err := cb.AllowContext(ctx)
+		if err != nil {
+			if d, ok := circuitbreaker.RetryAfter(err); ok {
+				ms := d.Milliseconds()
+				if ms < 1 {
+					ms = 1
+				}
+				grpc.SetTrailer(ctx, metadata.Pairs(PushbackKey, strconv.FormatInt(ms, 10)))
+			}
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+
+		defer func() {
+			if e := recover(); e != nil {
+				done(fmt.Errorf("grpcbreaker: panic: %v", e))
+				panic(e)
+			}
+		}()
+		resp, err = handler(ctx, req)
+		done(outcomeError(ctx, err))
+		return resp, err
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 zampo.
+
+package grpcbreaker
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// trailerStream 记录 SetTrailer 的 ServerTransportStream
+type trailerStream struct {
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string                  { return "/svc/Method" }
+func (s *trailerStream) SetHeader(metadata.MD) error     { return nil }
+func (s *trailerStream) SendHeader(metadata.MD) error    { return nil }
+func (s *trailerStream) SetTrailer(md metadata.MD) error { s.trailer = md; return nil }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	settings := tripOnFirstFailure()
+	settings.Timeout = 30 * time.Second
+	cb := circuitbreaker.NewCircuitBreaker("server", settings)
+	interceptor := UnaryServerInterceptor(cb)
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+
+	resp, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" {
+		t.Fatalf("interceptor() = %v, %v, want ok", resp, err)
+	}
+
+	// 业务错误不计为失败
+	interceptor(context.Background(), "req", info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	if cb.State() != gobreaker.StateClosed {
+		t.Fatalf("State() = %v after NotFound, want closed", cb.State())
+	}
+
+	interceptor(context.Background(), "req", info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	})
+	if cb.State() != gobreaker.StateOpen {
+		t.Fatalf("State() = %v after Internal, want open", cb.State())
+	}
+
+	stream := &trailerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	called := false
+	_, err = interceptor(ctx, "req", info, func(context.Context, interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+	if called || status.Code(err) != codes.Unavailable {
+		t.Errorf("rejected call: handler called = %v, code = %v, want Unavailable", called, status.Code(err))
+	}
+	ms, perr := strconv.ParseInt(firstValue(stream.trailer, PushbackKey), 10, 64)
+	if perr != nil || ms <= 29000 || ms > 30000 {
+		t.Errorf("pushback trailer = %v, want ~30000ms", stream.trailer)
+	}
+	if d, ok := ParsePushback(stream.trailer); !ok || d > 30*time.Second {
+		t.Errorf("ParsePushback() = %v, %v", d, ok)
+	}
+}
+
+func TestUnaryServerInterceptor_Panic(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("server", tripOnFirstFailure())
+	interceptor := UnaryServerInterceptor(cb)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic not propagated")
+			}
+		}()
+		interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+			panic(errors.New("boom"))
+		})
+	}()
+	if cb.State() != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want open after panic", cb.State())
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
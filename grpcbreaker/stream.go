@@ -0,0 +1,102 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package grpcbreaker
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// StreamClientInterceptor 返回带熔断保护的流式客户端拦截器
+// 建立流时检查熔断器，结果在流结束时才上报：RecvMsg 返回 io.EOF（或非服务端流的最终响应）计为成功，
+// 其他错误按 DefaultIsFailure 分类；流的生命周期通常较长，不应用 CallTimeout。
+// 调用方需读取至流结束或取消 ctx，否则该调用一直占用熔断器（半开状态下占用探测名额）
+func StreamClientInterceptor(cb *circuitbreaker.CircuitBreaker) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		done, err := cb.AllowContext(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			done(outcomeError(ctx, err))
+			return nil, err
+		}
+
+		s := &breakerStream{
+			ClientStream:  stream,
+			ctx:           ctx,
+			serverStreams: desc.ServerStreams,
+			done:          done,
+			finished:      make(chan struct{}),
+		}
+		go s.watch()
+		return s, nil
+	}
+}
+
+// breakerStream 在流结束时上报熔断结果
+type breakerStream struct {
+	grpc.ClientStream
+
+	ctx           context.Context
+	serverStreams bool
+	done          func(err error)
+
+	once     sync.Once
+	finished chan struct{}
+}
+
+// RecvMsg 接收消息并在流结束时上报结果
+func (s *breakerStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		s.finish(nil)
+	case err != nil:
+		s.finish(outcomeError(s.ctx, err))
+	case !s.serverStreams:
+		// 非服务端流（一元响应或客户端流）收到响应即结束
+		s.finish(nil)
+	}
+	return err
+}
+
+// watch 调用方 ctx 结束时上报，避免未读完的流一直占用熔断器
+func (s *breakerStream) watch() {
+	select {
+	case <-s.ctx.Done():
+		s.finish(s.ctx.Err())
+	case <-s.finished:
+	}
+}
+
+// finish 上报一次结果，仅第一次生效
+func (s *breakerStream) finish(err error) {
+	s.once.Do(func() {
+		s.done(err)
+		close(s.finished)
+	})
+}
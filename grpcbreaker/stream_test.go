@@ -0,0 +1,119 @@
+// Copyright 2025 zampo.
+
+package grpcbreaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// fakeClientStream 依次返回 recv 中的错误
+type fakeClientStream struct {
+	grpc.ClientStream
+	recv []error
+}
+
+func (f *fakeClientStream) RecvMsg(m interface{}) error {
+	err := f.recv[0]
+	f.recv = f.recv[1:]
+	return err
+}
+
+func streamerFor(recv ...error) grpc.Streamer {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &fakeClientStream{recv: recv}, nil
+	}
+}
+
+func TestStreamClientInterceptor_OutcomeOnTermination(t *testing.T) {
+	tests := []struct {
+		name          string
+		recv          []error
+		serverStreams bool
+		successes     uint32
+		failures      uint32
+	}{
+		{"server stream ends with EOF", []error{nil, nil, io.EOF}, true, 1, 0},
+		{"server stream fails", []error{nil, status.Error(codes.Unavailable, "")}, true, 0, 1},
+		{"business error not counted", []error{status.Error(codes.NotFound, "")}, true, 1, 0},
+		{"client stream response", []error{nil}, false, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+			interceptor := StreamClientInterceptor(cb)
+			desc := &grpc.StreamDesc{ServerStreams: tt.serverStreams}
+
+			stream, err := interceptor(context.Background(), desc, nil, "/svc/Stream", streamerFor(tt.recv...))
+			if err != nil {
+				t.Fatalf("interceptor() error = %v", err)
+			}
+			if got := cb.Stats().InFlight; got != 1 {
+				t.Errorf("InFlight after open = %v, want 1", got)
+			}
+			if got := cb.Counts().Requests - cb.Counts().TotalSuccesses - cb.Counts().TotalFailures; got != 1 {
+				t.Errorf("pending outcomes after open = %v, want 1", got)
+			}
+
+			for range tt.recv {
+				if stream.RecvMsg(nil) != nil {
+					break
+				}
+			}
+
+			counts := cb.Counts()
+			if counts.TotalSuccesses != tt.successes || counts.TotalFailures != tt.failures {
+				t.Errorf("successes, failures = %v, %v, want %v, %v", counts.TotalSuccesses, counts.TotalFailures, tt.successes, tt.failures)
+			}
+			if got := cb.Stats().InFlight; got != 0 {
+				t.Errorf("InFlight = %v, want 0", got)
+			}
+		})
+	}
+}
+
+func TestStreamClientInterceptor_CallerCancel(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+	interceptor := StreamClientInterceptor(cb)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := interceptor(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, "/svc/Stream", streamerFor(nil))
+	if err != nil {
+		t.Fatalf("interceptor() error = %v", err)
+	}
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for cb.Stats().InFlight != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("stream still in flight after cancel")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := cb.Counts().TotalFailures; got != 0 {
+		t.Errorf("TotalFailures = %v, want 0", got)
+	}
+}
+
+func TestStreamClientInterceptor_Rejected(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("test", tripOnFirstFailure())
+	fail(cb)
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, errors.New("streamer should not be called")
+	}
+	_, err := StreamClientInterceptor(cb)(context.Background(), &grpc.StreamDesc{}, nil, "/svc/Stream", streamer)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.Unavailable)
+	}
+}
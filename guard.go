@@ -0,0 +1,137 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SkipEvent 定时任务因熔断器拒绝而跳过的事件
+type SkipEvent struct {
+	// Breaker 熔断器名称
+	Breaker string
+	// Reason 拒绝原因
+	Reason Reason
+	// At 跳过时间
+	At time.Time
+	// RunbookURL 熔断器的处置手册地址
+	RunbookURL string
+	// Tier 熔断器的严重级别，可据此路由通知
+	Tier Tier
+}
+
+// GuardStats 定时任务执行统计
+type GuardStats struct {
+	// Runs 实际执行次数
+	Runs uint64
+	// Failures 执行失败次数
+	Failures uint64
+	// Skipped 因熔断器拒绝而跳过的次数
+	Skipped uint64
+	// LastSkipped 最近一次跳过的时间
+	LastSkipped time.Time
+}
+
+// JobGuard 定时任务守卫，依赖熔断打开时跳过本次执行而不是继续累积失败
+// 应为每个定时任务创建一个并在多次调度间复用，以便累积统计
+type JobGuard struct {
+	cb Executor
+
+	runs     atomic.Uint64
+	failures atomic.Uint64
+	skipped  atomic.Uint64
+
+	mu          sync.Mutex
+	lastSkipped time.Time
+	onSkip      func(SkipEvent)
+}
+
+// Guard 创建定时任务守卫
+func Guard(cb Executor) *JobGuard {
+	return &JobGuard{cb: cb}
+}
+
+// OnSkip 设置跳过事件回调（如记录日志或上报事件），返回守卫本身便于链式调用
+func (g *JobGuard) OnSkip(fn func(SkipEvent)) *JobGuard {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onSkip = fn
+	return g
+}
+
+// Run 执行任务，熔断器拒绝时跳过并返回 ran=false 与 nil 错误
+func (g *JobGuard) Run(fn func() error) (ran bool, err error) {
+	return g.RunContext(context.Background(), func(context.Context) error {
+		return fn()
+	})
+}
+
+// RunContext 与 Run 相同，fn 收到的 ctx 带有熔断器的调用超时
+func (g *JobGuard) RunContext(ctx context.Context, fn func(ctx context.Context) error) (ran bool, err error) {
+	_, err = g.cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+
+	if reason := ReasonOf(err); reason != ReasonNone {
+		g.skip(reason)
+		return false, nil
+	}
+	g.runs.Add(1)
+	if err != nil {
+		g.failures.Add(1)
+	}
+	return true, err
+}
+
+// skip 记录一次跳过并触发回调
+func (g *JobGuard) skip(reason Reason) {
+	g.skipped.Add(1)
+	stats := g.cb.Stats()
+	event := SkipEvent{
+		Breaker:    g.cb.Name(),
+		Reason:     reason,
+		At:         time.Now(),
+		RunbookURL: stats.RunbookURL,
+		Tier:       stats.Tier,
+	}
+
+	g.mu.Lock()
+	g.lastSkipped = event.At
+	onSkip := g.onSkip
+	g.mu.Unlock()
+
+	if onSkip != nil {
+		onSkip(event)
+	}
+}
+
+// Stats 返回执行统计
+func (g *JobGuard) Stats() GuardStats {
+	g.mu.Lock()
+	last := g.lastSkipped
+	g.mu.Unlock()
+
+	return GuardStats{
+		Runs:        g.runs.Load(),
+		Failures:    g.failures.Load(),
+		Skipped:     g.skipped.Load(),
+		LastSkipped: last,
+	}
+}
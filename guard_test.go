@@ -0,0 +1,41 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobGuard(t *testing.T) {
+	cb := NewCircuitBreaker("sync", DefaultSettings())
+	var events []SkipEvent
+	g := Guard(cb).OnSkip(func(e SkipEvent) {
+		events = append(events, e)
+	})
+
+	if ran, err := g.Run(func() error { return nil }); !ran || err != nil {
+		t.Errorf("Run() = %v, %v, want true, nil", ran, err)
+	}
+	boom := errors.New("boom")
+	if ran, err := g.Run(func() error { return boom }); !ran || !errors.Is(err, boom) {
+		t.Errorf("Run() = %v, %v, want true, %v", ran, err, boom)
+	}
+
+	cb.OpenFor(time.Minute)
+	if ran, err := g.Run(func() error {
+		t.Error("job should be skipped")
+		return nil
+	}); ran || err != nil {
+		t.Errorf("Run() = %v, %v, want false, nil", ran, err)
+	}
+
+	stats := g.Stats()
+	if stats.Runs != 2 || stats.Failures != 1 || stats.Skipped != 1 || stats.LastSkipped.IsZero() {
+		t.Errorf("Stats() = %+v, want 2 runs, 1 failure, 1 skipped", stats)
+	}
+	if len(events) != 1 || events[0].Breaker != "sync" || events[0].Reason != ReasonOpen {
+		t.Errorf("events = %+v, want one sync/OPEN", events)
+	}
+}
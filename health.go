@@ -0,0 +1,138 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// HealthInput 健康评分的输入信号
+type HealthInput struct {
+	State  gobreaker.State
+	Counts gobreaker.Counts
+	// LatencyP99 最近调用的 P99 耗时
+	LatencyP99 time.Duration
+	// Pressure 外部饱和度（0~1），未配置 PressureFunc 时为 0
+	Pressure float64
+}
+
+// HealthScorer 将多个信号合成为 0~100 的健康评分，100 表示完全健康
+type HealthScorer func(in HealthInput) float64
+
+// DefaultHealthScorer 默认评分：不考虑延迟，失败率与饱和度各占一半权重
+var DefaultHealthScorer = WeightedHealthScorer(0, 0.5, 0, 0.5)
+
+// WeightedHealthScorer 按权重合成失败率、延迟与饱和度评分
+// latencyTarget 为期望的 P99 耗时，P99 超出时延迟得分按 target/P99 下降；打开状态固定为 0 分
+func WeightedHealthScorer(latencyTarget time.Duration, failureWeight, latencyWeight, pressureWeight float64) HealthScorer {
+	total := failureWeight + latencyWeight + pressureWeight
+	return func(in HealthInput) float64 {
+		if in.State == gobreaker.StateOpen {
+			return 0
+		}
+		if total <= 0 {
+			return 100
+		}
+
+		failure := 1.0
+		if in.Counts.Requests > 0 {
+			failure = 1 - float64(in.Counts.TotalFailures)/float64(in.Counts.Requests)
+		}
+		latency := 1.0
+		if latencyTarget > 0 && in.LatencyP99 > latencyTarget {
+			latency = float64(latencyTarget) / float64(in.LatencyP99)
+		}
+		pressure := 1 - clamp(in.Pressure, 0, 1)
+
+		score := (failure*failureWeight + latency*latencyWeight + pressure*pressureWeight) / total
+		return clamp(score*100, 0, 100)
+	}
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// healthScore 计算熔断器当前健康评分，调用方需持有读锁
+func (cb *CircuitBreaker) healthScore(state gobreaker.State, counts gobreaker.Counts, p99 time.Duration) float64 {
+	in := HealthInput{State: state, Counts: counts, LatencyP99: p99}
+	if cb.settings.PressureFunc != nil {
+		in.Pressure = cb.settings.PressureFunc()
+	}
+
+	scorer := cb.settings.HealthScorer
+	if scorer == nil {
+		scorer = DefaultHealthScorer
+	}
+	return scorer(in)
+}
+
+// latencyWindowSize 最近调用耗时的保留数量
+const latencyWindowSize = 256
+
+// latencyWindow 最近调用耗时的环形缓冲区
+type latencyWindow struct {
+	mu      sync.Mutex
+	samples [latencyWindowSize]time.Duration
+	next    int
+	full    bool
+}
+
+func (w *latencyWindow) observe(d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % latencyWindowSize
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+// quantile 返回最近调用耗时的分位数，无数据时返回 0
+func (w *latencyWindow) quantile(q float64) time.Duration {
+	w.mu.Lock()
+	n := w.next
+	if w.full {
+		n = latencyWindowSize
+	}
+	sorted := make([]time.Duration, n)
+	copy(sorted, w.samples[:n])
+	w.mu.Unlock()
+
+	if n == 0 {
+		return 0
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(q*float64(n)+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= n {
+		idx = n - 1
+	}
+	return sorted[idx]
+}
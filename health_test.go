@@ -0,0 +1,63 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestWeightedHealthScorer(t *testing.T) {
+	scorer := WeightedHealthScorer(100*time.Millisecond, 1, 1, 0)
+
+	tests := []struct {
+		name string
+		in   HealthInput
+		want float64
+	}{
+		{"healthy", HealthInput{Counts: gobreaker.Counts{Requests: 10}, LatencyP99: 50 * time.Millisecond}, 100},
+		{"half failing", HealthInput{Counts: gobreaker.Counts{Requests: 10, TotalFailures: 5}}, 75},
+		{"slow", HealthInput{LatencyP99: 200 * time.Millisecond}, 75},
+		{"open", HealthInput{State: gobreaker.StateOpen}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := scorer(tt.in); got != tt.want {
+			t.Errorf("%s: score = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCircuitBreaker_HealthScore(t *testing.T) {
+	settings := DefaultSettings()
+	settings.PressureFunc = func() float64 { return 0.5 }
+	cb := NewCircuitBreaker("test", settings)
+
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+
+	// 失败率 50%，饱和度 50%，默认权重各占一半
+	if got := cb.Stats().HealthScore; got != 50 {
+		t.Errorf("HealthScore = %v, want %v", got, 50)
+	}
+}
+
+func TestLatencyWindow_Quantile(t *testing.T) {
+	var w latencyWindow
+	if got := w.quantile(0.99); got != 0 {
+		t.Errorf("quantile() on empty window = %v, want 0", got)
+	}
+
+	for i := 1; i <= 100; i++ {
+		w.observe(time.Duration(i) * time.Millisecond)
+	}
+	if got := w.quantile(0.99); got != 99*time.Millisecond {
+		t.Errorf("quantile(0.99) = %v, want %v", got, 99*time.Millisecond)
+	}
+	if got := w.quantile(0.5); got != 50*time.Millisecond {
+		t.Errorf("quantile(0.5) = %v, want %v", got, 50*time.Millisecond)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+var (
+	// ErrHookDropped 回调队列已满，回调被丢弃
+	ErrHookDropped = errors.New("circuitbreaker: hook queue full, dropped")
+	// ErrHookTimeout 回调执行超过 HookSettings.Timeout，工作协程已不再等待
+	ErrHookTimeout = errors.New("circuitbreaker: hook timeout")
+	// ErrHookPanic 回调发生 panic，已被隔离
+	ErrHookPanic = errors.New("circuitbreaker: hook panic")
+)
+
+// HookError 回调执行异常
+type HookError struct {
+	// Key 回调分组键，状态变更回调为熔断器名称
+	Key string
+	// Err ErrHookDropped、ErrHookTimeout 或包装了 panic 值的 ErrHookPanic
+	Err error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%v (%s)", e.Err, e.Key)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// HookSettings 回调工作池配置
+type HookSettings struct {
+	// Workers 工作协程数，默认 4
+	Workers int
+	// QueueSize 每个工作协程的队列长度，默认 256；队列满时丢弃回调而不是阻塞调用方
+	QueueSize int
+	// Timeout 单个回调的执行时限，默认 5 秒；到期后回调收到的 ctx 被取消，
+	// 工作协程不再等待（回调协程在返回前仍会运行），0 以下使用默认值
+	Timeout time.Duration
+	// OnError 回调被丢弃、超时或 panic 时调用，在工作池外的独立协程中执行
+	OnError func(HookError)
+}
+
+// HookStats 回调工作池统计
+type HookStats struct {
+	// Executed 已执行完成的回调数（含超时后最终返回的）
+	Executed uint64
+	// Dropped 因队列满被丢弃的回调数
+	Dropped uint64
+	// TimedOut 超过时限的回调数
+	TimedOut uint64
+	// Panicked 发生 panic 的回调数
+	Panicked uint64
+	// Running 已超时但仍在运行的回调数
+	Running int64
+}
+
+// hookTask 排队的回调
+type hookTask struct {
+	key string
+	fn  func(ctx context.Context)
+}
+
+// HookRunner 在有界工作池中异步执行用户回调（状态变更回调、通知、事件订阅者），
+// 使缓慢的外部通知（如 webhook）不会阻塞 Execute 或状态转换；同一键的回调由同一工作协程
+// 按提交顺序执行，保证单个熔断器的状态变更通知有序
+type HookRunner struct {
+	settings HookSettings
+	queues   []chan hookTask
+	wg       sync.WaitGroup
+
+	closeMu sync.RWMutex
+	closed  bool
+
+	executed atomic.Uint64
+	dropped  atomic.Uint64
+	timedOut atomic.Uint64
+	panicked atomic.Uint64
+	running  atomic.Int64
+}
+
+// NewHookRunner 创建并启动回调工作池，不再使用时调用 Close
+func NewHookRunner(settings HookSettings) *HookRunner {
+	if settings.Workers <= 0 {
+		settings.Workers = 4
+	}
+	if settings.QueueSize <= 0 {
+		settings.QueueSize = 256
+	}
+	if settings.Timeout <= 0 {
+		settings.Timeout = 5 * time.Second
+	}
+	h := &HookRunner{
+		settings: settings,
+		queues:   make([]chan hookTask, settings.Workers),
+	}
+	for i := range h.queues {
+		h.queues[i] = make(chan hookTask, settings.QueueSize)
+		h.wg.Add(1)
+		go h.work(h.queues[i])
+	}
+	return h
+}
+
+// Submit 提交回调，不阻塞；队列满或工作池已关闭时丢弃并返回 false
+func (h *HookRunner) Submit(key string, fn func(ctx context.Context)) bool {
+	h.closeMu.RLock()
+	defer h.closeMu.RUnlock()
+	if !h.closed {
+		select {
+		case h.queues[h.shard(key)] <- hookTask{key: key, fn: fn}:
+			return true
+		default:
+		}
+	}
+	h.dropped.Add(1)
+	h.report(key, ErrHookDropped)
+	return false
+}
+
+// StateChangeHook 将状态变更回调包装为经工作池异步执行的回调，按熔断器名称保证顺序；
+// 可直接用作 Settings.OnStateChange 或 Registry.Subscribe 的参数
+func (h *HookRunner) StateChangeHook(fn func(name string, from, to gobreaker.State)) func(name string, from, to gobreaker.State) {
+	return func(name string, from, to gobreaker.State) {
+		h.Submit(name, func(context.Context) { fn(name, from, to) })
+	}
+}
+
+// Stats 返回工作池统计
+func (h *HookRunner) Stats() HookStats {
+	return HookStats{
+		Executed: h.executed.Load(),
+		Dropped:  h.dropped.Load(),
+		TimedOut: h.timedOut.Load(),
+		Panicked: h.panicked.Load(),
+		Running:  h.running.Load(),
+	}
+}
+
+// Close 停止接收新回调，等待已排队的回调执行完毕（每个最多等待 Timeout）
+func (h *HookRunner) Close() {
+	h.closeMu.Lock()
+	if h.closed {
+		h.closeMu.Unlock()
+		return
+	}
+	h.closed = true
+	for _, q := range h.queues {
+		close(q)
+	}
+	h.closeMu.Unlock()
+	h.wg.Wait()
+}
+
+// shard 按键选择工作协程
+func (h *HookRunner) shard(key string) int {
+	f := fnv.New32a()
+	f.Write([]byte(key))
+	return int(f.Sum32() % uint32(len(h.queues)))
+}
+
+// work 工作协程：逐个执行队列中的回调
+func (h *HookRunner) work(queue <-chan hookTask) {
+	defer h.wg.Done()
+	for task := range queue {
+		h.run(task)
+	}
+}
+
+// 回调执行状态，决定超时计数由哪一方负责
+const (
+	hookRunning int32 = iota
+	hookFinished
+	hookAbandoned
+)
+
+// run 在独立协程中执行回调并最多等待 Timeout，隔离 panic
+func (h *HookRunner) run(task hookTask) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.settings.Timeout)
+	defer cancel()
+	var state atomic.Int32
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer func() {
+			if v := recover(); v != nil {
+				h.panicked.Add(1)
+				h.report(task.key, fmt.Errorf("%w: %v", ErrHookPanic, v))
+			}
+			h.executed.Add(1)
+			if !state.CompareAndSwap(hookRunning, hookFinished) {
+				h.running.Add(-1)
+			}
+		}()
+		task.fn(ctx)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if state.CompareAndSwap(hookRunning, hookAbandoned) {
+			h.running.Add(1)
+			h.timedOut.Add(1)
+			h.report(task.key, ErrHookTimeout)
+		}
+	}
+}
+
+// report 异步通知回调异常，避免 OnError 本身阻塞工作池或调用方
+func (h *HookRunner) report(key string, err error) {
+	if h.settings.OnError != nil {
+		go h.settings.OnError(HookError{Key: key, Err: err})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestHookRunner_SlowHookDoesNotBlockTransition(t *testing.T) {
+	hooks := NewHookRunner(HookSettings{Workers: 1, Timeout: time.Second})
+	defer hooks.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	settings := DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 }
+	settings.Hooks = hooks
+	settings.OnStateChange = func(name string, from, to gobreaker.State) { <-release }
+	cb := NewCircuitBreaker("svc", settings)
+
+	start := time.Now()
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	cb.Reset()
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("transitions took %v with a blocked hook", elapsed)
+	}
+	if cb.State() != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", cb.State(), gobreaker.StateClosed)
+	}
+}
+
+func TestHookRunner_OrderedPerKey(t *testing.T) {
+	hooks := NewHookRunner(HookSettings{Workers: 4})
+	var (
+		mu  sync.Mutex
+		got []int
+	)
+	for i := 0; i < 50; i++ {
+		i := i
+		hooks.Submit("payments", func(context.Context) {
+			mu.Lock()
+			got = append(got, i)
+			mu.Unlock()
+		})
+	}
+	hooks.Close()
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("hooks ran out of order: %v", got)
+		}
+	}
+	if len(got) != 50 || hooks.Stats().Executed != 50 {
+		t.Errorf("ran %d hooks, Stats() = %+v, want 50", len(got), hooks.Stats())
+	}
+}
+
+func TestHookRunner_TimeoutPanicDrop(t *testing.T) {
+	errs := make(chan HookError, 8)
+	hooks := NewHookRunner(HookSettings{
+		Workers:   1,
+		QueueSize: 1,
+		Timeout:   20 * time.Millisecond,
+		OnError:   func(e HookError) { errs <- e },
+	})
+	defer hooks.Close()
+
+	release := make(chan struct{})
+	var sawCancel sync.WaitGroup
+	sawCancel.Add(1)
+	hooks.Submit("slow", func(ctx context.Context) {
+		<-ctx.Done()
+		sawCancel.Done()
+		<-release
+	})
+	if e := <-errs; !errors.Is(&e, ErrHookTimeout) || e.Key != "slow" {
+		t.Errorf("error = %v, want timeout for slow", &e)
+	}
+	sawCancel.Wait()
+	waitFor(t, func() bool { return hooks.Stats().Running == 1 })
+
+	hooks.Submit("boom", func(context.Context) { panic("kaboom") })
+	if e := <-errs; !errors.Is(&e, ErrHookPanic) {
+		t.Errorf("error = %v, want panic", &e)
+	}
+
+	close(release)
+	waitFor(t, func() bool { return hooks.Stats().Running == 0 })
+
+	block := make(chan struct{})
+	hooks.Submit("a", func(context.Context) { <-block })
+	waitFor(t, func() bool { return len(hooks.queues[0]) == 0 })
+	hooks.Submit("b", func(context.Context) {})
+	if hooks.Submit("c", func(context.Context) {}) {
+		t.Error("Submit() with a full queue should drop")
+	}
+	close(block)
+	if st := hooks.Stats(); st.Dropped != 1 || st.Panicked != 1 || st.TimedOut != 1 {
+		t.Errorf("Stats() = %+v, want one drop, panic and timeout", st)
+	}
+}
+
+func TestRegistry_SetHookRunner(t *testing.T) {
+	hooks := NewHookRunner(HookSettings{})
+	defer hooks.Close()
+	r := NewRegistry()
+	r.SetHookRunner(hooks)
+
+	settings := DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 }
+	cb := r.GetOrCreate("payments", settings)
+
+	got := make(chan gobreaker.State, 2)
+	r.Subscribe(func(name string, from, to gobreaker.State) {
+		// 异步执行，可以安全调用熔断器方法
+		_ = cb.Stats()
+		got <- to
+	})
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	select {
+	case to := <-got:
+		if to != gobreaker.StateOpen {
+			t.Errorf("to = %v, want %v", to, gobreaker.StateOpen)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber not called within 1s")
+	}
+}
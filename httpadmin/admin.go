@@ -0,0 +1,188 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package httpadmin 提供熔断器的 HTTP 管理接口与调试页面，响应为 cbpb 消息的 protojson 编码。
+// 与 cbpb 一样是独立的嵌套模块，核心模块与 httpbreaker 因此不依赖 protobuf
+package httpadmin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+	"github.com/go-anyway/framework-circuitbreaker/cbpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// AdminHandler 熔断器 HTTP 管理接口，响应为 cbpb 消息的 JSON 编码：
+//
+//	GET  /breakers                                           列出所有熔断器
+//	GET  /breakers/{name}                                    获取单个熔断器
+//	POST /breakers/{name}/reset                              恢复为关闭状态并清除强制打开租约
+//	POST /breakers/{name}/force-open?duration=30s            获取或续约强制打开租约
+//	GET  /debug                                              HTML 调试页面，需调用 HandleDebug 启用
+//
+// 挂载到子路径时配合 http.StripPrefix 使用。令牌取自 Authorization: Bearer 请求头，
+// 证书主题取自已验证的客户端证书；未授权返回 401，无权限或只读模式下的修改操作返回 403，
+// 租约由其他持有者持有时返回 409。租约持有者与调用方身份绑定（见 circuitbreaker.AdminRequest.LeaseOwner），
+// 可选的 owner 参数只能是调用方的证书主题之一
+type AdminHandler struct {
+	registry *circuitbreaker.Registry
+	policy   circuitbreaker.AdminPolicy
+	mux      *http.ServeMux
+}
+
+// NewAdminHandler 创建 HTTP 管理接口
+func NewAdminHandler(registry *circuitbreaker.Registry, policy circuitbreaker.AdminPolicy) *AdminHandler {
+	h := &AdminHandler{
+		registry: registry,
+		policy:   policy,
+		mux:      http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /breakers", h.list)
+	h.mux.HandleFunc("GET /breakers/{name}", h.get)
+	h.mux.HandleFunc("POST /breakers/{name}/reset", h.reset)
+	h.mux.HandleFunc("POST /breakers/{name}/force-open", h.forceOpen)
+	return h
+}
+
+// ServeHTTP 实现 http.Handler
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.mux.ServeHTTP(w, req)
+}
+
+func (h *AdminHandler) list(w http.ResponseWriter, req *http.Request) {
+	if !h.authorize(w, req, circuitbreaker.AdminList, "") {
+		return
+	}
+	stats := h.registry.Stats()
+	resp := &cbpb.ListResponse{Breakers: make([]*cbpb.Snapshot, 0, len(stats))}
+	for _, st := range stats {
+		resp.Breakers = append(resp.Breakers, cbpb.FromStats(st))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *AdminHandler) get(w http.ResponseWriter, req *http.Request) {
+	if cb, ok := h.lookup(w, req, circuitbreaker.AdminGet); ok {
+		writeJSON(w, cbpb.FromStats(cb.Stats()))
+	}
+}
+
+func (h *AdminHandler) reset(w http.ResponseWriter, req *http.Request) {
+	if cb, ok := h.lookup(w, req, circuitbreaker.AdminReset); ok {
+		cb.Reset()
+		writeJSON(w, cbpb.FromStats(cb.Stats()))
+	}
+}
+
+func (h *AdminHandler) forceOpen(w http.ResponseWriter, req *http.Request) {
+	cb, ok := h.lookup(w, req, circuitbreaker.AdminForceOpen)
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(req.URL.Query().Get("duration"))
+	if err != nil || d <= 0 || d > circuitbreaker.MaxLeaseTTL {
+		http.Error(w, fmt.Sprintf("duration must be a Go duration in (0, %v]", circuitbreaker.MaxLeaseTTL), http.StatusBadRequest)
+		return
+	}
+	owner, err := circuitbreaker.AdminRequest{Token: bearerToken(req), Subjects: clientSubjects(req)}.LeaseOwner(req.URL.Query().Get("owner"))
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+	if lease, err := cb.ForceOpen(owner, d); err != nil {
+		http.Error(w, fmt.Sprintf("%v: %s until %s", err, lease.Owner, lease.Expires.UTC().Format(time.RFC3339)), http.StatusConflict)
+		return
+	}
+	writeJSON(w, cbpb.FromStats(cb.Stats()))
+}
+
+// lookup 授权后按路径中的名称查找熔断器，失败时已写入响应
+func (h *AdminHandler) lookup(w http.ResponseWriter, req *http.Request, op circuitbreaker.AdminOperation) (*circuitbreaker.CircuitBreaker, bool) {
+	name := req.PathValue("name")
+	if !h.authorize(w, req, op, name) {
+		return nil, false
+	}
+	cb, ok := h.registry.Get(name)
+	if !ok {
+		http.Error(w, "circuit breaker not found", http.StatusNotFound)
+	}
+	return cb, ok
+}
+
+// authorize 检查访问策略，拒绝时写入 401/403 并返回 false
+func (h *AdminHandler) authorize(w http.ResponseWriter, req *http.Request, op circuitbreaker.AdminOperation, name string) bool {
+	err := h.policy.Check(req.Context(), circuitbreaker.AdminRequest{
+		Operation: op,
+		Breaker:   name,
+		Token:     bearerToken(req),
+		Subjects:  clientSubjects(req),
+	})
+	if err != nil {
+		writeAuthError(w, err)
+		return false
+	}
+	return true
+}
+
+// writeAuthError 将 ErrUnauthenticated 写为 401，其余授权错误写为 403
+func writeAuthError(w http.ResponseWriter, err error) {
+	if errors.Is(err, circuitbreaker.ErrUnauthenticated) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusForbidden)
+}
+
+// bearerToken 从 Authorization 请求头提取 Bearer 令牌
+func bearerToken(req *http.Request) string {
+	scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// clientSubjects 提取已验证客户端证书的 CommonName 与 DNS SAN
+func clientSubjects(req *http.Request) []string {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := req.TLS.PeerCertificates[0]
+	var subjects []string
+	if cert.Subject.CommonName != "" {
+		subjects = append(subjects, cert.Subject.CommonName)
+	}
+	return append(subjects, cert.DNSNames...)
+}
+
+// writeJSON 以 protojson 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, m proto.Message) {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(data)
+}
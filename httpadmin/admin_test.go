@@ -0,0 +1,117 @@
+// Copyright 2025 zampo.
+
+package httpadmin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sony/gobreaker"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+	"github.com/go-anyway/framework-circuitbreaker/cbpb"
+)
+
+func serve(h http.Handler, method, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminHandler(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	cb := r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+	h := NewAdminHandler(r, circuitbreaker.AdminPolicy{
+		Authorizer: circuitbreaker.MutationAuthorizer(circuitbreaker.TokenAuthorizer("s3cret")),
+	})
+
+	rec := serve(h, http.MethodGet, "/breakers", "")
+	var list cbpb.ListResponse
+	if rec.Code != http.StatusOK || protojson.Unmarshal(rec.Body.Bytes(), &list) != nil || len(list.Breakers) != 1 {
+		t.Fatalf("GET /breakers = %v %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=1m", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous force-open = %v, want %v", rec.Code, http.StatusUnauthorized)
+	}
+	// 先鉴权再校验参数，未授权的调用方无法探测参数规则
+	if rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=bogus", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous force-open bad duration = %v, want %v", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=bogus", "s3cret"); rec.Code != http.StatusBadRequest {
+		t.Errorf("force-open bad duration = %v, want %v", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = serve(h, http.MethodPost, "/breakers/payments/force-open?duration=1m", "s3cret")
+	var snap cbpb.Snapshot
+	if rec.Code != http.StatusOK || protojson.Unmarshal(rec.Body.Bytes(), &snap) != nil || snap.State != cbpb.StateOpen {
+		t.Fatalf("force-open = %v %s, want open", rec.Code, rec.Body)
+	}
+
+	if rec := serve(h, http.MethodPost, "/breakers/payments/reset", "s3cret"); rec.Code != http.StatusOK {
+		t.Errorf("reset = %v, want %v", rec.Code, http.StatusOK)
+	}
+	if cb.State() != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", cb.State(), gobreaker.StateClosed)
+	}
+
+	if rec := serve(h, http.MethodGet, "/breakers/missing", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET missing = %v, want %v", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestAdminHandler_ReadOnly(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	cb := r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+	var audited []circuitbreaker.AdminAuditEvent
+	h := NewAdminHandler(r, circuitbreaker.AdminPolicy{
+		Mode:       circuitbreaker.AdminReadOnly,
+		Authorizer: circuitbreaker.TokenAuthorizer("s3cret"),
+		OnAudit:    func(e circuitbreaker.AdminAuditEvent) { audited = append(audited, e) },
+	})
+
+	if rec := serve(h, http.MethodGet, "/breakers/payments", "s3cret"); rec.Code != http.StatusOK {
+		t.Errorf("GET = %v, want %v", rec.Code, http.StatusOK)
+	}
+	if rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=1m", "s3cret"); rec.Code != http.StatusForbidden {
+		t.Errorf("force-open = %v, want %v", rec.Code, http.StatusForbidden)
+	}
+	if cb.State() != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", cb.State(), gobreaker.StateClosed)
+	}
+	if len(audited) != 1 || audited[0].Allowed {
+		t.Errorf("audited = %+v, want one denied mutation", audited)
+	}
+}
+
+func TestAdminHandler_ForceOpenLease(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	r.GetOrCreate("payments", circuitbreaker.DefaultSettings())
+	h := NewAdminHandler(r, circuitbreaker.AdminPolicy{Authorizer: circuitbreaker.TokenAuthorizer("alice-token", "bob-token")})
+	alice, _ := circuitbreaker.AdminRequest{Token: "alice-token"}.LeaseOwner("")
+
+	// 持有者与凭证绑定，不能以他人名义获取租约
+	if rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=1m&owner=alice", "bob-token"); rec.Code != http.StatusForbidden {
+		t.Errorf("force-open as another owner = %v, want %v", rec.Code, http.StatusForbidden)
+	}
+	rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=1m", "alice-token")
+	var snap cbpb.Snapshot
+	if rec.Code != http.StatusOK || protojson.Unmarshal(rec.Body.Bytes(), &snap) != nil || snap.Lease == nil || snap.Lease.Owner != alice {
+		t.Fatalf("force-open = %v %s, want lease held by %s", rec.Code, rec.Body, alice)
+	}
+	if rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=1m&owner="+alice, "bob-token"); rec.Code != http.StatusForbidden {
+		t.Errorf("renew by bob as alice = %v, want %v", rec.Code, http.StatusForbidden)
+	}
+	if rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=1m", "bob-token"); rec.Code != http.StatusConflict {
+		t.Errorf("force-open by bob = %v, want %v", rec.Code, http.StatusConflict)
+	}
+	if rec := serve(h, http.MethodPost, "/breakers/payments/force-open?duration=2h", "alice-token"); rec.Code != http.StatusBadRequest {
+		t.Errorf("force-open over MaxLeaseTTL = %v, want %v", rec.Code, http.StatusBadRequest)
+	}
+}
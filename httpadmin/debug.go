@@ -0,0 +1,300 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package httpadmin
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// DebugRecorder 周期性记录各熔断器的失败率与状态变更，供调试页面绘制最近一段时间的图表
+type DebugRecorder struct {
+	registry *circuitbreaker.Registry
+	interval time.Duration
+	points   int
+
+	mu          sync.Mutex
+	series      map[string]*debugSeries
+	unsubscribe func()
+}
+
+// debugSeries 单个熔断器的采样点与状态变更，按时间顺序，最多保留 points 个
+type debugSeries struct {
+	samples     []debugSample
+	transitions []debugTransition
+}
+
+type debugSample struct {
+	at          time.Time
+	failureRate float64
+}
+
+type debugTransition struct {
+	at       time.Time
+	from, to gobreaker.State
+}
+
+// NewDebugRecorder 创建记录器，每 interval（默认 10 秒）采样一次，保留最近 points（默认 90）个采样点；
+// 状态变更通过订阅实时记录，不再使用时调用 Close 取消订阅
+func NewDebugRecorder(r *circuitbreaker.Registry, interval time.Duration, points int) *DebugRecorder {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if points <= 0 {
+		points = 90
+	}
+	rec := &DebugRecorder{
+		registry: r,
+		interval: interval,
+		points:   points,
+		series:   make(map[string]*debugSeries),
+	}
+	rec.unsubscribe = r.Subscribe(rec.record)
+	return rec
+}
+
+// Run 按 interval 周期采样，直至 ctx 结束
+func (rec *DebugRecorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(rec.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rec.Sample()
+		}
+	}
+}
+
+// Schedule 在共享调度器（如 Registry.Scheduler）中按 interval 周期采样，代替 Run 的独立协程；
+// 返回的函数停止采样
+func (rec *DebugRecorder) Schedule(s *circuitbreaker.Scheduler) (cancel func()) {
+	return s.Every(rec.interval, func(time.Time) { rec.Sample() })
+}
+
+// Sample 采样一次当前失败率，Run 内部周期调用，也可由调用方自行调度
+func (rec *DebugRecorder) Sample() {
+	now := time.Now()
+	stats := rec.registry.Stats()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	live := make(map[string]bool, len(stats))
+	for _, s := range stats {
+		live[s.Name] = true
+		var rate float64
+		if s.Counts.Requests > 0 {
+			rate = float64(s.Counts.TotalFailures) / float64(s.Counts.Requests)
+		}
+		series := rec.seriesLocked(s.Name)
+		series.samples = appendBounded(series.samples, debugSample{at: now, failureRate: rate}, rec.points)
+	}
+	for name := range rec.series {
+		if !live[name] {
+			delete(rec.series, name)
+		}
+	}
+}
+
+// Close 取消状态变更订阅
+func (rec *DebugRecorder) Close() {
+	rec.unsubscribe()
+}
+
+// record 记录状态变更；在熔断器锁内调用，只使用记录器自身的锁
+func (rec *DebugRecorder) record(name string, from, to gobreaker.State) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	series := rec.seriesLocked(name)
+	series.transitions = appendBounded(series.transitions, debugTransition{at: time.Now(), from: from, to: to}, rec.points)
+}
+
+// seriesLocked 返回熔断器的记录，不存在时创建，调用方需持有锁
+func (rec *DebugRecorder) seriesLocked(name string) *debugSeries {
+	s, ok := rec.series[name]
+	if !ok {
+		s = &debugSeries{}
+		rec.series[name] = s
+	}
+	return s
+}
+
+// appendBounded 追加元素并丢弃超出 max 的最早元素
+func appendBounded[T any](s []T, v T, max int) []T {
+	s = append(s, v)
+	if len(s) > max {
+		s = append(s[:0], s[len(s)-max:]...)
+	}
+	return s
+}
+
+// HandleDebug 在 GET /debug 挂载自包含的 HTML 调试页面（无外部资源），展示各熔断器最近的
+// 失败率折线与状态时间线；按 AdminList 操作授权
+func (h *AdminHandler) HandleDebug(rec *DebugRecorder) {
+	h.mux.HandleFunc("GET /debug", func(w http.ResponseWriter, req *http.Request) {
+		if !h.authorize(w, req, circuitbreaker.AdminList, "") {
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = debugPage.Execute(w, rec.view(time.Now()))
+	})
+}
+
+// 图表尺寸（像素）
+const (
+	chartWidth  = 360
+	chartHeight = 40
+)
+
+// debugColors 各状态在时间线中的颜色
+var debugColors = map[gobreaker.State]string{
+	gobreaker.StateClosed:   "#2e7d32",
+	gobreaker.StateHalfOpen: "#f9a825",
+	gobreaker.StateOpen:     "#c62828",
+}
+
+type debugView struct {
+	Window   time.Duration
+	Width    int
+	Height   int
+	Breakers []debugBreakerView
+}
+
+type debugBreakerView struct {
+	Name        string
+	State       string
+	Color       string
+	Requests    uint32
+	FailureRate string
+	Sparkline   string
+	Segments    []debugSegment
+}
+
+type debugSegment struct {
+	X, Width float64
+	Color    string
+	Title    string
+}
+
+// view 按当前时间构造页面数据，横轴为最近 interval*points
+func (rec *DebugRecorder) view(now time.Time) debugView {
+	window := rec.interval * time.Duration(rec.points)
+	start := now.Add(-window)
+	x := func(t time.Time) float64 {
+		if t.Before(start) {
+			t = start
+		}
+		return float64(t.Sub(start)) / float64(window) * chartWidth
+	}
+
+	stats := rec.registry.Stats()
+	v := debugView{Window: window, Width: chartWidth, Height: chartHeight, Breakers: make([]debugBreakerView, 0, len(stats))}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	for _, s := range stats {
+		b := debugBreakerView{
+			Name:        s.Name,
+			State:       s.State.String(),
+			Color:       debugColors[s.State],
+			Requests:    s.Counts.Requests,
+			FailureRate: "-",
+		}
+		if s.Counts.Requests > 0 {
+			b.FailureRate = fmt.Sprintf("%.1f%%", float64(s.Counts.TotalFailures)/float64(s.Counts.Requests)*100)
+		}
+
+		series := rec.series[s.Name]
+		if series == nil {
+			series = &debugSeries{}
+		}
+		var points []string
+		for _, p := range series.samples {
+			if !p.at.Before(start) {
+				points = append(points, fmt.Sprintf("%.1f,%.1f", x(p.at), (1-p.failureRate)*chartHeight))
+			}
+		}
+		b.Sparkline = strings.Join(points, " ")
+
+		// 窗口起点的状态取窗口内首个变更的 from，无变更时即当前状态
+		state, from := s.State, start
+		var inWindow []debugTransition
+		for _, t := range series.transitions {
+			if !t.at.Before(start) {
+				inWindow = append(inWindow, t)
+			}
+		}
+		if len(inWindow) > 0 {
+			state = inWindow[0].from
+		}
+		for _, t := range inWindow {
+			b.Segments = append(b.Segments, segment(state, from, t.at, x))
+			state, from = t.to, t.at
+		}
+		b.Segments = append(b.Segments, segment(state, from, now, x))
+		v.Breakers = append(v.Breakers, b)
+	}
+	return v
+}
+
+// segment 构造时间线上 [from, to) 区间的色块
+func segment(state gobreaker.State, from, to time.Time, x func(time.Time) float64) debugSegment {
+	return debugSegment{
+		X:     x(from),
+		Width: x(to) - x(from),
+		Color: debugColors[state],
+		Title: fmt.Sprintf("%s since %s", state, from.UTC().Format(time.TimeOnly)),
+	}
+}
+
+var debugPage = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>circuit breakers</title>
+<style>
+body{font:13px monospace;margin:16px}
+table{border-collapse:collapse}
+td,th{padding:4px 10px;text-align:left;border-bottom:1px solid #ddd}
+svg{display:block;background:#fafafa}
+.state{font-weight:bold}
+</style></head><body>
+<h1>circuit breakers</h1>
+<p>last {{.Window}}; failure rate 0-100% (top = 100%), timeline green = closed, yellow = half-open, red = open</p>
+<table>
+<tr><th>name</th><th>state</th><th>requests</th><th>failure rate</th><th>failure rate history</th><th>state timeline</th></tr>
+{{- range .Breakers}}
+<tr>
+<td>{{.Name}}</td>
+<td class="state" style="color:{{.Color}}">{{.State}}</td>
+<td>{{.Requests}}</td>
+<td>{{.FailureRate}}</td>
+<td><svg width="{{$.Width}}" height="{{$.Height}}"><polyline fill="none" stroke="#c62828" stroke-width="1.5" points="{{.Sparkline}}"/></svg></td>
+<td><svg width="{{$.Width}}" height="12">{{range .Segments}}<rect x="{{printf "%.1f" .X}}" y="0" width="{{printf "%.1f" .Width}}" height="12" fill="{{.Color}}"><title>{{.Title}}</title></rect>{{end}}</svg></td>
+</tr>
+{{- end}}
+</table>
+</body></html>
+`))
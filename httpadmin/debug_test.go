@@ -0,0 +1,85 @@
+// Copyright 2025 zampo.
+
+package httpadmin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestAdminHandler_Debug(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 2 }
+	cb := r.GetOrCreate("payments", settings)
+	r.GetOrCreate("orders", circuitbreaker.DefaultSettings())
+
+	rec := NewDebugRecorder(r, time.Second, 60)
+	defer rec.Close()
+	h := NewAdminHandler(r, circuitbreaker.AdminPolicy{Authorizer: circuitbreaker.TokenAuthorizer("s3cret")})
+	h.HandleDebug(rec)
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	rec.Sample()
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	rec.Sample()
+
+	if got := serve(h, http.MethodGet, "/debug", ""); got.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous GET /debug = %v, want %v", got.Code, http.StatusUnauthorized)
+	}
+	got := serve(h, http.MethodGet, "/debug", "s3cret")
+	if got.Code != http.StatusOK || !strings.HasPrefix(got.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("GET /debug = %v %q", got.Code, got.Header().Get("Content-Type"))
+	}
+	body := got.Body.String()
+	for _, want := range []string{"payments", "orders", "<polyline", debugColors[gobreaker.StateOpen], ">open<"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+	if strings.Contains(body, "src=") || strings.Contains(body, "href=") {
+		t.Error("page should not reference external assets")
+	}
+}
+
+func TestDebugRecorder_View(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 }
+	cb := r.GetOrCreate("payments", settings)
+
+	rec := NewDebugRecorder(r, time.Second, 3)
+	defer rec.Close()
+	for i := 0; i < 5; i++ {
+		rec.Sample()
+	}
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	cb.Reset()
+
+	v := rec.view(time.Now())
+	if len(v.Breakers) != 1 {
+		t.Fatalf("breakers = %d, want 1", len(v.Breakers))
+	}
+	b := v.Breakers[0]
+	if n := len(strings.Fields(b.Sparkline)); n != 3 {
+		t.Errorf("sparkline points = %d, want 3 (bounded)", n)
+	}
+	// closed -> open -> closed
+	if len(b.Segments) != 3 || b.Segments[1].Color != debugColors[gobreaker.StateOpen] || b.Segments[2].Color != debugColors[gobreaker.StateClosed] {
+		t.Errorf("segments = %+v, want closed, open, closed", b.Segments)
+	}
+	var width float64
+	for _, s := range b.Segments {
+		width += s.Width
+	}
+	if width < chartWidth-1 || width > chartWidth+1 {
+		t.Errorf("timeline width = %v, want %v", width, chartWidth)
+	}
+}
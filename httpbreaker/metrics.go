@@ -0,0 +1,175 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package httpbreaker
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// OpenMetricsContentType OpenMetrics 文本格式的 Content-Type
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// MetricsHandler 返回以 OpenMetrics 文本格式暴露注册表统计的 /metrics 处理器，
+// 不依赖 prometheus/client_golang，Prometheus 可直接抓取；指标见 WriteOpenMetrics
+func MetricsHandler(r *circuitbreaker.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", OpenMetricsContentType)
+		if req.Method == http.MethodHead {
+			return
+		}
+		_ = WriteOpenMetrics(w, r.Stats())
+	})
+}
+
+// openMetric 单个指标族，熔断器名称作为 name 标签
+type openMetric struct {
+	name string
+	typ  string
+	unit string
+	help string
+	// samples 返回该熔断器的样本，每项为额外标签与值
+	samples func(s circuitbreaker.Stats) []openSample
+}
+
+type openSample struct {
+	labels [][2]string
+	value  float64
+}
+
+func single(v float64) []openSample {
+	return []openSample{{value: v}}
+}
+
+// openMetrics 暴露的指标族；计数器族名不含 _total 后缀，样本名自动追加
+var openMetrics = []openMetric{
+	{"circuitbreaker_state", "stateset", "", "Current breaker state.", func(s circuitbreaker.Stats) []openSample {
+		out := make([]openSample, 0, 3)
+		for _, state := range []gobreaker.State{gobreaker.StateClosed, gobreaker.StateHalfOpen, gobreaker.StateOpen} {
+			var v float64
+			if s.State == state {
+				v = 1
+			}
+			out = append(out, openSample{labels: [][2]string{{"circuitbreaker_state", state.String()}}, value: v})
+		}
+		return out
+	}},
+	{"circuitbreaker_requests", "gauge", "", "Requests in the current counting window.", func(s circuitbreaker.Stats) []openSample {
+		return single(float64(s.Counts.Requests))
+	}},
+	{"circuitbreaker_failures", "gauge", "", "Failures in the current counting window.", func(s circuitbreaker.Stats) []openSample {
+		return single(float64(s.Counts.TotalFailures))
+	}},
+	{"circuitbreaker_consecutive_failures", "gauge", "", "Consecutive failures.", func(s circuitbreaker.Stats) []openSample {
+		return single(float64(s.Counts.ConsecutiveFailures))
+	}},
+	{"circuitbreaker_in_flight", "gauge", "", "Calls currently executing.", func(s circuitbreaker.Stats) []openSample {
+		return single(float64(s.InFlight))
+	}},
+	{"circuitbreaker_max_in_flight", "gauge", "", "Peak concurrent calls.", func(s circuitbreaker.Stats) []openSample {
+		return single(float64(s.MaxInFlight))
+	}},
+	{"circuitbreaker_leaked_calls", "gauge", "", "Calls still running in the background after timing out.", func(s circuitbreaker.Stats) []openSample {
+		return single(float64(s.LeakedCalls))
+	}},
+	{"circuitbreaker_rejections", "counter", "", "Calls rejected without executing.", func(s circuitbreaker.Stats) []openSample {
+		if len(s.RejectionsByReason) == 0 {
+			return nil
+		}
+		out := make([]openSample, 0, len(s.RejectionsByReason))
+		for reason, n := range s.RejectionsByReason {
+			out = append(out, openSample{labels: [][2]string{{"reason", string(reason)}}, value: float64(n)})
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].labels[0][1] < out[j].labels[0][1] })
+		return out
+	}},
+	{"circuitbreaker_timeouts", "counter", "", "Calls that timed out, by bound.", func(s circuitbreaker.Stats) []openSample {
+		return []openSample{
+			{labels: [][2]string{{"bound", "call"}}, value: float64(s.CallTimeouts)},
+			{labels: [][2]string{{"bound", "deadline"}}, value: float64(s.DeadlineTimeouts)},
+		}
+	}},
+	{"circuitbreaker_slow_calls", "counter", "", "Successful calls over the latency budget, counted as failures.", func(s circuitbreaker.Stats) []openSample {
+		return single(float64(s.SlowCalls))
+	}},
+	{"circuitbreaker_oversized_results", "counter", "", "Successful calls over the result size limit, counted as failures.", func(s circuitbreaker.Stats) []openSample {
+		return single(float64(s.OversizedResults))
+	}},
+	{"circuitbreaker_latency_p99_seconds", "gauge", "seconds", "P99 latency of recent calls.", func(s circuitbreaker.Stats) []openSample {
+		return single(s.LatencyP99.Seconds())
+	}},
+	{"circuitbreaker_health_score", "gauge", "", "Health score from 0 to 100.", func(s circuitbreaker.Stats) []openSample {
+		return single(s.HealthScore)
+	}},
+}
+
+// WriteOpenMetrics 以 OpenMetrics 文本格式写出 stats，以 "# EOF" 结尾；每个熔断器以 name 标签区分，
+// 窗口计数为 gauge（窗口切换时清零），拒绝与超时等累计值为 counter
+func WriteOpenMetrics(w io.Writer, stats []circuitbreaker.Stats) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range openMetrics {
+		bw.WriteString("# TYPE " + m.name + " " + m.typ + "\n")
+		if m.unit != "" {
+			bw.WriteString("# UNIT " + m.name + " " + m.unit + "\n")
+		}
+		bw.WriteString("# HELP " + m.name + " " + m.help + "\n")
+
+		sample := m.name
+		if m.typ == "counter" {
+			sample += "_total"
+		}
+		for _, s := range stats {
+			for _, v := range m.samples(s) {
+				bw.WriteString(sample + "{name=\"" + escapeLabel(s.Name) + "\"")
+				for _, l := range v.labels {
+					bw.WriteString("," + l[0] + "=\"" + escapeLabel(l[1]) + "\"")
+				}
+				bw.WriteString("} " + formatValue(v.value) + "\n")
+			}
+		}
+	}
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel 按 OpenMetrics 转义标签值
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+// formatValue 格式化样本值，整数不带小数部分
+func formatValue(v float64) string {
+	if v == float64(int64(v)) && v < 1<<53 && v > -(1<<53) {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
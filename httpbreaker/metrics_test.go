@@ -0,0 +1,60 @@
+// Copyright 2025 zampo.
+
+package httpbreaker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	r := circuitbreaker.NewRegistry()
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	cb := r.GetOrCreate(`pay"ments`, settings)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	r.GetOrCreate("inventory", settings).Execute(func() (interface{}, error) { return nil, nil })
+
+	rec := httptest.NewRecorder()
+	MetricsHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); got != OpenMetricsContentType {
+		t.Errorf("Content-Type = %q", got)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE circuitbreaker_state stateset\n",
+		`circuitbreaker_state{name="pay\"ments",circuitbreaker_state="open"} 1` + "\n",
+		`circuitbreaker_state{name="pay\"ments",circuitbreaker_state="closed"} 0` + "\n",
+		"# TYPE circuitbreaker_rejections counter\n",
+		`circuitbreaker_rejections_total{name="pay\"ments",reason="OPEN"} 1` + "\n",
+		"# UNIT circuitbreaker_latency_p99_seconds seconds\n",
+		`circuitbreaker_requests{name="inventory"} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Error("body does not end with # EOF")
+	}
+
+	rec = httptest.NewRecorder()
+	MetricsHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %v, want %v", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	for in, want := range map[float64]string{3: "3", 0.25: "0.25", 1e-9: "1e-09"} {
+		if got := formatValue(in); got != want {
+			t.Errorf("formatValue(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package httpbreaker
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// defaultMaxCachedBody 可缓存响应体的默认上限
+const defaultMaxCachedBody = 1 << 20
+
+// staleWarning 返回缓存响应时附带的 Warning 头（RFC 7234 110 Response is Stale）
+const staleWarning = `110 - "Response is Stale"`
+
+// StaticResponse 熔断打开时返回的固定响应
+type StaticResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Handler 带熔断保护的服务端 http.Handler
+// 熔断打开时依次尝试：同一 URL 最近一次成功的缓存响应（附带 Age 与 Warning 头）、
+// OpenResponse，均不可用时返回 503；后两者附带按 NextProbeAt 计算的 Retry-After
+type Handler struct {
+	// Next 被保护的处理器
+	Next http.Handler
+	// Breaker 熔断器
+	Breaker *circuitbreaker.CircuitBreaker
+	// IsFailure 按响应状态码判断是否计为失败，为空时 5xx 计为失败
+	IsFailure func(status int) bool
+	// OpenResponse 熔断打开且无缓存时返回的固定响应，为空时返回 503
+	OpenResponse *StaticResponse
+	// LastKnownGood 缓存最近成功响应的 URL 数，0 表示不缓存
+	// 仅缓存无 Authorization/Cookie 的 GET 请求的 200 响应，且响应不含 Set-Cookie、
+	// Cache-Control: no-store/private，避免将个人数据返回给其他用户；
+	// 带 Vary 的响应仅提供给所列请求头与缓存时一致的请求，Vary: * 的响应不缓存
+	LastKnownGood int
+	// MaxCachedBody 可缓存响应体的上限，默认 1 MiB，超过时不缓存
+	MaxCachedBody int
+
+	once  sync.Once
+	cache *responseCache
+}
+
+// NewHandler 创建带熔断保护的 Handler
+func NewHandler(next http.Handler, cb *circuitbreaker.CircuitBreaker) *Handler {
+	return &Handler{Next: next, Breaker: cb}
+}
+
+// Middleware 返回使用 cb 保护处理器的中间件，configure 可为空，用于设置 Handler 的可选字段
+func Middleware(cb *circuitbreaker.CircuitBreaker, configure func(*Handler)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		h := NewHandler(next, cb)
+		if configure != nil {
+			configure(h)
+		}
+		return h
+	}
+}
+
+// ServeHTTP 实现 http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.once.Do(func() {
+		if h.LastKnownGood > 0 {
+			h.cache = newResponseCache(h.LastKnownGood)
+		}
+	})
+
+	done, err := h.Breaker.AllowContext(req.Context())
+	if err != nil {
+		h.serveOpen(w, req, err)
+		return
+	}
+
+	rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+	if h.cache != nil && cacheableRequest(req) {
+		rec.capture, rec.limit = true, h.maxCachedBody()
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			done(fmt.Errorf("httpbreaker: panic: %v", e))
+			panic(e)
+		}
+	}()
+	h.Next.ServeHTTP(rec, req)
+
+	if h.isFailure(rec.status) {
+		done(circuitbreaker.Annotate(&StatusError{StatusCode: rec.status}, rec.status, ""))
+		return
+	}
+	done(nil)
+	if rec.capture && rec.status == http.StatusOK && cacheableResponse(rec.Header()) {
+		if vary, ok := varyHeaders(req, rec.Header()); ok {
+			h.cache.put(cacheKey(req), &cachedResponse{
+				header: rec.Header().Clone(),
+				body:   rec.body.Bytes(),
+				stored: time.Now(),
+				vary:   vary,
+			})
+		}
+	}
+}
+
+// serveOpen 熔断打开时返回缓存或固定响应；非缓存响应按熔断器预计半开的时间设置 Retry-After
+func (h *Handler) serveOpen(w http.ResponseWriter, req *http.Request, rejection error) {
+	if h.cache != nil && cacheableRequest(req) {
+		if cached, ok := h.cache.get(cacheKey(req)); ok && varyMatches(cached.vary, req) {
+			header := w.Header()
+			for k, v := range cached.header {
+				header[k] = append([]string(nil), v...)
+			}
+			header.Set("Age", strconv.FormatInt(int64(time.Since(cached.stored)/time.Second), 10))
+			header.Add("Warning", staleWarning)
+			w.WriteHeader(http.StatusOK)
+			w.Write(cached.body)
+			return
+		}
+	}
+
+	if d, ok := circuitbreaker.RetryAfter(rejection); ok {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10))
+	}
+	if r := h.OpenResponse; r != nil {
+		header := w.Header()
+		for k, v := range r.Header {
+			header[k] = append([]string(nil), v...)
+		}
+		status := r.StatusCode
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		w.WriteHeader(status)
+		w.Write(r.Body)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
+
+func (h *Handler) isFailure(status int) bool {
+	if h.IsFailure != nil {
+		return h.IsFailure(status)
+	}
+	return status >= http.StatusInternalServerError
+}
+
+func (h *Handler) maxCachedBody() int {
+	if h.MaxCachedBody > 0 {
+		return h.MaxCachedBody
+	}
+	return defaultMaxCachedBody
+}
+
+// cacheableRequest 仅缓存不携带用户凭据的 GET 请求
+func cacheableRequest(req *http.Request) bool {
+	return req.Method == http.MethodGet && req.Header.Get("Authorization") == "" && req.Header.Get("Cookie") == ""
+}
+
+// cacheableResponse 排除设置 Cookie 或声明不可共享缓存的响应
+func cacheableResponse(header http.Header) bool {
+	if header.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := strings.ToLower(header.Get("Cache-Control"))
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "private")
+}
+
+func cacheKey(req *http.Request) string {
+	return req.Host + req.URL.RequestURI()
+}
+
+// varyHeaders 返回响应 Vary 头列出的请求头在 req 中的取值；Vary 含 "*" 时返回 false，表示不可缓存
+func varyHeaders(req *http.Request, header http.Header) (http.Header, bool) {
+	var vary http.Header
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return nil, false
+			}
+			if name == "" {
+				continue
+			}
+			if vary == nil {
+				vary = make(http.Header)
+			}
+			vary[http.CanonicalHeaderKey(name)] = slices.Clone(req.Header.Values(name))
+		}
+	}
+	return vary, true
+}
+
+// varyMatches 判断 req 中 Vary 列出的请求头是否与缓存时的取值一致
+func varyMatches(vary http.Header, req *http.Request) bool {
+	for name, values := range vary {
+		if !slices.Equal(req.Header.Values(name), values) {
+			return false
+		}
+	}
+	return true
+}
+
+// recorder 记录响应状态码，并在需要缓存时保存不超过 limit 的响应体
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+
+	capture bool
+	limit   int
+	body    bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	if r.capture {
+		if r.body.Len()+len(p) > r.limit {
+			r.capture = false
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush 实现 http.Flusher
+func (r *recorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// cachedResponse 最近一次成功的响应
+type cachedResponse struct {
+	header http.Header
+	body   []byte
+	stored time.Time
+	// vary 响应 Vary 头列出的请求头在缓存时的取值，仅提供给这些请求头一致的请求
+	vary http.Header
+}
+
+// responseCache 固定容量的最近最少使用响应缓存
+type responseCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key  string
+	resp *cachedResponse
+}
+
+func newResponseCache(max int) *responseCache {
+	return &responseCache{max: max, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *responseCache) get(key string) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).resp, true
+}
+
+func (c *responseCache) put(key string, resp *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*cacheEntry).resp = resp
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, resp: resp})
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 zampo.
+
+package httpbreaker
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func newMiddlewareBreaker() *circuitbreaker.CircuitBreaker {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	settings.Timeout = time.Minute
+	return circuitbreaker.NewCircuitBreaker("server", settings)
+}
+
+func serveRequest(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_LastKnownGood(t *testing.T) {
+	var failing atomic.Bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "fresh "+r.URL.Path)
+	})
+	cb := newMiddlewareBreaker()
+	h := Middleware(cb, func(h *Handler) { h.LastKnownGood = 8 })(next)
+
+	if rec := serveRequest(h, httptest.NewRequest(http.MethodGet, "/items", nil)); rec.Body.String() != "fresh /items" {
+		t.Fatalf("body = %q", rec.Body.String())
+	}
+
+	failing.Store(true)
+	if rec := serveRequest(h, httptest.NewRequest(http.MethodGet, "/items", nil)); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("Code = %v, want 500", rec.Code)
+	}
+	if cb.State() != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want open", cb.State())
+	}
+
+	rec := serveRequest(h, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "fresh /items" {
+		t.Errorf("open response = %v %q, want cached 200", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Warning") != staleWarning || rec.Header().Get("Age") == "" || rec.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("open response headers = %v", rec.Header())
+	}
+
+	// 未缓存的 URL 与携带凭据的请求不返回缓存
+	if rec := serveRequest(h, httptest.NewRequest(http.MethodGet, "/other", nil)); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("uncached Code = %v, want 503", rec.Code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Authorization", "Bearer x")
+	if rec := serveRequest(h, req); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("authorized Code = %v, want 503", rec.Code)
+	}
+}
+
+func TestHandler_NotCached(t *testing.T) {
+	for name, next := range map[string]http.HandlerFunc{
+		"set-cookie": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Set-Cookie", "session=1")
+			io.WriteString(w, "personal")
+		},
+		"private": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "private, max-age=60")
+			io.WriteString(w, "personal")
+		},
+		"too large": func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "0123456789")
+		},
+		"vary star": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Vary", "*")
+			io.WriteString(w, "varies")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cb := newMiddlewareBreaker()
+			h := &Handler{Next: next, Breaker: cb, LastKnownGood: 8, MaxCachedBody: 8}
+			serveRequest(h, httptest.NewRequest(http.MethodGet, "/me", nil))
+			cb.OpenFor(time.Minute)
+
+			if rec := serveRequest(h, httptest.NewRequest(http.MethodGet, "/me", nil)); rec.Code != http.StatusServiceUnavailable {
+				t.Errorf("Code = %v, want 503", rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_LastKnownGoodVary(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept-Encoding, Accept-Language")
+		io.WriteString(w, "hello "+r.Header.Get("Accept-Language"))
+	})
+	cb := newMiddlewareBreaker()
+	h := &Handler{Next: next, Breaker: cb, LastKnownGood: 8}
+
+	request := func(lang string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/greeting", nil)
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		return req
+	}
+	serveRequest(h, request("en"))
+	cb.OpenFor(time.Minute)
+
+	if rec := serveRequest(h, request("en")); rec.Code != http.StatusOK || rec.Body.String() != "hello en" {
+		t.Errorf("same variant = %v %q, want cached hello en", rec.Code, rec.Body.String())
+	}
+	for _, lang := range []string{"fr", ""} {
+		if rec := serveRequest(h, request(lang)); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Accept-Language %q Code = %v, want 503", lang, rec.Code)
+		}
+	}
+}
+
+func TestHandler_StaticResponse(t *testing.T) {
+	cb := newMiddlewareBreaker()
+	h := NewHandler(http.NotFoundHandler(), cb)
+	h.OpenResponse = &StaticResponse{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(`{"items":[]}`),
+	}
+
+	if rec := serveRequest(h, httptest.NewRequest(http.MethodGet, "/", nil)); rec.Code != http.StatusNotFound {
+		t.Fatalf("Code = %v, want 404 passthrough", rec.Code)
+	}
+	if cb.Counts().TotalFailures != 0 {
+		t.Error("4xx counted as failure")
+	}
+
+	cb.OpenFor(time.Minute)
+	rec := serveRequest(h, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"items":[]}` || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("static response = %v %v %q", rec.Code, rec.Header(), rec.Body.String())
+	}
+	if got := rec.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("Retry-After = %q, want 60", got)
+	}
+}
+
+func TestHandler_PanicCountsAsFailure(t *testing.T) {
+	cb := newMiddlewareBreaker()
+	h := NewHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }), cb)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic not propagated")
+			}
+		}()
+		serveRequest(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+	if cb.State() != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want open after panic", cb.State())
+	}
+}
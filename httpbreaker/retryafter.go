@@ -0,0 +1,67 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package httpbreaker
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRetryAfter 解析 Retry-After 头，支持秒数与 HTTP 日期两种格式
+// 返回相对 now 的等待时长，无法解析或已过期时返回 false
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if secs <= 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := at.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+// honorRetryAfter 在启用 HonorRetryAfter 时按 429/503 响应的 Retry-After 保持熔断器打开
+func (t *Transport) honorRetryAfter(resp *http.Response) {
+	if !t.HonorRetryAfter || resp == nil {
+		return
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return
+	}
+
+	// OpenFor 按熔断器的 Clock 计时，HTTP 日期格式也以同一时钟换算为时长
+	d, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), t.Breaker.Now())
+	if !ok {
+		return
+	}
+	if t.MaxRetryAfter > 0 && d > t.MaxRetryAfter {
+		d = t.MaxRetryAfter
+	}
+	t.Breaker.OpenFor(d)
+}
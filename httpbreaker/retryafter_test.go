@@ -0,0 +1,101 @@
+// Copyright 2025 zampo.
+
+package httpbreaker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+	"github.com/go-anyway/framework-circuitbreaker/circuitbreakertest"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 5 ", 5 * time.Second, true},
+		{"0", 0, false},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2025 00:00:30 GMT", 30 * time.Second, true},
+		{"Tue, 31 Dec 2024 23:59:00 GMT", 0, false},
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTransport_HonorRetryAfter(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+	transport := NewTransport(nil, cb)
+	transport.HonorRetryAfter = true
+	transport.MaxRetryAfter = time.Minute
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+	if until := time.Until(cb.OpenUntil()); until <= 0 || until > time.Minute {
+		t.Errorf("OpenUntil() in %v, want capped at %v", until, time.Minute)
+	}
+	if _, err := client.Get(srv.URL); !errors.Is(err, gobreaker.ErrOpenState) {
+		t.Errorf("Get() error = %v, want %v", err, gobreaker.ErrOpenState)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %v, want 1", calls)
+	}
+}
+
+func TestTransport_HonorRetryAfterUsesBreakerClock(t *testing.T) {
+	clock := circuitbreakertest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", clock.Now().Add(2*time.Minute).Format(http.TimeFormat))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	settings := circuitbreaker.DefaultSettings()
+	settings.Clock = clock
+	cb := circuitbreaker.NewCircuitBreaker("test", settings)
+	transport := NewTransport(nil, cb)
+	transport.HonorRetryAfter = true
+
+	resp, err := (&http.Client{Transport: transport}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got, want := cb.OpenUntil(), clock.Now().Add(2*time.Minute); !got.Equal(want) {
+		t.Errorf("OpenUntil() = %v, want %v", got, want)
+	}
+}
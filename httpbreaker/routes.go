@@ -0,0 +1,220 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package httpbreaker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Route 路由模式到熔断器的映射
+type Route struct {
+	// Pattern 路由模式，可带方法前缀（如 "POST /checkout"）；按 "/" 分段匹配，
+	// "*" 匹配任意单个段，末尾的 "**" 匹配剩余的任意段（含零段）
+	Pattern string `json:"pattern"`
+	// Breaker 熔断器名称，同名路由共享熔断器；配置取自 RouteHandler.Config
+	Breaker string `json:"breaker"`
+}
+
+// Routes 按配置顺序匹配的路由表，第一个匹配的路由生效
+type Routes []Route
+
+// ParseRoutes 解析并校验 JSON 路由表（Route 数组），未知字段视为错误
+func ParseRoutes(data []byte) (Routes, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var routes Routes
+	if err := dec.Decode(&routes); err != nil {
+		return nil, err
+	}
+	if err := routes.Validate(); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// Validate 校验路由表，返回所有问题
+func (rs Routes) Validate() error {
+	var errs []error
+	for i, r := range rs {
+		if _, err := compileRoute(r); err != nil {
+			errs = append(errs, fmt.Errorf("routes[%d]: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compiledRoute 预先分段的路由
+type compiledRoute struct {
+	method   string
+	segments []string
+	rest     bool
+	breaker  string
+}
+
+func compileRoute(r Route) (compiledRoute, error) {
+	if r.Breaker == "" {
+		return compiledRoute{}, errors.New("breaker must not be empty")
+	}
+	c := compiledRoute{breaker: r.Breaker}
+	path := r.Pattern
+	if method, p, ok := strings.Cut(r.Pattern, " "); ok {
+		c.method, path = method, strings.TrimSpace(p)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return compiledRoute{}, fmt.Errorf("pattern %q must start with /", r.Pattern)
+	}
+	c.segments = splitPath(path)
+	for i, seg := range c.segments {
+		if seg != "**" {
+			continue
+		}
+		if i != len(c.segments)-1 {
+			return compiledRoute{}, fmt.Errorf("pattern %q: ** must be the last segment", r.Pattern)
+		}
+		c.segments, c.rest = c.segments[:i], true
+	}
+	return c, nil
+}
+
+// match 判断请求方法与已分段的路径是否匹配
+func (c *compiledRoute) match(method string, segments []string) bool {
+	if c.method != "" && c.method != method {
+		return false
+	}
+	if len(segments) < len(c.segments) || (!c.rest && len(segments) != len(c.segments)) {
+		return false
+	}
+	for i, seg := range c.segments {
+		if seg != "*" && seg != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPath 按 "/" 分段，忽略首尾的 "/"
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// RouteHandler 按路由表为不同路由使用不同熔断器的服务端处理器，
+// 一个实例即可对 /search 与 /checkout 应用不同配置；未匹配任何路由的请求不经熔断直接处理
+type RouteHandler struct {
+	// Next 被保护的处理器
+	Next http.Handler
+	// Registry 熔断器所在注册表，路由首次命中时按名称创建
+	Registry *circuitbreaker.Registry
+	// Config 熔断器配置来源（Config.Settings），为空时使用 circuitbreaker.DefaultSettings
+	Config *circuitbreaker.Config
+	// Configure 设置每个熔断器对应 Handler 的可选字段，可为空
+	Configure func(*Handler)
+
+	routes   atomic.Pointer[[]compiledRoute]
+	handlers sync.Map // 熔断器名称 -> *Handler
+}
+
+// NewRouteHandler 创建按路由熔断的处理器，路由表无效时返回错误
+func NewRouteHandler(next http.Handler, r *circuitbreaker.Registry, routes Routes) (*RouteHandler, error) {
+	h := &RouteHandler{Next: next, Registry: r}
+	if err := h.SetRoutes(routes); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// RouteMiddleware 返回按路由熔断的中间件，路由表无效时返回错误
+func RouteMiddleware(r *circuitbreaker.Registry, routes Routes, configure func(*RouteHandler)) (func(http.Handler) http.Handler, error) {
+	if err := routes.Validate(); err != nil {
+		return nil, err
+	}
+	return func(next http.Handler) http.Handler {
+		h, _ := NewRouteHandler(next, r, routes)
+		if configure != nil {
+			configure(h)
+		}
+		return h
+	}, nil
+}
+
+// SetRoutes 替换路由表（热更新），已创建的熔断器按名称继续沿用
+func (h *RouteHandler) SetRoutes(routes Routes) error {
+	compiled := make([]compiledRoute, 0, len(routes))
+	for i, r := range routes {
+		c, err := compileRoute(r)
+		if err != nil {
+			return fmt.Errorf("routes[%d]: %w", i, err)
+		}
+		compiled = append(compiled, c)
+	}
+	h.routes.Store(&compiled)
+	return nil
+}
+
+// Match 返回请求匹配的熔断器名称
+func (h *RouteHandler) Match(req *http.Request) (string, bool) {
+	routes := h.routes.Load()
+	if routes == nil {
+		return "", false
+	}
+	segments := splitPath(req.URL.Path)
+	for i := range *routes {
+		if r := &(*routes)[i]; r.match(req.Method, segments) {
+			return r.breaker, true
+		}
+	}
+	return "", false
+}
+
+// ServeHTTP 实现 http.Handler
+func (h *RouteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	name, ok := h.Match(req)
+	if !ok {
+		h.Next.ServeHTTP(w, req)
+		return
+	}
+	h.handler(name).ServeHTTP(w, req)
+}
+
+// handler 返回熔断器对应的 Handler，不存在时创建
+func (h *RouteHandler) handler(name string) *Handler {
+	if v, ok := h.handlers.Load(name); ok {
+		return v.(*Handler)
+	}
+	settings := circuitbreaker.DefaultSettings()
+	if h.Config != nil {
+		settings = h.Config.Settings(name)
+	}
+	handler := NewHandler(h.Next, h.Registry.GetOrCreate(name, settings))
+	if h.Configure != nil {
+		h.Configure(handler)
+	}
+	v, _ := h.handlers.LoadOrStore(name, handler)
+	return v.(*Handler)
+}
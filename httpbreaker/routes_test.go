@@ -0,0 +1,100 @@
+// Copyright 2025 zampo.
+
+package httpbreaker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestRouteHandler_Match(t *testing.T) {
+	routes, err := ParseRoutes([]byte(`[
+		{"pattern": "POST /checkout", "breaker": "checkout"},
+		{"pattern": "/search", "breaker": "search"},
+		{"pattern": "/users/*/orders", "breaker": "orders"},
+		{"pattern": "/static/**", "breaker": "static"}
+	]`))
+	if err != nil {
+		t.Fatalf("ParseRoutes() error = %v", err)
+	}
+	h, err := NewRouteHandler(http.NotFoundHandler(), circuitbreaker.NewRegistry(), routes)
+	if err != nil {
+		t.Fatalf("NewRouteHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{http.MethodPost, "/checkout", "checkout"},
+		{http.MethodGet, "/checkout", ""},
+		{http.MethodGet, "/search/", "search"},
+		{http.MethodGet, "/users/42/orders", "orders"},
+		{http.MethodGet, "/users/42/orders/7", ""},
+		{http.MethodGet, "/static", "static"},
+		{http.MethodGet, "/static/css/site.css", "static"},
+		{http.MethodGet, "/other", ""},
+	}
+	for _, tt := range tests {
+		got, _ := h.Match(httptest.NewRequest(tt.method, tt.path, nil))
+		if got != tt.want {
+			t.Errorf("Match(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseRoutes_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`[{"pattern": "search", "breaker": "search"}]`,
+		`[{"pattern": "/a/**/b", "breaker": "a"}]`,
+		`[{"pattern": "/a"}]`,
+		`[{"pattern": "/a", "breaker": "a", "unknown": 1}]`,
+	} {
+		if _, err := ParseRoutes([]byte(data)); err == nil {
+			t.Errorf("ParseRoutes(%s) error = nil", data)
+		}
+	}
+}
+
+func TestRouteHandler_PerRouteSettings(t *testing.T) {
+	cfg, err := circuitbreaker.ParseConfig([]byte(`{"version":2,"breakers":{
+		"checkout":{"policies":[{"type":"consecutive_failures","threshold":1}]}
+	}}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	r := circuitbreaker.NewRegistry()
+	mw, err := RouteMiddleware(r, Routes{
+		{Pattern: "/checkout", Breaker: "checkout"},
+		{Pattern: "/search", Breaker: "search"},
+	}, func(h *RouteHandler) { h.Config = cfg })
+	if err != nil {
+		t.Fatalf("RouteMiddleware() error = %v", err)
+	}
+	h := mw(next)
+
+	serveRequest(h, httptest.NewRequest(http.MethodGet, "/checkout", nil))
+	serveRequest(h, httptest.NewRequest(http.MethodGet, "/search", nil))
+	serveRequest(h, httptest.NewRequest(http.MethodGet, "/unrouted", nil))
+
+	if cb, _ := r.Get("checkout"); cb.State() != gobreaker.StateOpen {
+		t.Errorf("checkout State() = %v, want open", cb.State())
+	}
+	if cb, _ := r.Get("search"); cb.State() != gobreaker.StateClosed {
+		t.Errorf("search State() = %v, want closed", cb.State())
+	}
+	if rec := serveRequest(h, httptest.NewRequest(http.MethodGet, "/checkout", nil)); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("checkout Code = %v, want 503", rec.Code)
+	}
+	if got := r.Names(); len(got) != 2 {
+		t.Errorf("Names() = %v, want checkout and search only", got)
+	}
+}
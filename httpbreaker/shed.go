@@ -0,0 +1,180 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package httpbreaker
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// 成本估计的默认参数
+const (
+	defaultCostAlpha    = 0.2
+	defaultShedStep     = time.Second
+	defaultMaxEndpoints = 1000
+)
+
+// CostShedder 按端点成本分级削减入站流量的服务端处理器：
+// 以处理耗时的 EWMA 估计各端点成本，服务级熔断器（如 SystemBreaker）检测到过载时，
+// 每个 Step 多削减一个最昂贵的端点，过载解除后每个 Step 恢复一个，最便宜的端点始终不会被削减
+type CostShedder struct {
+	// Next 被保护的处理器
+	Next http.Handler
+	// Overload 服务级熔断器，状态非关闭即视为过载
+	Overload circuitbreaker.Executor
+	// Endpoint 返回请求所属端点，为空时使用 "方法 路径"；路径含 ID 时应改用路由模式
+	// （如 RouteHandler.Match），避免端点数量无界
+	Endpoint func(req *http.Request) string
+	// Alpha EWMA 平滑系数（0~1），越大越偏重最近的耗时，默认 0.2
+	Alpha float64
+	// Step 调整削减级别的最短间隔，默认 1 秒，同时作为被削减请求的 Retry-After
+	Step time.Duration
+	// MaxEndpoints 跟踪的最大端点数，默认 1000，超出的端点不估计成本也不会被削减
+	MaxEndpoints int
+
+	mu       sync.Mutex
+	costs    map[string]float64
+	shed     map[string]bool
+	level    int
+	lastStep time.Time
+}
+
+// NewCostShedder 创建按端点成本削减流量的处理器
+func NewCostShedder(next http.Handler, overload circuitbreaker.Executor) *CostShedder {
+	return &CostShedder{Next: next, Overload: overload}
+}
+
+// ServeHTTP 实现 http.Handler，被削减的请求返回 503 与 Retry-After
+func (s *CostShedder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	endpoint := s.endpoint(req)
+	now := time.Now()
+	if s.admit(endpoint, now) {
+		s.Next.ServeHTTP(w, req)
+		s.observe(endpoint, time.Since(now))
+		return
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(int64((s.step()+time.Second-1)/time.Second), 10))
+	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
+
+// Costs 返回各端点当前的成本估计
+func (s *CostShedder) Costs() map[string]time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]time.Duration, len(s.costs))
+	for endpoint, cost := range s.costs {
+		out[endpoint] = time.Duration(cost)
+	}
+	return out
+}
+
+// Shed 返回当前被削减的端点（已排序）
+func (s *CostShedder) Shed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, 0, len(s.shed))
+	for endpoint := range s.shed {
+		out = append(out, endpoint)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// admit 按需调整削减级别，返回端点是否放行
+func (s *CostShedder) admit(endpoint string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastStep) >= s.step() {
+		s.lastStep = now
+		s.adjustLocked(s.Overload.State() != gobreaker.StateClosed)
+	}
+	return !s.shed[endpoint]
+}
+
+// adjustLocked 过载时提升一级、恢复时降低一级，并按成本重新选出被削减的端点，调用方需持有锁
+func (s *CostShedder) adjustLocked(overloaded bool) {
+	switch {
+	case overloaded && s.level < len(s.costs)-1:
+		s.level++
+	case !overloaded && s.level > 0:
+		s.level--
+	default:
+		return
+	}
+
+	endpoints := make([]string, 0, len(s.costs))
+	for endpoint := range s.costs {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if s.costs[endpoints[i]] != s.costs[endpoints[j]] {
+			return s.costs[endpoints[i]] > s.costs[endpoints[j]]
+		}
+		return endpoints[i] < endpoints[j]
+	})
+	s.shed = make(map[string]bool, s.level)
+	for _, endpoint := range endpoints[:s.level] {
+		s.shed[endpoint] = true
+	}
+}
+
+// observe 将一次处理耗时计入端点的 EWMA 成本
+func (s *CostShedder) observe(endpoint string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.costs == nil {
+		s.costs = make(map[string]float64)
+	}
+	cost, ok := s.costs[endpoint]
+	if !ok {
+		max := s.MaxEndpoints
+		if max <= 0 {
+			max = defaultMaxEndpoints
+		}
+		if len(s.costs) >= max {
+			return
+		}
+		s.costs[endpoint] = float64(d)
+		return
+	}
+	alpha := s.Alpha
+	if alpha <= 0 || alpha > 1 {
+		alpha = defaultCostAlpha
+	}
+	s.costs[endpoint] = cost + alpha*(float64(d)-cost)
+}
+
+func (s *CostShedder) endpoint(req *http.Request) string {
+	if s.Endpoint != nil {
+		return s.Endpoint(req)
+	}
+	return req.Method + " " + req.URL.Path
+}
+
+func (s *CostShedder) step() time.Duration {
+	if s.Step > 0 {
+		return s.Step
+	}
+	return defaultShedStep
+}
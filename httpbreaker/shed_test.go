@@ -0,0 +1,83 @@
+// Copyright 2025 zampo.
+
+package httpbreaker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestCostShedder_ShedsMostExpensiveFirst(t *testing.T) {
+	delays := map[string]time.Duration{"/slow": 6 * time.Millisecond, "/mid": 3 * time.Millisecond, "/fast": 0}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delays[r.URL.Path])
+	})
+	overload := circuitbreaker.NewCircuitBreaker("system", circuitbreaker.DefaultSettings())
+	s := NewCostShedder(next, overload)
+	s.Step = 10 * time.Millisecond
+
+	get := func(path string) int {
+		return serveRequest(s, httptest.NewRequest(http.MethodGet, path, nil)).Code
+	}
+	for _, path := range []string{"/slow", "/mid", "/fast"} {
+		get(path)
+	}
+	if got := s.Shed(); len(got) != 0 {
+		t.Fatalf("Shed() = %v before overload, want none", got)
+	}
+
+	overload.OpenFor(time.Minute)
+	time.Sleep(s.Step)
+	if code := get("/slow"); code != http.StatusServiceUnavailable {
+		t.Errorf("/slow Code = %v, want 503", code)
+	}
+	if code := get("/mid"); code != http.StatusOK {
+		t.Errorf("/mid Code = %v, want 200 at level 1", code)
+	}
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(s.Step)
+		get("/fast")
+	}
+	if got, want := s.Shed(), []string{"GET /mid", "GET /slow"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Shed() = %v, want %v (cheapest endpoint never shed)", got, want)
+	}
+
+	overload.Reset()
+	for i := 0; i < 2; i++ {
+		time.Sleep(s.Step)
+		get("/fast")
+	}
+	if got := s.Shed(); len(got) != 0 {
+		t.Errorf("Shed() = %v after recovery, want none", got)
+	}
+}
+
+func TestCostShedder_RetryAfter(t *testing.T) {
+	overload := circuitbreaker.NewCircuitBreaker("system", circuitbreaker.DefaultSettings())
+	s := NewCostShedder(http.NotFoundHandler(), overload)
+	s.Step = time.Millisecond
+	serveRequest(s, httptest.NewRequest(http.MethodGet, "/a", nil))
+	serveRequest(s, httptest.NewRequest(http.MethodGet, "/b", nil))
+
+	overload.OpenFor(time.Minute)
+	time.Sleep(2 * s.Step)
+	shed := 0
+	for _, path := range []string{"/a", "/b"} {
+		rec := serveRequest(s, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code == http.StatusServiceUnavailable {
+			shed++
+			if got := rec.Header().Get("Retry-After"); got != "1" {
+				t.Errorf("Retry-After = %q, want 1", got)
+			}
+		}
+	}
+	if shed != 1 {
+		t.Errorf("shed %d endpoints, want 1", shed)
+	}
+}
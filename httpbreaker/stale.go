@@ -0,0 +1,267 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package httpbreaker
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// StaleHeader 标记由 stale-if-error 提供的过期响应，值为拒绝原因（如 "OPEN"）或 "ERROR"
+const StaleHeader = "X-Circuitbreaker-Stale"
+
+// staleReasonError 因请求失败（而非熔断器拒绝）提供过期响应时 StaleHeader 的值
+const staleReasonError = "ERROR"
+
+// defaultMaxCacheBodySize 可缓存响应体的默认上限
+const defaultMaxCacheBodySize = 1 << 20
+
+// CachedResponse 缓存的响应
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// StoredAt 缓存时间
+	StoredAt time.Time
+	// StaleUntil 可作为 stale-if-error 响应提供的截止时间（新鲜期 + stale-if-error 窗口）
+	StaleUntil time.Time
+	// Vary 响应 Vary 头列出的请求头在缓存时的取值，仅提供给这些请求头一致的请求
+	Vary http.Header
+}
+
+// ResponseCache Transport 的 stale-if-error 响应缓存，实现需并发安全；
+// 可基于进程内 LRU（见 NewMemoryCache）或外部缓存实现
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+}
+
+// CacheKey 返回请求的缓存键（方法与完整 URL）；按 Vary 区分的请求头记录在 CachedResponse.Vary 中
+func CacheKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String()
+}
+
+// cacheControl 响应 Cache-Control 中与 stale-if-error 相关的指令
+type cacheControl struct {
+	noStore      bool
+	maxAge       time.Duration
+	staleIfError time.Duration
+	hasStale     bool
+}
+
+// parseCacheControl 解析 Cache-Control 头，忽略无法识别的指令
+func parseCacheControl(h http.Header) cacheControl {
+	var cc cacheControl
+	for _, value := range h.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			seconds, err := strconv.Atoi(strings.Trim(arg, `"`))
+			switch strings.ToLower(name) {
+			case "no-store":
+				cc.noStore = true
+			case "max-age":
+				if err == nil && seconds > 0 {
+					cc.maxAge = time.Duration(seconds) * time.Second
+				}
+			case "stale-if-error":
+				if err == nil && seconds >= 0 {
+					cc.staleIfError = time.Duration(seconds) * time.Second
+					cc.hasStale = true
+				}
+			}
+		}
+	}
+	return cc
+}
+
+// staleIfError 按 RFC 5861 处理 stale-if-error：成功响应写入缓存，请求失败（熔断器拒绝、传输错误、
+// 500/502/503/504）时以未超出 stale-if-error 窗口的缓存响应代替，并设置 StaleHeader 与 Age；
+// 调用方 ctx 已结束时不提供过期响应
+func (t *Transport) staleIfError(req *http.Request, resp *http.Response, err error) (*http.Response, error) {
+	key := CacheKey(req)
+	if err == nil && !isStaleError(resp.StatusCode) {
+		if resp.StatusCode == http.StatusOK {
+			t.store(key, req, resp)
+		}
+		return resp, nil
+	}
+	if req.Context().Err() != nil {
+		return resp, err
+	}
+
+	now := time.Now()
+	cached, ok := t.Cache.Get(key)
+	if !ok || now.After(cached.StaleUntil) || !varyMatches(cached.Vary, req) {
+		return resp, err
+	}
+	if resp != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	reason := staleReasonError
+	if r := circuitbreaker.ReasonOf(err); r != circuitbreaker.ReasonNone {
+		reason = string(r)
+	}
+	return cached.response(req, reason, now), nil
+}
+
+// isStaleError 判断状态码是否属于 RFC 5861 中可以提供过期响应的错误
+func isStaleError(status int) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// store 读取响应体并写入缓存，resp.Body 替换为可重新读取的内容；
+// 响应未携带 stale-if-error 且未配置 StaleIfError、带 no-store、Vary 为 "*" 或响应体超出上限时不缓存
+func (t *Transport) store(key string, req *http.Request, resp *http.Response) {
+	cc := parseCacheControl(resp.Header)
+	window := t.StaleIfError
+	if cc.hasStale {
+		window = cc.staleIfError
+	}
+	if cc.noStore || window <= 0 {
+		return
+	}
+	vary, ok := varyHeaders(req, resp.Header)
+	if !ok {
+		return
+	}
+
+	limit := t.MaxCacheBodySize
+	if limit <= 0 {
+		limit = defaultMaxCacheBodySize
+	}
+	body := resp.Body
+	buf, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil || int64(len(buf)) > limit {
+		// 读取失败或超出上限：原样交还调用方，不缓存
+		resp.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(buf), &errReader{body: body, err: err}), Closer: body}
+		return
+	}
+	body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(buf))
+
+	now := time.Now()
+	t.Cache.Set(key, &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       buf,
+		StoredAt:   now,
+		StaleUntil: now.Add(cc.maxAge + window),
+		Vary:       vary,
+	})
+}
+
+// response 构造过期响应
+func (c *CachedResponse) response(req *http.Request, reason string, now time.Time) *http.Response {
+	header := c.Header.Clone()
+	header.Set(StaleHeader, reason)
+	header.Set("Age", strconv.Itoa(int(now.Sub(c.StoredAt).Seconds())))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", c.StatusCode, http.StatusText(c.StatusCode)),
+		StatusCode:    c.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// replayBody 将已读取的部分与剩余响应体拼接
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader 先返回读取出错前剩余的内容，出错时返回原错误
+type errReader struct {
+	body io.Reader
+	err  error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.body.Read(p)
+}
+
+// MemoryCache 固定容量的进程内 LRU 响应缓存
+type MemoryCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key  string
+	resp *CachedResponse
+}
+
+// NewMemoryCache 创建最多保存 maxEntries 个响应的缓存
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = 1
+	}
+	return &MemoryCache{max: maxEntries, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get 实现 ResponseCache
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*memoryEntry).resp, true
+}
+
+// Set 实现 ResponseCache
+func (c *MemoryCache) Set(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*memoryEntry).resp = resp
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, resp: resp})
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 zampo.
+
+package httpbreaker
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// flakyServer 第一个请求返回 body 与 cacheControl，之后返回 503
+func flakyServer(t *testing.T, body, cacheControl string) *httptest.Server {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestTransport_StaleIfError(t *testing.T) {
+	srv := flakyServer(t, "fresh", "max-age=0, stale-if-error=60")
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	cb := circuitbreaker.NewCircuitBreaker("api", settings)
+	transport := NewTransport(nil, cb)
+	transport.Cache = NewMemoryCache(10)
+	client := &http.Client{Transport: transport}
+
+	if resp, body := get(t, client, srv.URL); body != "fresh" || resp.Header.Get(StaleHeader) != "" {
+		t.Fatalf("first = %q stale=%q, want fresh response", body, resp.Header.Get(StaleHeader))
+	}
+
+	resp, body := get(t, client, srv.URL)
+	if resp.StatusCode != http.StatusOK || body != "fresh" || resp.Header.Get(StaleHeader) != "ERROR" {
+		t.Errorf("after 503 = %v %q stale=%q, want cached 200 marked ERROR", resp.StatusCode, body, resp.Header.Get(StaleHeader))
+	}
+	if resp.Header.Get("Age") == "" {
+		t.Error("Age header missing")
+	}
+
+	// 503 使熔断器打开，拒绝时同样提供过期响应
+	resp, body = get(t, client, srv.URL)
+	if body != "fresh" || resp.Header.Get(StaleHeader) != string(circuitbreaker.ReasonOpen) {
+		t.Errorf("while open = %q stale=%q, want cached response marked OPEN", body, resp.Header.Get(StaleHeader))
+	}
+}
+
+func TestTransport_StaleIfErrorNotCached(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		cacheControl string
+		body         string
+		maxBody      int64
+	}{
+		{"no directive", "max-age=60", "fresh", 0},
+		{"no-store", "no-store, stale-if-error=60", "fresh", 0},
+		{"too large", "stale-if-error=60", strings.Repeat("x", 64), 16},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := flakyServer(t, tc.body, tc.cacheControl)
+			transport := NewTransport(nil, circuitbreaker.NewCircuitBreaker("api", circuitbreaker.DefaultSettings()))
+			transport.Cache = NewMemoryCache(10)
+			transport.MaxCacheBodySize = tc.maxBody
+			client := &http.Client{Transport: transport}
+
+			if _, body := get(t, client, srv.URL); body != tc.body {
+				t.Fatalf("first body = %q, want intact", body)
+			}
+			if resp, _ := get(t, client, srv.URL); resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("status = %v, want 503 without stale response", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestTransport_StaleIfErrorVary(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Cache-Control", "stale-if-error=60")
+		w.Header().Set("Vary", r.Header.Get("X-Vary"))
+		io.WriteString(w, "hello "+r.Header.Get("Accept-Language"))
+	}))
+	t.Cleanup(srv.Close)
+	transport := NewTransport(nil, circuitbreaker.NewCircuitBreaker("api", circuitbreaker.DefaultSettings()))
+	transport.Cache = NewMemoryCache(10)
+	client := &http.Client{Transport: transport}
+
+	do := func(path, lang, vary string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		req.Header.Set("Accept-Language", lang)
+		req.Header.Set("X-Vary", vary)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+	do("/greeting", "en", "Accept-Language")
+	do("/any", "en", "*")
+
+	if resp, body := do("/greeting", "en", ""); resp.StatusCode != http.StatusOK || body != "hello en" {
+		t.Errorf("same variant = %v %q, want stale hello en", resp.StatusCode, body)
+	}
+	if resp, _ := do("/greeting", "fr", ""); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("other variant status = %v, want 503", resp.StatusCode)
+	}
+	if resp, _ := do("/any", "en", ""); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Vary: * status = %v, want 503", resp.StatusCode)
+	}
+}
+
+func TestTransport_StaleIfErrorDefaultWindowAndExpiry(t *testing.T) {
+	srv := flakyServer(t, "fresh", "")
+	transport := NewTransport(nil, circuitbreaker.NewCircuitBreaker("api", circuitbreaker.DefaultSettings()))
+	cache := NewMemoryCache(10)
+	transport.Cache = cache
+	transport.StaleIfError = time.Minute
+	client := &http.Client{Transport: transport}
+
+	get(t, client, srv.URL)
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	cached, ok := cache.Get(CacheKey(req))
+	if !ok {
+		t.Fatal("response not cached with default StaleIfError")
+	}
+	cached.StaleUntil = time.Now().Add(-time.Second)
+	if resp, _ := get(t, client, srv.URL); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %v, want 503 once the stale window expired", resp.StatusCode)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package httpbreaker 提供熔断器的 HTTP 集成
+package httpbreaker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Transport 带熔断保护的 http.RoundTripper
+// 单次请求的超时取 min(熔断器 CallTimeout, 请求 ctx 剩余时间)，并覆盖响应体的读取；
+// CallTimeout 先到期时返回的错误满足 errors.Is(err, circuitbreaker.ErrCallTimeout)。
+// 请求 ctx 被调用方取消或超时默认不计为失败
+type Transport struct {
+	// Base 底层 RoundTripper，为空时使用 http.DefaultTransport
+	Base http.RoundTripper
+	// Breaker 熔断器
+	Breaker *circuitbreaker.CircuitBreaker
+	// IsFailure 判断请求是否计为失败，为空时使用 DefaultIsFailure
+	IsFailure func(resp *http.Response, err error) bool
+	// HonorRetryAfter 收到带 Retry-After 的 429/503 响应时，按其时长保持熔断器打开
+	HonorRetryAfter bool
+	// MaxRetryAfter Retry-After 时长上限，0 表示不限制
+	MaxRetryAfter time.Duration
+	// Cache 设置后对 GET/HEAD 请求启用 RFC 5861 stale-if-error：熔断器拒绝或请求失败时
+	// 以缓存的过期响应代替，并以 StaleHeader 标记
+	Cache ResponseCache
+	// StaleIfError 响应未携带 stale-if-error 指令时使用的窗口，0 表示只缓存携带该指令的响应
+	StaleIfError time.Duration
+	// MaxCacheBodySize 可缓存的响应体上限，超出时不缓存，默认 1 MiB
+	MaxCacheBodySize int64
+}
+
+// NewTransport 创建带熔断保护的 Transport
+func NewTransport(base http.RoundTripper, cb *circuitbreaker.CircuitBreaker) *Transport {
+	return &Transport{Base: base, Breaker: cb}
+}
+
+// StatusError 表示被 IsFailure 判定为失败的 HTTP 响应，仅用于熔断分类，不会返回给调用方
+// 可通过 Settings.IgnoreErrors/FailErrors 按类型名 "*httpbreaker.StatusError" 匹配
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("httpbreaker: unexpected status %d", e.StatusCode)
+}
+
+// DefaultIsFailure 默认失败判定：传输错误或 5xx 响应计为失败
+func DefaultIsFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.roundTrip(req)
+	if t.Cache == nil || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return resp, err
+	}
+	return t.staleIfError(req, resp, err)
+}
+
+// roundTrip 经熔断器发送请求
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	done, err := t.Breaker.AllowContext(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	callCtx, cancel, _ := t.Breaker.CallContext(req.Context())
+	resp, err := t.base().RoundTrip(req.WithContext(callCtx))
+	done(annotate(req, resp, circuitbreaker.ContextError(callCtx, t.outcomeError(resp, err))))
+	t.honorRetryAfter(resp)
+
+	if err != nil {
+		cancel()
+		return nil, t.timeoutError(callCtx, err)
+	}
+
+	// 响应体读取完毕前保持 ctx 有效
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// timeoutError 记录超时上限，并在 CallTimeout 到期时标记错误
+func (t *Transport) timeoutError(callCtx context.Context, err error) error {
+	bound := circuitbreaker.TimeoutBoundOf(callCtx)
+	t.Breaker.RecordTimeout(bound)
+	if bound == circuitbreaker.BoundCallTimeout {
+		return fmt.Errorf("%w: %w", circuitbreaker.ErrCallTimeout, err)
+	}
+	return err
+}
+
+// outcomeError 将 IsFailure 的判定转换为用于熔断分类的错误
+func (t *Transport) outcomeError(resp *http.Response, err error) error {
+	if !t.isFailure(resp, err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &StatusError{StatusCode: resp.StatusCode}
+}
+
+// annotate 为失败附加状态码与目标主机，供错误样本使用
+func annotate(req *http.Request, resp *http.Response, err error) error {
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	return circuitbreaker.Annotate(err, status, req.URL.Host)
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) isFailure(resp *http.Response, err error) bool {
+	if t.IsFailure != nil {
+		return t.IsFailure(resp, err)
+	}
+	return DefaultIsFailure(resp, err)
+}
+
+// cancelBody 在响应体关闭时释放调用 ctx
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 zampo.
+
+package httpbreaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestTransport_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+	client := &http.Client{Transport: NewTransport(nil, cb)}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "ok" {
+		t.Errorf("body = %q, %v, want ok, nil", body, err)
+	}
+	if got := cb.Counts().TotalSuccesses; got != 1 {
+		t.Errorf("TotalSuccesses = %v, want %v", got, 1)
+	}
+}
+
+func TestTransport_ServerErrorCountsAsFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+	client := &http.Client{Transport: NewTransport(nil, cb)}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("StatusCode = %v, want %v", resp.StatusCode, http.StatusBadGateway)
+	}
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %v, want %v", got, 1)
+	}
+}
+
+func TestTransport_ErrorSamples(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	settings := circuitbreaker.DefaultSettings()
+	settings.ErrorSamples = 1
+	cb := circuitbreaker.NewCircuitBreaker("test", settings)
+	client := &http.Client{Transport: NewTransport(nil, cb)}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	samples := cb.Stats().ErrorSamples
+	if len(samples) != 1 {
+		t.Fatalf("len(ErrorSamples) = %d, want 1", len(samples))
+	}
+	if s := samples[0]; s.StatusCode != http.StatusServiceUnavailable || s.Peer != srv.Listener.Addr().String() {
+		t.Errorf("ErrorSamples[0] = %+v", s)
+	}
+}
+
+func TestTransport_TimeoutBounds(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	settings := circuitbreaker.DefaultSettings()
+	settings.CallTimeout = 20 * time.Millisecond
+	cb := circuitbreaker.NewCircuitBreaker("test", settings)
+	client := &http.Client{Transport: NewTransport(nil, cb)}
+
+	_, err := client.Get(srv.URL)
+	if !errors.Is(err, circuitbreaker.ErrCallTimeout) {
+		t.Errorf("Get() error = %v, want %v", err, circuitbreaker.ErrCallTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	_, err = client.Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, circuitbreaker.ErrCallTimeout) {
+		t.Errorf("Do() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	stats := cb.Stats()
+	if stats.CallTimeouts != 1 || stats.DeadlineTimeouts != 1 {
+		t.Errorf("CallTimeouts, DeadlineTimeouts = %v, %v, want 1, 1", stats.CallTimeouts, stats.DeadlineTimeouts)
+	}
+}
+
+func TestTransport_CallerCancellationNotCounted(t *testing.T) {
+	started := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	cb := circuitbreaker.NewCircuitBreaker("test", circuitbreaker.DefaultSettings())
+	client := &http.Client{Transport: NewTransport(nil, cb)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do() error = %v, want %v", err, context.Canceled)
+	}
+
+	if got := cb.Counts().TotalFailures; got != 0 {
+		t.Errorf("TotalFailures = %v, want %v", got, 0)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/sony/gobreaker"
+)
+
+// allow 向 gobreaker 申请放行，调用方需持有读锁
+// 半开状态且配置了 CloseFailureRate 时，探测结果先由 probes 汇总，整个探测窗口结束后再统一上报；
+// 关闭状态下暂停计数期间的失败不上报，见 PauseCounting；半开探测另受 ProbeBudget 限制。
+// 半开探测还返回 neutral：结果被忽略时调用，归还探测名额而不计为成功或失败，关闭状态下为 nil
+func (cb *CircuitBreaker) allow() (done func(success bool), neutral func(), err error) {
+	if budget := cb.settings.ProbeBudget; budget != nil && cb.cb.State() == gobreaker.StateHalfOpen {
+		return cb.allowProbe(budget)
+	}
+	return cb.allowBackend()
+}
+
+// allowBackend 执行 allow 的放行申请
+func (cb *CircuitBreaker) allowBackend() (func(success bool), func(), error) {
+	backend := cb.cb
+	halfOpen := backend.State() == gobreaker.StateHalfOpen
+	epoch := cb.spareProbes.current()
+	done, err := cb.allowHalfOpen(backend, halfOpen)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !halfOpen {
+		return done, nil, nil
+	}
+	return done, func() { cb.spareProbes.park(backend, epoch, done) }, nil
+}
+
+// allowHalfOpen 向底层熔断器申请放行；半开名额已满时复用被忽略的探测归还的名额
+func (cb *CircuitBreaker) allowHalfOpen(backend *gobreaker.TwoStepCircuitBreaker, halfOpen bool) (func(success bool), error) {
+	done, err := backend.Allow()
+	if err != nil {
+		if halfOpen && errors.Is(err, gobreaker.ErrTooManyRequests) {
+			if spare := cb.spareProbes.take(backend); spare != nil {
+				return spare, nil
+			}
+		}
+		return nil, err
+	}
+	if halfOpen && cb.settings.CloseFailureRate > 0 {
+		return cb.probes.track(backend, done, cb.settings.MaxRequests, cb.settings.CloseFailureRate), nil
+	}
+	window := cb.window.Load()
+	var gen uint64
+	if window != nil {
+		gen = window.generation()
+	}
+	return func(success bool) {
+		if !success && cb.countingPaused.Load() && backend.State() == gobreaker.StateClosed {
+			return
+		}
+		if window != nil {
+			window.record(gen, success, cb.now())
+		}
+		done(success)
+	}, nil
+}
+
+// spareProbes 半开状态下结果被忽略的探测归还的名额
+// gobreaker 无法撤销已放行的请求，也不会在半开期间重新放行：被忽略的探测既不能按成功上报
+// （依赖从未成功返回却可能关闭熔断器），也不能一直不上报（名额耗尽后熔断器停留在半开状态），
+// 因此保留其上报函数，名额已满时交给下一个探测，由其结果结算该名额
+type spareProbes struct {
+	mu      sync.Mutex
+	backend *gobreaker.TwoStepCircuitBreaker
+	epoch   uint64
+	dones   []func(success bool)
+}
+
+// current 返回当前纪元，状态变更后归还的名额属于已结束的探测窗口，不再复用
+func (s *spareProbes) current() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.epoch
+}
+
+// park 归还 epoch 纪元内放行的探测名额
+func (s *spareProbes) park(backend *gobreaker.TwoStepCircuitBreaker, epoch uint64, done func(success bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.epoch != epoch {
+		return
+	}
+	if s.backend != backend {
+		s.backend = backend
+		s.dones = nil
+	}
+	s.dones = append(s.dones, done)
+}
+
+// take 取出 backend 一个归还的名额，没有时返回 nil
+func (s *spareProbes) take(backend *gobreaker.TwoStepCircuitBreaker) func(success bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.backend != backend || len(s.dones) == 0 {
+		return nil
+	}
+	done := s.dones[len(s.dones)-1]
+	s.dones = s.dones[:len(s.dones)-1]
+	return done
+}
+
+// clear 状态变更时丢弃归还的名额并进入新纪元；在 gobreaker 内部锁中调用
+func (s *spareProbes) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epoch++
+	s.dones = nil
+}
+
+// probeWindow 汇总一个半开探测窗口内的结果
+// gobreaker 在半开状态下遇到任一失败即重新打开；这里改为窗口内失败率低于阈值才关闭，
+// 否则重新打开，使开启与关闭使用不同阈值，避免在单一阈值附近反复振荡
+type probeWindow struct {
+	mu        sync.Mutex
+	backend   *gobreaker.TwoStepCircuitBreaker
+	epoch     uint64
+	pending   []func(success bool)
+	failures  uint32
+	successes uint32
+}
+
+// track 包装探测请求的 done，返回的函数只记录结果，窗口满时统一上报
+func (w *probeWindow) track(backend *gobreaker.TwoStepCircuitBreaker, done func(success bool), size uint32, rate float64) func(success bool) {
+	if size == 0 {
+		size = 1 // 与 gobreaker 一致：MaxRequests 为 0 时半开只放行 1 个请求
+	}
+
+	w.mu.Lock()
+	if w.backend != backend {
+		w.resetLocked(backend)
+	}
+	epoch := w.epoch
+	w.mu.Unlock()
+
+	return func(success bool) {
+		w.mu.Lock()
+		if w.backend != backend || w.epoch != epoch {
+			// 窗口已结算或熔断器已重建，直接上报，gobreaker 会忽略过期的结果
+			w.mu.Unlock()
+			done(success)
+			return
+		}
+
+		w.pending = append(w.pending, done)
+		if success {
+			w.successes++
+		} else {
+			w.failures++
+		}
+		total := w.successes + w.failures
+		if total < size {
+			w.mu.Unlock()
+			return
+		}
+
+		closeWindow := float64(w.failures)/float64(total) < rate
+		pending := w.pending
+		w.resetLocked(backend)
+		w.mu.Unlock()
+
+		// 关闭：全部按成功上报，达到 MaxRequests 次连续成功后关闭；否则首个失败即重新打开
+		for _, d := range pending {
+			d(closeWindow)
+		}
+	}
+}
+
+// resetLocked 开始新的探测窗口，调用方需持有锁
+func (w *probeWindow) resetLocked(backend *gobreaker.TwoStepCircuitBreaker) {
+	w.backend = backend
+	w.epoch++
+	w.pending = nil
+	w.failures = 0
+	w.successes = 0
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func halfOpenBreaker(t *testing.T, closeRate float64) *CircuitBreaker {
+	t.Helper()
+	settings := DefaultSettings()
+	settings.MaxRequests = 10
+	settings.Timeout = 10 * time.Millisecond
+	settings.CloseFailureRate = closeRate
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	cb := NewCircuitBreaker("test", settings)
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	time.Sleep(20 * time.Millisecond)
+	if got := cb.State(); got != gobreaker.StateHalfOpen {
+		t.Fatalf("State() = %v, want %v", got, gobreaker.StateHalfOpen)
+	}
+	return cb
+}
+
+func probe(cb *CircuitBreaker, failures, total int) {
+	for i := 0; i < total; i++ {
+		var err error
+		if i < failures {
+			err = errors.New("fail")
+		}
+		cb.Execute(func() (interface{}, error) { return nil, err })
+	}
+}
+
+func TestCloseFailureRate_ClosesBelowThreshold(t *testing.T) {
+	cb := halfOpenBreaker(t, 0.2)
+
+	probe(cb, 1, 9)
+	if got := cb.State(); got != gobreaker.StateHalfOpen {
+		t.Fatalf("State() mid-window = %v, want %v", got, gobreaker.StateHalfOpen)
+	}
+	probe(cb, 0, 1)
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+}
+
+func TestCloseFailureRate_ReopensAtThreshold(t *testing.T) {
+	cb := halfOpenBreaker(t, 0.2)
+
+	probe(cb, 2, 10)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+}
+
+func TestCloseFailureRate_Disabled(t *testing.T) {
+	cb := halfOpenBreaker(t, 0)
+
+	probe(cb, 1, 1)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package hystrixbreaker 提供与 hystrix-go 相同形式的 Do/Go/ConfigureCommand 接口，
+// 由本包的 Registry 支撑，便于仍在使用已归档的 hystrix-go 的服务逐步迁移：
+// 多数情况下只需将 import 从 github.com/afex/hystrix-go/hystrix 替换为本包
+package hystrixbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// 与 hystrix-go 相同的默认值，时间单位为毫秒
+var (
+	// DefaultTimeout 命令执行超时
+	DefaultTimeout = 1000
+	// DefaultMaxConcurrent 最大并发执行数
+	DefaultMaxConcurrent = 10
+	// DefaultVolumeThreshold 统计窗口内评估熔断所需的最小请求数
+	DefaultVolumeThreshold = 20
+	// DefaultSleepWindow 熔断打开后到允许探测的时间
+	DefaultSleepWindow = 5000
+	// DefaultErrorPercentThreshold 触发熔断的错误百分比
+	DefaultErrorPercentThreshold = 50
+)
+
+// rollingWindow hystrix-go 固定的 10 秒滚动统计窗口
+const rollingWindow = 10 * time.Second
+
+// CircuitError 命令未能执行时的错误，与 hystrix-go 同名类型一致
+type CircuitError struct {
+	Message string
+}
+
+func (e CircuitError) Error() string {
+	return "hystrix: " + e.Message
+}
+
+var (
+	// ErrMaxConcurrency 并发执行数达到 MaxConcurrentRequests
+	ErrMaxConcurrency = CircuitError{Message: "max concurrency"}
+	// ErrCircuitOpen 熔断器打开（含半开期间名额已满）
+	ErrCircuitOpen = CircuitError{Message: "circuit open"}
+	// ErrTimeout 执行超过 Timeout
+	ErrTimeout = CircuitError{Message: "timeout"}
+)
+
+// CommandConfig 命令配置，字段与 JSON 标签与 hystrix-go 一致，0 表示使用默认值
+type CommandConfig struct {
+	Timeout                int `json:"timeout"`
+	MaxConcurrentRequests  int `json:"max_concurrent_requests"`
+	RequestVolumeThreshold int `json:"request_volume_threshold"`
+	SleepWindow            int `json:"sleep_window"`
+	ErrorPercentThreshold  int `json:"error_percent_threshold"`
+}
+
+// Settings 将命令配置转换为熔断器配置：Timeout 为 CallTimeout，SleepWindow 为打开期，
+// 10 秒滚动窗口内请求数达到 RequestVolumeThreshold 且错误率达到 ErrorPercentThreshold 时熔断，半开期间只放行一个探测
+func (c CommandConfig) Settings() circuitbreaker.Settings {
+	settings := circuitbreaker.DefaultSettings()
+	settings.MaxRequests = 1
+	settings.WindowMode = circuitbreaker.WindowRolling
+	settings.Interval = rollingWindow
+	settings.CallTimeout = time.Duration(orDefault(c.Timeout, DefaultTimeout)) * time.Millisecond
+	settings.Timeout = time.Duration(orDefault(c.SleepWindow, DefaultSleepWindow)) * time.Millisecond
+	settings.MinimumRequests = uint32(orDefault(c.RequestVolumeThreshold, DefaultVolumeThreshold))
+	settings.ReadyToTrip = circuitbreaker.FailureRate(float64(orDefault(c.ErrorPercentThreshold, DefaultErrorPercentThreshold)) / 100)
+	return settings
+}
+
+func orDefault(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
+
+// command 一个命令的熔断器与并发名额
+type command struct {
+	cb    *circuitbreaker.CircuitBreaker
+	slots chan struct{}
+}
+
+var (
+	mu       sync.Mutex
+	registry = circuitbreaker.NewRegistry()
+	configs  = make(map[string]CommandConfig)
+	commands = make(map[string]*command)
+)
+
+// SetRegistry 设置命令熔断器所在的注册表，以便与其他熔断器一起导出统计与管理；
+// 已创建的命令保留在原注册表中，通常在程序启动、首次执行命令前调用
+func SetRegistry(r *circuitbreaker.Registry) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry = r
+	commands = make(map[string]*command)
+}
+
+// Registry 返回命令熔断器所在的注册表
+func Registry() *circuitbreaker.Registry {
+	mu.Lock()
+	defer mu.Unlock()
+	return registry
+}
+
+// Configure 批量配置命令
+func Configure(cmds map[string]CommandConfig) {
+	for name, config := range cmds {
+		ConfigureCommand(name, config)
+	}
+}
+
+// ConfigureCommand 配置命令，已创建的命令热更新熔断配置与并发上限
+func ConfigureCommand(name string, config CommandConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	configs[name] = config
+	if cmd, ok := commands[name]; ok {
+		cmd.cb.UpdateSettings(config.Settings())
+		commands[name] = &command{cb: cmd.cb, slots: newSlots(config)}
+	}
+}
+
+// GetCircuit 返回命令的熔断器，不存在时创建，created 表示本次调用是否新建
+func GetCircuit(name string) (cb *circuitbreaker.CircuitBreaker, created bool, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	cmd, created := getCommandLocked(name)
+	return cmd.cb, created, nil
+}
+
+// Flush 移除所有命令及其熔断器，配置保留；主要用于测试
+func Flush() {
+	mu.Lock()
+	defer mu.Unlock()
+	for name, cmd := range commands {
+		registry.Remove(cmd.cb.Name())
+		delete(commands, name)
+	}
+}
+
+func getCommand(name string) *command {
+	mu.Lock()
+	defer mu.Unlock()
+	cmd, _ := getCommandLocked(name)
+	return cmd
+}
+
+// getCommandLocked 返回命令，不存在时按配置（未配置时为默认值）创建，调用方需持有 mu
+func getCommandLocked(name string) (*command, bool) {
+	if cmd, ok := commands[name]; ok {
+		return cmd, false
+	}
+	config := configs[name]
+	cmd := &command{cb: registry.GetOrCreate(name, config.Settings()), slots: newSlots(config)}
+	commands[name] = cmd
+	return cmd, true
+}
+
+func newSlots(config CommandConfig) chan struct{} {
+	return make(chan struct{}, orDefault(config.MaxConcurrentRequests, DefaultMaxConcurrent))
+}
+
+// Do 同步执行 run，失败或被拒绝时执行 fallback（可为空）
+func Do(name string, run func() error, fallback func(error) error) error {
+	var fallbackC func(context.Context, error) error
+	if fallback != nil {
+		fallbackC = func(_ context.Context, err error) error { return fallback(err) }
+	}
+	return DoC(context.Background(), name, func(context.Context) error { return run() }, fallbackC)
+}
+
+// DoC 同步执行 run，失败或被拒绝时执行 fallback（可为空）；run 收到的 ctx 带有命令超时
+func DoC(ctx context.Context, name string, run func(context.Context) error, fallback func(context.Context, error) error) error {
+	err := execute(ctx, getCommand(name), run)
+	if err == nil || fallback == nil {
+		return err
+	}
+	if fbErr := fallback(ctx, err); fbErr != nil {
+		return fmt.Errorf("fallback failed with '%v'. run error was '%v'", fbErr, err)
+	}
+	return nil
+}
+
+// Go 异步执行 run，返回的通道只在最终出错（含 fallback 失败）时收到错误，成功时不发送也不关闭
+func Go(name string, run func() error, fallback func(error) error) chan error {
+	errs := make(chan error, 1)
+	go func() {
+		if err := Do(name, run, fallback); err != nil {
+			errs <- err
+		}
+	}()
+	return errs
+}
+
+// GoC 与 Go 相同，run 与 fallback 接收 ctx
+func GoC(ctx context.Context, name string, run func(context.Context) error, fallback func(context.Context, error) error) chan error {
+	errs := make(chan error, 1)
+	go func() {
+		if err := DoC(ctx, name, run, fallback); err != nil {
+			errs <- err
+		}
+	}()
+	return errs
+}
+
+// execute 占用并发名额后经熔断器执行，拒绝与超时转换为 hystrix-go 的错误
+func execute(ctx context.Context, cmd *command, run func(context.Context) error) error {
+	select {
+	case cmd.slots <- struct{}{}:
+		defer func() { <-cmd.slots }()
+	default:
+		return ErrMaxConcurrency
+	}
+
+	_, err := cmd.cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, run(ctx)
+	})
+	switch {
+	case err == nil:
+		return nil
+	case isOpen(circuitbreaker.ReasonOf(err)):
+		return ErrCircuitOpen
+	case errors.Is(err, circuitbreaker.ErrCallTimeout),
+		// run 自行响应命令超时返回时，错误为 ctx 的 DeadlineExceeded
+		errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+		return ErrTimeout
+	default:
+		return err
+	}
+}
+
+// isOpen 判断拒绝原因是否对应 hystrix-go 的熔断打开
+func isOpen(reason circuitbreaker.Reason) bool {
+	switch reason {
+	case circuitbreaker.ReasonOpen, circuitbreaker.ReasonHalfOpenLimit, circuitbreaker.ReasonForced:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 zampo.
+
+package hystrixbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestDo_FallbackAndTrip(t *testing.T) {
+	defer Flush()
+	ConfigureCommand("trip", CommandConfig{RequestVolumeThreshold: 2, ErrorPercentThreshold: 50, SleepWindow: 60000})
+
+	boom := errors.New("boom")
+	var fallbackErr error
+	for i := 0; i < 2; i++ {
+		err := Do("trip", func() error { return boom }, func(err error) error {
+			fallbackErr = err
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Do() error = %v, want nil after successful fallback", err)
+		}
+	}
+	if fallbackErr != boom {
+		t.Errorf("fallback error = %v, want %v", fallbackErr, boom)
+	}
+
+	cb, created, _ := GetCircuit("trip")
+	if created || cb.State() != gobreaker.StateOpen {
+		t.Fatalf("GetCircuit() = %v created=%v, want existing open breaker", cb.State(), created)
+	}
+	if err := Do("trip", func() error { return nil }, nil); err != ErrCircuitOpen {
+		t.Errorf("Do() error = %v, want %v", err, ErrCircuitOpen)
+	}
+	err := Do("trip", func() error { return nil }, func(error) error { return errors.New("no cache") })
+	if err == nil || err.Error() != "fallback failed with 'no cache'. run error was 'hystrix: circuit open'" {
+		t.Errorf("Do() error = %v", err)
+	}
+}
+
+func TestDo_Timeout(t *testing.T) {
+	defer Flush()
+	ConfigureCommand("slow", CommandConfig{Timeout: 10})
+
+	err := DoC(context.Background(), "slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, nil)
+	if err != ErrTimeout {
+		t.Errorf("DoC() error = %v, want %v", err, ErrTimeout)
+	}
+}
+
+func TestDo_MaxConcurrency(t *testing.T) {
+	defer Flush()
+	ConfigureCommand("busy", CommandConfig{MaxConcurrentRequests: 1})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	errs := Go("busy", func() error {
+		close(started)
+		<-release
+		return nil
+	}, nil)
+	<-started
+
+	if err := Do("busy", func() error { return nil }, nil); err != ErrMaxConcurrency {
+		t.Errorf("Do() error = %v, want %v", err, ErrMaxConcurrency)
+	}
+	close(release)
+	select {
+	case err := <-errs:
+		t.Errorf("Go() error = %v, want none", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestGo_ReportsError(t *testing.T) {
+	defer Flush()
+	boom := errors.New("boom")
+	if err := <-Go("fails", func() error { return boom }, nil); err != boom {
+		t.Errorf("Go() error = %v, want %v", err, boom)
+	}
+	if _, ok := Registry().Get("fails"); !ok {
+		t.Error("command breaker not registered")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+)
+
+// ExecFunc 一次受熔断保护的调用，返回值与 Execute 相同
+type ExecFunc func(ctx context.Context) (interface{}, error)
+
+// Interceptor 包装 Execute/ExecuteContext 的中间件，用于统一添加日志、追踪、指标、鉴权等横切逻辑。
+// next 包含准入判定与实际调用：被拒绝时 next 返回 *RejectionError；不调用 next 即可短路，
+// 此时调用既不经过熔断器也不计入统计。ctx 中可通过 CallInfoFromContext 获取熔断器信息
+type Interceptor func(next ExecFunc) ExecFunc
+
+// CallInfo 被拦截调用所属熔断器的信息
+type CallInfo struct {
+	Breaker string
+	Tier    Tier
+	Labels  map[string]string
+}
+
+// callInfoKey CallInfo 在 ctx 中的键
+type callInfoKey struct{}
+
+// CallInfoFromContext 返回拦截器 ctx 中的调用信息
+func CallInfoFromContext(ctx context.Context) (CallInfo, bool) {
+	info, ok := ctx.Value(callInfoKey{}).(CallInfo)
+	return info, ok
+}
+
+// Chain 将多个拦截器合并为一个，第一个位于最外层
+func Chain(interceptors ...Interceptor) Interceptor {
+	return func(next ExecFunc) ExecFunc {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			next = interceptors[i](next)
+		}
+		return next
+	}
+}
+
+// interceptorChain 返回 Settings.Interceptors 合并后的拦截器与携带 CallInfo 的 ctx，未配置拦截器时返回 nil
+func (cb *CircuitBreaker) interceptorChain(ctx context.Context) (Interceptor, context.Context) {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	if len(cb.settings.Interceptors) == 0 {
+		return nil, ctx
+	}
+	info := CallInfo{Breaker: cb.name, Tier: cb.settings.Tier.orDefault(), Labels: cb.settings.Labels}
+	return Chain(cb.settings.Interceptors...), context.WithValue(ctx, callInfoKey{}, info)
+}
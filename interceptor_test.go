@@ -0,0 +1,135 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// tracing 记录拦截器的进入与退出顺序
+type tracing struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (tr *tracing) interceptor(name string) Interceptor {
+	return func(next ExecFunc) ExecFunc {
+		return func(ctx context.Context) (interface{}, error) {
+			tr.add(name + ">")
+			defer tr.add("<" + name)
+			return next(ctx)
+		}
+	}
+}
+
+func (tr *tracing) add(event string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.events = append(tr.events, event)
+}
+
+func TestInterceptors_Order(t *testing.T) {
+	tr := &tracing{}
+	settings := DefaultSettings()
+	settings.Interceptors = []Interceptor{tr.interceptor("log"), tr.interceptor("trace")}
+	cb := NewCircuitBreaker("intercepted", settings)
+
+	cb.Execute(func() (interface{}, error) {
+		tr.add("call")
+		return nil, nil
+	})
+	cb.ExecuteContext(context.Background(), func(context.Context) (interface{}, error) {
+		tr.add("call")
+		return nil, nil
+	})
+
+	once := []string{"log>", "trace>", "call", "<trace", "<log"}
+	if want := append(append([]string{}, once...), once...); !reflect.DeepEqual(tr.events, want) {
+		t.Errorf("events = %v, want %v", tr.events, want)
+	}
+}
+
+func TestInterceptors_SeeRejectionsAndCallInfo(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		reasons []Reason
+		infos   []CallInfo
+	)
+	metrics := func(next ExecFunc) ExecFunc {
+		return func(ctx context.Context) (interface{}, error) {
+			result, err := next(ctx)
+			info, _ := CallInfoFromContext(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			reasons = append(reasons, ReasonOf(err))
+			infos = append(infos, info)
+			return result, err
+		}
+	}
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	settings.Tier = TierBestEffort
+	settings.Labels = map[string]string{"service": "search"}
+	settings.Interceptors = []Interceptor{metrics}
+	cb := NewCircuitBreaker("search", settings)
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+
+	if want := []Reason{ReasonNone, ReasonOpen}; !reflect.DeepEqual(reasons, want) {
+		t.Errorf("reasons = %v, want %v", reasons, want)
+	}
+	want := CallInfo{Breaker: "search", Tier: TierBestEffort, Labels: map[string]string{"service": "search"}}
+	if !reflect.DeepEqual(infos[0], want) {
+		t.Errorf("CallInfo = %+v, want %+v", infos[0], want)
+	}
+}
+
+func TestInterceptors_ShortCircuitAndContext(t *testing.T) {
+	type tokenKey struct{}
+	errDenied := errors.New("denied")
+	auth := func(next ExecFunc) ExecFunc {
+		return func(ctx context.Context) (interface{}, error) {
+			if ctx.Value(tokenKey{}) == nil {
+				return nil, errDenied
+			}
+			return next(context.WithValue(ctx, tokenKey{}, "checked"))
+		}
+	}
+	settings := DefaultSettings()
+	settings.Interceptors = []Interceptor{auth}
+	cb := NewCircuitBreaker("auth", settings)
+
+	if _, err := cb.ExecuteContext(context.Background(), func(context.Context) (interface{}, error) {
+		t.Error("fn called without token")
+		return nil, nil
+	}); !errors.Is(err, errDenied) {
+		t.Errorf("ExecuteContext() error = %v, want %v", err, errDenied)
+	}
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Requests after short circuit = %d, want 0", got)
+	}
+
+	ctx := context.WithValue(context.Background(), tokenKey{}, "token")
+	got, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		return ctx.Value(tokenKey{}), nil
+	})
+	if err != nil || got != "checked" {
+		t.Errorf("ExecuteContext() = %v, %v, want value set by interceptor", got, err)
+	}
+}
+
+func TestChain(t *testing.T) {
+	tr := &tracing{}
+	call := Chain(tr.interceptor("a"), Chain(tr.interceptor("b"), tr.interceptor("c")))(func(context.Context) (interface{}, error) {
+		return nil, nil
+	})
+	call(context.Background())
+	if want := []string{"a>", "b>", "c>", "<c", "<b", "<a"}; !reflect.DeepEqual(tr.events, want) {
+		t.Errorf("events = %v, want %v", tr.events, want)
+	}
+}
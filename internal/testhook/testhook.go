@@ -0,0 +1,26 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package testhook 在根包与 circuitbreakertest 之间传递仅供测试使用的内部操作，
+// 避免这些操作作为 CircuitBreaker 的方法导出给生产代码
+package testhook
+
+import (
+	"github.com/sony/gobreaker"
+)
+
+// TransitionTo 将 cb（*circuitbreaker.CircuitBreaker）切换到 state，由根包在 init 中设置
+var TransitionTo func(cb any, state gobreaker.State) error
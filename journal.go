@@ -0,0 +1,253 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// ErrJournalClosed 事件日志已关闭
+var ErrJournalClosed = errors.New("circuitbreaker: event journal closed")
+
+// StateEvent 持久化的状态变更事件
+type StateEvent struct {
+	// Seq 日志内单调递增的序号，从 1 开始
+	Seq     uint64
+	Breaker string
+	From    gobreaker.State
+	To      gobreaker.State
+	At      time.Time
+}
+
+// EventLog 状态变更事件的持久化存储，按追加顺序分配连续序号，并为每个订阅者保存已确认位置
+type EventLog interface {
+	// Append 追加事件并分配 Seq
+	Append(e StateEvent) (StateEvent, error)
+	// Read 返回序号大于 after 的事件，按序号升序，最多 limit 条
+	Read(after uint64, limit int) ([]StateEvent, error)
+	// Cursor 返回订阅者已确认的最大序号，从未确认时为 0
+	Cursor(subscriber string) (uint64, error)
+	// Commit 保存订阅者已确认的序号
+	Commit(subscriber string, seq uint64) error
+}
+
+// JournalSettings 事件日志配置
+type JournalSettings struct {
+	// BatchSize 订阅者每次读取的事件数，默认 100
+	BatchSize int
+	// RetryBackoff 订阅者处理失败后的重试间隔，默认 1 秒
+	RetryBackoff time.Duration
+	// OnError 写入日志或订阅者处理失败时调用
+	OnError func(err error)
+}
+
+// EventJournal 将注册表内所有状态变更按发生顺序写入 EventLog，并以至少一次、按序的语义
+// 投递给持久订阅者（如审计系统）：订阅者处理成功后才推进其确认位置，重启后从确认位置继续。
+// 与 Registry.Subscribe 的内存通知不同，订阅者处理缓慢或暂时不可用时事件不会丢失；
+// 事件在状态变更回调中同步追加，EventLog 的 Append 应尽量快速
+type EventJournal struct {
+	log         EventLog
+	settings    JournalSettings
+	unsubscribe func()
+
+	mu     sync.Mutex
+	wake   chan struct{}
+	closed bool
+}
+
+// NewEventJournal 创建事件日志并开始记录 r 的状态变更，不再使用时调用 Close
+func NewEventJournal(r *Registry, log EventLog, settings JournalSettings) *EventJournal {
+	if settings.BatchSize <= 0 {
+		settings.BatchSize = 100
+	}
+	if settings.RetryBackoff <= 0 {
+		settings.RetryBackoff = time.Second
+	}
+	j := &EventJournal{
+		log:      log,
+		settings: settings,
+		wake:     make(chan struct{}),
+	}
+	j.unsubscribe = r.Subscribe(j.record)
+	return j
+}
+
+// record 追加状态变更；在熔断器锁内调用，不可访问注册表
+func (j *EventJournal) record(name string, from, to gobreaker.State) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.closed {
+		return
+	}
+	if _, err := j.log.Append(StateEvent{Breaker: name, From: from, To: to, At: time.Now()}); err != nil {
+		j.reportError(err)
+		return
+	}
+	// 唤醒所有等待新事件的订阅者
+	close(j.wake)
+	j.wake = make(chan struct{})
+}
+
+// Subscribe 以 id 作为持久订阅者按序投递事件，阻塞直至 ctx 结束或 Close。
+// fn 返回错误时按 RetryBackoff 重试同一事件，不会跳过；进程在确认前退出时，
+// 重启后会再次投递该事件，fn 应按 Seq 幂等处理
+func (j *EventJournal) Subscribe(ctx context.Context, id string, fn func(ctx context.Context, e StateEvent) error) error {
+	cursor, err := j.log.Cursor(id)
+	if err != nil {
+		return err
+	}
+	for {
+		j.mu.Lock()
+		wake, closed := j.wake, j.closed
+		j.mu.Unlock()
+		if closed {
+			return ErrJournalClosed
+		}
+
+		events, err := j.log.Read(cursor, j.settings.BatchSize)
+		if err != nil {
+			j.reportError(err)
+			if err := j.sleep(ctx, j.settings.RetryBackoff); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(events) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-wake:
+			}
+			continue
+		}
+
+		for _, e := range events {
+			if err := j.deliver(ctx, e, fn); err != nil {
+				return err
+			}
+			if err := j.log.Commit(id, e.Seq); err != nil {
+				// 确认失败时事件会在重启后重复投递，仍满足至少一次语义
+				j.reportError(err)
+			}
+			cursor = e.Seq
+		}
+	}
+}
+
+// deliver 投递单个事件，失败时重试直至成功、ctx 结束或 Close
+func (j *EventJournal) deliver(ctx context.Context, e StateEvent, fn func(ctx context.Context, e StateEvent) error) error {
+	for {
+		err := fn(ctx, e)
+		if err == nil {
+			return nil
+		}
+		j.reportError(err)
+		if err := j.sleep(ctx, j.settings.RetryBackoff); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep 等待 d，期间 ctx 结束或 Close 时返回对应错误
+func (j *EventJournal) sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.closed {
+		return ErrJournalClosed
+	}
+	return nil
+}
+
+// Close 停止记录并使所有 Subscribe 返回 ErrJournalClosed
+func (j *EventJournal) Close() {
+	j.unsubscribe()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.closed {
+		j.closed = true
+		close(j.wake)
+	}
+}
+
+// reportError 调用 OnError
+func (j *EventJournal) reportError(err error) {
+	if j.settings.OnError != nil {
+		j.settings.OnError(err)
+	}
+}
+
+// MemoryEventLog 内存事件日志，仅适用于测试或可接受重启丢失的场景
+type MemoryEventLog struct {
+	mu      sync.Mutex
+	events  []StateEvent
+	cursors map[string]uint64
+}
+
+// NewMemoryEventLog 创建内存事件日志
+func NewMemoryEventLog() *MemoryEventLog {
+	return &MemoryEventLog{cursors: make(map[string]uint64)}
+}
+
+// Append 实现 EventLog
+func (l *MemoryEventLog) Append(e StateEvent) (StateEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e.Seq = uint64(len(l.events)) + 1
+	l.events = append(l.events, e)
+	return e, nil
+}
+
+// Read 实现 EventLog
+func (l *MemoryEventLog) Read(after uint64, limit int) ([]StateEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if after >= uint64(len(l.events)) {
+		return nil, nil
+	}
+	events := l.events[after:]
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return append([]StateEvent(nil), events...), nil
+}
+
+// Cursor 实现 EventLog
+func (l *MemoryEventLog) Cursor(subscriber string) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cursors[subscriber], nil
+}
+
+// Commit 实现 EventLog
+func (l *MemoryEventLog) Commit(subscriber string, seq uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cursors[subscriber] = seq
+	return nil
+}
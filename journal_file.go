@@ -0,0 +1,248 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// 事件日志目录中的文件名
+const (
+	journalFile  = "events.jsonl"
+	cursorPrefix = "cursor-"
+)
+
+// fileEvent 事件在磁盘上的编码，状态以名称保存便于直接查看
+type fileEvent struct {
+	Seq     uint64    `json:"seq"`
+	Breaker string    `json:"breaker"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	At      time.Time `json:"at"`
+}
+
+// FileEventLog 基于本地目录的事件日志：事件以 JSON Lines 追加写入 events.jsonl，
+// 每个订阅者的确认位置保存在 cursor-<id> 文件中（写临时文件后原子替换）。
+// 打开时丢弃崩溃导致的不完整尾行；日志不会自动截断，需要时由运维按确认位置归档
+type FileEventLog struct {
+	mu      sync.Mutex
+	dir     string
+	f       *os.File
+	offsets []int64
+	size    int64
+	sync    bool
+}
+
+// OpenFileEventLog 打开或创建 dir 下的事件日志；syncWrites 为 true 时每次写入后 fsync，
+// 可在断电时不丢失已追加的事件，代价是状态变更回调中多一次磁盘同步
+func OpenFileEventLog(dir string, syncWrites bool) (*FileEventLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l := &FileEventLog{dir: dir, f: f, sync: syncWrites}
+	if err := l.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// load 扫描已有事件建立偏移索引，截断不完整的尾行
+func (l *FileEventLog) load() error {
+	r := bufio.NewReader(l.f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// 没有换行符的尾行是崩溃时未写完的事件
+			if len(line) > 0 {
+				if err := l.f.Truncate(offset); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var e fileEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("circuitbreaker: event log corrupt at offset %d: %w", offset, err)
+		}
+		if e.Seq != uint64(len(l.offsets))+1 {
+			return fmt.Errorf("circuitbreaker: event log out of sequence at offset %d: got %d", offset, e.Seq)
+		}
+		l.offsets = append(l.offsets, offset)
+		offset += int64(len(line))
+	}
+	l.size = offset
+	return nil
+}
+
+// Append 实现 EventLog
+func (l *FileEventLog) Append(e StateEvent) (StateEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Seq = uint64(len(l.offsets)) + 1
+	line, err := json.Marshal(fileEvent{Seq: e.Seq, Breaker: e.Breaker, From: e.From.String(), To: e.To.String(), At: e.At})
+	if err != nil {
+		return StateEvent{}, err
+	}
+	line = append(line, '\n')
+	if _, err := l.f.WriteAt(line, l.size); err != nil {
+		return StateEvent{}, err
+	}
+	if l.sync {
+		if err := l.f.Sync(); err != nil {
+			return StateEvent{}, err
+		}
+	}
+	l.offsets = append(l.offsets, l.size)
+	l.size += int64(len(line))
+	return e, nil
+}
+
+// Read 实现 EventLog
+func (l *FileEventLog) Read(after uint64, limit int) ([]StateEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	total := uint64(len(l.offsets))
+	if after >= total {
+		return nil, nil
+	}
+	end := total
+	if limit > 0 && end-after > uint64(limit) {
+		end = after + uint64(limit)
+	}
+	stop := l.size
+	if end < total {
+		stop = l.offsets[end]
+	}
+	buf := make([]byte, stop-l.offsets[after])
+	if _, err := l.f.ReadAt(buf, l.offsets[after]); err != nil {
+		return nil, err
+	}
+
+	events := make([]StateEvent, 0, end-after)
+	for _, line := range bytes.SplitAfter(buf, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var fe fileEvent
+		if err := json.Unmarshal(line, &fe); err != nil {
+			return nil, err
+		}
+		events = append(events, StateEvent{Seq: fe.Seq, Breaker: fe.Breaker, From: parseState(fe.From), To: parseState(fe.To), At: fe.At})
+	}
+	return events, nil
+}
+
+// Cursor 实现 EventLog
+func (l *FileEventLog) Cursor(subscriber string) (uint64, error) {
+	path, err := l.cursorPath(subscriber)
+	if err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// Commit 实现 EventLog
+func (l *FileEventLog) Commit(subscriber string, seq uint64) error {
+	path, err := l.cursorPath(subscriber)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(l.dir, cursorPrefix+"*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strconv.FormatUint(seq, 10) + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if l.sync {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Close 关闭日志文件
+func (l *FileEventLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+// cursorPath 返回订阅者确认位置文件路径，id 仅允许字母、数字与 - _ .
+func (l *FileEventLog) cursorPath(subscriber string) (string, error) {
+	valid := subscriber != "" && subscriber != "." && subscriber != ".."
+	for _, r := range subscriber {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			valid = false
+			break
+		}
+	}
+	if !valid {
+		return "", fmt.Errorf("circuitbreaker: invalid subscriber id %q", subscriber)
+	}
+	return filepath.Join(l.dir, cursorPrefix+subscriber), nil
+}
+
+// parseState 解析 gobreaker.State.String 的输出，无法识别时视为关闭
+func parseState(name string) gobreaker.State {
+	switch name {
+	case gobreaker.StateOpen.String():
+		return gobreaker.StateOpen
+	case gobreaker.StateHalfOpen.String():
+		return gobreaker.StateHalfOpen
+	default:
+		return gobreaker.StateClosed
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestFileEventLog(t *testing.T) {
+	dir := t.TempDir()
+	log, err := OpenFileEventLog(dir, true)
+	if err != nil {
+		t.Fatalf("OpenFileEventLog() error = %v", err)
+	}
+	at := time.Now().UTC().Truncate(time.Millisecond)
+	for _, to := range []gobreaker.State{gobreaker.StateOpen, gobreaker.StateHalfOpen, gobreaker.StateClosed} {
+		if _, err := log.Append(StateEvent{Breaker: "payments", To: to, At: at}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	if err := log.Commit("audit", 2); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	log.Close()
+
+	// 模拟写入中途崩溃留下的不完整尾行
+	f, _ := os.OpenFile(filepath.Join(dir, journalFile), os.O_WRONLY|os.O_APPEND, 0)
+	f.WriteString(`{"seq":4,"breaker":"pay`)
+	f.Close()
+
+	log, err = OpenFileEventLog(dir, false)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	defer log.Close()
+
+	cursor, err := log.Cursor("audit")
+	if err != nil || cursor != 2 {
+		t.Fatalf("Cursor() = %d, %v, want 2", cursor, err)
+	}
+	events, err := log.Read(cursor, 10)
+	if err != nil || len(events) != 1 || events[0].Seq != 3 || events[0].To != gobreaker.StateClosed || !events[0].At.Equal(at) {
+		t.Fatalf("Read(2) = %+v, %v, want seq 3 to closed", events, err)
+	}
+	if e, err := log.Append(StateEvent{Breaker: "orders", To: gobreaker.StateOpen}); err != nil || e.Seq != 4 {
+		t.Errorf("Append() after truncation = %+v, %v, want seq 4", e, err)
+	}
+	if events, _ := log.Read(0, 2); len(events) != 2 || events[1].To != gobreaker.StateHalfOpen {
+		t.Errorf("Read(0, 2) = %+v, want first two events", events)
+	}
+
+	if cursor, err := log.Cursor("new"); err != nil || cursor != 0 {
+		t.Errorf("Cursor(new) = %d, %v, want 0", cursor, err)
+	}
+	if err := log.Commit("../escape", 1); err == nil {
+		t.Error("Commit() with path in subscriber id should fail")
+	}
+}
+
+func TestFileEventLog_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, journalFile), []byte("garbage\n"), 0o644)
+	if _, err := OpenFileEventLog(dir, false); err == nil {
+		t.Error("OpenFileEventLog() should fail on a corrupt log")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestEventJournal_OrderedAtLeastOnce(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	r := NewRegistry()
+	cb := r.GetOrCreate("payments", settings)
+	log := NewMemoryEventLog()
+	j := NewEventJournal(r, log, JournalSettings{RetryBackoff: time.Millisecond})
+	defer j.Close()
+
+	// 订阅前发生的状态变更同样会被投递
+	tripBreaker(cb)
+	cb.Reset()
+
+	var (
+		mu       sync.Mutex
+		got      []StateEvent
+		failures = 2
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- j.Subscribe(ctx, "audit", func(ctx context.Context, e StateEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if e.Seq == 2 && failures > 0 {
+				failures--
+				return errors.New("audit store unavailable")
+			}
+			got = append(got, e)
+			return nil
+		})
+	}()
+
+	tripBreaker(cb)
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 3
+	})
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Subscribe() error = %v, want %v", err, context.Canceled)
+	}
+
+	want := []gobreaker.State{gobreaker.StateOpen, gobreaker.StateClosed, gobreaker.StateOpen}
+	for i, e := range got {
+		if e.Seq != uint64(i+1) || e.Breaker != "payments" || e.To != want[i] {
+			t.Errorf("event %d = %+v, want seq %d to %v", i, e, i+1, want[i])
+		}
+	}
+	if cursor, _ := log.Cursor("audit"); cursor != 3 {
+		t.Errorf("Cursor() = %d, want 3", cursor)
+	}
+}
+
+func TestEventJournal_ResumesFromCursor(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	r := NewRegistry()
+	cb := r.GetOrCreate("payments", settings)
+	log := NewMemoryEventLog()
+	j := NewEventJournal(r, log, JournalSettings{})
+
+	tripBreaker(cb)
+	cb.Reset()
+	log.Commit("audit", 1)
+
+	seen := make(chan StateEvent, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- j.Subscribe(ctx, "audit", func(ctx context.Context, e StateEvent) error {
+			seen <- e
+			return nil
+		})
+	}()
+
+	if e := <-seen; e.Seq != 2 || e.To != gobreaker.StateClosed {
+		t.Errorf("first event after restart = %+v, want seq 2", e)
+	}
+	j.Close()
+	if err := <-done; !errors.Is(err, ErrJournalClosed) {
+		t.Errorf("Subscribe() after Close error = %v, want %v", err, ErrJournalClosed)
+	}
+}
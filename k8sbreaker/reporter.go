@@ -0,0 +1,150 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package k8sbreaker 将熔断器状态变更作为 Kubernetes Event 记录在所在 Pod 上，
+// 使 kubectl describe pod 能在其他 Pod 事件旁看到依赖故障的开始与恢复
+package k8sbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sony/gobreaker"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+)
+
+// Event 原因（Reason）
+const (
+	// ReasonOpened 熔断器打开，事件类型为 Warning
+	ReasonOpened = "CircuitOpened"
+	// ReasonHalfOpen 熔断器进入半开，仅在 WithHalfOpen 时记录
+	ReasonHalfOpen = "CircuitHalfOpen"
+	// ReasonClosed 熔断器恢复关闭
+	ReasonClosed = "CircuitClosed"
+)
+
+// Component 事件来源组件名
+const Component = "circuitbreaker"
+
+// serviceAccountNamespace Pod 内服务账号所在命名空间文件
+const serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Reporter 将熔断器状态变更记录为 object（通常是所在 Pod）上的 Event
+type Reporter struct {
+	recorder    record.EventRecorder
+	object      runtime.Object
+	halfOpen    bool
+	broadcaster record.EventBroadcaster
+}
+
+// Option Reporter 配置项
+type Option func(*Reporter)
+
+// WithHalfOpen 同时记录进入半开状态的事件，默认只记录打开与恢复关闭
+func WithHalfOpen() Option {
+	return func(r *Reporter) { r.halfOpen = true }
+}
+
+// New 使用已有的 EventRecorder 创建 Reporter，事件记录在 object 上
+func New(recorder record.EventRecorder, object runtime.Object, opts ...Option) *Reporter {
+	r := &Reporter{recorder: recorder, object: object}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// NewForPod 获取 namespace/name 指定的 Pod，并创建将事件写入 API Server 的 Reporter；
+// 服务账号需有 pods 的 get 与 events 的 create/patch 权限，使用完毕后调用 Close
+func NewForPod(ctx context.Context, client kubernetes.Interface, namespace, name string, opts ...Option) (*Reporter, error) {
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("k8sbreaker: get pod %s/%s: %w", namespace, name, err)
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events(namespace)})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: Component, Host: pod.Spec.NodeName})
+
+	r := New(recorder, pod, opts...)
+	r.broadcaster = broadcaster
+	return r, nil
+}
+
+// NewInCluster 使用 Pod 内的服务账号凭据创建 Reporter：Pod 名称取自 POD_NAME 环境变量（未设置时为主机名），
+// 命名空间取自 POD_NAMESPACE 环境变量（未设置时为服务账号所在命名空间），
+// 两个环境变量可通过 Downward API 注入
+func NewInCluster(ctx context.Context, opts ...Option) (*Reporter, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("k8sbreaker: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("k8sbreaker: %w", err)
+	}
+
+	name := os.Getenv("POD_NAME")
+	if name == "" {
+		if name, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("k8sbreaker: %w", err)
+		}
+	}
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		ns, err := os.ReadFile(serviceAccountNamespace)
+		if err != nil {
+			return nil, errors.New("k8sbreaker: pod namespace unknown, set POD_NAMESPACE")
+		}
+		namespace = strings.TrimSpace(string(ns))
+	}
+	return NewForPod(ctx, client, namespace, name, opts...)
+}
+
+// OnStateChange 记录状态变更事件，可直接用作 Settings.OnStateChange 或 Registry.Subscribe 的参数；
+// 事件由 EventRecorder 异步发送，不阻塞熔断器
+func (r *Reporter) OnStateChange(name string, from, to gobreaker.State) {
+	switch to {
+	case gobreaker.StateOpen:
+		r.recorder.Eventf(r.object, corev1.EventTypeWarning, ReasonOpened,
+			"Circuit breaker %q opened (was %s): dependency is failing, calls are rejected", name, from)
+	case gobreaker.StateHalfOpen:
+		if r.halfOpen {
+			r.recorder.Eventf(r.object, corev1.EventTypeNormal, ReasonHalfOpen,
+				"Circuit breaker %q is half-open: probing dependency", name)
+		}
+	case gobreaker.StateClosed:
+		r.recorder.Eventf(r.object, corev1.EventTypeNormal, ReasonClosed,
+			"Circuit breaker %q closed (was %s): dependency recovered", name, from)
+	}
+}
+
+// Close 停止由 NewForPod 创建的事件广播，已排队的事件可能被丢弃；使用外部 EventRecorder 时无操作
+func (r *Reporter) Close() {
+	if r.broadcaster != nil {
+		r.broadcaster.Shutdown()
+	}
+}
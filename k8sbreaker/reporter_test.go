@@ -0,0 +1,89 @@
+// Copyright 2025 zampo.
+
+package k8sbreaker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestReporter_OnStateChange(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := New(recorder, &corev1.Pod{})
+
+	r.OnStateChange("payments", gobreaker.StateClosed, gobreaker.StateOpen)
+	r.OnStateChange("payments", gobreaker.StateOpen, gobreaker.StateHalfOpen)
+	r.OnStateChange("payments", gobreaker.StateHalfOpen, gobreaker.StateClosed)
+	close(recorder.Events)
+
+	var got []string
+	for e := range recorder.Events {
+		got = append(got, e)
+	}
+	if len(got) != 2 {
+		t.Fatalf("events = %q, want open and close only", got)
+	}
+	if !strings.HasPrefix(got[0], "Warning CircuitOpened") || !strings.Contains(got[0], `"payments"`) {
+		t.Errorf("event = %q, want Warning CircuitOpened for payments", got[0])
+	}
+	if !strings.HasPrefix(got[1], "Normal CircuitClosed") {
+		t.Errorf("event = %q, want Normal CircuitClosed", got[1])
+	}
+}
+
+func TestReporter_WithHalfOpen(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := New(recorder, &corev1.Pod{}, WithHalfOpen())
+
+	r.OnStateChange("payments", gobreaker.StateOpen, gobreaker.StateHalfOpen)
+	if e := <-recorder.Events; !strings.HasPrefix(e, "Normal CircuitHalfOpen") {
+		t.Errorf("event = %q, want Normal CircuitHalfOpen", e)
+	}
+}
+
+func TestNewForPod_PostsEventOnPod(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "prod", UID: "uid-1"}}
+	client := fake.NewClientset(pod)
+	r, err := NewForPod(context.Background(), client, "prod", "api-0")
+	if err != nil {
+		t.Fatalf("NewForPod() error = %v", err)
+	}
+	defer r.Close()
+
+	r2 := circuitbreaker.NewRegistry()
+	defer r2.Subscribe(r.OnStateChange)()
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	cb := r2.GetOrCreate("payments", settings)
+	cb.Execute(func() (interface{}, error) { return nil, context.DeadlineExceeded })
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		events, _ := client.CoreV1().Events("prod").List(context.Background(), metav1.ListOptions{})
+		if len(events.Items) > 0 {
+			e := events.Items[0]
+			if e.InvolvedObject.Kind != "Pod" || e.InvolvedObject.UID != "uid-1" || e.Reason != ReasonOpened {
+				t.Errorf("event = %+v, want CircuitOpened on pod api-0", e)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("no event recorded on pod")
+}
+
+func TestNewForPod_MissingPod(t *testing.T) {
+	if _, err := NewForPod(context.Background(), fake.NewClientset(), "prod", "api-0"); err == nil {
+		t.Error("NewForPod() error = nil, want not found")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// MaxLeaseTTL 强制打开租约单次的最长有效期，需要更久时由持有者续约
+const MaxLeaseTTL = time.Hour
+
+var (
+	// ErrForcedOpen 熔断器被人工强制打开，errors.Is 同样匹配 gobreaker.ErrOpenState
+	ErrForcedOpen = fmt.Errorf("circuitbreaker: forced open: %w", gobreaker.ErrOpenState)
+	// ErrLeaseHeld 强制打开租约由其他持有者持有
+	ErrLeaseHeld = errors.New("circuitbreaker: force-open lease held by another owner")
+	// ErrInvalidLease 租约持有者为空或有效期不在 (0, MaxLeaseTTL] 内
+	ErrInvalidLease = errors.New("circuitbreaker: invalid force-open lease")
+)
+
+// Lease 强制打开租约：到期未续约即自动失效，避免遗忘的人工干预长期阻断流量
+type Lease struct {
+	// Owner 持有者标识，如操作人或工单号
+	Owner string
+	// Acquired 首次获取时间，续约不改变
+	Acquired time.Time
+	// Expires 到期时间
+	Expires time.Time
+}
+
+// active 判断租约在 now 时是否有效
+func (l *Lease) active(now time.Time) bool {
+	return l != nil && now.Before(l.Expires)
+}
+
+// ForceOpen 以 owner 身份获取或续约强制打开租约，ttl 内请求以 ReasonForced 拒绝
+// 租约由其他持有者持有且未到期时返回 ErrLeaseHeld；同一持有者重复调用即为续约
+func (cb *CircuitBreaker) ForceOpen(owner string, ttl time.Duration) (Lease, error) {
+	if owner == "" || ttl <= 0 || ttl > MaxLeaseTTL {
+		return Lease{}, fmt.Errorf("%w: owner %q, ttl %v", ErrInvalidLease, owner, ttl)
+	}
+
+	for {
+		now := cb.now()
+		cur := cb.lease.Load()
+		next := &Lease{Owner: owner, Acquired: now, Expires: now.Add(ttl)}
+		if cur.active(now) {
+			if cur.Owner != owner {
+				return *cur, ErrLeaseHeld
+			}
+			next.Acquired = cur.Acquired
+		}
+		if cb.lease.CompareAndSwap(cur, next) {
+			return *next, nil
+		}
+	}
+}
+
+// ReleaseLease 由持有者提前释放租约；租约已失效时直接返回，由其他持有者持有时返回 ErrLeaseHeld
+func (cb *CircuitBreaker) ReleaseLease(owner string) error {
+	for {
+		cur := cb.lease.Load()
+		if !cur.active(cb.now()) {
+			return nil
+		}
+		if cur.Owner != owner {
+			return ErrLeaseHeld
+		}
+		if cb.lease.CompareAndSwap(cur, nil) {
+			return nil
+		}
+	}
+}
+
+// Lease 返回当前有效的强制打开租约
+func (cb *CircuitBreaker) Lease() (Lease, bool) {
+	if l := cb.lease.Load(); l.active(cb.now()) {
+		return *l, true
+	}
+	return Lease{}, false
+}
+
+// activeLease 返回当前有效租约的副本，无有效租约时返回 nil
+func (cb *CircuitBreaker) activeLease() *Lease {
+	if l, ok := cb.Lease(); ok {
+		return &l
+	}
+	return nil
+}
+
+// leaseUntil 返回有效租约的到期时间，无有效租约时返回零值
+func (cb *CircuitBreaker) leaseUntil() time.Time {
+	if l := cb.lease.Load(); l.active(cb.now()) {
+		return l.Expires
+	}
+	return time.Time{}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestForceOpen_Lease(t *testing.T) {
+	cb := NewCircuitBreaker("svc", DefaultSettings())
+
+	lease, err := cb.ForceOpen("alice", time.Minute)
+	if err != nil || lease.Owner != "alice" {
+		t.Fatalf("ForceOpen() = %+v, %v", lease, err)
+	}
+	_, err = cb.Execute(func() (interface{}, error) { return nil, nil })
+	if !errors.Is(err, ErrForcedOpen) || !errors.Is(err, gobreaker.ErrOpenState) || ReasonOf(err) != ReasonForced {
+		t.Fatalf("Execute() error = %v, want forced open", err)
+	}
+	if after, ok := RetryAfter(err); !ok || after <= 50*time.Second || after > time.Minute {
+		t.Errorf("RetryAfter() = %v, %v, want about %v", after, ok, time.Minute)
+	}
+	if got := cb.Stats().Lease; got == nil || got.Owner != "alice" {
+		t.Errorf("Stats().Lease = %+v, want alice", got)
+	}
+
+	held, err := cb.ForceOpen("bob", time.Minute)
+	if !errors.Is(err, ErrLeaseHeld) || held.Owner != "alice" {
+		t.Errorf("ForceOpen(bob) = %+v, %v, want held by alice", held, err)
+	}
+	if err := cb.ReleaseLease("bob"); !errors.Is(err, ErrLeaseHeld) {
+		t.Errorf("ReleaseLease(bob) error = %v, want %v", err, ErrLeaseHeld)
+	}
+
+	renewed, err := cb.ForceOpen("alice", 2*time.Minute)
+	if err != nil || !renewed.Acquired.Equal(lease.Acquired) || !renewed.Expires.After(lease.Expires) {
+		t.Errorf("renew = %+v, %v, want same Acquired and later Expires than %+v", renewed, err, lease)
+	}
+
+	if err := cb.ReleaseLease("alice"); err != nil {
+		t.Fatalf("ReleaseLease(alice) error = %v", err)
+	}
+	if _, ok := cb.Lease(); ok {
+		t.Error("Lease() after release should be empty")
+	}
+	if _, err := cb.Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("Execute() after release error = %v", err)
+	}
+}
+
+func TestForceOpen_Expiry(t *testing.T) {
+	cb := NewCircuitBreaker("svc", DefaultSettings())
+	if _, err := cb.ForceOpen("alice", 20*time.Millisecond); err != nil {
+		t.Fatalf("ForceOpen() error = %v", err)
+	}
+	if _, err := cb.Allow(); err == nil {
+		t.Fatal("Allow() during lease should reject")
+	}
+	waitFor(t, func() bool {
+		_, ok := cb.Lease()
+		return !ok
+	})
+	if _, err := cb.ForceOpen("bob", time.Minute); err != nil {
+		t.Errorf("ForceOpen(bob) after expiry error = %v", err)
+	}
+}
+
+func TestForceOpen_ResetAndInvalid(t *testing.T) {
+	cb := NewCircuitBreaker("svc", DefaultSettings())
+	if _, err := cb.ForceOpen("alice", time.Minute); err != nil {
+		t.Fatalf("ForceOpen() error = %v", err)
+	}
+	cb.Reset()
+	if _, ok := cb.Lease(); ok {
+		t.Error("Reset() should clear the lease")
+	}
+
+	for _, tt := range []struct {
+		owner string
+		ttl   time.Duration
+	}{
+		{"", time.Minute},
+		{"alice", 0},
+		{"alice", MaxLeaseTTL + time.Second},
+	} {
+		if _, err := cb.ForceOpen(tt.owner, tt.ttl); !errors.Is(err, ErrInvalidLease) {
+			t.Errorf("ForceOpen(%q, %v) error = %v, want %v", tt.owner, tt.ttl, err, ErrInvalidLease)
+		}
+	}
+}
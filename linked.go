@@ -0,0 +1,153 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// LinkedGroupSettings 联动熔断组配置
+type LinkedGroupSettings struct {
+	// Threshold 组内自行打开的熔断器达到该数量时联动打开其余成员，默认过半
+	Threshold int
+	// Hold 联动打开的最长保持时间，默认 60 秒；组内打开数回落到阈值以下时提前解除
+	Hold time.Duration
+	// OnTrip 联动打开时回调，参数为被联动打开的成员名称
+	// 在成员的状态变更回调中同步调用，不可阻塞，也不可调用组内熔断器的方法
+	OnTrip func(linked []string)
+}
+
+// linkedMember 组成员及其联动状态
+type linkedMember struct {
+	cb *CircuitBreaker
+	// open 底层熔断器是否自行打开（联动保持不计入）
+	open atomic.Bool
+	// until 由组设置的 OpenFor 截止时间（UnixNano），0 表示未联动
+	until atomic.Int64
+}
+
+// LinkedGroup 联动熔断组：同一依赖集群的多个端点中有 Threshold 个打开时，
+// 认为集群整体不可用，其余成员通过 OpenFor 一并打开，不必各自等到失败次数达标
+// 联动打开的成员不计入打开数，避免组内相互维持；自行打开的成员进入半开后联动随即解除，
+// 由恢复中的成员探测集群是否恢复
+type LinkedGroup struct {
+	settings LinkedGroupSettings
+	members  []*linkedMember
+
+	mu      sync.Mutex
+	tripped bool
+	cancels []func()
+}
+
+// NewLinkedGroup 创建联动熔断组并订阅成员状态变更，不再使用时调用 Close
+func NewLinkedGroup(settings LinkedGroupSettings, members ...*CircuitBreaker) *LinkedGroup {
+	if settings.Threshold <= 0 {
+		settings.Threshold = len(members)/2 + 1
+	}
+	if settings.Hold <= 0 {
+		settings.Hold = defaultOpenTimeout
+	}
+
+	g := &LinkedGroup{settings: settings}
+	for _, cb := range members {
+		m := &linkedMember{cb: cb}
+		m.open.Store(cb.State() == gobreaker.StateOpen && !cb.heldOpen())
+		g.members = append(g.members, m)
+	}
+	for _, m := range g.members {
+		m := m
+		g.cancels = append(g.cancels, m.cb.addListener(func(_ string, _, to gobreaker.State) {
+			m.open.Store(to == gobreaker.StateOpen)
+			g.evaluate()
+		}))
+	}
+	g.evaluate()
+	return g
+}
+
+// Tripped 返回组是否处于联动打开状态
+func (g *LinkedGroup) Tripped() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.tripped
+}
+
+// Close 取消订阅并解除联动打开
+func (g *LinkedGroup) Close() {
+	g.mu.Lock()
+	cancels := g.cancels
+	g.cancels = nil
+	g.mu.Unlock()
+
+	// 回调持有监听器读锁时会等待 g.mu，取消订阅需在 g.mu 之外进行
+	for _, cancel := range cancels {
+		cancel()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.release()
+}
+
+// evaluate 按当前打开数联动打开或解除，可能在成员的 gobreaker 锁内调用，只操作原子变量
+func (g *LinkedGroup) evaluate() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	open := 0
+	for _, m := range g.members {
+		if m.open.Load() {
+			open++
+		}
+	}
+	if open < g.settings.Threshold {
+		g.release()
+		return
+	}
+
+	until := time.Now().Add(g.settings.Hold).UnixNano()
+	var linked []string
+	for _, m := range g.members {
+		if m.open.Load() {
+			continue
+		}
+		if m.cb.holdUntil(until) {
+			m.until.Store(until)
+		}
+		linked = append(linked, m.cb.name)
+	}
+	if !g.tripped {
+		g.tripped = true
+		if g.settings.OnTrip != nil && len(linked) > 0 {
+			g.settings.OnTrip(linked)
+		}
+	}
+}
+
+// release 解除联动打开；保持期已被其他来源（如 Retry-After）延长的成员不受影响
+func (g *LinkedGroup) release() {
+	g.tripped = false
+	for _, m := range g.members {
+		if until := m.until.Swap(0); until != 0 {
+			m.cb.openUntil.CompareAndSwap(until, 0)
+		}
+	}
+}
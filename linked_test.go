@@ -0,0 +1,84 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func newLinkedMembers(names ...string) []*CircuitBreaker {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	settings.Timeout = 50 * time.Millisecond
+	members := make([]*CircuitBreaker, len(names))
+	for i, name := range names {
+		members[i] = NewCircuitBreaker(name, settings)
+	}
+	return members
+}
+
+func tripBreaker(cb *CircuitBreaker) {
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("down") })
+}
+
+func TestLinkedGroup(t *testing.T) {
+	members := newLinkedMembers("a", "b", "c", "d")
+	a, b, c, d := members[0], members[1], members[2], members[3]
+
+	var linked []string
+	g := NewLinkedGroup(LinkedGroupSettings{Threshold: 2, Hold: time.Minute, OnTrip: func(names []string) {
+		linked = names
+	}}, members...)
+	defer g.Close()
+
+	tripBreaker(a)
+	if g.Tripped() || c.State() != gobreaker.StateClosed {
+		t.Fatal("group tripped below threshold")
+	}
+
+	tripBreaker(b)
+	if !g.Tripped() {
+		t.Fatal("Tripped() = false after threshold reached")
+	}
+	if c.State() != gobreaker.StateOpen || d.State() != gobreaker.StateOpen {
+		t.Errorf("linked members c=%v d=%v, want open", c.State(), d.State())
+	}
+	if !reflect.DeepEqual(linked, []string{"c", "d"}) {
+		t.Errorf("OnTrip linked = %v, want [c d]", linked)
+	}
+	if _, err := c.Execute(func() (interface{}, error) { return nil, nil }); ReasonOf(err) != ReasonOpen {
+		t.Errorf("linked member Execute() reason = %v, want %v", ReasonOf(err), ReasonOpen)
+	}
+
+	// 自行打开的成员进入半开后解除联动，其余成员恢复放行
+	waitFor(t, func() bool { return a.State() == gobreaker.StateHalfOpen })
+	if g.Tripped() || c.State() != gobreaker.StateClosed || d.State() != gobreaker.StateClosed {
+		t.Errorf("after recovery: Tripped=%v c=%v d=%v, want released", g.Tripped(), c.State(), d.State())
+	}
+}
+
+func TestLinkedGroup_CloseKeepsExternalHolds(t *testing.T) {
+	members := newLinkedMembers("a", "b", "c")
+	g := NewLinkedGroup(LinkedGroupSettings{Hold: time.Minute}, members...)
+
+	tripBreaker(members[0])
+	tripBreaker(members[1])
+	if members[2].State() != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want linked open with default majority threshold", members[2].State())
+	}
+
+	// 其他来源延长的保持期不被解除
+	members[2].OpenFor(time.Hour)
+	g.Close()
+	if members[2].OpenUntil().IsZero() {
+		t.Error("Close() released a hold extended by OpenFor")
+	}
+	if g.Tripped() {
+		t.Error("Tripped() = true after Close")
+	}
+}
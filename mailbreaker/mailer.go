@@ -0,0 +1,134 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package mailbreaker 提供带熔断保护的邮件发送，中继不可用时暂存邮件而不是持续重试
+package mailbreaker
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"sync"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// ErrQueueFull 熔断打开期间暂存队列已满
+var ErrQueueFull = errors.New("mailbreaker: queue full")
+
+// IsFailure 默认失败判定：SMTP 5xx 永久错误（如收件人不存在）与中继健康无关，不计为失败
+func IsFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return false
+	}
+	return true
+}
+
+// Mailer 带熔断保护的邮件发送器
+type Mailer struct {
+	sender   Sender
+	cb       *circuitbreaker.CircuitBreaker
+	maxQueue int
+
+	mu    sync.Mutex
+	queue []Message
+	// flushing 正在由 Flush 发送的邮件数，计入队列容量
+	flushing int
+}
+
+// NewMailer 创建发送器，maxQueue 为熔断打开期间最多暂存的邮件数，0 表示不暂存
+func NewMailer(sender Sender, cb *circuitbreaker.CircuitBreaker, maxQueue int) *Mailer {
+	return &Mailer{sender: sender, cb: cb, maxQueue: maxQueue}
+}
+
+// Send 发送邮件，熔断打开时暂存并返回 queued=true；队列已满时返回 ErrQueueFull
+func (m *Mailer) Send(ctx context.Context, msg Message) (queued bool, err error) {
+	done, err := m.cb.AllowContext(ctx)
+	if err != nil {
+		if !errors.Is(err, gobreaker.ErrOpenState) && !errors.Is(err, gobreaker.ErrTooManyRequests) {
+			return false, err
+		}
+		return true, m.enqueue(msg)
+	}
+
+	err = m.sender.Send(ctx, msg)
+	if IsFailure(err) {
+		done(err)
+	} else {
+		done(nil)
+	}
+	return false, err
+}
+
+func (m *Mailer) enqueue(msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.queue)+m.flushing >= m.maxQueue {
+		return ErrQueueFull
+	}
+	m.queue = append(m.queue, msg)
+	return nil
+}
+
+// Flush 发送暂存的邮件，熔断器再次拒绝或发送失败时停止，返回成功发送数
+// 应由调用方定时调用；永久错误的邮件会被丢弃并通过错误返回。
+// 发送前在锁内取走整个队列，并发调用的 Flush 与 Send 不会重复发送或丢失邮件，未发送的邮件放回队首
+func (m *Mailer) Flush(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	pending := m.queue
+	m.queue = nil
+	m.flushing += len(pending)
+	m.mu.Unlock()
+
+	next := 0
+	defer func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.flushing -= len(pending)
+		m.queue = append(pending[next:len(pending):len(pending)], m.queue...)
+	}()
+
+	for next < len(pending) {
+		done, err := m.cb.AllowContext(ctx)
+		if err != nil {
+			return next, err
+		}
+		err = m.sender.Send(ctx, pending[next])
+		if IsFailure(err) {
+			done(err)
+			return next, err
+		}
+		done(nil)
+		next++
+		if err != nil {
+			return next - 1, err
+		}
+	}
+	return next, nil
+}
+
+// Pending 返回暂存的邮件数
+func (m *Mailer) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.queue) + m.flushing
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 zampo.
+
+package mailbreaker
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestMailer_QueuesWhileOpen(t *testing.T) {
+	relayDown := true
+	sent := 0
+	sender := SenderFunc(func(ctx context.Context, msg Message) error {
+		if relayDown {
+			return errors.New("connection refused")
+		}
+		sent++
+		return nil
+	})
+
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	cb := circuitbreaker.NewCircuitBreaker("smtp", settings)
+	m := NewMailer(sender, cb, 1)
+
+	if _, err := m.Send(context.Background(), Message{To: []string{"a@example.com"}}); err == nil {
+		t.Fatal("Send() should fail while the relay is down")
+	}
+	queued, err := m.Send(context.Background(), Message{To: []string{"b@example.com"}})
+	if !queued || err != nil {
+		t.Fatalf("Send() = %v, %v, want true, nil", queued, err)
+	}
+	if _, err := m.Send(context.Background(), Message{}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Send() error = %v, want %v", err, ErrQueueFull)
+	}
+
+	// 中继恢复、熔断器重建后清空暂存队列
+	relayDown = false
+	cb.UpdateSettings(circuitbreaker.DefaultSettings())
+	n, err := m.Flush(context.Background())
+	if n != 1 || err != nil {
+		t.Errorf("Flush() = %v, %v, want 1, nil", n, err)
+	}
+	if m.Pending() != 0 || sent != 1 {
+		t.Errorf("Pending() = %v, sent = %v, want 0, 1", m.Pending(), sent)
+	}
+}
+
+func TestMailer_ConcurrentFlushSendsOnce(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		sent    []string
+		relayUp bool
+	)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	sender := SenderFunc(func(ctx context.Context, msg Message) error {
+		mu.Lock()
+		up := relayUp
+		mu.Unlock()
+		if !up {
+			return errors.New("connection refused")
+		}
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, msg.To[0])
+		return nil
+	})
+
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	cb := circuitbreaker.NewCircuitBreaker("smtp", settings)
+	m := NewMailer(sender, cb, 3)
+	m.Send(context.Background(), Message{To: []string{"trip@example.com"}})
+	for _, to := range []string{"a", "b", "c"} {
+		if queued, err := m.Send(context.Background(), Message{To: []string{to}}); !queued || err != nil {
+			t.Fatalf("Send(%s) = %v, %v, want true, nil", to, queued, err)
+		}
+	}
+
+	mu.Lock()
+	relayUp = true
+	mu.Unlock()
+	cb.UpdateSettings(circuitbreaker.DefaultSettings())
+
+	flushed := make(chan int)
+	go func() {
+		n, _ := m.Flush(context.Background())
+		flushed <- n
+	}()
+	<-started
+	// 队列已被第一个 Flush 取走，第二个 Flush 无事可做，取走的邮件仍计入 Pending
+	if n, err := m.Flush(context.Background()); n != 0 || err != nil {
+		t.Errorf("concurrent Flush() = %v, %v, want 0, nil", n, err)
+	}
+	if got := m.Pending(); got != 3 {
+		t.Errorf("Pending() during Flush = %d, want 3", got)
+	}
+	close(release)
+
+	if n := <-flushed; n != 3 {
+		t.Errorf("Flush() = %d, want 3", n)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"a", "b", "c"}; !slices.Equal(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&textproto.Error{Code: 550, Msg: "mailbox unavailable"}, false},
+		{&textproto.Error{Code: 421, Msg: "service not available"}, true},
+		{errors.New("connection reset"), true},
+	}
+
+	for _, tt := range tests {
+		if got := IsFailure(tt.err); got != tt.want {
+			t.Errorf("IsFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
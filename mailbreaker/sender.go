@@ -0,0 +1,116 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package mailbreaker
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+)
+
+// Message 待发送的邮件
+type Message struct {
+	From string
+	To   []string
+	// Data 完整的 RFC 5322 邮件内容（含头部）
+	Data []byte
+}
+
+// Sender 邮件发送接口，SMTP 中继与 API 类邮件服务商均可实现
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SenderFunc 函数形式的 Sender，便于接入 API 类邮件服务商
+type SenderFunc func(ctx context.Context, msg Message) error
+
+// Send 实现 Sender
+func (f SenderFunc) Send(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// SMTPSender 基于 net/smtp 的发送器，支持 ctx 取消与超时
+type SMTPSender struct {
+	// Addr 中继地址，如 "smtp.example.com:587"
+	Addr string
+	// Auth 认证方式，为空时不认证
+	Auth smtp.Auth
+	// TLSConfig STARTTLS 配置，为空时使用中继主机名作为 ServerName
+	TLSConfig *tls.Config
+}
+
+// Send 实现 Sender
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		config := s.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+		if err := c.StartTLS(config); err != nil {
+			return err
+		}
+	}
+	if s.Auth != nil {
+		if err := c.Auth(s.Auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(msg.From); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg.Data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
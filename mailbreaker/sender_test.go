@@ -0,0 +1,82 @@
+// Copyright 2025 zampo.
+
+package mailbreaker
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP 极简 SMTP 服务端，记录收到的 DATA 内容
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	data := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ready")
+		var body strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					data <- body.String()
+					reply("250 ok")
+					continue
+				}
+				body.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), data
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	addr, data := fakeSMTP(t)
+	s := &SMTPSender{Addr: addr}
+
+	err := s.Send(context.Background(), Message{
+		From: "noreply@example.com",
+		To:   []string{"user@example.com"},
+		Data: []byte("Subject: hi\r\n\r\nhello\r\n"),
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got := <-data; !strings.Contains(got, "hello") {
+		t.Errorf("DATA = %q, want it to contain hello", got)
+	}
+}
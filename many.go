@@ -0,0 +1,71 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotRegistered 注册表中不存在指定名称的熔断器
+var ErrNotRegistered = errors.New("circuitbreaker: breaker not registered")
+
+// Result ExecuteMany 中单个依赖的执行结果
+type Result struct {
+	// Value 调用返回值
+	Value interface{}
+	// Err 调用错误、拒绝错误（*RejectionError）或 ErrNotRegistered
+	Err error
+	// Skipped 熔断器拒绝了调用，fn 未执行
+	Skipped bool
+	// Reason 拒绝原因，未跳过时为 ReasonNone
+	Reason Reason
+}
+
+// ExecuteMany 并发执行 scatter-gather 调用：calls 的键为熔断器名称，每个调用经注册表中
+// 同名熔断器的 ExecuteContext 执行，返回每个键的结果。熔断器打开的键被跳过（Skipped 为 true）
+// 而不执行，单个依赖失败不会取消其他调用；未注册的键返回 ErrNotRegistered
+func (r *Registry) ExecuteMany(ctx context.Context, calls map[string]func(ctx context.Context) (interface{}, error)) map[string]Result {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]Result, len(calls))
+	)
+	for name, fn := range calls {
+		cb, ok := r.Get(name)
+		if !ok {
+			mu.Lock()
+			results[name] = Result{Err: fmt.Errorf("%w: %q", ErrNotRegistered, name)}
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cb.ExecuteContext(ctx, fn)
+			res := Result{Value: value, Err: err, Reason: ReasonOf(err)}
+			res.Skipped = res.Reason != ReasonNone
+			mu.Lock()
+			results[name] = res
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRegistry_ExecuteMany(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	r := NewRegistry()
+	r.GetOrCreate("inventory", settings)
+	r.GetOrCreate("pricing", settings)
+	tripBreaker(r.GetOrCreate("reviews", settings))
+
+	errPricing := errors.New("pricing down")
+	var reviewsCalled atomic.Bool
+	results := r.ExecuteMany(context.Background(), map[string]func(ctx context.Context) (interface{}, error){
+		"inventory": func(ctx context.Context) (interface{}, error) { return 7, nil },
+		"pricing":   func(ctx context.Context) (interface{}, error) { return nil, errPricing },
+		"reviews": func(ctx context.Context) (interface{}, error) {
+			reviewsCalled.Store(true)
+			return nil, nil
+		},
+		"missing": func(ctx context.Context) (interface{}, error) { return nil, nil },
+	})
+
+	if len(results) != 4 {
+		t.Fatalf("results = %d, want 4", len(results))
+	}
+	if res := results["inventory"]; res.Value != 7 || res.Err != nil || res.Skipped {
+		t.Errorf("inventory = %+v, want 7", res)
+	}
+	if res := results["pricing"]; !errors.Is(res.Err, errPricing) || res.Skipped {
+		t.Errorf("pricing = %+v, want %v", res, errPricing)
+	}
+	if res := results["reviews"]; !res.Skipped || res.Reason != ReasonOpen || reviewsCalled.Load() {
+		t.Errorf("reviews = %+v, called = %v, want skipped without calling", res, reviewsCalled.Load())
+	}
+	if res := results["missing"]; !errors.Is(res.Err, ErrNotRegistered) || res.Skipped {
+		t.Errorf("missing = %+v, want %v", res, ErrNotRegistered)
+	}
+}
@@ -0,0 +1,110 @@
+//go:build !cbnotelemetry
+
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// 与 Envoy core.v3.HealthStatus 枚举名称保持一致，便于控制面直接映射
+const (
+	MeshHealthy   = "HEALTHY"
+	MeshDegraded  = "DEGRADED"
+	MeshUnhealthy = "UNHEALTHY"
+)
+
+// MeshEndpointStatus 单个依赖的网格健康状态
+type MeshEndpointStatus struct {
+	// Name 熔断器名称，通常为上游集群或 host:port
+	Name string `json:"name"`
+	// HealthStatus Envoy 风格的健康状态
+	HealthStatus string `json:"health_status"`
+	// State 熔断器原始状态
+	State string `json:"state"`
+	// Description 依赖说明
+	Description string `json:"description,omitempty"`
+	// RunbookURL 处置手册地址
+	RunbookURL string `json:"runbook_url,omitempty"`
+	// Tier 严重级别
+	Tier Tier `json:"tier"`
+}
+
+// MeshStatus 网格控制面消费的状态报告
+type MeshStatus struct {
+	GeneratedAt time.Time            `json:"generated_at"`
+	Endpoints   []MeshEndpointStatus `json:"endpoints"`
+}
+
+// MeshHealthStatus 将熔断器状态映射为 Envoy 健康状态
+// 半开映射为 DEGRADED：仍可接收少量流量，但网格应优先选择其他后端
+func MeshHealthStatus(state gobreaker.State) string {
+	switch state {
+	case gobreaker.StateClosed:
+		return MeshHealthy
+	case gobreaker.StateHalfOpen:
+		return MeshDegraded
+	default:
+		return MeshUnhealthy
+	}
+}
+
+// MeshStatus 生成注册表内所有熔断器的网格状态报告
+func (r *Registry) MeshStatus() MeshStatus {
+	names := r.Names()
+	status := MeshStatus{
+		GeneratedAt: time.Now(),
+		Endpoints:   make([]MeshEndpointStatus, 0, len(names)),
+	}
+
+	for _, name := range names {
+		cb, ok := r.Get(name)
+		if !ok {
+			continue
+		}
+		state, settings := cb.State(), cb.GetSettings()
+		status.Endpoints = append(status.Endpoints, MeshEndpointStatus{
+			Name:         name,
+			HealthStatus: MeshHealthStatus(state),
+			State:        state.String(),
+			Description:  settings.Description,
+			RunbookURL:   settings.RunbookURL,
+			Tier:         cb.Tier(),
+		})
+	}
+	return status
+}
+
+// MeshHandler 返回输出网格状态 JSON 的 HTTP 处理器，供控制面定期拉取
+func MeshHandler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(r.MeshStatus())
+	})
+}
@@ -0,0 +1,78 @@
+//go:build !cbnotelemetry
+
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestMeshHealthStatus(t *testing.T) {
+	tests := []struct {
+		state gobreaker.State
+		want  string
+	}{
+		{gobreaker.StateClosed, MeshHealthy},
+		{gobreaker.StateHalfOpen, MeshDegraded},
+		{gobreaker.StateOpen, MeshUnhealthy},
+	}
+
+	for _, tt := range tests {
+		if got := MeshHealthStatus(tt.state); got != tt.want {
+			t.Errorf("MeshHealthStatus(%v) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestMeshHandler(t *testing.T) {
+	r := NewRegistry()
+	r.GetOrCreate("inventory", DefaultSettings())
+	settings := DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	settings.RunbookURL = "https://runbooks.example.com/payments"
+	cb := r.GetOrCreate("payments", settings)
+	cb.Execute(func() (interface{}, error) {
+		return nil, errors.New("fail")
+	})
+
+	rec := httptest.NewRecorder()
+	MeshHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mesh", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %v, want %v", rec.Code, http.StatusOK)
+	}
+	var status MeshStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(status.Endpoints) != 2 {
+		t.Fatalf("len(Endpoints) = %v, want %v", len(status.Endpoints), 2)
+	}
+	if got := status.Endpoints[0]; got.Name != "inventory" || got.HealthStatus != MeshHealthy {
+		t.Errorf("Endpoints[0] = %+v, want inventory %v", got, MeshHealthy)
+	}
+	if got := status.Endpoints[1]; got.Name != "payments" || got.HealthStatus != MeshUnhealthy {
+		t.Errorf("Endpoints[1] = %+v, want payments %v", got, MeshUnhealthy)
+	}
+	if got := status.Endpoints[1].RunbookURL; got != settings.RunbookURL {
+		t.Errorf("RunbookURL = %v, want %v", got, settings.RunbookURL)
+	}
+}
+
+func TestMeshHandler_MethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	MeshHandler(NewRegistry()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mesh", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
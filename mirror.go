@@ -0,0 +1,157 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// MirrorSettings 流量镜像配置
+type MirrorSettings struct {
+	// SampleRate 熔断打开期间被镜像的请求比例（0~1）
+	SampleRate float64
+	// RecoverAfter 连续镜像成功达到该次数时提前关闭熔断器，0 表示仅统计不干预
+	RecoverAfter uint32
+	// MaxInFlight 同时进行的镜像请求上限，默认 1
+	MaxInFlight int32
+	// Timeout 单次镜像请求超时，默认 5 秒
+	Timeout time.Duration
+}
+
+// MirrorStats 镜像统计
+type MirrorStats struct {
+	Mirrored  uint64
+	Successes uint64
+	Failures  uint64
+}
+
+// Mirror 熔断打开期间将部分真实请求以 fire-and-forget 方式镜像到恢复中的依赖，
+// 镜像结果只用于判断依赖是否恢复，不影响返回给用户的响应（用户仍走降级路径）
+type Mirror struct {
+	breaker  *CircuitBreaker
+	settings MirrorSettings
+
+	inFlight    atomic.Int32
+	consecutive atomic.Uint32
+	mirrored    atomic.Uint64
+	successes   atomic.Uint64
+	failures    atomic.Uint64
+}
+
+// NewMirror 创建镜像器
+func NewMirror(cb *CircuitBreaker, settings MirrorSettings) *Mirror {
+	if settings.MaxInFlight <= 0 {
+		settings.MaxInFlight = 1
+	}
+	if settings.Timeout <= 0 {
+		settings.Timeout = 5 * time.Second
+	}
+	return &Mirror{breaker: cb, settings: settings}
+}
+
+// Try 在熔断器打开时按采样率异步执行 fn，返回是否发起了镜像
+// 通常在降级分支中调用，fn 应发送请求的副本；OpenFor 保持期内遵循下游背压，不镜像
+func (m *Mirror) Try(fn func(ctx context.Context) error) bool {
+	cb := m.breaker
+	if cb.heldOpen() || cb.State() != gobreaker.StateOpen {
+		return false
+	}
+	if rand.Float64() >= m.settings.SampleRate {
+		return false
+	}
+	if m.inFlight.Add(1) > m.settings.MaxInFlight {
+		m.inFlight.Add(-1)
+		return false
+	}
+
+	m.mirrored.Add(1)
+	go func() {
+		defer m.inFlight.Add(-1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), m.settings.Timeout)
+		defer cancel()
+		m.observe(cb.GetSettings().Classify(fn(ctx)))
+	}()
+	return true
+}
+
+// observe 记录一次镜像结果，连续成功达到阈值时关闭熔断器
+func (m *Mirror) observe(outcome Outcome) {
+	switch outcome {
+	case OutcomeSuccess:
+		m.successes.Add(1)
+		n := m.consecutive.Add(1)
+		if m.settings.RecoverAfter > 0 && n >= m.settings.RecoverAfter && m.breaker.reset(false) {
+			m.consecutive.Store(0)
+		}
+	case OutcomeFailure:
+		m.failures.Add(1)
+		m.consecutive.Store(0)
+	}
+}
+
+// Stats 返回镜像统计
+func (m *Mirror) Stats() MirrorStats {
+	return MirrorStats{
+		Mirrored:  m.mirrored.Load(),
+		Successes: m.successes.Load(),
+		Failures:  m.failures.Load(),
+	}
+}
+
+// reset 将熔断器重置为关闭并触发状态变更回调；force 为 false 时仅重置打开状态，
+// 未重置时返回 false
+func (cb *CircuitBreaker) reset(force bool) bool {
+	cb.mu.Lock()
+	from := cb.cb.State()
+	if cb.timeoutHeld() || cb.pendingHalfOpen.Load() {
+		// 设置 Clock 时底层熔断器已提前进入半开，对外仍处于打开状态
+		from = gobreaker.StateOpen
+	}
+	if !force && from != gobreaker.StateOpen {
+		cb.mu.Unlock()
+		return false
+	}
+	cb.cb = gobreaker.NewTwoStepCircuitBreaker(cb.buildSettings(cb.settings))
+	cb.clearTimeoutHold()
+	if w := cb.window.Load(); w != nil {
+		w.reset()
+	}
+	// 新建底层熔断器不经过 buildSettings 中的 OnStateChange，需在此完成恢复关闭时的清理
+	cb.spareProbes.clear()
+	if from == gobreaker.StateHalfOpen {
+		cb.probeSlots.releaseAll()
+	}
+	cb.errorSamples.clear()
+	cb.sealTrip()
+	onChange := cb.settings.stateChangeHook()
+	cb.mu.Unlock()
+
+	if from == gobreaker.StateClosed {
+		return true
+	}
+	if onChange != nil {
+		onChange(cb.name, from, gobreaker.StateClosed)
+	}
+	cb.notifyListeners(cb.name, from, gobreaker.StateClosed)
+	return true
+}
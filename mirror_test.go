@@ -0,0 +1,72 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestMirror_RecoversAfterSuccesses(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Timeout = time.Hour
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	var transitions []gobreaker.State
+	var mu sync.Mutex
+	settings.OnStateChange = func(name string, from, to gobreaker.State) {
+		mu.Lock()
+		transitions = append(transitions, to)
+		mu.Unlock()
+	}
+	cb := NewCircuitBreaker("test", settings)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+
+	m := NewMirror(cb, MirrorSettings{SampleRate: 1, RecoverAfter: 2})
+	for i := 0; i < 2; i++ {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		if !m.Try(func(ctx context.Context) error {
+			defer wg.Done()
+			return nil
+		}) {
+			t.Fatalf("Try() #%d = false, want true", i)
+		}
+		wg.Wait()
+		for m.inFlight.Load() != 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(transitions) != 2 || transitions[1] != gobreaker.StateClosed {
+		t.Errorf("transitions = %v, want [open closed]", transitions)
+	}
+	if got := m.Stats(); got.Mirrored != 2 || got.Successes != 2 {
+		t.Errorf("Stats() = %+v, want 2 mirrored, 2 successes", got)
+	}
+}
+
+func TestMirror_SkipsWhenClosedOrHeld(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+	m := NewMirror(cb, MirrorSettings{SampleRate: 1})
+	fn := func(ctx context.Context) error { return nil }
+
+	if m.Try(fn) {
+		t.Error("Try() = true while closed, want false")
+	}
+	cb.OpenFor(time.Minute)
+	if m.Try(fn) {
+		t.Error("Try() = true during OpenFor, want false")
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package mqttbreaker 提供按 broker 熔断的 MQTT 发布/订阅包装
+//
+// 通过 Client 接口适配 MQTT 客户端库，以 eclipse/paho.mqtt.golang 为例：
+//
+//	type pahoClient struct{ c mqtt.Client }
+//
+//	func (p pahoClient) Publish(ctx context.Context, m mqttbreaker.Message) error {
+//		t := p.c.Publish(m.Topic, m.QoS, m.Retained, m.Payload)
+//		select {
+//		case <-t.Done():
+//			return t.Error()
+//		case <-ctx.Done():
+//			return ctx.Err()
+//		}
+//	}
+package mqttbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// ErrQueueFull 熔断打开期间暂存队列已满
+var ErrQueueFull = errors.New("mqttbreaker: queue full")
+
+// Message MQTT 消息
+type Message struct {
+	Topic    string
+	QoS      byte
+	Retained bool
+	Payload  []byte
+}
+
+// Client MQTT 客户端适配接口，方法应在 broker 确认（或 ctx 结束）后返回
+type Client interface {
+	Publish(ctx context.Context, msg Message) error
+	Subscribe(ctx context.Context, topic string, qos byte, handler func(Message)) error
+}
+
+// OpenPolicy 熔断打开期间发布消息的处理方式
+type OpenPolicy int
+
+const (
+	// Drop 直接丢弃并返回熔断拒绝错误，适用于行情等时效性数据
+	Drop OpenPolicy = iota
+	// Queue 暂存到队列，由 Flush 在恢复后补发
+	Queue
+)
+
+// Option 配置项
+type Option func(*Broker)
+
+// WithRegistry 设置熔断器注册表，便于与健康检查、管理接口共享
+func WithRegistry(r *circuitbreaker.Registry) Option {
+	return func(b *Broker) { b.registry = r }
+}
+
+// WithSettings 设置熔断器配置
+func WithSettings(settings circuitbreaker.Settings) Option {
+	return func(b *Broker) { b.settings = settings }
+}
+
+// WithQueue 熔断打开期间暂存消息，最多 max 条
+func WithQueue(max int) Option {
+	return func(b *Broker) {
+		b.policy = Queue
+		b.maxQueue = max
+	}
+}
+
+// Broker 单个 MQTT broker 的熔断包装，注册表中命名为 "mqtt:<名称>"
+type Broker struct {
+	name     string
+	client   Client
+	registry *circuitbreaker.Registry
+	settings circuitbreaker.Settings
+	policy   OpenPolicy
+	maxQueue int
+
+	cb *circuitbreaker.CircuitBreaker
+
+	mu      sync.Mutex
+	queue   []Message
+	dropped atomic.Uint64
+}
+
+// NewBroker 创建 broker 包装，默认 Drop 策略
+func NewBroker(name string, client Client, opts ...Option) *Broker {
+	b := &Broker{
+		name:     name,
+		client:   client,
+		registry: circuitbreaker.NewRegistry(),
+		settings: circuitbreaker.DefaultSettings(),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.cb = b.registry.GetOrCreate("mqtt:"+name, b.settings)
+	return b
+}
+
+// Breaker 返回该 broker 的熔断器
+func (b *Broker) Breaker() *circuitbreaker.CircuitBreaker {
+	return b.cb
+}
+
+// Publish 发布消息；熔断打开时按策略丢弃（返回拒绝错误）或暂存（返回 queued=true），
+// 暂存队列已满时返回 ErrQueueFull
+func (b *Broker) Publish(ctx context.Context, msg Message) (queued bool, err error) {
+	done, err := b.cb.AllowContext(ctx)
+	if err != nil {
+		if b.policy == Queue && rejected(err) {
+			return true, b.enqueue(msg)
+		}
+		b.dropped.Add(1)
+		return false, err
+	}
+
+	err = b.client.Publish(ctx, msg)
+	done(err)
+	return false, err
+}
+
+// Subscribe 订阅主题，熔断打开时直接返回拒绝错误
+func (b *Broker) Subscribe(ctx context.Context, topic string, qos byte, handler func(Message)) error {
+	done, err := b.cb.AllowContext(ctx)
+	if err != nil {
+		return err
+	}
+	err = b.client.Subscribe(ctx, topic, qos, handler)
+	done(err)
+	return err
+}
+
+// rejected 是否为熔断拒绝（打开或半开名额已满）
+func rejected(err error) bool {
+	return errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests)
+}
+
+func (b *Broker) enqueue(msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.queue) >= b.maxQueue {
+		b.dropped.Add(1)
+		return ErrQueueFull
+	}
+	b.queue = append(b.queue, msg)
+	return nil
+}
+
+// Flush 按顺序补发暂存的消息，熔断器再次拒绝或发布失败时停止，返回成功发送数
+// 应由调用方定时或在熔断器关闭时调用
+func (b *Broker) Flush(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		b.mu.Lock()
+		if len(b.queue) == 0 {
+			b.mu.Unlock()
+			return sent, nil
+		}
+		msg := b.queue[0]
+		b.mu.Unlock()
+
+		done, err := b.cb.AllowContext(ctx)
+		if err != nil {
+			return sent, err
+		}
+		err = b.client.Publish(ctx, msg)
+		done(err)
+		if err != nil {
+			return sent, err
+		}
+
+		b.mu.Lock()
+		b.queue = b.queue[1:]
+		b.mu.Unlock()
+		sent++
+	}
+}
+
+// Pending 返回暂存的消息数
+func (b *Broker) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queue)
+}
+
+// Dropped 返回因熔断丢弃的消息数（Drop 策略或队列已满）
+func (b *Broker) Dropped() uint64 {
+	return b.dropped.Load()
+}
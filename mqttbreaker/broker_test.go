@@ -0,0 +1,87 @@
+// Copyright 2025 zampo.
+
+package mqttbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+type fakeClient struct {
+	down      bool
+	published []string
+}
+
+func (f *fakeClient) Publish(ctx context.Context, msg Message) error {
+	if f.down {
+		return errors.New("broker unreachable")
+	}
+	f.published = append(f.published, msg.Topic)
+	return nil
+}
+
+func (f *fakeClient) Subscribe(ctx context.Context, topic string, qos byte, handler func(Message)) error {
+	if f.down {
+		return errors.New("broker unreachable")
+	}
+	return nil
+}
+
+func tripOnFirstFailure() circuitbreaker.Settings {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	return settings
+}
+
+func TestBroker_DropWhileOpen(t *testing.T) {
+	client := &fakeClient{down: true}
+	b := NewBroker("eu-1", client, WithSettings(tripOnFirstFailure()))
+	ctx := context.Background()
+
+	if _, err := b.Publish(ctx, Message{Topic: "ticks"}); err == nil {
+		t.Fatal("Publish() error = nil, want broker error")
+	}
+	queued, err := b.Publish(ctx, Message{Topic: "ticks"})
+	if queued || circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Errorf("Publish() = %v, %v, want dropped with open rejection", queued, err)
+	}
+	if got := b.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %v, want 1", got)
+	}
+	if err := b.Subscribe(ctx, "ticks", 1, func(Message) {}); circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Errorf("Subscribe() error = %v, want open rejection", err)
+	}
+}
+
+func TestBroker_QueueAndFlush(t *testing.T) {
+	client := &fakeClient{down: true}
+	r := circuitbreaker.NewRegistry()
+	b := NewBroker("eu-1", client, WithRegistry(r), WithSettings(tripOnFirstFailure()), WithQueue(2))
+	ctx := context.Background()
+
+	b.Publish(ctx, Message{Topic: "a"})
+	for _, topic := range []string{"b", "c"} {
+		if queued, err := b.Publish(ctx, Message{Topic: topic}); !queued || err != nil {
+			t.Errorf("Publish(%s) = %v, %v, want queued", topic, queued, err)
+		}
+	}
+	if _, err := b.Publish(ctx, Message{Topic: "d"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Publish(d) error = %v, want %v", err, ErrQueueFull)
+	}
+
+	client.down = false
+	b.Breaker().Reset()
+	sent, err := b.Flush(ctx)
+	if sent != 2 || err != nil {
+		t.Errorf("Flush() = %v, %v, want 2, nil", sent, err)
+	}
+	if b.Pending() != 0 || len(client.published) != 2 || client.published[0] != "b" {
+		t.Errorf("published = %v, pending = %v", client.published, b.Pending())
+	}
+	if _, ok := r.Get("mqtt:eu-1"); !ok {
+		t.Error("breaker not registered as mqtt:eu-1")
+	}
+}
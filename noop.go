@@ -0,0 +1,65 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/sony/gobreaker"
+)
+
+// noopName 空熔断器的名称
+const noopName = "noop"
+
+// noopBreaker 总是执行的空熔断器
+type noopBreaker struct{}
+
+var _ Executor = noopBreaker{}
+
+// Noop 返回总是执行 fn、从不拒绝也不统计的 Executor，状态始终为关闭；
+// 供库接收可选的熔断器参数，未配置时以 Noop 代替 nil，避免在各处判空，见 OrNoop
+func Noop() Executor {
+	return noopBreaker{}
+}
+
+// OrNoop 在 e 为 nil（含值为 nil 的指针，如 (*CircuitBreaker)(nil)）时返回 Noop，否则返回 e
+func OrNoop(e Executor) Executor {
+	if e == nil {
+		return Noop()
+	}
+	if v := reflect.ValueOf(e); v.Kind() == reflect.Pointer && v.IsNil() {
+		return Noop()
+	}
+	return e
+}
+
+func (noopBreaker) Name() string { return noopName }
+
+func (noopBreaker) Execute(fn func() (interface{}, error)) (interface{}, error) {
+	return fn()
+}
+
+func (noopBreaker) ExecuteContext(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return fn(ctx)
+}
+
+func (noopBreaker) State() gobreaker.State { return gobreaker.StateClosed }
+
+func (noopBreaker) Stats() Stats {
+	return Stats{Name: noopName, State: gobreaker.StateClosed, HealthScore: 100}
+}
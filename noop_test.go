@@ -0,0 +1,41 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestNoop_AlwaysExecutes(t *testing.T) {
+	e := Noop()
+	boom := errors.New("boom")
+	for i := 0; i < 10; i++ {
+		if _, err := e.Execute(func() (interface{}, error) { return nil, boom }); err != boom {
+			t.Fatalf("Execute() error = %v, want %v", err, boom)
+		}
+	}
+	v, err := e.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) { return "ok", nil })
+	if v != "ok" || err != nil {
+		t.Errorf("ExecuteContext() = %v, %v", v, err)
+	}
+	if e.State() != gobreaker.StateClosed || e.Stats().Counts.Requests != 0 {
+		t.Errorf("State() = %v, Stats() = %+v, want closed without counts", e.State(), e.Stats())
+	}
+}
+
+func TestOrNoop(t *testing.T) {
+	var cb *CircuitBreaker
+	for _, e := range []Executor{nil, cb} {
+		if got := OrNoop(e); got.Name() != "noop" {
+			t.Errorf("OrNoop(%#v) = %v, want noop", e, got.Name())
+		}
+	}
+	real := NewCircuitBreaker("svc", DefaultSettings())
+	if got := OrNoop(real); got != real {
+		t.Errorf("OrNoop(real) = %v, want real", got)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+)
+
+func TestNoTelemetryTag_DropsNetworkImports(t *testing.T) {
+	ctx := build.Default
+	ctx.BuildTags = []string{"cbnotelemetry"}
+	pkg, err := ctx.ImportDir(".", 0)
+	if err != nil {
+		t.Fatalf("ImportDir() error = %v", err)
+	}
+	for _, path := range pkg.Imports {
+		if path == "net" || strings.HasPrefix(path, "net/http") {
+			t.Errorf("package imports %s with cbnotelemetry", path)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Outcome 调用结果分类
+type Outcome int
+
+const (
+	// OutcomeSuccess 计为成功
+	OutcomeSuccess Outcome = iota
+	// OutcomeFailure 计为失败
+	OutcomeFailure
+	// OutcomeIgnored 不计入熔断统计
+	OutcomeIgnored
+)
+
+// String 返回结果分类名称
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeFailure:
+		return "failure"
+	default:
+		return "ignored"
+	}
+}
+
+// ErrorMatcher 错误匹配器，可直接使用函数字面量作为自定义判定
+type ErrorMatcher func(err error) bool
+
+// MatchErrors 匹配错误链中包含任一目标错误（errors.Is）
+func MatchErrors(targets ...error) ErrorMatcher {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchErrorTypes 按类型名匹配错误链中的任一错误，类型名与 fmt 的 %T 输出一致，如 "*net.OpError"
+// 适用于无法导入错误类型的场景（如配置文件）
+func MatchErrorTypes(names ...string) ErrorMatcher {
+	return func(err error) bool {
+		matched := false
+		walkErrors(err, func(e error) bool {
+			typeName := fmt.Sprintf("%T", e)
+			for _, name := range names {
+				if typeName == name {
+					matched = true
+					return false
+				}
+			}
+			return true
+		})
+		return matched
+	}
+}
+
+// walkErrors 深度优先遍历错误链（含 errors.Join 产生的多分支），visit 返回 false 时停止
+func walkErrors(err error, visit func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !visit(err) {
+		return false
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkErrors(u.Unwrap(), visit)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if !walkErrors(e, visit) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Classify 对调用错误分类，依次应用 FailErrors（黑名单，总是计为失败）、
+// IgnoreErrors（白名单，不计入统计）与 IsSuccessful
+func (s Settings) Classify(err error) Outcome {
+	if err != nil {
+		for _, match := range s.FailErrors {
+			if match(err) {
+				return OutcomeFailure
+			}
+		}
+		for _, match := range s.IgnoreErrors {
+			if match(err) {
+				return OutcomeIgnored
+			}
+		}
+	}
+
+	if s.IsSuccessful != nil {
+		if s.IsSuccessful(err) {
+			return OutcomeSuccess
+		}
+		return OutcomeFailure
+	}
+	if err == nil {
+		return OutcomeSuccess
+	}
+	return OutcomeFailure
+}
+
+// classifyContext 在 Classify 之前排除调用方自身 ctx 取消或超时导致的错误
+func (s Settings) classifyContext(ctx context.Context, err error) Outcome {
+	if !s.CountCallerCancellation && callerCanceled(ctx, err) {
+		return OutcomeIgnored
+	}
+	return s.Classify(err)
+}
+
+// callerCanceled 判断错误是否源自调用方自身 ctx 的取消或超时，
+// 熔断器 CallTimeout 派生的超时不属于此类；DeadlineOverhead 预算耗尽视同调用方超时
+func callerCanceled(ctx context.Context, err error) bool {
+	if errors.Is(err, ErrDeadlineBudget) {
+		return true
+	}
+	return err != nil && ctx.Err() != nil &&
+		(errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded))
+}
+
+// reportOutcome 向 gobreaker 上报已分类的结果
+// 被忽略的结果（含未计入统计的调用方取消）不上报：关闭状态下的调用无需结算，
+// 半开探测经 neutral 归还名额给下一个探测，既不计为成功（依赖未成功返回时不能关闭熔断器），
+// 也不会让熔断器因名额耗尽停留在半开状态
+func reportOutcome(done func(success bool), neutral func(), outcome Outcome) Outcome {
+	switch outcome {
+	case OutcomeSuccess:
+		done(true)
+	case OutcomeFailure:
+		done(false)
+	default:
+		if neutral != nil {
+			neutral()
+		}
+	}
+	return outcome
+}
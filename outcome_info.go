@@ -0,0 +1,76 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"time"
+)
+
+// OutcomeInfo 单次调用的结果信息，见 Settings.OnOutcome
+type OutcomeInfo struct {
+	// Breaker 熔断器名称
+	Breaker string
+	// Outcome 结果分类，被拒绝的调用为 OutcomeIgnored
+	Outcome Outcome
+	// Err 记入统计的错误：被拒绝时为 *RejectionError，超过 LatencyBudget 或 MaxResultSize 的成功调用
+	// 为包装了 ErrSlowCall 或 ErrResultTooLarge 的错误；Allow 上报的结果不带错误
+	Err error
+	// Duration 调用耗时，被拒绝时为 0；超时的调用为到超时为止的耗时，
+	// 开启 DeferTimeoutOutcome 时为调用实际返回时的耗时
+	Duration time.Duration
+	// Rejected 调用被拒绝，未实际执行
+	Rejected bool
+	// Reason 拒绝原因，未被拒绝时为 ReasonNone
+	Reason Reason
+	// Probe 调用是半开状态下放行的探测
+	Probe bool
+	// Labels 熔断器的 Settings.Labels，与配置共享，不可修改
+	Labels map[string]string
+}
+
+// emitOutcome 调用结果计入统计后按放行快照通知 Settings.OnOutcome
+func (cb *CircuitBreaker) emitOutcome(a *admission, outcome Outcome, err error, start time.Time) {
+	if a.settings.OnOutcome == nil {
+		return
+	}
+	a.settings.OnOutcome(OutcomeInfo{
+		Breaker:  cb.name,
+		Outcome:  outcome,
+		Err:      err,
+		Duration: time.Since(start),
+		Probe:    a.probe,
+		Labels:   a.settings.Labels,
+	})
+}
+
+// emitRejection 通知 Settings.OnOutcome 调用被拒绝
+func (cb *CircuitBreaker) emitRejection(err error) {
+	cb.mu.RLock()
+	onOutcome, labels := cb.settings.OnOutcome, cb.settings.Labels
+	cb.mu.RUnlock()
+	if onOutcome == nil {
+		return
+	}
+	onOutcome(OutcomeInfo{
+		Breaker:  cb.name,
+		Outcome:  OutcomeIgnored,
+		Err:      err,
+		Rejected: true,
+		Reason:   ReasonOf(err),
+		Labels:   labels,
+	})
+}
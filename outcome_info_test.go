@@ -0,0 +1,126 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// outcomeRecorder 记录 OnOutcome 收到的信息
+type outcomeRecorder struct {
+	mu    sync.Mutex
+	infos []OutcomeInfo
+}
+
+func (r *outcomeRecorder) record(info OutcomeInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.infos = append(r.infos, info)
+}
+
+func (r *outcomeRecorder) all() []OutcomeInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]OutcomeInfo(nil), r.infos...)
+}
+
+func TestOnOutcome_Execute(t *testing.T) {
+	var rec outcomeRecorder
+	settings := DefaultSettings()
+	settings.Timeout = 20 * time.Millisecond
+	settings.MaxRequests = 1
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 }
+	settings.Labels = map[string]string{"service": "payments"}
+	settings.OnOutcome = rec.record
+	cb := NewCircuitBreaker("payments", settings)
+
+	boom := errors.New("boom")
+	cb.Execute(func() (interface{}, error) {
+		time.Sleep(5 * time.Millisecond)
+		return nil, boom
+	})
+	cb.Execute(func() (interface{}, error) { return "unreachable", nil })
+	time.Sleep(30 * time.Millisecond)
+	cb.Execute(func() (interface{}, error) { return "ok", nil })
+
+	infos := rec.all()
+	if len(infos) != 3 {
+		t.Fatalf("got %d outcomes, want 3: %+v", len(infos), infos)
+	}
+
+	failed := infos[0]
+	if failed.Breaker != "payments" || failed.Outcome != OutcomeFailure || !errors.Is(failed.Err, boom) {
+		t.Errorf("first outcome = %+v, want failure with boom", failed)
+	}
+	if failed.Duration < 5*time.Millisecond || failed.Rejected || failed.Probe {
+		t.Errorf("first outcome = %+v, want executed non-probe call of at least 5ms", failed)
+	}
+	if failed.Labels["service"] != "payments" {
+		t.Errorf("Labels = %v, want service=payments", failed.Labels)
+	}
+
+	rejected := infos[1]
+	if !rejected.Rejected || rejected.Reason != ReasonOpen || rejected.Outcome != OutcomeIgnored || rejected.Duration != 0 {
+		t.Errorf("second outcome = %+v, want rejection with reason %v", rejected, ReasonOpen)
+	}
+	if ReasonOf(rejected.Err) != ReasonOpen {
+		t.Errorf("rejection Err = %v, want *RejectionError", rejected.Err)
+	}
+
+	probe := infos[2]
+	if !probe.Probe || probe.Outcome != OutcomeSuccess || probe.Err != nil {
+		t.Errorf("third outcome = %+v, want successful probe", probe)
+	}
+}
+
+func TestOnOutcome_SlowCall(t *testing.T) {
+	var rec outcomeRecorder
+	settings := DefaultSettings()
+	settings.LatencyBudget = time.Millisecond
+	settings.OnOutcome = rec.record
+	cb := NewCircuitBreaker("test", settings)
+
+	done, err := cb.Allow()
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	done(true)
+
+	infos := rec.all()
+	if len(infos) != 1 || infos[0].Outcome != OutcomeFailure || !errors.Is(infos[0].Err, ErrSlowCall) {
+		t.Fatalf("outcomes = %+v, want one failure with ErrSlowCall", infos)
+	}
+}
+
+func TestOnOutcome_CallTimeout(t *testing.T) {
+	var rec outcomeRecorder
+	settings := DefaultSettings()
+	settings.CallTimeout = 10 * time.Millisecond
+	settings.OnOutcome = rec.record
+	cb := NewCircuitBreaker("test", settings)
+
+	release := make(chan struct{})
+	defer close(release)
+	_, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-release
+		return nil, nil
+	})
+	if !errors.Is(err, ErrCallTimeout) {
+		t.Fatalf("ExecuteContext() error = %v, want %v", err, ErrCallTimeout)
+	}
+
+	infos := rec.all()
+	if len(infos) != 1 || infos[0].Outcome != OutcomeFailure || !errors.Is(infos[0].Err, ErrCallTimeout) {
+		t.Fatalf("outcomes = %+v, want one failure with ErrCallTimeout", infos)
+	}
+	if infos[0].Duration < 10*time.Millisecond {
+		t.Errorf("Duration = %v, want at least the call timeout", infos[0].Duration)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+var errNotFound = errors.New("not found")
+
+func TestSettings_Classify(t *testing.T) {
+	settings := Settings{
+		IgnoreErrors: []ErrorMatcher{MatchErrors(context.Canceled, errNotFound)},
+		FailErrors:   []ErrorMatcher{MatchErrorTypes("*net.OpError")},
+		IsSuccessful: func(err error) bool {
+			return err == nil || errors.Is(err, errNotFound)
+		},
+	}
+
+	opErr := &net.OpError{Op: "dial", Err: context.Canceled}
+	tests := []struct {
+		name string
+		err  error
+		want Outcome
+	}{
+		{"nil", nil, OutcomeSuccess},
+		{"plain failure", errors.New("boom"), OutcomeFailure},
+		{"ignored sentinel", fmt.Errorf("wrapped: %w", context.Canceled), OutcomeIgnored},
+		{"ignored before IsSuccessful", errNotFound, OutcomeIgnored},
+		{"denylist wins", opErr, OutcomeFailure},
+		{"denylist in joined error", errors.Join(errors.New("x"), opErr), OutcomeFailure},
+		{"predicate", errors.New("custom"), OutcomeFailure},
+	}
+
+	for _, tt := range tests {
+		if got := settings.Classify(tt.err); got != tt.want {
+			t.Errorf("%s: Classify(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCircuitBreaker_IgnoredErrorsDoNotTrip(t *testing.T) {
+	settings := DefaultSettings()
+	settings.IgnoreErrors = []ErrorMatcher{MatchErrors(errNotFound)}
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 2
+	}
+	cb := NewCircuitBreaker("test", settings)
+
+	for i := 0; i < 5; i++ {
+		_, err := cb.Execute(func() (interface{}, error) {
+			return nil, errNotFound
+		})
+		if !errors.Is(err, errNotFound) {
+			t.Fatalf("Execute() error = %v, want %v", err, errNotFound)
+		}
+	}
+
+	counts := cb.Counts()
+	if counts.TotalFailures != 0 || counts.TotalSuccesses != 0 {
+		t.Errorf("failures, successes = %v, %v, want 0, 0", counts.TotalFailures, counts.TotalSuccesses)
+	}
+	if state := cb.State(); state != gobreaker.StateClosed {
+		t.Errorf("State = %v, want %v", state, gobreaker.StateClosed)
+	}
+}
+
+func TestCircuitBreaker_IsSuccessful(t *testing.T) {
+	settings := DefaultSettings()
+	settings.IsSuccessful = func(err error) bool {
+		return err == nil || errors.Is(err, errNotFound)
+	}
+	cb := NewCircuitBreaker("test", settings)
+
+	cb.Execute(func() (interface{}, error) {
+		return nil, errNotFound
+	})
+
+	if got := cb.Counts().TotalSuccesses; got != 1 {
+		t.Errorf("TotalSuccesses = %v, want %v", got, 1)
+	}
+}
+
+// probingBreaker 返回已进入半开状态的熔断器，半开状态下放行 2 个探测
+func probingBreaker(t *testing.T, settings Settings) *CircuitBreaker {
+	t.Helper()
+	settings.MaxRequests = 2
+	settings.Timeout = 10 * time.Millisecond
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	cb := NewCircuitBreaker("test", settings)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	time.Sleep(20 * time.Millisecond)
+	if state := cb.State(); state != gobreaker.StateHalfOpen {
+		t.Fatalf("State = %v, want %v", state, gobreaker.StateHalfOpen)
+	}
+	return cb
+}
+
+func TestCircuitBreaker_IgnoredProbesDoNotClose(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		modify func(s *Settings)
+	}{
+		{"default", func(s *Settings) {}},
+		{"close failure rate", func(s *Settings) { s.CloseFailureRate = 0.5 }},
+		{"probe budget", func(s *Settings) { s.ProbeBudget = NewProbeBudget(1) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultSettings()
+			settings.IgnoreErrors = []ErrorMatcher{MatchErrors(errNotFound)}
+			tt.modify(&settings)
+			cb := probingBreaker(t, settings)
+
+			for i := 0; i < 5; i++ {
+				_, err := cb.Execute(func() (interface{}, error) { return nil, errNotFound })
+				if !errors.Is(err, errNotFound) {
+					t.Fatalf("probe %d: Execute() error = %v, want %v (ignored probes must return their slot)", i, err, errNotFound)
+				}
+			}
+			if state := cb.State(); state != gobreaker.StateHalfOpen {
+				t.Fatalf("after ignored probes: State = %v, want %v", state, gobreaker.StateHalfOpen)
+			}
+
+			for i := 0; i < 2; i++ {
+				if _, err := cb.Execute(func() (interface{}, error) { return "ok", nil }); err != nil {
+					t.Fatalf("success %d: Execute() error = %v", i, err)
+				}
+			}
+			if state := cb.State(); state != gobreaker.StateClosed {
+				t.Errorf("after successful probes: State = %v, want %v", state, gobreaker.StateClosed)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_CanceledProbesDoNotClose(t *testing.T) {
+	cb := probingBreaker(t, DefaultSettings())
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		done, err := cb.AllowContext(ctx)
+		if err != nil {
+			t.Fatalf("probe %d: AllowContext() error = %v", i, err)
+		}
+		cancel()
+		done(ctx.Err())
+	}
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+			cancel()
+			return nil, ctx.Err()
+		})
+		if ReasonOf(err) != ReasonNone {
+			t.Fatalf("probe %d: ExecuteContext() rejected: %v", i, err)
+		}
+	}
+	if state := cb.State(); state != gobreaker.StateHalfOpen {
+		t.Errorf("after canceled probes: State = %v, want %v", state, gobreaker.StateHalfOpen)
+	}
+}
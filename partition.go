@@ -0,0 +1,160 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// Partitioned 将一个逻辑依赖的流量按标签划分（如 baseline/canary、region-a/region-b），
+// 每个分区使用独立熔断器但共享同一份配置，一个分区熔断不影响其他分区；
+// 大量分区同时恢复时可通过 Settings.ProbeBudget 限制各分区半开探测的总数
+type Partitioned struct {
+	name string
+
+	mu       sync.RWMutex
+	settings Settings
+	parts    map[string]*CircuitBreaker
+}
+
+// NewPartitioned 创建分区熔断器组
+func NewPartitioned(name string, settings Settings) *Partitioned {
+	return &Partitioned{
+		name:     name,
+		settings: settings,
+		parts:    make(map[string]*CircuitBreaker),
+	}
+}
+
+// Name 返回逻辑依赖名称
+func (p *Partitioned) Name() string {
+	return p.name
+}
+
+// Partition 返回指定标签的熔断器，不存在时创建，命名为 "<name>/<label>"
+func (p *Partitioned) Partition(label string) *CircuitBreaker {
+	p.mu.RLock()
+	cb, ok := p.parts[label]
+	p.mu.RUnlock()
+	if ok {
+		return cb
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cb, ok := p.parts[label]; ok {
+		return cb
+	}
+	cb = NewCircuitBreaker(p.name+"/"+label, p.settings)
+	p.parts[label] = cb
+	return cb
+}
+
+// Lookup 返回指定标签的熔断器，不存在时不创建
+func (p *Partitioned) Lookup(label string) (*CircuitBreaker, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	cb, ok := p.parts[label]
+	return cb, ok
+}
+
+// Labels 返回所有分区标签（已排序）
+func (p *Partitioned) Labels() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	labels := make([]string, 0, len(p.parts))
+	for label := range p.parts {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// UpdateSettings 更新共享配置并应用到所有分区
+func (p *Partitioned) UpdateSettings(settings Settings) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.settings = settings
+	for _, cb := range p.parts {
+		cb.UpdateSettings(settings)
+	}
+}
+
+// Stats 返回所有分区的统计快照（按标签排序）
+func (p *Partitioned) Stats() []Stats {
+	labels := p.Labels()
+	stats := make([]Stats, 0, len(labels))
+	for _, label := range labels {
+		if cb, ok := p.Lookup(label); ok {
+			stats = append(stats, cb.Stats())
+		}
+	}
+	return stats
+}
+
+// PartitionComparison 两个分区的对比结果，供自动化金丝雀分析使用
+type PartitionComparison struct {
+	Baseline Stats
+	Canary   Stats
+	// BaselineFailureRate 基线分区当前窗口失败率
+	BaselineFailureRate float64
+	// CanaryFailureRate 金丝雀分区当前窗口失败率
+	CanaryFailureRate float64
+	// FailureRateDelta 金丝雀失败率减去基线失败率，正值表示金丝雀更差
+	FailureRateDelta float64
+	// LatencyP99Delta 金丝雀 P99 耗时减去基线 P99 耗时
+	LatencyP99Delta time.Duration
+	// HealthScoreDelta 金丝雀健康评分减去基线健康评分，负值表示金丝雀更差
+	HealthScoreDelta float64
+}
+
+// Compare 对比两个分区的统计，任一分区不存在时返回 false，不会创建分区
+func (p *Partitioned) Compare(baseline, canary string) (PartitionComparison, bool) {
+	bcb, ok := p.Lookup(baseline)
+	if !ok {
+		return PartitionComparison{}, false
+	}
+	ccb, ok := p.Lookup(canary)
+	if !ok {
+		return PartitionComparison{}, false
+	}
+	b, c := bcb.Stats(), ccb.Stats()
+	bRate, cRate := failureRate(b.Counts), failureRate(c.Counts)
+	return PartitionComparison{
+		Baseline:            b,
+		Canary:              c,
+		BaselineFailureRate: bRate,
+		CanaryFailureRate:   cRate,
+		FailureRateDelta:    cRate - bRate,
+		LatencyP99Delta:     c.LatencyP99 - b.LatencyP99,
+		HealthScoreDelta:    c.HealthScore - b.HealthScore,
+	}, true
+}
+
+// failureRate 返回窗口内失败率，无请求时为 0
+func failureRate(counts gobreaker.Counts) float64 {
+	if counts.Requests == 0 {
+		return 0
+	}
+	return float64(counts.TotalFailures) / float64(counts.Requests)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestPartitioned_IsolatesPartitions(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 2
+	}
+	p := NewPartitioned("orders", settings)
+
+	for i := 0; i < 2; i++ {
+		p.Partition("canary").Execute(func() (interface{}, error) {
+			return nil, errors.New("fail")
+		})
+	}
+	p.Partition("baseline").Execute(func() (interface{}, error) {
+		return nil, nil
+	})
+
+	if got := p.Partition("canary").State(); got != gobreaker.StateOpen {
+		t.Errorf("canary State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+	if got := p.Partition("baseline").State(); got != gobreaker.StateClosed {
+		t.Errorf("baseline State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+	if got := p.Partition("canary").Name(); got != "orders/canary" {
+		t.Errorf("Name() = %v, want orders/canary", got)
+	}
+	if got := p.Labels(); !reflect.DeepEqual(got, []string{"baseline", "canary"}) {
+		t.Errorf("Labels() = %v, want [baseline canary]", got)
+	}
+}
+
+func TestPartitioned_Compare(t *testing.T) {
+	p := NewPartitioned("orders", DefaultSettings())
+
+	p.Partition("baseline").Execute(func() (interface{}, error) { return nil, nil })
+	p.Partition("canary").Execute(func() (interface{}, error) { return nil, nil })
+	p.Partition("canary").Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+
+	cmp, ok := p.Compare("baseline", "canary")
+	if !ok {
+		t.Fatal("Compare() ok = false, want true")
+	}
+	if cmp.BaselineFailureRate != 0 || cmp.CanaryFailureRate != 0.5 || cmp.FailureRateDelta != 0.5 {
+		t.Errorf("failure rates = %v, %v, %v, want 0, 0.5, 0.5",
+			cmp.BaselineFailureRate, cmp.CanaryFailureRate, cmp.FailureRateDelta)
+	}
+	if cmp.HealthScoreDelta >= 0 {
+		t.Errorf("HealthScoreDelta = %v, want < 0", cmp.HealthScoreDelta)
+	}
+
+	if _, ok := p.Compare("baseline", "typo"); ok {
+		t.Error("Compare() with unknown label ok = true, want false")
+	}
+	if got := p.Labels(); !reflect.DeepEqual(got, []string{"baseline", "canary"}) {
+		t.Errorf("Labels() after Compare = %v, want [baseline canary]", got)
+	}
+}
+
+func TestPartitioned_UpdateSettings(t *testing.T) {
+	p := NewPartitioned("orders", DefaultSettings())
+	p.Partition("a")
+
+	settings := DefaultSettings()
+	settings.MaxRequests = 7
+	p.UpdateSettings(settings)
+
+	if got := p.Partition("a").GetSettings().MaxRequests; got != 7 {
+		t.Errorf("existing MaxRequests = %v, want 7", got)
+	}
+	if got := p.Partition("b").GetSettings().MaxRequests; got != 7 {
+		t.Errorf("new MaxRequests = %v, want 7", got)
+	}
+}
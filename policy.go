@@ -0,0 +1,110 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"github.com/sony/gobreaker"
+)
+
+// FleetCountsFunc 返回集群中除本实例外其他实例上某熔断器的请求数与失败数
+// 在 ReadyToTrip 中调用，不可阻塞，也不可回调本熔断器的方法
+type FleetCountsFunc func(name string) (requests, failures uint64)
+
+// FleetFailureRate 返回仅依据集群整体失败率决定是否熔断的 ReadyToTrip
+// 本实例计数与 remote 汇总后，请求数达到 minRequests 且失败率不低于 rate 时才熔断，
+// 避免单个实例因一条坏连接而打开熔断器
+func FleetFailureRate(name string, remote FleetCountsFunc, minRequests uint64, rate float64) func(counts gobreaker.Counts) bool {
+	return func(counts gobreaker.Counts) bool {
+		requests, failures := remote(name)
+		requests += uint64(counts.Requests)
+		failures += uint64(counts.TotalFailures)
+
+		if requests == 0 || requests < minRequests {
+			return false
+		}
+		return float64(failures)/float64(requests) >= rate
+	}
+}
+
+// withMinimumRequests 请求量不足时跳过熔断判定；外部饱和度（PressureFunc）不受此限制
+func withMinimumRequests(readyToTrip func(counts gobreaker.Counts) bool, n uint32) func(counts gobreaker.Counts) bool {
+	if readyToTrip == nil {
+		readyToTrip = defaultReadyToTrip
+	}
+	return func(counts gobreaker.Counts) bool {
+		return counts.Requests >= n && readyToTrip(counts)
+	}
+}
+
+// TripPolicy 熔断触发策略，可直接赋值给 Settings.ReadyToTrip，并通过 AnyOf/AllOf/Not 组合
+type TripPolicy func(counts gobreaker.Counts) bool
+
+// AnyOf 任一策略满足即熔断，未提供策略时永不熔断
+func AnyOf(policies ...TripPolicy) TripPolicy {
+	return func(counts gobreaker.Counts) bool {
+		for _, p := range policies {
+			if p(counts) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf 所有策略均满足才熔断，未提供策略时永不熔断
+// 常用于为失败率等策略附加最小请求量条件，如 AllOf(MinRequests(20), FailureRate(0.5))
+func AllOf(policies ...TripPolicy) TripPolicy {
+	return func(counts gobreaker.Counts) bool {
+		if len(policies) == 0 {
+			return false
+		}
+		for _, p := range policies {
+			if !p(counts) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Not 对策略取反
+func Not(policy TripPolicy) TripPolicy {
+	return func(counts gobreaker.Counts) bool {
+		return !policy(counts)
+	}
+}
+
+// ConsecutiveFailures 连续失败次数达到 n 时满足
+func ConsecutiveFailures(n uint32) TripPolicy {
+	return func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= n
+	}
+}
+
+// FailureRate 当前窗口失败率不低于 rate 时满足，无请求时不满足
+func FailureRate(rate float64) TripPolicy {
+	return func(counts gobreaker.Counts) bool {
+		return counts.Requests > 0 && failureRate(counts) >= rate
+	}
+}
+
+// MinRequests 当前窗口请求数达到 n 时满足
+func MinRequests(n uint32) TripPolicy {
+	return func(counts gobreaker.Counts) bool {
+		return counts.Requests >= n
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TripPolicyFactory 根据配置构造熔断策略，返回的错误会作为配置校验错误报告
+// 通常通过 json.Unmarshal(p.Params, &params) 解析自定义参数
+type TripPolicyFactory func(p PolicyConfig) (TripPolicy, error)
+
+var (
+	tripPoliciesMu sync.RWMutex
+	tripPolicies   = make(map[string]TripPolicyFactory)
+)
+
+// RegisterTripPolicy 注册具名熔断策略，配置中 policies[].type 为该名称时由 factory 构造策略
+// 配置热加载（重新 ParseConfig 并 Apply）时按名称重新构造，策略随配置一同更新
+// 通常在 init 中调用；名称为空、与内置策略或已注册策略重名、factory 为 nil 时 panic
+func RegisterTripPolicy(name string, factory TripPolicyFactory) {
+	if name == "" || factory == nil {
+		panic("circuitbreaker: RegisterTripPolicy requires a name and a factory")
+	}
+	if name == PolicyConsecutiveFailures || name == PolicyFailureRate {
+		panic(fmt.Sprintf("circuitbreaker: trip policy %q is built in", name))
+	}
+
+	tripPoliciesMu.Lock()
+	defer tripPoliciesMu.Unlock()
+	if _, dup := tripPolicies[name]; dup {
+		panic(fmt.Sprintf("circuitbreaker: trip policy %q already registered", name))
+	}
+	tripPolicies[name] = factory
+}
+
+// TripPolicies 返回已注册的具名策略名称（不含以 Threshold 配置的内置策略，按名称排序）
+func TripPolicies() []string {
+	tripPoliciesMu.RLock()
+	defer tripPoliciesMu.RUnlock()
+
+	names := make([]string, 0, len(tripPolicies))
+	for name := range tripPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unregisterTripPolicy 移除具名策略，仅供测试使用
+func unregisterTripPolicy(name string) {
+	tripPoliciesMu.Lock()
+	delete(tripPolicies, name)
+	tripPoliciesMu.Unlock()
+}
+
+// tripPolicyRegistered 报告名称是否为已注册的具名策略
+func tripPolicyRegistered(name string) bool {
+	tripPoliciesMu.RLock()
+	defer tripPoliciesMu.RUnlock()
+	_, ok := tripPolicies[name]
+	return ok
+}
+
+// buildTripPolicy 按配置构造熔断策略；内置策略的阈值由 Validate 校验
+func buildTripPolicy(p PolicyConfig) (TripPolicy, error) {
+	switch p.Type {
+	case PolicyConsecutiveFailures:
+		return ConsecutiveFailures(uint32(p.Threshold)), nil
+	case PolicyFailureRate:
+		return FailureRate(p.Threshold), nil
+	}
+
+	tripPoliciesMu.RLock()
+	factory, ok := tripPolicies[p.Type]
+	tripPoliciesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown policy %q", p.Type)
+	}
+	policy, err := factory(p)
+	if err == nil && policy == nil {
+		err = fmt.Errorf("policy %q: factory returned nil", p.Type)
+	}
+	return policy, err
+}
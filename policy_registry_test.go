@@ -0,0 +1,92 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func registerTimeoutBudget(t *testing.T) {
+	t.Helper()
+	RegisterTripPolicy("timeout_budget", func(p PolicyConfig) (TripPolicy, error) {
+		var params struct {
+			MaxFailures uint32 `json:"max_failures"`
+		}
+		if err := json.Unmarshal(p.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.MaxFailures == 0 {
+			return nil, errors.New("max_failures must be positive")
+		}
+		return func(counts gobreaker.Counts) bool {
+			return counts.TotalFailures >= params.MaxFailures
+		}, nil
+	})
+	t.Cleanup(func() { unregisterTripPolicy("timeout_budget") })
+}
+
+func customPolicyConfig(maxFailures int) []byte {
+	return []byte(fmt.Sprintf(`{
+  "version": 2,
+  "breakers": {
+    "search": {"policies": [{"type": "timeout_budget", "params": {"max_failures": %d}}]}
+  }
+}`, maxFailures))
+}
+
+func TestRegisterTripPolicy_Config(t *testing.T) {
+	registerTimeoutBudget(t)
+
+	cfg, err := ParseConfig(customPolicyConfig(2))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	trip := cfg.Settings("search").ReadyToTrip
+	if trip(gobreaker.Counts{TotalFailures: 1}) || !trip(gobreaker.Counts{TotalFailures: 2}) {
+		t.Error("custom policy not applied with configured params")
+	}
+
+	// 热加载：重新解析后按新参数构造策略
+	r := NewRegistry()
+	cfg.Apply(r)
+	reloaded, err := ParseConfig(customPolicyConfig(3))
+	if err != nil {
+		t.Fatalf("ParseConfig() reload error = %v", err)
+	}
+	reloaded.Apply(r)
+	cb, _ := r.Get("search")
+	if trip := cb.GetSettings().ReadyToTrip; trip(gobreaker.Counts{TotalFailures: 2}) {
+		t.Error("reloaded policy still uses old params")
+	}
+}
+
+func TestRegisterTripPolicy_Errors(t *testing.T) {
+	registerTimeoutBudget(t)
+
+	_, err := ParseConfig(customPolicyConfig(0))
+	if err == nil || !strings.Contains(err.Error(), "breakers.search.policies[0]: max_failures must be positive") {
+		t.Errorf("ParseConfig() error = %v, want factory error with path", err)
+	}
+
+	for _, name := range []string{"", PolicyFailureRate, "timeout_budget"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterTripPolicy(%q) did not panic", name)
+				}
+			}()
+			RegisterTripPolicy(name, func(PolicyConfig) (TripPolicy, error) { return nil, nil })
+		}()
+	}
+
+	if got := TripPolicies(); !reflect.DeepEqual(got, []string{PolicySLOBurnRate, "timeout_budget"}) {
+		t.Errorf("TripPolicies() = %v", got)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestFleetFailureRate(t *testing.T) {
+	var remoteRequests, remoteFailures uint64
+	remote := func(name string) (uint64, uint64) {
+		return remoteRequests, remoteFailures
+	}
+	trip := FleetFailureRate("svc", remote, 10, 0.5)
+
+	local := gobreaker.Counts{Requests: 2, TotalFailures: 2}
+
+	// 本实例 100% 失败，但集群其他实例健康
+	remoteRequests, remoteFailures = 98, 0
+	if trip(local) {
+		t.Error("should not trip when the fleet is healthy")
+	}
+
+	remoteRequests, remoteFailures = 18, 8
+	if !trip(local) {
+		t.Error("should trip when the fleet failure rate reaches the threshold")
+	}
+
+	remoteRequests, remoteFailures = 0, 0
+	if trip(local) {
+		t.Error("should not trip below the minimum fleet volume")
+	}
+}
+
+func TestFleetFailureRate_WithBreaker(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = FleetFailureRate("svc", func(string) (uint64, uint64) {
+		return 100, 0
+	}, 10, 0.5)
+	cb := NewCircuitBreaker("svc", settings)
+
+	for i := 0; i < 10; i++ {
+		cb.Execute(func() (interface{}, error) {
+			return nil, errors.New("fail")
+		})
+	}
+
+	if state := cb.State(); state != gobreaker.StateClosed {
+		t.Errorf("State = %v, want %v", state, gobreaker.StateClosed)
+	}
+}
+
+func TestTripPolicyCombinators(t *testing.T) {
+	policy := AnyOf(
+		ConsecutiveFailures(5),
+		AllOf(MinRequests(10), FailureRate(0.5)),
+	)
+
+	tests := []struct {
+		counts gobreaker.Counts
+		want   bool
+	}{
+		{gobreaker.Counts{Requests: 4, TotalFailures: 4, ConsecutiveFailures: 4}, false},
+		{gobreaker.Counts{Requests: 5, TotalFailures: 5, ConsecutiveFailures: 5}, true},
+		{gobreaker.Counts{Requests: 10, TotalFailures: 5, ConsecutiveFailures: 1}, true},
+		{gobreaker.Counts{Requests: 10, TotalFailures: 4, ConsecutiveFailures: 1}, false},
+	}
+
+	for _, tt := range tests {
+		if got := policy(tt.counts); got != tt.want {
+			t.Errorf("policy(%+v) = %v, want %v", tt.counts, got, tt.want)
+		}
+	}
+	if AnyOf()(gobreaker.Counts{}) || AllOf()(gobreaker.Counts{}) {
+		t.Error("empty AnyOf/AllOf should never trip")
+	}
+	if Not(MinRequests(1))(gobreaker.Counts{Requests: 1}) {
+		t.Error("Not(MinRequests(1)) should be false with 1 request")
+	}
+}
+
+func TestTripPolicy_AsReadyToTrip(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = AllOf(MinRequests(2), FailureRate(1))
+	cb := NewCircuitBreaker("test", settings)
+
+	fail := func() (interface{}, error) { return nil, errors.New("fail") }
+	cb.Execute(fail)
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+	cb.Execute(fail)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+}
+
+func TestSettings_MinimumRequests(t *testing.T) {
+	settings := DefaultSettings()
+	settings.MinimumRequests = 3
+	settings.ReadyToTrip = FailureRate(0.5)
+	cb := NewCircuitBreaker("test", settings)
+
+	fail := func() (interface{}, error) { return nil, errors.New("fail") }
+	for i := 0; i < 2; i++ {
+		cb.Execute(fail)
+		if got := cb.State(); got != gobreaker.StateClosed {
+			t.Fatalf("State() after %d failures = %v, want %v", i+1, got, gobreaker.StateClosed)
+		}
+	}
+	cb.Execute(fail)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+}
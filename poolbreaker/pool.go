@@ -0,0 +1,201 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package poolbreaker 提供熔断感知的后台任务工作池：相关熔断器打开时暂停从队列消费，
+// 而不是取出任务后立即失败
+package poolbreaker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Handler 任务处理函数
+type Handler func(ctx context.Context, job interface{}) error
+
+// Pool 熔断感知的工作池
+type Pool struct {
+	handler    Handler
+	workers    int
+	breaker    circuitbreaker.Executor
+	breakerFor func(job interface{}) circuitbreaker.Executor
+	pause      time.Duration
+	onError    func(job interface{}, err error)
+
+	paused atomic.Int32
+}
+
+// Option 工作池配置项
+type Option func(*Pool)
+
+// WithWorkers 设置并发工作协程数，默认 1
+func WithWorkers(n int) Option {
+	return func(p *Pool) { p.workers = n }
+}
+
+// WithBreaker 设置所有任务共用的熔断器，打开时所有工作协程停止从队列取任务
+func WithBreaker(cb circuitbreaker.Executor) Option {
+	return func(p *Pool) { p.breaker = cb }
+}
+
+// WithBreakerFunc 按任务选择熔断器（如按任务的目标依赖），
+// 任务对应熔断器打开时，持有该任务的工作协程暂停直至熔断器放行
+func WithBreakerFunc(fn func(job interface{}) circuitbreaker.Executor) Option {
+	return func(p *Pool) { p.breakerFor = fn }
+}
+
+// WithRegistry 按任务键从注册表获取熔断器，不存在时使用 settings 创建
+func WithRegistry(r *circuitbreaker.Registry, settings circuitbreaker.Settings, key func(job interface{}) string) Option {
+	return WithBreakerFunc(func(job interface{}) circuitbreaker.Executor {
+		return r.GetOrCreate(key(job), settings)
+	})
+}
+
+// WithPauseInterval 设置熔断打开时重新检查的间隔，默认 1 秒
+func WithPauseInterval(d time.Duration) Option {
+	return func(p *Pool) { p.pause = d }
+}
+
+// WithErrorHandler 设置任务失败回调（如记录日志、写入死信队列）
+func WithErrorHandler(fn func(job interface{}, err error)) Option {
+	return func(p *Pool) { p.onError = fn }
+}
+
+// New 创建工作池
+func New(handler Handler, opts ...Option) *Pool {
+	p := &Pool{
+		handler: handler,
+		workers: 1,
+		pause:   time.Second,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Paused 返回当前因熔断打开而暂停的工作协程数
+func (p *Pool) Paused() int {
+	return int(p.paused.Load())
+}
+
+// Run 从 jobs 消费并处理任务，直至 jobs 关闭且任务处理完毕或 ctx 结束
+// ctx 结束时返回 ctx.Err()，暂停中的任务不会被处理
+func (p *Pool) Run(ctx context.Context, jobs <-chan interface{}) error {
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx, jobs)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// work 单个工作协程的消费循环
+func (p *Pool) work(ctx context.Context, jobs <-chan interface{}) {
+	for {
+		if p.breaker != nil && !p.waitClosed(ctx, p.breaker) {
+			return
+		}
+
+		var job interface{}
+		select {
+		case <-ctx.Done():
+			return
+		case j, ok := <-jobs:
+			if !ok {
+				return
+			}
+			job = j
+		}
+
+		if !p.process(ctx, job) {
+			return
+		}
+	}
+}
+
+// process 处理单个任务，熔断器拒绝时暂停后重试同一任务，ctx 结束时返回 false
+func (p *Pool) process(ctx context.Context, job interface{}) bool {
+	cb := p.breaker
+	if p.breakerFor != nil {
+		cb = p.breakerFor(job)
+	}
+	if cb == nil {
+		p.report(job, p.handler(ctx, job))
+		return true
+	}
+
+	for {
+		if !p.waitClosed(ctx, cb) {
+			return false
+		}
+		_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+			return nil, p.handler(ctx, job)
+		})
+		if circuitbreaker.ReasonOf(err) == circuitbreaker.ReasonNone {
+			p.report(job, err)
+			return true
+		}
+		// 半开名额已满等拒绝：稍后重试同一任务
+		if !p.sleep(ctx) {
+			return false
+		}
+	}
+}
+
+// waitClosed 等待熔断器离开打开状态，ctx 结束时返回 false
+func (p *Pool) waitClosed(ctx context.Context, cb circuitbreaker.Executor) bool {
+	if cb.State() != gobreaker.StateOpen {
+		return true
+	}
+
+	p.paused.Add(1)
+	defer p.paused.Add(-1)
+	for cb.State() == gobreaker.StateOpen {
+		if !p.sleep(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// sleep 等待一个暂停间隔，ctx 结束时返回 false
+func (p *Pool) sleep(ctx context.Context) bool {
+	timer := time.NewTimer(p.pause)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (p *Pool) report(job interface{}, err error) {
+	if err != nil && p.onError != nil {
+		p.onError(job, err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 zampo.
+
+package poolbreaker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestPool_ProcessesJobs(t *testing.T) {
+	var sum atomic.Int64
+	p := New(func(ctx context.Context, job interface{}) error {
+		sum.Add(int64(job.(int)))
+		return nil
+	}, WithWorkers(3), WithRegistry(circuitbreaker.NewRegistry(), circuitbreaker.DefaultSettings(),
+		func(job interface{}) string { return "jobs" }))
+
+	jobs := make(chan interface{}, 10)
+	for i := 1; i <= 10; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	if err := p.Run(context.Background(), jobs); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if sum.Load() != 55 {
+		t.Errorf("sum = %v, want 55", sum.Load())
+	}
+}
+
+func TestPool_PausesWhileOpen(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("api", circuitbreaker.DefaultSettings())
+	cb.OpenFor(50 * time.Millisecond)
+
+	var processed atomic.Int32
+	p := New(func(ctx context.Context, job interface{}) error {
+		processed.Add(1)
+		return nil
+	}, WithBreaker(cb), WithPauseInterval(5*time.Millisecond))
+
+	jobs := make(chan interface{}, 2)
+	jobs <- 1
+	jobs <- 2
+	close(jobs)
+
+	done := make(chan error, 1)
+	go func() { done <- p.Run(context.Background(), jobs) }()
+
+	time.Sleep(20 * time.Millisecond)
+	if p.Paused() != 1 || processed.Load() != 0 || len(jobs) != 2 {
+		t.Errorf("Paused() = %v, processed = %v, queued = %v, want 1, 0, 2", p.Paused(), processed.Load(), len(jobs))
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if processed.Load() != 2 {
+		t.Errorf("processed = %v, want 2", processed.Load())
+	}
+}
+
+func TestPool_StopsOnContext(t *testing.T) {
+	cb := circuitbreaker.NewCircuitBreaker("api", circuitbreaker.DefaultSettings())
+	cb.OpenFor(time.Hour)
+	p := New(func(ctx context.Context, job interface{}) error { return nil },
+		WithBreaker(cb), WithPauseInterval(time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Run(ctx, make(chan interface{})); err != context.DeadlineExceeded {
+		t.Errorf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
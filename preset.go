@@ -0,0 +1,130 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrUnknownPreset 预设名称未定义
+var ErrUnknownPreset = errors.New("circuitbreaker: unknown preset")
+
+// 内置预设名称，可用于 PresetSettings 与配置文件的 preset 字段；预设只是起点，
+// 调用超时、延迟预算等与具体依赖相关的值应按实际 SLO 覆盖
+const (
+	// PresetAggressive 快速打开、快速恢复：10 秒滚动窗口内至少 5 个请求后，连续 3 次失败或失败率达到 20% 即打开，
+	// 5 秒后以 1 个探测请求尝试恢复。适用于宁可误判也不能让故障级联的关键路径依赖
+	PresetAggressive = "aggressive"
+	// PresetConservative 只对持续的高错误率打开：60 秒滚动窗口内至少 50 个请求且失败率达到 50% 时打开，
+	// 60 秒后以 5 个探测请求尝试恢复，探测失败率低于 20% 才关闭。适用于偶发错误常见、误打开代价高的依赖
+	PresetConservative = "conservative"
+	// PresetLatencySensitive 把慢调用当作失败：单次调用 1 秒超时，成功但超过 500ms 的调用计为失败，
+	// 30 秒滚动窗口内至少 20 个请求且失败率达到 30% 时打开，15 秒后以 3 个探测请求尝试恢复；
+	// 按 10% 采样建立延迟基线，调用方剩余时间不足基线 P99 时不发起调用。适用于同步请求链路上的在线依赖
+	PresetLatencySensitive = "latency-sensitive"
+	// PresetBatch 容忍长耗时与突发错误：按最近 200 次调用计数，至少 50 个请求后连续 20 次失败或失败率达到 80% 时打开，
+	// 2 分钟后以 10 个探测请求尝试恢复；超时的调用按最终实际结果计数。适用于批处理、数据同步等离线任务
+	PresetBatch = "batch"
+)
+
+// presets 内置预设，以配置形式定义以便与配置文件共用合并逻辑
+var presets = map[string]BreakerConfig{
+	PresetAggressive: {
+		MaxRequests:     1,
+		WindowMode:      WindowRolling,
+		Interval:        Duration(10 * time.Second),
+		Timeout:         Duration(5 * time.Second),
+		MinimumRequests: 5,
+		Policies: []PolicyConfig{
+			{Type: PolicyConsecutiveFailures, Threshold: 3},
+			{Type: PolicyFailureRate, Threshold: 0.2},
+		},
+	},
+	PresetConservative: {
+		MaxRequests:      5,
+		WindowMode:       WindowRolling,
+		Interval:         Duration(60 * time.Second),
+		Timeout:          Duration(60 * time.Second),
+		MinimumRequests:  50,
+		CloseFailureRate: 0.2,
+		Policies: []PolicyConfig{
+			{Type: PolicyFailureRate, Threshold: 0.5},
+		},
+	},
+	PresetLatencySensitive: {
+		MaxRequests:       3,
+		WindowMode:        WindowRolling,
+		Interval:          Duration(30 * time.Second),
+		Timeout:           Duration(15 * time.Second),
+		CallTimeout:       Duration(time.Second),
+		LatencyBudget:     Duration(500 * time.Millisecond),
+		LatencySampleRate: 0.1,
+		DeadlineQuantile:  0.99,
+		MinimumRequests:   20,
+		Policies: []PolicyConfig{
+			{Type: PolicyFailureRate, Threshold: 0.3},
+		},
+	},
+	PresetBatch: {
+		MaxRequests:         10,
+		WindowMode:          WindowCountBased,
+		WindowSize:          200,
+		Timeout:             Duration(2 * time.Minute),
+		MinimumRequests:     50,
+		DeferTimeoutOutcome: true,
+		Policies: []PolicyConfig{
+			{Type: PolicyConsecutiveFailures, Threshold: 20},
+			{Type: PolicyFailureRate, Threshold: 0.8},
+		},
+	},
+}
+
+// Presets 返回内置预设名称（按名称排序）
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PresetSettings 返回在 DefaultSettings 之上应用预设的配置，可在此基础上继续修改；
+// 名称未定义时返回包装了 ErrUnknownPreset 的错误
+func PresetSettings(name string) (Settings, error) {
+	preset, ok := presets[name]
+	if !ok {
+		return Settings{}, fmt.Errorf("%w %q%s", ErrUnknownPreset, name, presetHint(name))
+	}
+	settings := DefaultSettings()
+	preset.apply(&settings)
+	return settings, nil
+}
+
+// presetHint 返回未知预设名称的提示：最接近的预设与可用预设列表
+func presetHint(name string) string {
+	names := Presets()
+	hint := ""
+	if s := suggest(name, names); s != "" {
+		hint = fmt.Sprintf(", did you mean %q?", s)
+	}
+	return fmt.Sprintf("%s (valid: %s)", hint, strings.Join(names, ", "))
+}
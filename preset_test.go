@@ -0,0 +1,102 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPresets(t *testing.T) {
+	want := []string{PresetAggressive, PresetBatch, PresetConservative, PresetLatencySensitive}
+	if got := Presets(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Presets() = %v, want %v", got, want)
+	}
+	for _, name := range Presets() {
+		settings, err := PresetSettings(name)
+		if err != nil {
+			t.Fatalf("PresetSettings(%q) error = %v", name, err)
+		}
+		if settings.ReadyToTrip == nil || settings.TripPolicyName == nil {
+			t.Errorf("%s: ReadyToTrip or TripPolicyName not set", name)
+		}
+		if w := settings.Warnings(); len(w) > 0 {
+			t.Errorf("%s: Warnings() = %v, want none", name, w)
+		}
+		if err := presets[name].validate(name); err != nil {
+			t.Errorf("%s: validate() = %v", name, err)
+		}
+	}
+}
+
+func TestPresetSettings_Unknown(t *testing.T) {
+	_, err := PresetSettings("agressive")
+	if !errors.Is(err, ErrUnknownPreset) {
+		t.Fatalf("PresetSettings() error = %v, want %v", err, ErrUnknownPreset)
+	}
+	if !strings.Contains(err.Error(), `did you mean "aggressive"?`) {
+		t.Errorf("error = %q, want a suggestion", err)
+	}
+}
+
+func TestPresetAggressive_Trips(t *testing.T) {
+	settings, err := PresetSettings(PresetAggressive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb := NewCircuitBreaker("test", settings)
+	for i := 0; i < 5; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	}
+	if got := ReasonOf(executeOK(cb)); got != ReasonOpen {
+		t.Fatalf("after 5 failures: reason = %v, want %v", got, ReasonOpen)
+	}
+	if got, ok := cb.LastTripCause(); !ok || got.Policy != PolicyConsecutiveFailures {
+		t.Errorf("LastTripCause() = %+v, want %q", got, PolicyConsecutiveFailures)
+	}
+}
+
+func executeOK(cb *CircuitBreaker) error {
+	_, err := cb.Execute(func() (interface{}, error) { return "ok", nil })
+	return err
+}
+
+func TestConfig_Preset(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{"version":2,
+		"defaults":{"preset":"conservative"},
+		"breakers":{
+			"payments":{"preset":"latency-sensitive","call_timeout":"300ms"},
+			"reports":{}
+		}}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	payments := cfg.Settings("payments")
+	if payments.CallTimeout != 300*time.Millisecond {
+		t.Errorf("payments CallTimeout = %v, want override 300ms", payments.CallTimeout)
+	}
+	if payments.LatencyBudget != 500*time.Millisecond || payments.Timeout != 15*time.Second {
+		t.Errorf("payments = LatencyBudget %v, Timeout %v, want latency-sensitive preset values", payments.LatencyBudget, payments.Timeout)
+	}
+
+	reports := cfg.Settings("reports")
+	want, _ := PresetSettings(PresetConservative)
+	if reports.Timeout != want.Timeout || reports.MinimumRequests != want.MinimumRequests || reports.CloseFailureRate != want.CloseFailureRate {
+		t.Errorf("reports = %+v, want conservative preset from defaults", reports)
+	}
+}
+
+func TestConfig_UnknownPreset(t *testing.T) {
+	_, err := ParseConfig([]byte(`{"version":2,"breakers":{"payments":{"preset":"latency_sensitive"}}}`))
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Path != "breakers.payments.preset" {
+		t.Fatalf("ParseConfig() error = %v, want ConfigError at breakers.payments.preset", err)
+	}
+	if !strings.Contains(err.Error(), `did you mean "latency-sensitive"?`) {
+		t.Errorf("error = %q, want a suggestion", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"github.com/sony/gobreaker"
+)
+
+// withPressure 在原有熔断判定前检查外部饱和度
+func withPressure(readyToTrip func(counts gobreaker.Counts) bool, pressure func() float64, max float64) func(counts gobreaker.Counts) bool {
+	if readyToTrip == nil {
+		readyToTrip = defaultReadyToTrip
+	}
+	return func(counts gobreaker.Counts) bool {
+		return pressure() >= max || readyToTrip(counts)
+	}
+}
+
+// tripOnPressure 关闭状态下外部饱和度达到 MaxPressure 时，在放行前以一次合成失败触发熔断，
+// 使饱和度升高但调用尚未失败时也能及时熔断（withPressure 据此判定打开）；调用方需持有 cb.mu 读锁
+func (cb *CircuitBreaker) tripOnPressure() {
+	settings := cb.settings
+	if settings.PressureFunc == nil || settings.MaxPressure <= 0 || cb.cb.State() != gobreaker.StateClosed {
+		return
+	}
+	if settings.PressureFunc() < settings.MaxPressure {
+		return
+	}
+	if done, err := cb.cb.Allow(); err == nil {
+		done(false)
+	}
+}
+
+// defaultReadyToTrip 与 gobreaker 默认策略一致：连续失败超过 5 次
+func defaultReadyToTrip(counts gobreaker.Counts) bool {
+	return counts.ConsecutiveFailures > 5
+}
+
+// RecordFailure 记录一次合成失败而不执行任何调用，
+// 用于将连接池耗尽、队列积压等外部饱和信号提前注入熔断器；熔断器拒绝时返回对应错误
+func (cb *CircuitBreaker) RecordFailure() error {
+	done, err := cb.Allow()
+	if err != nil {
+		return err
+	}
+	done(false)
+	return nil
+}
+
+// RecordSuccess 记录一次合成成功而不执行任何调用
+func (cb *CircuitBreaker) RecordSuccess() error {
+	done, err := cb.Allow()
+	if err != nil {
+		return err
+	}
+	done(true)
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestRecordFailure(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 2
+	}
+	cb := NewCircuitBreaker("test", settings)
+
+	for i := 0; i < 2; i++ {
+		if err := cb.RecordFailure(); err != nil {
+			t.Fatalf("RecordFailure() error = %v", err)
+		}
+	}
+
+	if state := cb.State(); state != gobreaker.StateOpen {
+		t.Errorf("State = %v, want %v", state, gobreaker.StateOpen)
+	}
+	if err := cb.RecordFailure(); !errors.Is(err, gobreaker.ErrOpenState) {
+		t.Errorf("RecordFailure() error = %v, want %v", err, gobreaker.ErrOpenState)
+	}
+}
+
+func TestRecordSuccess(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+
+	if err := cb.RecordSuccess(); err != nil {
+		t.Fatalf("RecordSuccess() error = %v", err)
+	}
+	if got := cb.Counts().TotalSuccesses; got != 1 {
+		t.Errorf("TotalSuccesses = %v, want %v", got, 1)
+	}
+}
+
+func TestPressureFunc(t *testing.T) {
+	pressure := 0.0
+	settings := DefaultSettings()
+	settings.PressureFunc = func() float64 { return pressure }
+	settings.MaxPressure = 0.9
+	cb := NewCircuitBreaker("test", settings)
+
+	fail := func() {
+		cb.Execute(func() (interface{}, error) {
+			return nil, errors.New("fail")
+		})
+	}
+
+	fail()
+	if state := cb.State(); state != gobreaker.StateClosed {
+		t.Fatalf("State = %v, want %v", state, gobreaker.StateClosed)
+	}
+
+	pressure = 0.95
+	fail()
+	if state := cb.State(); state != gobreaker.StateOpen {
+		t.Errorf("State under pressure = %v, want %v", state, gobreaker.StateOpen)
+	}
+}
+
+func TestPressureFunc_TripsBeforeFailures(t *testing.T) {
+	pressure := 0.0
+	settings := DefaultSettings()
+	settings.PressureFunc = func() float64 { return pressure }
+	settings.MaxPressure = 0.9
+	cb := NewCircuitBreaker("test", settings)
+
+	succeed := func() (interface{}, error) { return "ok", nil }
+	if _, err := cb.Execute(succeed); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// 饱和度升高后无需等待调用失败，下一次放行即被拒绝
+	pressure = 0.95
+	if _, err := cb.Execute(succeed); ReasonOf(err) != ReasonOpen {
+		t.Errorf("Execute() under pressure error = %v, want open rejection", err)
+	}
+	if cause, ok := cb.LastTripCause(); !ok || cause.Policy != TripByPressure {
+		t.Errorf("LastTripCause() = %+v, %v, want pressure", cause, ok)
+	}
+}
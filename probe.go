@@ -0,0 +1,147 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// ProbeLimiter 探测限速器，*rate.Limiter（golang.org/x/time/rate）满足该接口
+type ProbeLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// ProberSettings 后台探测配置
+type ProberSettings struct {
+	// Limiter 探测速率，如 rate.NewLimiter(rate.Every(time.Second), 1)，必填
+	Limiter ProbeLimiter
+	// Probe 探测函数，按 Settings 的错误分类判断成功与否，必填
+	Probe func(ctx context.Context) error
+	// RecoverAfter 连续探测成功达到该次数时关闭熔断器，默认 1
+	RecoverAfter uint32
+	// Timeout 单次探测超时，默认 5 秒
+	Timeout time.Duration
+}
+
+// ProberStats 探测统计
+type ProberStats struct {
+	Probes    uint64
+	Successes uint64
+	Failures  uint64
+}
+
+// Prober 熔断打开期间按限速器持续发起后台探测，连续成功后提前关闭熔断器，
+// 适用于始终繁忙、不希望等待整个 Timeout 才恢复的依赖；Timeout 到期后的半开流程照常生效
+type Prober struct {
+	breaker  *CircuitBreaker
+	settings ProberSettings
+
+	consecutive atomic.Uint32
+	probes      atomic.Uint64
+	successes   atomic.Uint64
+	failures    atomic.Uint64
+}
+
+// NewProber 创建后台探测器，需调用 Run 启动
+func NewProber(cb *CircuitBreaker, settings ProberSettings) *Prober {
+	if settings.RecoverAfter == 0 {
+		settings.RecoverAfter = 1
+	}
+	if settings.Timeout <= 0 {
+		settings.Timeout = 5 * time.Second
+	}
+	return &Prober{breaker: cb, settings: settings}
+}
+
+// Run 持续运行直至 ctx 结束：熔断器打开时按限速器探测，其余时间等待熔断器打开
+// OpenFor 保持期内遵循下游背压，不探测
+func (p *Prober) Run(ctx context.Context) {
+	opened := make(chan struct{}, 1)
+	remove := p.breaker.addListener(func(name string, from, to gobreaker.State) {
+		if to == gobreaker.StateOpen {
+			select {
+			case opened <- struct{}{}:
+			default:
+			}
+		}
+	})
+	defer remove()
+
+	for {
+		if !p.probing() {
+			p.consecutive.Store(0)
+			wait := time.Until(p.breaker.OpenUntil())
+			if wait <= 0 {
+				wait = time.Hour
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-opened:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+
+		if err := p.settings.Limiter.Wait(ctx); err != nil {
+			return
+		}
+		if p.probing() {
+			p.probe(ctx)
+		}
+	}
+}
+
+// probing 是否需要探测：底层熔断器处于打开状态且不在 OpenFor 保持期
+func (p *Prober) probing() bool {
+	cb := p.breaker
+	return !cb.heldOpen() && cb.State() == gobreaker.StateOpen
+}
+
+// probe 执行一次探测，连续成功达到阈值时关闭熔断器
+func (p *Prober) probe(ctx context.Context) {
+	p.probes.Add(1)
+	probeCtx, cancel := context.WithTimeout(ctx, p.settings.Timeout)
+	defer cancel()
+
+	switch p.breaker.GetSettings().Classify(p.settings.Probe(probeCtx)) {
+	case OutcomeSuccess:
+		p.successes.Add(1)
+		if p.consecutive.Add(1) >= p.settings.RecoverAfter && p.breaker.reset(false) {
+			p.consecutive.Store(0)
+		}
+	case OutcomeFailure:
+		p.failures.Add(1)
+		p.consecutive.Store(0)
+	}
+}
+
+// Stats 返回探测统计
+func (p *Prober) Stats() ProberStats {
+	return ProberStats{
+		Probes:    p.probes.Load(),
+		Successes: p.successes.Load(),
+		Failures:  p.failures.Load(),
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/sony/gobreaker"
+)
+
+// ErrProbeBudgetExhausted 一组熔断器共享的半开探测名额已满，errors.Is 同样匹配 gobreaker.ErrTooManyRequests
+var ErrProbeBudgetExhausted = fmt.Errorf("circuitbreaker: probe budget exhausted: %w", gobreaker.ErrTooManyRequests)
+
+// ProbeBudget 限制一组熔断器同时进行的半开探测总数：大量按键熔断器（如 Partitioned 的各分区）
+// 同时恢复时，避免各自的半开探测叠加冲击共享的下游服务。通过 Settings.ProbeBudget 在熔断器间共享，
+// 名额已满时半开请求以 ReasonHalfOpenLimit 被拒绝
+type ProbeBudget struct {
+	max      int64
+	active   atomic.Int64
+	peak     atomic.Int64
+	rejected atomic.Uint64
+}
+
+// ProbeBudgetStats 探测名额统计
+type ProbeBudgetStats struct {
+	// Max 同时进行的探测上限
+	Max int
+	// Active 正在进行的探测数
+	Active int
+	// Peak 历史最大同时探测数
+	Peak int
+	// Rejected 因名额已满被拒绝的半开请求数
+	Rejected uint64
+}
+
+// NewProbeBudget 创建探测名额，max 小于 1 时按 1 处理
+func NewProbeBudget(max int) *ProbeBudget {
+	if max < 1 {
+		max = 1
+	}
+	return &ProbeBudget{max: int64(max)}
+}
+
+// acquire 占用一个名额，已满时返回 false
+func (b *ProbeBudget) acquire() bool {
+	for {
+		n := b.active.Load()
+		if n >= b.max {
+			b.rejected.Add(1)
+			return false
+		}
+		if b.active.CompareAndSwap(n, n+1) {
+			for {
+				peak := b.peak.Load()
+				if n+1 <= peak || b.peak.CompareAndSwap(peak, n+1) {
+					return true
+				}
+			}
+		}
+	}
+}
+
+// release 归还一个名额
+func (b *ProbeBudget) release() {
+	b.active.Add(-1)
+}
+
+// Stats 返回探测名额统计
+func (b *ProbeBudget) Stats() ProbeBudgetStats {
+	return ProbeBudgetStats{
+		Max:      int(b.max),
+		Active:   int(b.active.Load()),
+		Peak:     int(b.peak.Load()),
+		Rejected: b.rejected.Load(),
+	}
+}
+
+// probeSlots 熔断器从 ProbeBudget 占用的名额：state 高 32 位为半开轮次，低 32 位为本轮占用数。
+// 离开半开状态或重建底层熔断器时一次性归还本轮全部名额，结果被忽略而未上报的探测不会泄漏名额
+type probeSlots struct {
+	budget atomic.Pointer[ProbeBudget]
+	state  atomic.Uint64
+}
+
+// acquire 占用一个名额，返回所属轮次
+func (s *probeSlots) acquire(budget *ProbeBudget) (round uint32, ok bool) {
+	if !budget.acquire() {
+		return 0, false
+	}
+	s.budget.Store(budget)
+	return uint32(s.state.Add(1) >> 32), true
+}
+
+// release 归还 round 轮次占用的一个名额，该轮已结束时忽略
+func (s *probeSlots) release(round uint32) {
+	for {
+		v := s.state.Load()
+		if uint32(v>>32) != round || uint32(v) == 0 {
+			return
+		}
+		if s.state.CompareAndSwap(v, v-1) {
+			s.budget.Load().release()
+			return
+		}
+	}
+}
+
+// releaseAll 结束当前轮次并归还全部名额，在 gobreaker 内部锁中调用，只使用原子操作
+func (s *probeSlots) releaseAll() {
+	for {
+		v := s.state.Load()
+		if s.state.CompareAndSwap(v, (v>>32+1)<<32) {
+			if n := uint32(v); n > 0 {
+				s.budget.Load().active.Add(-int64(n))
+			}
+			return
+		}
+	}
+}
+
+// allowProbe 半开状态下从 Settings.ProbeBudget 占用名额后放行，上报结果时归还；调用方需持有读锁
+func (cb *CircuitBreaker) allowProbe(budget *ProbeBudget) (func(success bool), func(), error) {
+	round, ok := cb.probeSlots.acquire(budget)
+	if !ok {
+		return nil, nil, ErrProbeBudgetExhausted
+	}
+	done, neutral, err := cb.allowBackend()
+	if err != nil {
+		cb.probeSlots.release(round)
+		return nil, nil, err
+	}
+	if neutral != nil {
+		inner := neutral
+		neutral = func() {
+			cb.probeSlots.release(round)
+			inner()
+		}
+	}
+	return func(success bool) {
+		cb.probeSlots.release(round)
+		done(success)
+	}, neutral, nil
+}
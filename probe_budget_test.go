@@ -0,0 +1,105 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// halfOpenPartitions 返回共享 budget 且均已进入半开状态的分区熔断器
+func halfOpenPartitions(t *testing.T, budget *ProbeBudget, maxRequests uint32, labels ...string) []*CircuitBreaker {
+	t.Helper()
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	settings.MaxRequests = maxRequests
+	settings.Timeout = 10 * time.Millisecond
+	settings.ProbeBudget = budget
+	p := NewPartitioned("tenant", settings)
+
+	var cbs []*CircuitBreaker
+	for _, label := range labels {
+		cb := p.Partition(label)
+		cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+		cbs = append(cbs, cb)
+	}
+	for _, cb := range cbs {
+		waitFor(t, func() bool { return cb.State() == gobreaker.StateHalfOpen })
+	}
+	return cbs
+}
+
+func TestProbeBudget_LimitsGroupProbes(t *testing.T) {
+	budget := NewProbeBudget(1)
+	cbs := halfOpenPartitions(t, budget, 1, "a", "b")
+
+	done, err := cbs[0].Allow()
+	if err != nil {
+		t.Fatalf("Allow() first probe error = %v", err)
+	}
+	_, err = cbs[1].Allow()
+	if !errors.Is(err, ErrProbeBudgetExhausted) || !errors.Is(err, gobreaker.ErrTooManyRequests) || ReasonOf(err) != ReasonHalfOpenLimit {
+		t.Fatalf("Allow() over budget error = %v, want ErrProbeBudgetExhausted", err)
+	}
+	if got := budget.Stats(); got != (ProbeBudgetStats{Max: 1, Active: 1, Peak: 1, Rejected: 1}) {
+		t.Errorf("Stats() = %+v", got)
+	}
+
+	done(true)
+	if got := cbs[0].State(); got != gobreaker.StateClosed {
+		t.Errorf("State() after probe = %v, want closed", got)
+	}
+	if _, err := cbs[1].Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("Execute() after slot released error = %v", err)
+	}
+	if got := budget.Stats().Active; got != 0 {
+		t.Errorf("Active = %d, want 0", got)
+	}
+}
+
+func TestProbeBudget_ReleasedWhenLeavingHalfOpen(t *testing.T) {
+	budget := NewProbeBudget(4)
+	cb := halfOpenPartitions(t, budget, 2, "a")[0]
+
+	first, _ := cb.Allow()
+	second, err := cb.Allow()
+	if err != nil {
+		t.Fatalf("Allow() second probe error = %v", err)
+	}
+	// 第一个探测失败使熔断器重新打开，未上报的第二个探测名额随之归还
+	first(false)
+	if got := budget.Stats().Active; got != 0 {
+		t.Errorf("Active after reopen = %d, want 0", got)
+	}
+	second(true)
+	if got := budget.Stats().Active; got != 0 {
+		t.Errorf("Active after late report = %d, want 0", got)
+	}
+
+	waitFor(t, func() bool { return cb.State() == gobreaker.StateHalfOpen })
+	if _, err := cb.Allow(); err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	cb.Reset()
+	if got := budget.Stats(); got.Active != 0 || got.Peak != 2 {
+		t.Errorf("Stats() after Reset = %+v, want no active probes, peak 2", got)
+	}
+}
+
+func TestProbeBudget_NotUsedWhenClosed(t *testing.T) {
+	budget := NewProbeBudget(1)
+	settings := DefaultSettings()
+	settings.ProbeBudget = budget
+	cb := NewCircuitBreaker("closed", settings)
+	for i := 0; i < 3; i++ {
+		if _, err := cb.Allow(); err != nil {
+			t.Fatalf("Allow() error = %v", err)
+		}
+	}
+	if got := budget.Stats(); got.Peak != 0 {
+		t.Errorf("Stats() = %+v, want closed calls outside the budget", got)
+	}
+}
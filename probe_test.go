@@ -0,0 +1,82 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// tickLimiter 每次 Wait 固定等待 interval 的测试限速器
+type tickLimiter struct {
+	interval time.Duration
+}
+
+func (l tickLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(l.interval):
+		return nil
+	}
+}
+
+func TestProber_RecoversOpenBreaker(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	settings.Timeout = time.Hour
+	cb := NewCircuitBreaker("test", settings)
+
+	var healthy atomic.Bool
+	p := NewProber(cb, ProberSettings{
+		Limiter:      tickLimiter{interval: time.Millisecond},
+		RecoverAfter: 2,
+		Probe: func(ctx context.Context) error {
+			if healthy.Load() {
+				return nil
+			}
+			return errors.New("still down")
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+	waitFor(t, func() bool { return p.Stats().Failures >= 2 })
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+
+	healthy.Store(true)
+	waitFor(t, func() bool { return cb.State() == gobreaker.StateClosed })
+	if got := p.Stats().Successes; got < 2 {
+		t.Errorf("Successes = %v, want >= 2", got)
+	}
+}
+
+func TestProber_IdleWhileClosedOrHeld(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+	p := NewProber(cb, ProberSettings{
+		Limiter: tickLimiter{interval: time.Millisecond},
+		Probe: func(ctx context.Context) error {
+			t.Error("probe should not run")
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	cb.OpenFor(time.Minute)
+	p.Run(ctx)
+
+	if got := p.Stats().Probes; got != 0 {
+		t.Errorf("Probes = %v, want 0", got)
+	}
+}
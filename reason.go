@@ -0,0 +1,181 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// ErrDeadlineTooShort 调用方剩余时间不足 DeadlineOverhead，请求未发起即被拒绝
+var ErrDeadlineTooShort = errors.New("circuitbreaker: deadline too short")
+
+// Reason 请求被拒绝的原因，可映射为不同的 HTTP 状态码与指标标签
+type Reason string
+
+const (
+	// ReasonNone 非拒绝错误
+	ReasonNone Reason = ""
+	// ReasonOpen 熔断器打开
+	ReasonOpen Reason = "OPEN"
+	// ReasonHalfOpenLimit 半开状态探测名额已满
+	ReasonHalfOpenLimit Reason = "HALF_OPEN_LIMIT"
+	// ReasonDeadlineTooShort 调用方剩余时间不足
+	ReasonDeadlineTooShort Reason = "DEADLINE_TOO_SHORT"
+	// ReasonForced 人工强制打开
+	ReasonForced Reason = "FORCED"
+	// ReasonOverload 本地资源过载（SystemBreaker）
+	ReasonOverload Reason = "OVERLOAD"
+)
+
+// RejectionError 熔断器拒绝请求时返回的错误，Unwrap 返回底层错误（如 gobreaker.ErrOpenState）
+type RejectionError struct {
+	// Breaker 拒绝请求的熔断器名称
+	Breaker string
+	// Reason 拒绝原因
+	Reason Reason
+	// Err 底层错误
+	Err error
+	// RetryAt 熔断器打开时预计转为半开、可再次探测的时间（按熔断器的 Clock），其他原因或未知时为零值
+	RetryAt time.Time
+
+	// now 拒绝请求的熔断器的时间源，RetryAfter 据此计算剩余时长；为空时使用 time.Now
+	now func() time.Time
+}
+
+// Error 实现 error，与底层错误信息一致
+func (e *RejectionError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap 返回底层错误
+func (e *RejectionError) Unwrap() error {
+	return e.Err
+}
+
+// ReasonOf 返回错误对应的拒绝原因，非拒绝错误返回 ReasonNone
+// 未经 RejectionError 包装的 gobreaker 错误同样可以识别
+func ReasonOf(err error) Reason {
+	var rejection *RejectionError
+	switch {
+	case errors.As(err, &rejection):
+		return rejection.Reason
+	case errors.Is(err, ErrForcedOpen):
+		return ReasonForced
+	case errors.Is(err, gobreaker.ErrOpenState):
+		return ReasonOpen
+	case errors.Is(err, gobreaker.ErrTooManyRequests):
+		return ReasonHalfOpenLimit
+	case errors.Is(err, ErrDeadlineTooShort):
+		return ReasonDeadlineTooShort
+	case errors.Is(err, ErrSystemOverload):
+		return ReasonOverload
+	default:
+		return ReasonNone
+	}
+}
+
+// RetryAfter 返回拒绝错误建议的重试等待时长，可用于 HTTP Retry-After 等；
+// 非拒绝错误、重试时间未知或已过时返回 false
+func RetryAfter(err error) (time.Duration, bool) {
+	var rejection *RejectionError
+	if !errors.As(err, &rejection) || rejection.RetryAt.IsZero() {
+		return 0, false
+	}
+	now := time.Now
+	if rejection.now != nil {
+		now = rejection.now
+	}
+	if d := rejection.RetryAt.Sub(now()); d > 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+// rejectionReasons 分别计数的拒绝原因
+var rejectionReasons = [...]Reason{
+	ReasonOpen, ReasonHalfOpenLimit, ReasonDeadlineTooShort, ReasonForced, ReasonOverload,
+}
+
+// rejections 被熔断器拒绝、未实际执行的调用计数（自创建起累计），与 Counts 中已执行的失败分开统计
+type rejections struct {
+	counts [len(rejectionReasons)]atomic.Uint64
+}
+
+// record 按原因记录一次拒绝
+func (r *rejections) record(reason Reason) {
+	for i, known := range rejectionReasons {
+		if known == reason {
+			r.counts[i].Add(1)
+			return
+		}
+	}
+}
+
+// snapshot 返回拒绝总数与按原因的计数，没有拒绝时 byReason 为 nil
+func (r *rejections) snapshot() (total uint64, byReason map[Reason]uint64) {
+	for i := range r.counts {
+		n := r.counts[i].Load()
+		if n == 0 {
+			continue
+		}
+		if byReason == nil {
+			byReason = make(map[Reason]uint64)
+		}
+		byReason[rejectionReasons[i]] = n
+		total += n
+	}
+	return total, byReason
+}
+
+// reject 将拒绝错误包装为 RejectionError
+func reject(name string, err error) error {
+	return &RejectionError{Breaker: name, Reason: ReasonOf(err), Err: err}
+}
+
+// rejectOpen 与 reject 相同，打开与强制打开的拒绝附带 RetryAt，调用方需持有读锁
+func (cb *CircuitBreaker) rejectOpen(err error) error {
+	rejection := &RejectionError{Breaker: cb.name, Reason: ReasonOf(err), Err: err}
+	if rejection.Reason == ReasonOpen || rejection.Reason == ReasonForced {
+		if state, until := cb.openStateLocked(); state == gobreaker.StateOpen {
+			rejection.RetryAt = until
+			rejection.now = cb.now
+		}
+	}
+	return rejection
+}
+
+// checkBudget 调用方剩余时间不足 DeadlineOverhead，或扣除后低于 DeadlineQuantile 分位数时返回 ErrDeadlineTooShort，
+// 调用方需持有读锁
+func (cb *CircuitBreaker) checkBudget(ctx context.Context) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	remaining := time.Until(deadline)
+	if overhead := cb.settings.DeadlineOverhead; overhead > 0 {
+		if remaining <= overhead {
+			return reject(cb.name, ErrDeadlineTooShort)
+		}
+		remaining -= overhead
+	}
+	return cb.checkDeadlineQuantile(remaining)
+}
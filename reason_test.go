@@ -0,0 +1,115 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestReasonOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Reason
+	}{
+		{nil, ReasonNone},
+		{errors.New("boom"), ReasonNone},
+		{gobreaker.ErrOpenState, ReasonOpen},
+		{gobreaker.ErrTooManyRequests, ReasonHalfOpenLimit},
+		{fmt.Errorf("wrapped: %w", ErrDeadlineTooShort), ReasonDeadlineTooShort},
+		{ErrSystemOverload, ReasonOverload},
+		{&RejectionError{Reason: ReasonForced, Err: errors.New("forced open")}, ReasonForced},
+	}
+
+	for _, tt := range tests {
+		if got := ReasonOf(tt.err); got != tt.want {
+			t.Errorf("ReasonOf(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestExecute_RejectionError(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	cb := NewCircuitBreaker("payments", settings)
+	cb.Execute(func() (interface{}, error) {
+		return nil, errors.New("fail")
+	})
+
+	_, err := cb.Execute(func() (interface{}, error) {
+		return nil, nil
+	})
+	var rejection *RejectionError
+	if !errors.As(err, &rejection) {
+		t.Fatalf("Execute() error = %T, want *RejectionError", err)
+	}
+	if rejection.Breaker != "payments" || rejection.Reason != ReasonOpen {
+		t.Errorf("RejectionError = %+v, want payments %v", rejection, ReasonOpen)
+	}
+	if !errors.Is(err, gobreaker.ErrOpenState) || err.Error() != gobreaker.ErrOpenState.Error() {
+		t.Errorf("Execute() error = %v, want %v", err, gobreaker.ErrOpenState)
+	}
+}
+
+func TestExecuteContext_DeadlineTooShort(t *testing.T) {
+	settings := DefaultSettings()
+	settings.DeadlineOverhead = 50 * time.Millisecond
+	cb := NewCircuitBreaker("test", settings)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	called := false
+	_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+
+	if ReasonOf(err) != ReasonDeadlineTooShort {
+		t.Errorf("ReasonOf() = %q, want %q", ReasonOf(err), ReasonDeadlineTooShort)
+	}
+	if called {
+		t.Error("fn should not be called")
+	}
+	if _, err := cb.AllowContext(ctx); !errors.Is(err, ErrDeadlineTooShort) {
+		t.Errorf("AllowContext() error = %v, want %v", err, ErrDeadlineTooShort)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if _, ok := RetryAfter(errors.New("plain")); ok {
+		t.Error("RetryAfter(plain error) ok = true")
+	}
+	if _, ok := RetryAfter(&RejectionError{Reason: ReasonHalfOpenLimit, Err: gobreaker.ErrTooManyRequests}); ok {
+		t.Error("RetryAfter without RetryAt ok = true")
+	}
+	if _, ok := RetryAfter(&RejectionError{Reason: ReasonOpen, Err: gobreaker.ErrOpenState, RetryAt: time.Now().Add(-time.Second)}); ok {
+		t.Error("RetryAfter with past RetryAt ok = true")
+	}
+	err := fmt.Errorf("wrapped: %w", &RejectionError{Reason: ReasonOpen, Err: gobreaker.ErrOpenState, RetryAt: time.Now().Add(time.Minute)})
+	if d, ok := RetryAfter(err); !ok || d <= 59*time.Second {
+		t.Errorf("RetryAfter() = %v, %v, want ~1m", d, ok)
+	}
+}
+
+func TestRetryAfter_UsesBreakerClock(t *testing.T) {
+	clock := &manualClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	settings := DefaultSettings()
+	settings.Clock = clock
+	settings.Timeout = 30 * time.Second
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	cb := NewCircuitBreaker("test", settings)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+
+	clock.Advance(10 * time.Second)
+	_, err := cb.Execute(func() (interface{}, error) { return nil, nil })
+	if d, ok := RetryAfter(err); !ok || d != 20*time.Second {
+		t.Errorf("RetryAfter() = %v, %v, want 20s by the breaker clock", d, ok)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// RecoveryEvent 熔断器在一次较长的故障后恢复关闭的事件
+type RecoveryEvent struct {
+	// Breaker 熔断器名称
+	Breaker string
+	// OpenedAt 本次故障首次打开的时间
+	OpenedAt time.Time
+	// ClosedAt 恢复关闭的时间
+	ClosedAt time.Time
+	// Outage 从首次打开到关闭的时长，期间半开探测失败后重新打开不会重新计时
+	Outage time.Duration
+	// Trips 本次故障期间进入打开状态的次数
+	Trips int
+}
+
+// outage 进行中的一次故障
+type outage struct {
+	cb       *CircuitBreaker
+	openedAt time.Time
+	trips    int
+}
+
+// OnRecovery 订阅注册表内熔断器的恢复事件：熔断器关闭且本次故障持续不短于 minOutage 时，
+// 在新的协程中调用 fn，应用可在其中主动刷新故障期间变得陈旧的缓存数据（fn 可以阻塞，也可以调用熔断器的方法）。
+// 只跟踪订阅之后开始的故障，时间按熔断器的 Settings.Clock 记录；打开期间被移除的熔断器不再跟踪。
+// 返回取消订阅函数
+func (r *Registry) OnRecovery(minOutage time.Duration, fn func(RecoveryEvent)) func() {
+	var (
+		mu      sync.Mutex
+		outages = make(map[string]*outage)
+	)
+	unsubscribe := r.subscribe("", func(cb *CircuitBreaker, from, to gobreaker.State) {
+		now := cb.now()
+		name := cb.Name()
+		mu.Lock()
+		defer mu.Unlock()
+		// 打开期间被移除（或被同名熔断器替换）的熔断器不会再关闭，清理其记录
+		for key, o := range outages {
+			if o.cb.removed.Load() || (key == name && o.cb != cb) {
+				delete(outages, key)
+			}
+		}
+		switch to {
+		case gobreaker.StateOpen:
+			o, ok := outages[name]
+			if !ok {
+				o = &outage{cb: cb, openedAt: now}
+				outages[name] = o
+			}
+			o.trips++
+		case gobreaker.StateClosed:
+			o, ok := outages[name]
+			if !ok {
+				return
+			}
+			delete(outages, name)
+			if d := now.Sub(o.openedAt); d >= minOutage {
+				// 订阅回调在熔断器内部锁中执行，刷新缓存等耗时操作放到独立协程
+				go fn(RecoveryEvent{Breaker: name, OpenedAt: o.openedAt, ClosedAt: now, Outage: d, Trips: o.trips})
+			}
+		}
+	})
+	return func() {
+		unsubscribe()
+		mu.Lock()
+		defer mu.Unlock()
+		clear(outages)
+	}
+}
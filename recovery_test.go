@@ -0,0 +1,126 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// manualClock 测试用时间源，circuitbreakertest 依赖本包，这里不能引入
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// recoveringBreaker 在 r 中创建按 clock 计时、单次失败即打开的熔断器
+func recoveringBreaker(r *Registry, clock *manualClock) *CircuitBreaker {
+	settings := DefaultSettings()
+	settings.Clock = clock
+	settings.MaxRequests = 1
+	settings.Timeout = 20 * time.Second
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	return r.GetOrCreate("catalog", settings)
+}
+
+func recoveryFail() (interface{}, error) { return nil, errors.New("fail") }
+
+func recoveryOK() (interface{}, error) { return "ok", nil }
+
+func TestRegistry_OnRecovery(t *testing.T) {
+	r := NewRegistry()
+	clock := &manualClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cb := recoveringBreaker(r, clock)
+
+	events := make(chan RecoveryEvent, 1)
+	defer r.OnRecovery(30*time.Second, func(ev RecoveryEvent) { events <- ev })()
+	long := make(chan RecoveryEvent, 1)
+	defer r.OnRecovery(time.Hour, func(ev RecoveryEvent) { long <- ev })()
+
+	openedAt := clock.Now()
+	cb.Execute(recoveryFail)
+	clock.Advance(25 * time.Second)
+	// 半开探测失败，重新打开但不重新计时
+	cb.Execute(recoveryFail)
+	clock.Advance(25 * time.Second)
+	cb.Execute(recoveryOK)
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Fatalf("State() = %v, want closed", got)
+	}
+
+	select {
+	case ev := <-events:
+		want := RecoveryEvent{Breaker: "catalog", OpenedAt: openedAt, ClosedAt: clock.Now(), Outage: 50 * time.Second, Trips: 2}
+		if ev != want {
+			t.Errorf("event = %+v, want %+v", ev, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no recovery event")
+	}
+	select {
+	case ev := <-long:
+		t.Errorf("event %+v delivered to subscriber with longer minimum outage", ev)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestRegistry_OnRecoveryShortOutage(t *testing.T) {
+	r := NewRegistry()
+	clock := &manualClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cb := recoveringBreaker(r, clock)
+
+	events := make(chan RecoveryEvent, 1)
+	defer r.OnRecovery(time.Minute, func(ev RecoveryEvent) { events <- ev })()
+
+	cb.Execute(recoveryFail)
+	clock.Advance(20 * time.Second)
+	cb.Execute(recoveryOK)
+
+	select {
+	case ev := <-events:
+		t.Errorf("event = %+v, want none for an outage shorter than the minimum", ev)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestRegistry_OnRecoveryForgetsRemovedBreakers(t *testing.T) {
+	r := NewRegistry()
+	clock := &manualClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	events := make(chan RecoveryEvent, 1)
+	defer r.OnRecovery(0, func(ev RecoveryEvent) { events <- ev })()
+
+	recoveringBreaker(r, clock).Execute(recoveryFail)
+	r.Remove("catalog")
+
+	// 打开期间被移除的熔断器不再关闭，同名熔断器重新注册后不应沿用其打开时间
+	cb := recoveringBreaker(r, clock)
+	clock.Advance(time.Hour)
+	cb.Execute(recoveryFail)
+	clock.Advance(20 * time.Second)
+	cb.Execute(recoveryOK)
+
+	select {
+	case ev := <-events:
+		if ev.Outage != 20*time.Second || ev.Trips != 1 {
+			t.Errorf("event = %+v, want an outage of 20s with 1 trip", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no recovery event")
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sony/gobreaker"
+)
+
+// ErrAlreadyRegistered 同名熔断器已注册
+var ErrAlreadyRegistered = errors.New("circuitbreaker: breaker already registered")
+
+// Registry 熔断器注册表，按名称管理一组熔断器（通常每个下游依赖一个）
+type Registry struct {
+	// shards 按名称哈希分片，每次请求都调用 GetOrCreate 的大量按 key 熔断器只竞争所在分片的锁
+	shards [registryShards]registryShard
+	seed   maphash.Seed
+
+	// observers 使用独立的锁，避免与熔断器监听器锁形成环
+	obsMu     sync.RWMutex
+	observers map[uint64]observer
+	nextID    uint64
+	hooks     atomic.Pointer[HookRunner]
+
+	// sched 共享调度器，见 Scheduler
+	schedOnce sync.Once
+	sched     *Scheduler
+}
+
+// observer 状态变更订阅者，tier 为空时接收所有级别
+type observer struct {
+	tier Tier
+	fn   func(cb *CircuitBreaker, from, to gobreaker.State)
+}
+
+// registryShards 注册表分片数
+const registryShards = 64
+
+// registryShard 注册表分片，缓存行填充避免相邻分片的锁互相失效
+type registryShard struct {
+	mu       sync.RWMutex
+	breakers map[string]*CircuitBreaker
+	detach   map[string]func()
+	_        [64]byte
+}
+
+// NewRegistry 创建新的注册表
+func NewRegistry() *Registry {
+	r := &Registry{
+		seed:      maphash.MakeSeed(),
+		observers: make(map[uint64]observer),
+	}
+	for i := range r.shards {
+		r.shards[i].breakers = make(map[string]*CircuitBreaker)
+		r.shards[i].detach = make(map[string]func())
+	}
+	return r
+}
+
+// shard 返回 name 所在分片，哈希计算不分配内存
+func (r *Registry) shard(name string) *registryShard {
+	return &r.shards[maphash.String(r.seed, name)%registryShards]
+}
+
+// all 返回所有熔断器的快照，顺序不定
+func (r *Registry) all() []*CircuitBreaker {
+	var breakers []*CircuitBreaker
+	for i := range r.shards {
+		s := &r.shards[i]
+		s.mu.RLock()
+		for _, cb := range s.breakers {
+			breakers = append(breakers, cb)
+		}
+		s.mu.RUnlock()
+	}
+	return breakers
+}
+
+// Register 注册已有的熔断器，同名熔断器已存在时返回 ErrAlreadyRegistered
+func (r *Registry) Register(cb *CircuitBreaker) error {
+	s := r.shard(cb.Name())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.breakers[cb.Name()]; ok {
+		return ErrAlreadyRegistered
+	}
+	r.add(s, cb)
+	return nil
+}
+
+// GetOrCreate 获取指定名称的熔断器，不存在时使用 settings 创建
+func (r *Registry) GetOrCreate(name string, settings Settings) *CircuitBreaker {
+	s := r.shard(name)
+	s.mu.RLock()
+	cb, ok := s.breakers[name]
+	s.mu.RUnlock()
+	if ok {
+		return cb
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cb, ok := s.breakers[name]; ok {
+		return cb
+	}
+	cb = NewCircuitBreaker(name, settings)
+	r.add(s, cb)
+	return cb
+}
+
+// add 加入熔断器并转发其状态变更，调用方需持有分片写锁
+func (r *Registry) add(s *registryShard, cb *CircuitBreaker) {
+	cb.removed.Store(false)
+	s.breakers[cb.Name()] = cb
+	s.detach[cb.Name()] = cb.addListener(func(name string, from, to gobreaker.State) {
+		r.notify(cb, from, to)
+	})
+}
+
+// Get 获取指定名称的熔断器
+func (r *Registry) Get(name string) (*CircuitBreaker, bool) {
+	s := r.shard(name)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cb, ok := s.breakers[name]
+	return cb, ok
+}
+
+// Remove 移除指定名称的熔断器；开启 Settings.Strict 的熔断器移除后继续调用会 panic
+func (r *Registry) Remove(name string) {
+	s := r.shard(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if detach, ok := s.detach[name]; ok {
+		detach()
+	}
+	if cb, ok := s.breakers[name]; ok {
+		cb.removed.Store(true)
+	}
+	delete(s.breakers, name)
+	delete(s.detach, name)
+}
+
+// Names 返回所有熔断器名称（已排序）
+func (r *Registry) Names() []string {
+	breakers := r.all()
+	names := make([]string, 0, len(breakers))
+	for _, cb := range breakers {
+		names = append(names, cb.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Subscribe 订阅注册表内所有熔断器的状态变更，返回取消订阅函数
+// 回调在熔断器内部锁中同步调用，不可阻塞，也不可回调同一熔断器的方法；设置 SetHookRunner 后改为异步调用
+func (r *Registry) Subscribe(fn func(name string, from, to gobreaker.State)) func() {
+	return r.subscribe("", byName(fn))
+}
+
+// byName 将按名称接收的回调适配为订阅者回调
+func byName(fn func(name string, from, to gobreaker.State)) func(cb *CircuitBreaker, from, to gobreaker.State) {
+	return func(cb *CircuitBreaker, from, to gobreaker.State) { fn(cb.Name(), from, to) }
+}
+
+// subscribe 添加订阅者，tier 为空时接收所有级别；回调直接收到熔断器，无需在其内部锁中查询注册表
+func (r *Registry) subscribe(tier Tier, fn func(cb *CircuitBreaker, from, to gobreaker.State)) func() {
+	r.obsMu.Lock()
+	defer r.obsMu.Unlock()
+
+	r.nextID++
+	id := r.nextID
+	r.observers[id] = observer{tier: tier, fn: fn}
+
+	return func() {
+		r.obsMu.Lock()
+		defer r.obsMu.Unlock()
+		delete(r.observers, id)
+	}
+}
+
+// SetHookRunner 设置执行订阅者回调的工作池，之后的状态变更通知均异步分发；nil 恢复为同步调用
+func (r *Registry) SetHookRunner(h *HookRunner) {
+	r.hooks.Store(h)
+}
+
+// notify 将状态变更分发给匹配级别的订阅者
+func (r *Registry) notify(cb *CircuitBreaker, from, to gobreaker.State) {
+	r.obsMu.RLock()
+	defer r.obsMu.RUnlock()
+	hooks := r.hooks.Load()
+	tier := cb.Tier()
+	for _, obs := range r.observers {
+		if obs.tier != "" && obs.tier != tier {
+			continue
+		}
+		if hooks != nil {
+			fn := obs.fn
+			hooks.Submit(cb.Name(), func(context.Context) { fn(cb, from, to) })
+			continue
+		}
+		obs.fn(cb, from, to)
+	}
+}
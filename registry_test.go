@@ -0,0 +1,152 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestRegistry_GetOrCreate(t *testing.T) {
+	r := NewRegistry()
+
+	cb1 := r.GetOrCreate("svc", DefaultSettings())
+	cb2 := r.GetOrCreate("svc", DefaultSettings())
+
+	if cb1 != cb2 {
+		t.Error("GetOrCreate should return the same breaker for the same name")
+	}
+	if got, ok := r.Get("svc"); !ok || got != cb1 {
+		t.Errorf("Get() = %v, %v, want %v, true", got, ok, cb1)
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+
+	if err := r.Register(NewCircuitBreaker("svc", DefaultSettings())); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	err := r.Register(NewCircuitBreaker("svc", DefaultSettings()))
+	if !errors.Is(err, ErrAlreadyRegistered) {
+		t.Errorf("Register() error = %v, want %v", err, ErrAlreadyRegistered)
+	}
+}
+
+func TestRegistry_NamesAndRemove(t *testing.T) {
+	r := NewRegistry()
+	r.GetOrCreate("b", DefaultSettings())
+	r.GetOrCreate("a", DefaultSettings())
+
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Names() = %v, want %v", got, []string{"a", "b"})
+	}
+
+	r.Remove("a")
+	if _, ok := r.Get("a"); ok {
+		t.Error("Get() after Remove should return false")
+	}
+}
+
+func TestRegistry_Subscribe(t *testing.T) {
+	r := NewRegistry()
+	settings := DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+	cb := r.GetOrCreate("svc", settings)
+
+	var got []gobreaker.State
+	unsubscribe := r.Subscribe(func(name string, from, to gobreaker.State) {
+		if name == "svc" {
+			got = append(got, to)
+		}
+	})
+
+	cb.Execute(func() (interface{}, error) {
+		return nil, errors.New("fail")
+	})
+	unsubscribe()
+
+	if len(got) != 1 || got[0] != gobreaker.StateOpen {
+		t.Errorf("transitions = %v, want [%v]", got, gobreaker.StateOpen)
+	}
+}
+
+func TestRegistry_ConcurrentGetOrCreate(t *testing.T) {
+	r := NewRegistry()
+	keys := benchmarkKeys(500)
+	got := make([][]*CircuitBreaker, 8)
+
+	var wg sync.WaitGroup
+	for g := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, key := range keys {
+				got[g] = append(got[g], r.GetOrCreate(key, DefaultSettings()))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for g := 1; g < len(got); g++ {
+		for i := range keys {
+			if got[g][i] != got[0][i] {
+				t.Fatalf("GetOrCreate(%q) returned different breakers across goroutines", keys[i])
+			}
+		}
+	}
+	if n := len(r.Names()); n != len(keys) {
+		t.Errorf("len(Names()) = %d, want %d", n, len(keys))
+	}
+	r.Remove(keys[0])
+	if _, ok := r.Get(keys[0]); ok {
+		t.Errorf("Get(%q) after Remove found a breaker", keys[0])
+	}
+}
+
+// benchmarkKeys 代理场景下的按 key 熔断器名称
+func benchmarkKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "upstream-" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func BenchmarkRegistry_GetOrCreate(b *testing.B) {
+	keys := benchmarkKeys(50000)
+	r := NewRegistry()
+	for _, key := range keys {
+		r.GetOrCreate(key, DefaultSettings())
+	}
+	settings := DefaultSettings()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			r.GetOrCreate(keys[i%len(keys)], settings)
+			i += 7919
+		}
+	})
+}
+
+func BenchmarkRegistry_GetOrCreateCold(b *testing.B) {
+	keys := benchmarkKeys(b.N)
+	r := NewRegistry()
+	settings := DefaultSettings()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, key := range keys {
+		r.GetOrCreate(key, settings)
+	}
+}
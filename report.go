@@ -0,0 +1,105 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/sony/gobreaker"
+)
+
+// seriesPerBreaker 每个熔断器导出的指标序列数估算：
+// 状态（每个状态一条）3、窗口计数 5、并发与峰值 2、泄漏调用 1、超时后结果 2、
+// 超时上限 2、P99 耗时 1、健康评分 1
+const seriesPerBreaker = 17
+
+// backendSize gobreaker 实例的估算大小（TwoStep 包装与内部熔断器）
+const backendSize = int64(unsafe.Sizeof(gobreaker.TwoStepCircuitBreaker{})) + 160
+
+// TrafficEntry 单个熔断器的流量
+type TrafficEntry struct {
+	Name string
+	// Requests 当前统计窗口内的请求数
+	Requests uint32
+}
+
+// Report 注册表的容量与基数报告，用于发现命名过细导致的熔断器数量与指标基数膨胀
+type Report struct {
+	// Breakers 熔断器数量
+	Breakers int
+	// EstimatedBytes 熔断器占用内存的估算值
+	EstimatedBytes int64
+	// MetricSeries 导出指标的序列数估算值
+	MetricSeries int
+	// ByPrefix 按名称前缀（首个 ":" 或 "/" 之前的部分）统计的熔断器数量，
+	// 某个前缀数量异常时通常意味着名称中包含了 ID、URL 参数等高基数字段
+	ByPrefix map[string]int
+	// TopByTraffic 当前窗口请求数最多的熔断器（降序）
+	TopByTraffic []TrafficEntry
+}
+
+// Report 生成注册表报告，topN 为 TopByTraffic 的条数
+func (r *Registry) Report(topN int) Report {
+	names := r.Names()
+	report := Report{ByPrefix: make(map[string]int)}
+	traffic := make([]TrafficEntry, 0, len(names))
+
+	for _, name := range names {
+		cb, ok := r.Get(name)
+		if !ok {
+			continue
+		}
+		report.Breakers++
+		report.EstimatedBytes += cb.footprint()
+		report.ByPrefix[namePrefix(name)]++
+		traffic = append(traffic, TrafficEntry{Name: name, Requests: cb.Counts().Requests})
+	}
+	report.MetricSeries = report.Breakers * seriesPerBreaker
+
+	sort.SliceStable(traffic, func(i, j int) bool { return traffic[i].Requests > traffic[j].Requests })
+	if topN < len(traffic) {
+		traffic = traffic[:topN]
+	}
+	report.TopByTraffic = traffic
+	return report
+}
+
+// footprint 估算单个熔断器占用的内存
+func (cb *CircuitBreaker) footprint() int64 {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	size := int64(unsafe.Sizeof(*cb)) + backendSize + int64(len(cb.name))
+	for k, v := range cb.settings.Labels {
+		size += int64(len(k) + len(v))
+	}
+
+	cb.listenerMu.RLock()
+	size += int64(len(cb.listeners)) * 48
+	cb.listenerMu.RUnlock()
+	return size
+}
+
+// namePrefix 返回名称前缀，无分隔符时返回整个名称
+func namePrefix(name string) string {
+	if i := strings.IndexAny(name, ":/"); i > 0 {
+		return name[:i]
+	}
+	return name
+}
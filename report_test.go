@@ -0,0 +1,38 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+func TestRegistry_Report(t *testing.T) {
+	r := NewRegistry()
+	for i := 0; i < 5; i++ {
+		r.GetOrCreate(fmt.Sprintf("dial:10.0.0.%d:80", i), DefaultSettings())
+	}
+	busy := r.GetOrCreate("payments/charge", DefaultSettings())
+	for i := 0; i < 3; i++ {
+		busy.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	r.GetOrCreate("plain", DefaultSettings())
+
+	report := r.Report(2)
+	if report.Breakers != 7 {
+		t.Errorf("Breakers = %v, want 7", report.Breakers)
+	}
+	if report.MetricSeries != 7*seriesPerBreaker {
+		t.Errorf("MetricSeries = %v, want %v", report.MetricSeries, 7*seriesPerBreaker)
+	}
+	if min := int64(7 * unsafe.Sizeof(CircuitBreaker{})); report.EstimatedBytes < min {
+		t.Errorf("EstimatedBytes = %v, want >= %v", report.EstimatedBytes, min)
+	}
+	if report.ByPrefix["dial"] != 5 || report.ByPrefix["payments"] != 1 || report.ByPrefix["plain"] != 1 {
+		t.Errorf("ByPrefix = %v", report.ByPrefix)
+	}
+	if len(report.TopByTraffic) != 2 || report.TopByTraffic[0] != (TrafficEntry{Name: "payments/charge", Requests: 3}) {
+		t.Errorf("TopByTraffic = %+v", report.TopByTraffic)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrSlowCall 调用成功但耗时超过 Settings.LatencyBudget，按失败计数时记录在错误样本中，不返回给调用方
+	ErrSlowCall = errors.New("circuitbreaker: call exceeded latency budget")
+	// ErrResultTooLarge 调用成功但结果超过 Settings.MaxResultSize，按失败计数时记录在错误样本中，不返回给调用方
+	ErrResultTooLarge = errors.New("circuitbreaker: result exceeds size limit")
+)
+
+// Sizer 结果可报告自身大小时实现该接口，供 DefaultResultSize 使用
+type Sizer interface {
+	Size() int64
+}
+
+// DefaultResultSize 默认的结果大小计算：支持 []byte、string、Sizer 与带 Len() int 的类型
+// （如 *bytes.Buffer、*strings.Reader），其他类型返回 -1 表示未知，不参与 MaxResultSize 检查
+func DefaultResultSize(result interface{}) int64 {
+	switch r := result.(type) {
+	case []byte:
+		return int64(len(r))
+	case string:
+		return int64(len(r))
+	case Sizer:
+		return r.Size()
+	case interface{ Len() int }:
+		return int64(r.Len())
+	default:
+		return -1
+	}
+}
+
+// guardResult 对已分类为成功的调用应用 LatencyBudget 与 MaxResultSize：
+// 降级的依赖可能以极大、极慢的响应"成功"返回，这类调用同样按失败计数，返回分类结果与记入错误样本的错误
+func (cb *CircuitBreaker) guardResult(settings *Settings, outcome Outcome, err error, result interface{}, start time.Time) (Outcome, error) {
+	if outcome != OutcomeSuccess {
+		return outcome, err
+	}
+	if settings.LatencyBudget > 0 {
+		if elapsed := time.Since(start); elapsed > settings.LatencyBudget {
+			cb.slowCalls.Add(1)
+			return OutcomeFailure, fmt.Errorf("%w: took %v, budget %v", ErrSlowCall, elapsed, settings.LatencyBudget)
+		}
+	}
+	if settings.MaxResultSize > 0 {
+		size := settings.resultSize(result)
+		if size > settings.MaxResultSize {
+			cb.oversizedResults.Add(1)
+			return OutcomeFailure, fmt.Errorf("%w: %d bytes, limit %d", ErrResultTooLarge, size, settings.MaxResultSize)
+		}
+	}
+	return outcome, err
+}
+
+// resultSize 返回结果大小，未设置 ResultSize 时使用 DefaultResultSize
+func (s *Settings) resultSize(result interface{}) int64 {
+	if s.ResultSize != nil {
+		return s.ResultSize(result)
+	}
+	return DefaultResultSize(result)
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecute_MaxResultSize(t *testing.T) {
+	settings := DefaultSettings()
+	settings.MaxResultSize = 4
+	settings.ErrorSamples = 1
+	cb := NewCircuitBreaker("test", settings)
+
+	result, err := cb.Execute(func() (interface{}, error) { return []byte("too large"), nil })
+	if err != nil || string(result.([]byte)) != "too large" {
+		t.Fatalf("Execute() = %v, %v, want result passed through", result, err)
+	}
+	cb.Execute(func() (interface{}, error) { return "ok", nil })
+
+	stats := cb.Stats()
+	if stats.Counts.TotalFailures != 1 || stats.Counts.TotalSuccesses != 1 {
+		t.Errorf("Counts = %+v, want 1 failure and 1 success", stats.Counts)
+	}
+	if stats.OversizedResults != 1 {
+		t.Errorf("OversizedResults = %v, want %v", stats.OversizedResults, 1)
+	}
+	if len(stats.ErrorSamples) != 1 || !strings.Contains(stats.ErrorSamples[0].Error, ErrResultTooLarge.Error()) {
+		t.Errorf("ErrorSamples = %+v, want %v", stats.ErrorSamples, ErrResultTooLarge)
+	}
+}
+
+func TestExecuteContext_LatencyBudget(t *testing.T) {
+	settings := DefaultSettings()
+	settings.LatencyBudget = 10 * time.Millisecond
+	settings.CallTimeout = time.Second
+	cb := NewCircuitBreaker("test", settings)
+
+	result, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return "slow", nil
+	})
+	if err != nil || result != "slow" {
+		t.Fatalf("ExecuteContext() = %v, %v, want slow, nil", result, err)
+	}
+
+	stats := cb.Stats()
+	if stats.Counts.TotalFailures != 1 {
+		t.Errorf("TotalFailures = %v, want %v", stats.Counts.TotalFailures, 1)
+	}
+	if stats.SlowCalls != 1 {
+		t.Errorf("SlowCalls = %v, want %v", stats.SlowCalls, 1)
+	}
+}
+
+func TestAllow_LatencyBudget(t *testing.T) {
+	settings := DefaultSettings()
+	settings.LatencyBudget = 10 * time.Millisecond
+	cb := NewCircuitBreaker("test", settings)
+
+	done, err := cb.Allow()
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	done(true)
+
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %v, want %v", got, 1)
+	}
+}
+
+func TestDefaultResultSize(t *testing.T) {
+	tests := []struct {
+		result interface{}
+		want   int64
+	}{
+		{[]byte("abc"), 3},
+		{"abcd", 4},
+		{bytes.NewBufferString("ab"), 2},
+		{42, -1},
+		{nil, -1},
+	}
+	for _, tt := range tests {
+		if got := DefaultResultSize(tt.result); got != tt.want {
+			t.Errorf("DefaultResultSize(%v) = %v, want %v", tt.result, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxSampleErrorLen 错误样本中错误信息的最大长度（字节），截断时不拆分 UTF-8 字符
+const maxSampleErrorLen = 256
+
+// ErrorSample 一次失败调用的错误样本
+type ErrorSample struct {
+	// Error 错误信息，超长时截断
+	Error string
+	// StatusCode 协议状态码（如 HTTP 状态码），未标注时为 0
+	StatusCode int
+	// Peer 对端地址，未标注时为空
+	Peer string
+	// At 记录时间
+	At time.Time
+}
+
+// AnnotatedError 为错误附加请求信息，失败时作为错误样本的状态码与对端地址
+// 不影响结果分类：errors.Is/As 与 MatchErrorTypes 均透过它匹配被包装的错误
+type AnnotatedError struct {
+	Err        error
+	StatusCode int
+	Peer       string
+}
+
+func (e *AnnotatedError) Error() string { return e.Err.Error() }
+
+func (e *AnnotatedError) Unwrap() error { return e.Err }
+
+// Annotate 为错误附加状态码与对端地址，err 为 nil 时返回 nil
+func Annotate(err error, statusCode int, peer string) error {
+	if err == nil {
+		return nil
+	}
+	return &AnnotatedError{Err: err, StatusCode: statusCode, Peer: peer}
+}
+
+// errorSamples 最近失败调用的错误样本环形缓冲，熔断器恢复关闭时清空，
+// 因此保留的是当前故障周期内最近的样本
+type errorSamples struct {
+	mu      sync.Mutex
+	samples []ErrorSample
+	next    int
+}
+
+// add 记录一次失败的样本，limit 为保留的样本数上限，不大于 0 时不记录
+func (s *errorSamples) add(limit int, sample ErrorSample) {
+	if limit <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.samples) > limit {
+		// 上限调小后保留最近的样本
+		s.samples = append([]ErrorSample(nil), s.snapshotLocked()[len(s.samples)-limit:]...)
+		s.next = 0
+	}
+	if len(s.samples) < limit {
+		s.samples = append(s.samples, sample)
+		return
+	}
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % limit
+}
+
+// newErrorSample 由错误构造 at 时刻的样本，提取 AnnotatedError 中的状态码与对端地址
+func newErrorSample(err error, at time.Time) ErrorSample {
+	sample := ErrorSample{Error: err.Error(), At: at}
+	if len(sample.Error) > maxSampleErrorLen {
+		cut := maxSampleErrorLen
+		for cut > 0 && !utf8.RuneStart(sample.Error[cut]) {
+			cut--
+		}
+		sample.Error = sample.Error[:cut]
+	}
+	var annotated *AnnotatedError
+	if errors.As(err, &annotated) {
+		sample.StatusCode, sample.Peer = annotated.StatusCode, annotated.Peer
+	}
+	return sample
+}
+
+// snapshot 按时间顺序返回样本副本
+func (s *errorSamples) snapshot() []ErrorSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshotLocked()
+}
+
+func (s *errorSamples) snapshotLocked() []ErrorSample {
+	if len(s.samples) == 0 {
+		return nil
+	}
+	out := make([]ErrorSample, 0, len(s.samples))
+	out = append(out, s.samples[s.next:]...)
+	return append(out, s.samples[:s.next]...)
+}
+
+// clear 清空样本
+func (s *errorSamples) clear() {
+	s.mu.Lock()
+	s.samples, s.next = nil, 0
+	s.mu.Unlock()
+}
+
+// sample 失败结果记录错误样本，并作为刚触发的打开原因的错误，返回 outcome 便于链式调用
+func (cb *CircuitBreaker) sample(settings *Settings, outcome Outcome, err error) Outcome {
+	if outcome == OutcomeFailure && err != nil {
+		sample := newErrorSample(err, cb.now())
+		cb.errorSamples.add(settings.ErrorSamples, sample)
+		cb.attachTripError(sample)
+	}
+	return outcome
+}
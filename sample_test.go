@@ -0,0 +1,152 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestErrorSamples(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ErrorSamples = 2
+	settings.ReadyToTrip = ConsecutiveFailures(3)
+	settings.MaxRequests = 1
+	settings.Timeout = 20 * time.Millisecond
+	cb := NewCircuitBreaker("samples", settings)
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("first") })
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	cb.ExecuteContext(context.Background(), func(context.Context) (interface{}, error) {
+		return nil, Annotate(errors.New("second"), 503, "10.0.0.1:80")
+	})
+	done, _ := cb.AllowContext(context.Background())
+	done(errors.New(strings.Repeat("x", 1000)))
+
+	samples := cb.Stats().ErrorSamples
+	if len(samples) != 2 {
+		t.Fatalf("len(ErrorSamples) = %d, want 2", len(samples))
+	}
+	if s := samples[0]; s.Error != "second" || s.StatusCode != 503 || s.Peer != "10.0.0.1:80" || s.At.IsZero() {
+		t.Errorf("ErrorSamples[0] = %+v", s)
+	}
+	if len(samples[1].Error) != maxSampleErrorLen {
+		t.Errorf("len(ErrorSamples[1].Error) = %d, want truncated to %d", len(samples[1].Error), maxSampleErrorLen)
+	}
+
+	// 熔断后恢复关闭时清空样本
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("trip") })
+	if cb.State() != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want open", cb.State())
+	}
+	waitFor(t, func() bool { return cb.State() == gobreaker.StateHalfOpen })
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	if got := cb.Stats().ErrorSamples; got != nil {
+		t.Errorf("ErrorSamples after close = %+v, want nil", got)
+	}
+}
+
+func TestErrorSamples_ClearedByReset(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ErrorSamples = 4
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	cb := NewCircuitBreaker("reset", settings)
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("trip") })
+	if cb.State() != gobreaker.StateOpen || len(cb.Stats().ErrorSamples) != 1 {
+		t.Fatalf("State() = %v, ErrorSamples = %+v, want open with 1 sample", cb.State(), cb.Stats().ErrorSamples)
+	}
+	cb.Reset()
+	if got := cb.Stats().ErrorSamples; got != nil {
+		t.Errorf("ErrorSamples after Reset = %+v, want nil", got)
+	}
+
+}
+
+func TestTripCause_SealedByReset(t *testing.T) {
+	pressure := 1.0
+	settings := DefaultSettings()
+	settings.ErrorSamples = 4
+	settings.PressureFunc = func() float64 { return pressure }
+	settings.MaxPressure = 0.5
+	cb := NewCircuitBreaker("reset", settings)
+
+	// 饱和度触发的打开没有失败调用，打开原因等待关联错误
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	if cause := cb.Stats().LastTripCause; cb.State() != gobreaker.StateOpen || cause == nil || cause.Error != nil {
+		t.Fatalf("State() = %v, LastTripCause = %+v, want open by pressure", cb.State(), cause)
+	}
+	cb.Reset()
+	pressure = 0
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("later") })
+	if cause := cb.Stats().LastTripCause; cause == nil || cause.Error != nil {
+		t.Errorf("LastTripCause after Reset = %+v, want no error attached", cause)
+	}
+}
+
+func TestNewErrorSample(t *testing.T) {
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg := strings.Repeat("x", maxSampleErrorLen-1) + "错误"
+	s := newErrorSample(errors.New(msg), at)
+	if !s.At.Equal(at) {
+		t.Errorf("At = %v, want %v", s.At, at)
+	}
+	if !utf8.ValidString(s.Error) || s.Error != strings.Repeat("x", maxSampleErrorLen-1) {
+		t.Errorf("Error = %q, want truncated before the split rune", s.Error)
+	}
+}
+
+func TestErrorSamples_DisabledAndIgnored(t *testing.T) {
+	settings := DefaultSettings()
+	cb := NewCircuitBreaker("off", settings)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	if got := cb.Stats().ErrorSamples; got != nil {
+		t.Errorf("ErrorSamples with sampling disabled = %+v", got)
+	}
+
+	ignored := errors.New("ignored")
+	settings.ErrorSamples = 4
+	settings.IgnoreErrors = []ErrorMatcher{MatchErrors(ignored)}
+	cb = NewCircuitBreaker("ignored", settings)
+	cb.Execute(func() (interface{}, error) { return nil, Annotate(ignored, 404, "") })
+	if got := cb.Stats().ErrorSamples; got != nil {
+		t.Errorf("ErrorSamples for ignored error = %+v", got)
+	}
+}
+
+func TestErrorSamples_ShrinkLimit(t *testing.T) {
+	var s errorSamples
+	for i := 0; i < 5; i++ {
+		s.add(4, ErrorSample{Error: fmt.Sprintf("e%d", i)})
+	}
+	s.add(2, ErrorSample{Error: "e5"})
+
+	var got []string
+	for _, sample := range s.snapshot() {
+		got = append(got, sample.Error)
+	}
+	if strings.Join(got, ",") != "e4,e5" {
+		t.Errorf("samples = %v, want [e4 e5]", got)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	if Annotate(nil, 500, "peer") != nil {
+		t.Error("Annotate(nil) != nil")
+	}
+	base := errors.New("base")
+	err := Annotate(base, 500, "peer")
+	if !errors.Is(err, base) || err.Error() != "base" {
+		t.Errorf("Annotate() = %v, want wrapping base", err)
+	}
+	if !MatchErrorTypes("*errors.errorString")(err) {
+		t.Error("MatchErrorTypes does not see through AnnotatedError")
+	}
+}
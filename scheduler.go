@@ -0,0 +1,222 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sync"
+	"time"
+)
+
+// 调度器默认参数
+const (
+	// DefaultSchedulerTick 时间轮默认刻度，任务的触发精度不高于该值
+	DefaultSchedulerTick = 100 * time.Millisecond
+	// DefaultSchedulerSlots 时间轮默认槽数
+	DefaultSchedulerSlots = 512
+)
+
+// Scheduler 基于哈希时间轮的共享调度器：所有任务由同一个协程按刻度推进执行，
+// 任务数量只占用内存而不占用协程与定时器，适用于为上万个按 key 创建的熔断器做周期统计或上报。
+// 没有任务时协程自动退出，添加任务时再启动。
+// 任务在调度协程中依次执行，须快速返回；耗时操作（如网络上报）应自行转交其他协程
+type Scheduler struct {
+	tick time.Duration
+
+	mu      sync.Mutex
+	slots   [][]*scheduledTask
+	cursor  int
+	tasks   int
+	running bool
+	closed  bool
+	stop    chan struct{}
+}
+
+// scheduledTask 时间轮中的任务，字段由 Scheduler.mu 保护
+type scheduledTask struct {
+	fn       func(now time.Time)
+	interval time.Duration
+	rounds   int
+	done     bool
+}
+
+// NewScheduler 创建调度器，tick 为刻度（默认 DefaultSchedulerTick），slots 为槽数（默认 DefaultSchedulerSlots）；
+// 不再使用时调用 Close
+func NewScheduler(tick time.Duration, slots int) *Scheduler {
+	if tick <= 0 {
+		tick = DefaultSchedulerTick
+	}
+	if slots <= 0 {
+		slots = DefaultSchedulerSlots
+	}
+	return &Scheduler{tick: tick, slots: make([][]*scheduledTask, slots)}
+}
+
+// Every 每隔 interval 执行一次 fn，首次在 interval 之后；返回的函数取消任务，可重复调用
+func (s *Scheduler) Every(interval time.Duration, fn func(now time.Time)) (cancel func()) {
+	if interval < s.tick {
+		interval = s.tick
+	}
+	return s.add(&scheduledTask{fn: fn, interval: interval}, interval)
+}
+
+// After 在 d 之后执行一次 fn；返回的函数在执行前取消任务
+func (s *Scheduler) After(d time.Duration, fn func(now time.Time)) (cancel func()) {
+	return s.add(&scheduledTask{fn: fn}, d)
+}
+
+// Len 返回尚未取消的任务数
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tasks
+}
+
+// Close 停止调度并丢弃所有任务，之后添加的任务不会执行
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for i, slot := range s.slots {
+		for _, t := range slot {
+			t.done = true
+		}
+		s.slots[i] = nil
+	}
+	s.tasks = 0
+	if s.running {
+		close(s.stop)
+		s.running = false
+	}
+}
+
+// add 放入任务并按需启动调度协程
+func (s *Scheduler) add(t *scheduledTask, delay time.Duration) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return func() {}
+	}
+	s.placeLocked(t, delay)
+	s.tasks++
+	if !s.running {
+		s.running = true
+		s.stop = make(chan struct{})
+		go s.run(s.stop)
+	}
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !t.done {
+			t.done = true
+			s.tasks--
+		}
+	}
+}
+
+// placeLocked 将任务放入 delay 之后的槽，超过一圈的部分记为剩余圈数
+func (s *Scheduler) placeLocked(t *scheduledTask, delay time.Duration) {
+	ticks := int((delay + s.tick - 1) / s.tick)
+	if ticks < 1 {
+		ticks = 1
+	}
+	n := len(s.slots)
+	t.rounds = (ticks - 1) / n
+	slot := (s.cursor + ticks) % n
+	s.slots[slot] = append(s.slots[slot], t)
+}
+
+// run 按刻度推进时间轮，调度滞后时补齐错过的刻度；没有任务时退出
+func (s *Scheduler) run(stop chan struct{}) {
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+	start, advanced := time.Now(), 0
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			target := int(now.Sub(start) / s.tick)
+			for ; advanced < target; advanced++ {
+				due, idle := s.advance(stop)
+				for _, fn := range due {
+					fn(now)
+				}
+				if idle {
+					return
+				}
+			}
+		}
+	}
+}
+
+// advance 推进一个刻度，返回到期的任务；periodic 任务在执行前重新放入时间轮。
+// idle 表示已没有任务且调度协程应退出
+func (s *Scheduler) advance(stop chan struct{}) (due []func(time.Time), idle bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != stop {
+		// 已被 Close 或被新的调度协程取代
+		return nil, true
+	}
+	s.cursor = (s.cursor + 1) % len(s.slots)
+	slot := s.slots[s.cursor]
+	s.slots[s.cursor] = nil
+	var keep []*scheduledTask
+	for _, t := range slot {
+		switch {
+		case t.done:
+		case t.rounds > 0:
+			t.rounds--
+			keep = append(keep, t)
+		case t.interval > 0:
+			due = append(due, t.fn)
+			s.placeLocked(t, t.interval)
+		default:
+			due = append(due, t.fn)
+			t.done = true
+			s.tasks--
+		}
+	}
+	// 重新放入的周期任务可能落在当前槽（interval 恰为整圈），需与保留的任务合并
+	s.slots[s.cursor] = append(keep, s.slots[s.cursor]...)
+	if s.tasks == 0 && len(due) == 0 {
+		s.running = false
+		return nil, true
+	}
+	return due, false
+}
+
+// Scheduler 返回注册表共享的调度器，首次调用时创建（默认刻度与槽数）
+func (r *Registry) Scheduler() *Scheduler {
+	r.schedOnce.Do(func() {
+		r.sched = NewScheduler(0, 0)
+	})
+	return r.sched
+}
+
+// EveryBreaker 每隔 interval 对注册表中的每个熔断器执行一次 fn，所有熔断器共用注册表调度器中的一个任务，
+// 不为每个熔断器创建协程或定时器；fn 在调度协程中执行，须快速返回
+func (r *Registry) EveryBreaker(interval time.Duration, fn func(cb *CircuitBreaker, now time.Time)) (cancel func()) {
+	return r.Scheduler().Every(interval, func(now time.Time) {
+		for _, cb := range r.all() {
+			fn(cb, now)
+		}
+	})
+}
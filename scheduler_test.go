@@ -0,0 +1,104 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Every(t *testing.T) {
+	s := NewScheduler(time.Millisecond, 8)
+	defer s.Close()
+
+	var runs atomic.Int32
+	cancel := s.Every(5*time.Millisecond, func(time.Time) { runs.Add(1) })
+	waitFor(t, func() bool { return runs.Load() >= 3 })
+
+	cancel()
+	cancel()
+	if got := s.Len(); got != 0 {
+		t.Errorf("Len() after cancel = %d, want 0", got)
+	}
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	if got := runs.Load(); got > stopped+1 {
+		t.Errorf("runs after cancel = %d, want at most %d", got, stopped+1)
+	}
+}
+
+func TestScheduler_AfterSpansRounds(t *testing.T) {
+	s := NewScheduler(time.Millisecond, 4)
+	defer s.Close()
+
+	start := time.Now()
+	fired := make(chan time.Duration, 1)
+	s.After(20*time.Millisecond, func(now time.Time) { fired <- now.Sub(start) })
+	cancelled := s.After(time.Millisecond, func(time.Time) { t.Error("cancelled task ran") })
+	cancelled()
+
+	select {
+	case elapsed := <-fired:
+		if elapsed < 20*time.Millisecond {
+			t.Errorf("After fired after %v, want at least 20ms", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("After did not fire")
+	}
+	waitFor(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return !s.running
+	})
+}
+
+func TestScheduler_CloseDropsTasks(t *testing.T) {
+	s := NewScheduler(time.Millisecond, 8)
+	var runs atomic.Int32
+	s.Every(time.Millisecond, func(time.Time) { runs.Add(1) })
+	s.Close()
+	s.Every(time.Millisecond, func(time.Time) { runs.Add(1) })
+
+	if got := s.Len(); got != 0 {
+		t.Errorf("Len() after Close = %d, want 0", got)
+	}
+	stopped := runs.Load()
+	time.Sleep(10 * time.Millisecond)
+	if got := runs.Load(); got != stopped {
+		t.Errorf("runs after Close = %d, want %d", got, stopped)
+	}
+}
+
+func TestRegistry_EveryBreaker(t *testing.T) {
+	r := NewRegistry()
+	for _, name := range []string{"a", "b", "c"} {
+		r.GetOrCreate(name, DefaultSettings())
+	}
+	if r.Scheduler() != r.Scheduler() {
+		t.Fatal("Scheduler() returned different instances")
+	}
+	defer r.Scheduler().Close()
+
+	var calls atomic.Int32
+	seen := make(chan string, 16)
+	cancel := r.EveryBreaker(time.Millisecond, func(cb *CircuitBreaker, now time.Time) {
+		if calls.Add(1) <= 3 {
+			seen <- cb.Name()
+		}
+	})
+	defer cancel()
+
+	names := map[string]bool{}
+	for len(names) < 3 {
+		select {
+		case name := <-seen:
+			names[name] = true
+		case <-time.After(time.Second):
+			t.Fatalf("visited %v, want a, b and c", names)
+		}
+	}
+	if got := r.Scheduler().Len(); got != 1 {
+		t.Errorf("Len() = %d, want one task for all breakers", got)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sony/gobreaker"
+)
+
+// PolicySLOBurnRate 按 SLO 错误预算消耗速率熔断的具名策略，Threshold 为 BurnRate，
+// params 为 {"target": 0.999, "min_requests": 100}，统计窗口即熔断器的 interval
+const PolicySLOBurnRate = "slo_burn_rate"
+
+func init() {
+	RegisterTripPolicy(PolicySLOBurnRate, func(p PolicyConfig) (TripPolicy, error) {
+		var params struct {
+			Target      float64 `json:"target"`
+			MinRequests uint32  `json:"min_requests"`
+		}
+		if len(p.Params) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(p.Params))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&params); err != nil {
+				return nil, fmt.Errorf("params: %w", err)
+			}
+		}
+		slo := SLO{Target: params.Target, BurnRate: p.Threshold, MinRequests: params.MinRequests}
+		if err := slo.validate(); err != nil {
+			return nil, err
+		}
+		return SLOBurnRate(slo), nil
+	})
+}
+
+// SLO 可用性目标及触发熔断的错误预算消耗速率
+// 统计窗口为 Settings.Interval：按多窗口告警的惯例，短窗口（如 5 分钟）配合较高的 BurnRate（如 14.4）
+type SLO struct {
+	// Target 可用性目标，如 0.999
+	Target float64
+	// BurnRate 触发熔断的消耗速率，1 表示恰好在 SLO 周期结束时耗尽错误预算，
+	// 14.4 表示按当前速率约 1/14.4 个周期（30 天周期约 2 天）即耗尽
+	BurnRate float64
+	// MinRequests 窗口请求数达到该值才评估，避免低流量时单次失败即触发
+	MinRequests uint32
+}
+
+// validate 校验 SLO 参数
+func (s SLO) validate() error {
+	if s.Target <= 0 || s.Target >= 1 {
+		return fmt.Errorf("target must be in (0, 1), got %v", s.Target)
+	}
+	if s.BurnRate <= 0 {
+		return fmt.Errorf("burn rate must be positive, got %v", s.BurnRate)
+	}
+	return nil
+}
+
+// Burn 返回窗口内的错误预算消耗速率：失败率 / (1 - Target)，无请求时为 0
+func (s SLO) Burn(counts gobreaker.Counts) float64 {
+	if counts.Requests == 0 || s.Target >= 1 {
+		return 0
+	}
+	return failureRate(counts) / (1 - s.Target)
+}
+
+// SLOBurnRate 返回错误预算消耗速率不低于 slo.BurnRate 时满足的策略，
+// 使熔断阈值与已定义的 SLO 保持一致，而不是单独维护失败次数阈值
+func SLOBurnRate(slo SLO) TripPolicy {
+	return func(counts gobreaker.Counts) bool {
+		// 1 - Target 的浮点误差会使恰好达到阈值的速率略低于 BurnRate，比较时留出相对误差
+		return counts.Requests > 0 && counts.Requests >= slo.MinRequests && slo.Burn(counts) >= slo.BurnRate*(1-1e-9)
+	}
+}
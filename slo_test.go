@@ -0,0 +1,68 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestSLOBurnRate(t *testing.T) {
+	slo := SLO{Target: 0.99, BurnRate: 10, MinRequests: 100}
+	policy := SLOBurnRate(slo)
+
+	tests := []struct {
+		name   string
+		counts gobreaker.Counts
+		want   bool
+	}{
+		{"no requests", gobreaker.Counts{}, false},
+		{"below minimum", gobreaker.Counts{Requests: 50, TotalFailures: 50}, false},
+		{"burn 5x", gobreaker.Counts{Requests: 200, TotalFailures: 10}, false},
+		{"burn 10x", gobreaker.Counts{Requests: 200, TotalFailures: 20}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy(tt.counts); got != tt.want {
+				t.Errorf("SLOBurnRate()(%+v) = %v, want %v (burn %v)", tt.counts, got, tt.want, slo.Burn(tt.counts))
+			}
+		})
+	}
+
+	if got := slo.Burn(gobreaker.Counts{Requests: 1000, TotalFailures: 5}); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Burn() = %v, want 0.5", got)
+	}
+}
+
+func TestSLOBurnRate_Config(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{
+  "version": 2,
+  "breakers": {
+    "checkout": {
+      "interval": "5m",
+      "policies": [{"type": "slo_burn_rate", "threshold": 14.4, "params": {"target": 0.999, "min_requests": 10}}]
+    }
+  }
+}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	trip := cfg.Settings("checkout").ReadyToTrip
+	if trip(gobreaker.Counts{Requests: 1000, TotalFailures: 14}) || !trip(gobreaker.Counts{Requests: 1000, TotalFailures: 15}) {
+		t.Error("slo_burn_rate policy does not trip at 14.4x burn of a 99.9% target")
+	}
+
+	for _, policy := range []string{
+		`{"type": "slo_burn_rate", "threshold": 14.4, "params": {"target": 1}}`,
+		`{"type": "slo_burn_rate", "threshold": 0, "params": {"target": 0.99}}`,
+		`{"type": "slo_burn_rate", "threshold": 2, "params": {"target": 0.99, "windw": "1h"}}`,
+	} {
+		_, err := ParseConfig([]byte(`{"version": 2, "breakers": {"x": {"policies": [` + policy + `]}}}`))
+		if err == nil || !strings.Contains(err.Error(), "breakers.x.policies[0]") {
+			t.Errorf("ParseConfig(%s) error = %v, want policy error", policy, err)
+		}
+	}
+}
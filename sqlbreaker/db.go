@@ -0,0 +1,101 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package sqlbreaker 提供 database/sql 的熔断器包装
+package sqlbreaker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// IsFailure 默认失败判定：sql.ErrNoRows 与调用方取消不计为失败
+func IsFailure(err error) bool {
+	return err != nil &&
+		!errors.Is(err, sql.ErrNoRows) &&
+		!errors.Is(err, context.Canceled)
+}
+
+// failure 将 IsFailure 的判定转换为用于熔断分类的错误
+func failure(err error) error {
+	if IsFailure(err) {
+		return err
+	}
+	return nil
+}
+
+// DB 带熔断保护的 *sql.DB
+// 仅覆盖建立查询/执行这一步，Rows 迭代过程中的错误不计入熔断
+type DB struct {
+	db *sql.DB
+	cb *circuitbreaker.CircuitBreaker
+}
+
+// Wrap 使用熔断器包装 *sql.DB
+func Wrap(db *sql.DB, cb *circuitbreaker.CircuitBreaker) *DB {
+	return &DB{db: db, cb: cb}
+}
+
+// Unwrap 返回底层 *sql.DB
+func (d *DB) Unwrap() *sql.DB {
+	return d.db
+}
+
+// Breaker 返回熔断器
+func (d *DB) Breaker() *circuitbreaker.CircuitBreaker {
+	return d.cb
+}
+
+// ExecContext 执行语句
+func (d *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	done, err := d.cb.AllowContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := d.db.ExecContext(ctx, query, args...)
+	done(failure(err))
+	return res, err
+}
+
+// QueryContext 执行查询
+func (d *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	done, err := d.cb.AllowContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	done(failure(err))
+	return rows, err
+}
+
+// PingContext 检查连接
+func (d *DB) PingContext(ctx context.Context) error {
+	done, err := d.cb.AllowContext(ctx)
+	if err != nil {
+		return err
+	}
+	err = d.db.PingContext(ctx)
+	done(failure(err))
+	return err
+}
+
+// Close 关闭底层连接池
+func (d *DB) Close() error {
+	return d.db.Close()
+}
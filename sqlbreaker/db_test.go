@@ -0,0 +1,114 @@
+// Copyright 2025 zampo.
+
+package sqlbreaker
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// fakeDriver DSN 为 "down" 时所有操作失败，其余 DSN 正常返回空结果集
+type fakeDriver struct {
+	mu      sync.Mutex
+	queries map[string]int
+}
+
+var testDriver = &fakeDriver{queries: make(map[string]int)}
+
+func init() {
+	sql.Register("sqlbreaker-fake", testDriver)
+}
+
+func (d *fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return &fakeConn{dsn: dsn, d: d}, nil
+}
+
+func (d *fakeDriver) count(dsn string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.queries[dsn]
+}
+
+var errDown = errors.New("connection refused")
+
+type fakeConn struct {
+	dsn string
+	d   *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (c *fakeConn) Ping(ctx context.Context) error {
+	if c.dsn == "down" {
+		return errDown
+	}
+	return nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.mu.Lock()
+	c.d.queries[c.dsn]++
+	c.d.mu.Unlock()
+	if c.dsn == "down" {
+		return nil, errDown
+	}
+	return &fakeRows{}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.dsn == "down" {
+		return nil, errDown
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeRows struct{}
+
+func (r *fakeRows) Columns() []string              { return []string{"v"} }
+func (r *fakeRows) Close() error                   { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+func TestDB_Failures(t *testing.T) {
+	db, err := sql.Open("sqlbreaker-fake", "down")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	wrapped := Wrap(db, circuitbreaker.NewCircuitBreaker("db", circuitbreaker.DefaultSettings()))
+	defer wrapped.Close()
+
+	if _, err := wrapped.ExecContext(context.Background(), "UPDATE t SET v = 1"); !errors.Is(err, errDown) {
+		t.Errorf("ExecContext() error = %v, want %v", err, errDown)
+	}
+	if got := wrapped.Breaker().Counts().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %v, want %v", got, 1)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{sql.ErrNoRows, false},
+		{context.Canceled, false},
+		{errDown, true},
+	}
+
+	for _, tt := range tests {
+		if got := IsFailure(tt.err); got != tt.want {
+			t.Errorf("IsFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
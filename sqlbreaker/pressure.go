@@ -0,0 +1,33 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package sqlbreaker
+
+import (
+	"database/sql"
+)
+
+// PoolPressure 返回连接池占用率（使用中连接数 / 最大连接数），可作为 Settings.PressureFunc
+// 未设置 MaxOpenConns 时连接池无上限，始终返回 0
+func PoolPressure(db *sql.DB) func() float64 {
+	return func() float64 {
+		stats := db.Stats()
+		if stats.MaxOpenConnections <= 0 {
+			return 0
+		}
+		return float64(stats.InUse) / float64(stats.MaxOpenConnections)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2025 zampo.
+
+package sqlbreaker
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestPoolPressure(t *testing.T) {
+	db, err := sql.Open("sqlbreaker-fake", "pool")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+
+	pressure := PoolPressure(db)
+	if got := pressure(); got != 0 {
+		t.Errorf("pressure without limit = %v, want %v", got, 0)
+	}
+
+	db.SetMaxOpenConns(2)
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	defer conn.Close()
+
+	if got := pressure(); got != 0.5 {
+		t.Errorf("pressure = %v, want %v", got, 0.5)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package sqlbreaker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// ErrNoReplicas 副本列表为空
+var ErrNoReplicas = errors.New("sqlbreaker: no replicas")
+
+// ReplicaSet 只读副本集合，每个副本独立熔断
+// 读请求按配置顺序尝试，副本熔断打开或调用失败时自动转移到下一个副本
+type ReplicaSet struct {
+	replicas []*DB
+}
+
+// OpenReplicaSet 按 DSN 列表打开副本，熔断器命名为 "<name>/replica-<序号>"，
+// 不使用 DSN 命名以免泄露连接凭据
+func OpenReplicaSet(name, driverName string, dsns []string, settings circuitbreaker.Settings) (*ReplicaSet, error) {
+	if len(dsns) == 0 {
+		return nil, ErrNoReplicas
+	}
+
+	dbs := make([]*sql.DB, 0, len(dsns))
+	for _, dsn := range dsns {
+		db, err := sql.Open(driverName, dsn)
+		if err != nil {
+			for _, opened := range dbs {
+				opened.Close()
+			}
+			return nil, err
+		}
+		dbs = append(dbs, db)
+	}
+	return NewReplicaSet(name, dbs, settings), nil
+}
+
+// NewReplicaSet 使用已打开的连接池创建副本集合
+func NewReplicaSet(name string, dbs []*sql.DB, settings circuitbreaker.Settings) *ReplicaSet {
+	rs := &ReplicaSet{replicas: make([]*DB, 0, len(dbs))}
+	for i, db := range dbs {
+		cb := circuitbreaker.NewCircuitBreaker(fmt.Sprintf("%s/replica-%d", name, i), settings)
+		rs.replicas = append(rs.replicas, Wrap(db, cb))
+	}
+	return rs
+}
+
+// Replicas 返回全部副本（按配置顺序）
+func (rs *ReplicaSet) Replicas() []*DB {
+	return rs.replicas
+}
+
+// Ordered 返回故障转移顺序：未打开的副本在前，打开的副本在后，各自保持配置顺序
+func (rs *ReplicaSet) Ordered() []*DB {
+	ordered := make([]*DB, 0, len(rs.replicas))
+	var open []*DB
+	for _, r := range rs.replicas {
+		if r.cb.State() == gobreaker.StateOpen {
+			open = append(open, r)
+			continue
+		}
+		ordered = append(ordered, r)
+	}
+	return append(ordered, open...)
+}
+
+// QueryContext 在第一个可用副本上执行查询
+func (rs *ReplicaSet) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := rs.try(ctx, func(r *DB) error {
+		var err error
+		rows, err = r.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// PingContext 检查是否至少有一个副本可用
+func (rs *ReplicaSet) PingContext(ctx context.Context) error {
+	return rs.try(ctx, func(r *DB) error {
+		return r.PingContext(ctx)
+	})
+}
+
+// try 按故障转移顺序依次尝试，业务错误（非失败）直接返回不再转移
+func (rs *ReplicaSet) try(ctx context.Context, fn func(r *DB) error) error {
+	if len(rs.replicas) == 0 {
+		return ErrNoReplicas
+	}
+
+	var lastErr error
+	for _, r := range rs.Ordered() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := fn(r)
+		if err == nil || !shouldFailover(err) {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+func shouldFailover(err error) bool {
+	return errors.Is(err, gobreaker.ErrOpenState) ||
+		errors.Is(err, gobreaker.ErrTooManyRequests) ||
+		IsFailure(err)
+}
+
+// Close 关闭全部副本
+func (rs *ReplicaSet) Close() error {
+	var errs []error
+	for _, r := range rs.replicas {
+		if err := r.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
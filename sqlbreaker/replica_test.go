@@ -0,0 +1,49 @@
+// Copyright 2025 zampo.
+
+package sqlbreaker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func TestReplicaSet_Failover(t *testing.T) {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 2
+	}
+	rs, err := OpenReplicaSet("orders", "sqlbreaker-fake", []string{"down", "replica-b"}, settings)
+	if err != nil {
+		t.Fatalf("OpenReplicaSet() error = %v", err)
+	}
+	defer rs.Close()
+
+	for i := 0; i < 3; i++ {
+		rows, err := rs.QueryContext(context.Background(), "SELECT v FROM t")
+		if err != nil {
+			t.Fatalf("QueryContext() error = %v", err)
+		}
+		rows.Close()
+	}
+
+	if got := rs.Replicas()[0].Breaker().State(); got != gobreaker.StateOpen {
+		t.Errorf("replica-0 State = %v, want %v", got, gobreaker.StateOpen)
+	}
+	// 熔断打开后不再访问故障副本
+	if got := testDriver.count("down"); got != 2 {
+		t.Errorf("queries to failed replica = %v, want %v", got, 2)
+	}
+	if got := rs.Ordered()[0].Breaker().Name(); got != "orders/replica-1" {
+		t.Errorf("Ordered()[0] = %v, want orders/replica-1", got)
+	}
+}
+
+func TestOpenReplicaSet_Empty(t *testing.T) {
+	if _, err := OpenReplicaSet("orders", "sqlbreaker-fake", nil, circuitbreaker.DefaultSettings()); err != ErrNoReplicas {
+		t.Errorf("OpenReplicaSet() error = %v, want %v", err, ErrNoReplicas)
+	}
+}
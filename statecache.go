@@ -0,0 +1,63 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sync/atomic"
+
+	"github.com/sony/gobreaker"
+)
+
+// stateCache 底层熔断器最近一次的状态，在 OnStateChange 中更新，
+// 使 State/Allowed 无需获取 cb.mu 与 gobreaker 内部锁
+type stateCache struct {
+	state atomic.Int32
+	// openExpires 打开状态的超时时刻（UnixNano），之后 gobreaker 会在下次访问时进入半开，缓存不再可信
+	openExpires atomic.Int64
+}
+
+// store 记录状态变更，在 gobreaker 内部锁中调用，只使用原子操作
+func (c *stateCache) store(to gobreaker.State, openExpires int64) {
+	if to == gobreaker.StateOpen {
+		c.openExpires.Store(openExpires)
+	}
+	c.state.Store(int32(to))
+}
+
+// cachedState 无锁读取状态，缓存可能已过期（打开超时、被推迟的半开通知）时返回 false，由调用方走加锁路径
+func (cb *CircuitBreaker) cachedState() (gobreaker.State, bool) {
+	if cb.pendingHalfOpen.Load() {
+		return 0, false
+	}
+	state := gobreaker.State(cb.states.state.Load())
+	if state == gobreaker.StateOpen {
+		return state, cb.now().UnixNano() < cb.states.openExpires.Load()
+	}
+	// 未设置保持期与租约时只有一次原子读
+	if cb.openUntil.Load() != 0 || cb.lease.Load() != nil || cb.timeoutUntil.Load() != 0 {
+		if cb.heldOpen() || cb.timeoutHeld() {
+			return gobreaker.StateOpen, true
+		}
+	}
+	return state, true
+}
+
+// Allowed 当前是否可能放行请求（非打开状态），只做无锁读取，适合负载均衡、健康检查等高频轮询；
+// 半开状态下仍受 MaxRequests 等限制，实际准入以 Execute/Allow 为准
+func (cb *CircuitBreaker) Allowed() bool {
+	return cb.State() != gobreaker.StateOpen
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestStateCache_FollowsTransitions(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	settings.MaxRequests = 1
+	settings.Timeout = 20 * time.Millisecond
+	cb := NewCircuitBreaker("cached", settings)
+
+	if got := cb.State(); got != gobreaker.StateClosed || !cb.Allowed() {
+		t.Fatalf("State() = %v, Allowed() = %v, want closed and allowed", got, cb.Allowed())
+	}
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	if got := cb.State(); got != gobreaker.StateOpen || cb.Allowed() {
+		t.Fatalf("State() after trip = %v, Allowed() = %v, want open and rejected", got, cb.Allowed())
+	}
+
+	// 打开超时后缓存失效，回退到底层熔断器完成打开→半开
+	waitFor(t, func() bool { return cb.State() == gobreaker.StateHalfOpen })
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() after probe = %v, want closed", got)
+	}
+}
+
+func TestStateCache_HoldsAndRebuilds(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	cb := NewCircuitBreaker("held", settings)
+
+	cb.OpenFor(time.Hour)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() during OpenFor = %v, want open", got)
+	}
+	cb.Reset()
+	if _, err := cb.ForceOpen("oncall", time.Minute); err != nil {
+		t.Fatalf("ForceOpen() error = %v", err)
+	}
+	if cb.Allowed() {
+		t.Error("Allowed() with active lease = true")
+	}
+	cb.Reset()
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	cb.UpdateSettings(settings)
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() after UpdateSettings = %v, want closed", got)
+	}
+}
+
+func TestStateCache_ConcurrentReads(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	settings.Timeout = time.Millisecond
+	cb := NewCircuitBreaker("racy", settings)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cb.State()
+				cb.Allowed()
+			}
+		}()
+	}
+	for j := 0; j < 100; j++ {
+		cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+		cb.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	wg.Wait()
+}
+
+func BenchmarkCircuitBreaker_State(b *testing.B) {
+	cb := NewCircuitBreaker("bench", DefaultSettings())
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			cb.State()
+		}
+	})
+}
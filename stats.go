@@ -0,0 +1,160 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"maps"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// Stats 熔断器运行时统计快照
+type Stats struct {
+	// Name 熔断器名称
+	Name string
+	// State 当前状态
+	State gobreaker.State
+	// Counts 当前统计窗口内的计数
+	Counts gobreaker.Counts
+	// InFlight 正在执行中的调用数
+	InFlight int64
+	// MaxInFlight 历史最大并发调用数，用于评估隔离舱（bulkhead）上限
+	MaxInFlight int64
+	// LeakedCalls 已超时返回但仍在后台运行的调用数
+	LeakedCalls int64
+	// LateSuccesses 超时后最终成功的调用数
+	LateSuccesses uint64
+	// LateFailures 超时后最终失败的调用数
+	LateFailures uint64
+	// LatePanics 超时后发生 panic 的调用数，这类调用计入 LateFailures，panic 不会重新抛出
+	LatePanics uint64
+	// CallTimeouts 因 CallTimeout 先到期而超时的调用数
+	CallTimeouts uint64
+	// DeadlineTimeouts 因调用方 ctx 截止时间先到期而超时的调用数
+	DeadlineTimeouts uint64
+	// SlowCalls 成功但超过 LatencyBudget、按失败计数的调用数
+	SlowCalls uint64
+	// OversizedResults 成功但结果超过 MaxResultSize、按失败计数的调用数
+	OversizedResults uint64
+	// LatencyP99 最近调用的 P99 耗时
+	LatencyP99 time.Duration
+	// HealthScore 0~100 的综合健康评分，可用于加权负载均衡
+	HealthScore float64
+	// Description 依赖说明，见 Settings.Description
+	Description string
+	// RunbookURL 处置手册地址，见 Settings.RunbookURL
+	RunbookURL string
+	// Tier 严重级别，可作为指标标签
+	Tier Tier
+	// CountingPaused 是否处于暂停计数期间，见 PauseCounting
+	CountingPaused bool
+	// Labels 自定义标签，见 Settings.Labels
+	Labels map[string]string
+	// ErrorSamples 当前故障周期内最近的错误样本（按时间顺序），见 Settings.ErrorSamples
+	ErrorSamples []ErrorSample
+	// Lease 当前有效的强制打开租约，无租约时为 nil
+	Lease *Lease
+	// LastTripCause 最近一次进入打开状态的原因，从未打开时为 nil
+	LastTripCause *TripCause
+	// LatencyBaseline 成功调用耗时基线，未启用 LatencySampleRate 或尚无采样时为 nil
+	LatencyBaseline *LatencyBaseline
+	// Rejections 被熔断器拒绝、未实际执行的调用总数（自创建起累计），不计入 Counts
+	Rejections uint64
+	// RejectionsByReason 按原因的拒绝数，没有拒绝时为 nil
+	RejectionsByReason map[Reason]uint64
+}
+
+// Stats 获取运行时统计快照
+func (cb *CircuitBreaker) Stats() Stats {
+	cb.flushHalfOpen()
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	state, counts := cb.state(), cb.windowed(cb.cb.Counts())
+	p99 := cb.latencies.quantile(0.99)
+	var baseline *LatencyBaseline
+	if b, ok := cb.baseline.summary(); ok {
+		baseline = &b
+	}
+	rejected, byReason := cb.rejections.snapshot()
+	return Stats{
+		Name:        cb.name,
+		State:       state,
+		Counts:      counts,
+		InFlight:    cb.inFlight.Load(),
+		MaxInFlight: cb.maxInFlight.Load(),
+
+		LeakedCalls:   cb.leaked.Load(),
+		LateSuccesses: cb.lateSuccesses.Load(),
+		LateFailures:  cb.lateFailures.Load(),
+		LatePanics:    cb.latePanics.Load(),
+
+		CallTimeouts:     cb.callTimeouts.Load(),
+		DeadlineTimeouts: cb.deadlineTimeouts.Load(),
+
+		SlowCalls:        cb.slowCalls.Load(),
+		OversizedResults: cb.oversizedResults.Load(),
+
+		LatencyP99:  p99,
+		HealthScore: cb.healthScore(state, counts, p99),
+
+		Description: cb.settings.Description,
+		RunbookURL:  cb.settings.RunbookURL,
+		Tier:        cb.Tier(),
+
+		CountingPaused: cb.countingPaused.Load(),
+		Labels:         maps.Clone(cb.settings.Labels),
+		ErrorSamples:   cb.errorSamples.snapshot(),
+		Lease:          cb.activeLease(),
+		LastTripCause:  cb.lastTripCause(),
+
+		LatencyBaseline: baseline,
+
+		Rejections:         rejected,
+		RejectionsByReason: byReason,
+	}
+}
+
+// PauseCounting 暂停失败计数，用于发布、计划内故障演练等已知的噪声期
+// 暂停期间关闭状态下的失败不参与熔断判定，但已打开的熔断器照常拒绝请求，
+// 半开探测照常计数，保护不会被关闭
+func (cb *CircuitBreaker) PauseCounting() {
+	cb.countingPaused.Store(true)
+}
+
+// ResumeCounting 恢复失败计数
+func (cb *CircuitBreaker) ResumeCounting() {
+	cb.countingPaused.Store(false)
+}
+
+// ResetMaxInFlight 将并发峰值重置为当前并发数，便于按周期观察峰值
+func (cb *CircuitBreaker) ResetMaxInFlight() {
+	cb.maxInFlight.Store(cb.inFlight.Load())
+}
+
+// Stats 获取注册表内所有熔断器的统计快照（按名称排序）
+func (r *Registry) Stats() []Stats {
+	names := r.Names()
+	stats := make([]Stats, 0, len(names))
+	for _, name := range names {
+		if cb, ok := r.Get(name); ok {
+			stats = append(stats, cb.Stats())
+		}
+	}
+	return stats
+}
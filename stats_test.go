@@ -0,0 +1,149 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestCircuitBreaker_Stats_Concurrency(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+
+	const n = 3
+	var started, release sync.WaitGroup
+	started.Add(n)
+	release.Add(1)
+
+	var done sync.WaitGroup
+	for i := 0; i < n; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			cb.Execute(func() (interface{}, error) {
+				started.Done()
+				release.Wait()
+				return nil, nil
+			})
+		}()
+	}
+
+	started.Wait()
+	stats := cb.Stats()
+	if stats.InFlight != n {
+		t.Errorf("InFlight = %v, want %v", stats.InFlight, n)
+	}
+
+	release.Done()
+	done.Wait()
+
+	stats = cb.Stats()
+	if stats.InFlight != 0 {
+		t.Errorf("InFlight after completion = %v, want %v", stats.InFlight, 0)
+	}
+	if stats.MaxInFlight != n {
+		t.Errorf("MaxInFlight = %v, want %v", stats.MaxInFlight, n)
+	}
+
+	cb.ResetMaxInFlight()
+	if got := cb.Stats().MaxInFlight; got != 0 {
+		t.Errorf("MaxInFlight after reset = %v, want %v", got, 0)
+	}
+}
+
+func TestRegistry_Stats(t *testing.T) {
+	r := NewRegistry()
+	r.GetOrCreate("b", DefaultSettings())
+	r.GetOrCreate("a", DefaultSettings())
+
+	stats := r.Stats()
+	if len(stats) != 2 || stats[0].Name != "a" || stats[1].Name != "b" {
+		t.Errorf("Stats() = %+v, want a, b", stats)
+	}
+}
+
+func TestCircuitBreaker_Stats_Metadata(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Description = "Payments provider API"
+	settings.RunbookURL = "https://runbooks.example.com/payments"
+	cb := NewCircuitBreaker("payments-api", settings)
+
+	stats := cb.Stats()
+	if stats.Description != settings.Description || stats.RunbookURL != settings.RunbookURL {
+		t.Errorf("Stats() = %q, %q, want %q, %q", stats.Description, stats.RunbookURL, settings.Description, settings.RunbookURL)
+	}
+}
+
+func TestCircuitBreaker_Stats_LabelsAreCopied(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Labels = map[string]string{"service": "payments"}
+	cb := NewCircuitBreaker("payments-api", settings)
+
+	cb.Stats().Labels["service"] = "mutated"
+	if got := cb.Stats().Labels["service"]; got != "payments" {
+		t.Errorf("Labels[service] after mutating a snapshot = %q, want payments", got)
+	}
+}
+
+func TestCircuitBreaker_PauseCounting(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	cb := NewCircuitBreaker("test", settings)
+	fail := func() (interface{}, error) { return nil, errors.New("fail") }
+
+	cb.PauseCounting()
+	for i := 0; i < 3; i++ {
+		cb.Execute(fail)
+	}
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() while paused = %v, want %v", got, gobreaker.StateClosed)
+	}
+	if !cb.Stats().CountingPaused {
+		t.Error("CountingPaused = false, want true")
+	}
+
+	cb.ResumeCounting()
+	cb.Execute(fail)
+	if got := cb.State(); got != gobreaker.StateOpen {
+		t.Errorf("State() after resume = %v, want %v", got, gobreaker.StateOpen)
+	}
+
+	// 暂停计数不影响已打开熔断器的保护
+	cb.PauseCounting()
+	if _, err := cb.Execute(fail); ReasonOf(err) != ReasonOpen {
+		t.Errorf("Execute() error = %v, want rejection", err)
+	}
+}
+
+func TestStats_RejectionsSeparateFromFailures(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	cb := NewCircuitBreaker("shed", settings)
+
+	if stats := cb.Stats(); stats.Rejections != 0 || stats.RejectionsByReason != nil {
+		t.Errorf("Rejections before calls = %d, %v, want none", stats.Rejections, stats.RejectionsByReason)
+	}
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	for i := 0; i < 3; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	if _, err := cb.ForceOpen("oncall", time.Minute); err != nil {
+		t.Fatalf("ForceOpen() error = %v", err)
+	}
+	cb.Allow()
+
+	stats := cb.Stats()
+	// 进入打开状态时计数清零，之后的拒绝不计入 Counts
+	if stats.Counts.Requests != 0 {
+		t.Errorf("Counts = %+v, want rejections excluded", stats.Counts)
+	}
+	want := map[Reason]uint64{ReasonOpen: 3, ReasonForced: 1}
+	if stats.Rejections != 4 || !reflect.DeepEqual(stats.RejectionsByReason, want) {
+		t.Errorf("Rejections = %d, %v, want 4, %v", stats.Rejections, stats.RejectionsByReason, want)
+	}
+}
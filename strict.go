@@ -0,0 +1,88 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"fmt"
+	"time"
+)
+
+// MisuseError 对熔断器的误用。Settings.Strict 开启时作为 panic 的值，可用 recover 后类型断言获取详情；
+// 关闭时仅 nil 函数作为错误返回
+type MisuseError struct {
+	// Breaker 熔断器名称
+	Breaker string
+	// Op 误用的操作，如 "Execute"、"UpdateSettings"
+	Op string
+	// Problem 问题描述
+	Problem string
+}
+
+func (e *MisuseError) Error() string {
+	return fmt.Sprintf("circuitbreaker: misuse of %q in %s: %s", e.Breaker, e.Op, e.Problem)
+}
+
+// negativeDurations 返回配置中为负数的时长字段
+func (s Settings) negativeDurations() []string {
+	var problems []string
+	for _, f := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"Interval", s.Interval},
+		{"Timeout", s.Timeout},
+		{"CallTimeout", s.CallTimeout},
+		{"DeadlineOverhead", s.DeadlineOverhead},
+		{"LatencyBudget", s.LatencyBudget},
+	} {
+		if f.d < 0 {
+			problems = append(problems, fmt.Sprintf("%s is negative (%v)", f.name, f.d))
+		}
+	}
+	return problems
+}
+
+// checkSettings 严格模式下配置含负数时长时 panic
+func (cb *CircuitBreaker) checkSettings(op string, settings Settings) {
+	if !settings.Strict {
+		return
+	}
+	if problems := settings.negativeDurations(); len(problems) > 0 {
+		panic(&MisuseError{Breaker: cb.name, Op: op, Problem: fmt.Sprint(problems)})
+	}
+}
+
+// misuse 严格模式下 panic，否则返回误用错误
+func (cb *CircuitBreaker) misuse(op, problem string) error {
+	err := &MisuseError{Breaker: cb.name, Op: op, Problem: problem}
+	if cb.strict.Load() {
+		panic(err)
+	}
+	return err
+}
+
+// checkCall 检查调用入口的误用：fn 为 nil 时返回错误（严格模式下 panic），
+// 严格模式下使用已从注册表移除的熔断器时 panic
+func (cb *CircuitBreaker) checkCall(op string, nilFn bool) error {
+	if nilFn {
+		return cb.misuse(op, "fn is nil")
+	}
+	if cb.removed.Load() && cb.strict.Load() {
+		return cb.misuse(op, "breaker was removed from its registry; look it up again with GetOrCreate")
+	}
+	return nil
+}
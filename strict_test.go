@@ -0,0 +1,97 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mustPanicMisuse 断言 fn 以 *MisuseError panic，返回其值
+func mustPanicMisuse(t *testing.T, fn func()) *MisuseError {
+	t.Helper()
+	var got interface{}
+	func() {
+		defer func() { got = recover() }()
+		fn()
+	}()
+	misuse, ok := got.(*MisuseError)
+	if !ok {
+		t.Fatalf("panic = %v, want *MisuseError", got)
+	}
+	return misuse
+}
+
+func strictSettings() Settings {
+	settings := DefaultSettings()
+	settings.Strict = true
+	return settings
+}
+
+func TestStrict_NilFunc(t *testing.T) {
+	cb := NewCircuitBreaker("svc", strictSettings())
+	misuse := mustPanicMisuse(t, func() { cb.Execute(nil) })
+	if misuse.Breaker != "svc" || misuse.Op != "Execute" {
+		t.Errorf("misuse = %+v", misuse)
+	}
+	mustPanicMisuse(t, func() { cb.ExecuteContext(context.Background(), nil) })
+
+	lenient := NewCircuitBreaker("svc", DefaultSettings())
+	var target *MisuseError
+	if _, err := lenient.Execute(nil); !errors.As(err, &target) {
+		t.Errorf("Execute(nil) error = %v, want *MisuseError", err)
+	}
+	if got := lenient.Counts().Requests; got != 0 {
+		t.Errorf("Requests = %v, want nil fn not counted", got)
+	}
+}
+
+func TestStrict_NegativeDurations(t *testing.T) {
+	settings := strictSettings()
+	settings.Timeout = -time.Second
+	misuse := mustPanicMisuse(t, func() { NewCircuitBreaker("svc", settings) })
+	if !strings.Contains(misuse.Problem, "Timeout is negative") {
+		t.Errorf("Problem = %q", misuse.Problem)
+	}
+
+	cb := NewCircuitBreaker("svc", strictSettings())
+	bad := strictSettings()
+	bad.CallTimeout = -time.Millisecond
+	if misuse := mustPanicMisuse(t, func() { cb.UpdateSettings(bad) }); misuse.Op != "UpdateSettings" {
+		t.Errorf("Op = %q, want UpdateSettings", misuse.Op)
+	}
+	mustPanicMisuse(t, func() { cb.OpenFor(-time.Second) })
+
+	// 非严格模式保持原有的容忍行为
+	lenient := DefaultSettings()
+	lenient.Timeout = -time.Second
+	NewCircuitBreaker("svc", lenient).OpenFor(-time.Second)
+}
+
+func TestStrict_UseAfterRemove(t *testing.T) {
+	r := NewRegistry()
+	cb := r.GetOrCreate("svc", strictSettings())
+	r.Remove("svc")
+	misuse := mustPanicMisuse(t, func() { cb.Execute(func() (interface{}, error) { return nil, nil }) })
+	if !strings.Contains(misuse.Problem, "removed") {
+		t.Errorf("Problem = %q", misuse.Problem)
+	}
+	mustPanicMisuse(t, func() { cb.Allow() })
+
+	// 重新注册后恢复可用
+	if err := r.Register(cb); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if _, err := cb.Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("Execute() error = %v", err)
+	}
+
+	lenient := r.GetOrCreate("lenient", DefaultSettings())
+	r.Remove("lenient")
+	if _, err := lenient.Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("lenient Execute() after Remove error = %v", err)
+	}
+}
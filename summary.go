@@ -0,0 +1,392 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// ErrorCount 周期内出现的错误信息及次数
+type ErrorCount struct {
+	Error string
+	Count int
+}
+
+// BreakerSummary 单个熔断器在一个周期内的汇总
+type BreakerSummary struct {
+	Name string
+	// Uptime 周期内未处于打开状态的时间占比（0~1），半开计为可用
+	Uptime float64
+	// Trips 周期内进入打开状态的次数
+	Trips int
+	// OpenDuration 周期内处于打开状态的总时长
+	OpenDuration time.Duration
+	// LongestOpen 周期内最长的一次打开时长，跨周期的打开只计入周期内的部分
+	LongestOpen time.Duration
+	// TopErrors 周期内出现次数最多的错误样本（降序），需设置 Settings.ErrorSamples，
+	// 否则只包含每次熔断的触发错误（TripCause.Error）
+	TopErrors []ErrorCount
+}
+
+// Summary 一个周期的汇总报告，Breakers 按名称排序
+type Summary struct {
+	From     time.Time
+	To       time.Time
+	Breakers []BreakerSummary
+}
+
+// SummarySink 接收周期汇总，如写入日志、文件或 webhook
+type SummarySink func(ctx context.Context, s Summary) error
+
+// SummarySettings 汇总配置
+type SummarySettings struct {
+	// Period 汇总周期，默认 24 小时；Run 按 UTC 整周期对齐（24 小时即每天 UTC 零点）
+	Period time.Duration
+	// TopErrors 每个熔断器保留的错误样本数，默认 5
+	TopErrors int
+	// Sink 接收汇总，为空时 Run 只切分周期、不输出
+	Sink SummarySink
+	// OnError Sink 返回错误时调用
+	OnError func(err error)
+}
+
+// breakerSlice 单个熔断器在当前周期内的累计
+type breakerSlice struct {
+	// openSince 当前打开区间在本周期内的起点，未打开时为零值
+	openSince time.Time
+	trips     int
+	open      time.Duration
+	longest   time.Duration
+	errors    map[string]int
+	// lastSample 已计入的最新错误样本时间，避免重复计数
+	lastSample time.Time
+}
+
+// Summarizer 按周期汇总注册表内各熔断器的可用率、熔断次数、最长打开时长与主要错误，
+// 用于可靠性周报等回顾。状态变更经 Registry.Subscribe 记录，只统计订阅之后发生的变更
+type Summarizer struct {
+	registry    *Registry
+	settings    SummarySettings
+	unsubscribe func()
+	wake        chan struct{}
+
+	mu       sync.Mutex
+	from     time.Time
+	breakers map[string]*breakerSlice
+	// collect 有状态变更、待收集错误样本的熔断器
+	collect map[string]bool
+}
+
+// NewSummarizer 创建汇总器并开始记录 r 的状态变更，当前周期从此刻开始，不再使用时调用 Close
+func NewSummarizer(r *Registry, settings SummarySettings) *Summarizer {
+	if settings.Period <= 0 {
+		settings.Period = 24 * time.Hour
+	}
+	if settings.TopErrors <= 0 {
+		settings.TopErrors = 5
+	}
+	s := &Summarizer{
+		registry: r,
+		settings: settings,
+		wake:     make(chan struct{}, 1),
+		from:     time.Now(),
+		breakers: make(map[string]*breakerSlice),
+		collect:  make(map[string]bool),
+	}
+	s.unsubscribe = r.Subscribe(s.record)
+	for _, name := range r.Names() {
+		if cb, ok := r.Get(name); ok && cb.State() == gobreaker.StateOpen {
+			s.mu.Lock()
+			s.sliceLocked(name).openSince = s.from
+			s.mu.Unlock()
+		}
+	}
+	return s
+}
+
+// record 记录状态变更；在熔断器锁内调用，不可访问熔断器，错误样本由 Run 或 Flush 异步收集
+func (s *Summarizer) record(name string, from, to gobreaker.State) {
+	now := time.Now()
+	s.mu.Lock()
+	b := s.sliceLocked(name)
+	switch {
+	case to == gobreaker.StateOpen:
+		b.trips++
+		if b.openSince.IsZero() {
+			b.openSince = now
+		}
+	case from == gobreaker.StateOpen:
+		b.closeInterval(now)
+	}
+	s.collect[name] = true
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sliceLocked 返回熔断器的累计，不存在时创建，调用方需持有 mu
+func (s *Summarizer) sliceLocked(name string) *breakerSlice {
+	b, ok := s.breakers[name]
+	if !ok {
+		b = &breakerSlice{errors: make(map[string]int)}
+		s.breakers[name] = b
+	}
+	return b
+}
+
+// closeInterval 结束当前打开区间（截止到 at）
+func (b *breakerSlice) closeInterval(at time.Time) {
+	if b.openSince.IsZero() {
+		return
+	}
+	d := at.Sub(b.openSince)
+	b.open += d
+	b.longest = max(b.longest, d)
+	b.openSince = time.Time{}
+}
+
+// collectErrors 收集有状态变更的熔断器的错误样本，在熔断器锁外调用
+func (s *Summarizer) collectErrors() {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.collect))
+	for name := range s.collect {
+		names = append(names, name)
+	}
+	s.collect = make(map[string]bool)
+	s.mu.Unlock()
+
+	for _, name := range names {
+		cb, ok := s.registry.Get(name)
+		if !ok {
+			continue
+		}
+		samples := cb.Stats().ErrorSamples
+		if cause, ok := cb.LastTripCause(); ok && cause.Error != nil && len(samples) == 0 {
+			samples = []ErrorSample{*cause.Error}
+		}
+
+		s.mu.Lock()
+		b := s.sliceLocked(name)
+		for _, sample := range samples {
+			if !sample.At.After(b.lastSample) {
+				continue
+			}
+			b.errors[sample.Error]++
+			b.lastSample = sample.At
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Flush 结束当前周期并返回其汇总，下一周期从 now 开始；仍处于打开状态的熔断器
+// 本周期计入到 now 为止，下一周期从 now 起继续计算
+func (s *Summarizer) Flush(now time.Time) Summary {
+	s.mu.Lock()
+	for name := range s.breakers {
+		s.collect[name] = true
+	}
+	s.mu.Unlock()
+	s.collectErrors()
+	names := s.registry.Names()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summary := Summary{From: s.from, To: now}
+	period := now.Sub(s.from)
+	// 已移除的熔断器仍输出本周期的汇总，但不再延续到下一周期
+	registered := make(map[string]bool, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		registered[name] = true
+		seen[name] = true
+	}
+	for name := range s.breakers {
+		seen[name] = true
+	}
+
+	next := make(map[string]*breakerSlice)
+	for name := range seen {
+		b := s.sliceLocked(name)
+		stillOpen := !b.openSince.IsZero()
+		b.closeInterval(now)
+
+		entry := BreakerSummary{
+			Name:         name,
+			Uptime:       1,
+			Trips:        b.trips,
+			OpenDuration: b.open,
+			LongestOpen:  b.longest,
+			TopErrors:    topErrors(b.errors, s.settings.TopErrors),
+		}
+		if period > 0 {
+			entry.Uptime = max(1-float64(b.open)/float64(period), 0)
+		}
+		summary.Breakers = append(summary.Breakers, entry)
+
+		if !registered[name] {
+			continue
+		}
+		carry := &breakerSlice{errors: make(map[string]int), lastSample: b.lastSample}
+		if stillOpen {
+			carry.openSince = now
+		}
+		next[name] = carry
+	}
+	sort.Slice(summary.Breakers, func(i, j int) bool { return summary.Breakers[i].Name < summary.Breakers[j].Name })
+
+	s.breakers = next
+	s.from = now
+	return summary
+}
+
+// topErrors 按次数降序返回前 n 个错误，次数相同时按错误信息排序
+func topErrors(counts map[string]int, n int) []ErrorCount {
+	out := make([]ErrorCount, 0, len(counts))
+	for msg, count := range counts {
+		out = append(out, ErrorCount{Error: msg, Count: count})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Error < out[j].Error
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Run 在每个周期结束时（按 UTC 整周期对齐）生成汇总并交给 Sink，阻塞直至 ctx 结束；
+// 期间在状态变更后收集错误样本
+func (s *Summarizer) Run(ctx context.Context) error {
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(s.settings.Period).Add(s.settings.Period).Sub(now))
+		for waiting := true; waiting; {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-s.wake:
+				s.collectErrors()
+			case at := <-timer.C:
+				summary := s.Flush(at)
+				if s.settings.Sink != nil {
+					if err := s.settings.Sink(ctx, summary); err != nil && s.settings.OnError != nil {
+						s.settings.OnError(err)
+					}
+				}
+				waiting = false
+			}
+		}
+	}
+}
+
+// Close 停止记录状态变更
+func (s *Summarizer) Close() {
+	s.unsubscribe()
+}
+
+// TextSummarySink 以表格形式写入 w，可配合 log.Writer() 写入日志
+func TextSummarySink(w io.Writer) SummarySink {
+	var mu sync.Mutex
+	return func(_ context.Context, s Summary) error {
+		mu.Lock()
+		defer mu.Unlock()
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "circuit breaker summary %s ~ %s\n", s.From.UTC().Format(time.RFC3339), s.To.UTC().Format(time.RFC3339))
+		fmt.Fprintln(tw, "BREAKER\tUPTIME\tTRIPS\tOPEN\tLONGEST\tTOP ERROR")
+		for _, b := range s.Breakers {
+			top := "-"
+			if len(b.TopErrors) > 0 {
+				top = fmt.Sprintf("%s (x%d)", b.TopErrors[0].Error, b.TopErrors[0].Count)
+			}
+			fmt.Fprintf(tw, "%s\t%.3f%%\t%d\t%s\t%s\t%s\n",
+				b.Name, b.Uptime*100, b.Trips, b.OpenDuration.Round(time.Second), b.LongestOpen.Round(time.Second), top)
+		}
+		return tw.Flush()
+	}
+}
+
+// summaryJSON JSON 汇总格式，时长以毫秒表示
+type summaryJSON struct {
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Breakers []breakerSummaryJSON `json:"breakers"`
+}
+
+type breakerSummaryJSON struct {
+	Name           string       `json:"name"`
+	Uptime         float64      `json:"uptime"`
+	Trips          int          `json:"trips"`
+	OpenDurationMs int64        `json:"open_duration_ms"`
+	LongestOpenMs  int64        `json:"longest_open_ms"`
+	TopErrors      []errorCount `json:"top_errors,omitempty"`
+}
+
+type errorCount struct {
+	Error string `json:"error"`
+	Count int    `json:"count"`
+}
+
+// MarshalJSON 编码为 JSON，时长字段以毫秒表示（open_duration_ms、longest_open_ms）
+func (s Summary) MarshalJSON() ([]byte, error) {
+	out := summaryJSON{From: s.From.UTC(), To: s.To.UTC(), Breakers: make([]breakerSummaryJSON, 0, len(s.Breakers))}
+	for _, b := range s.Breakers {
+		entry := breakerSummaryJSON{
+			Name:           b.Name,
+			Uptime:         b.Uptime,
+			Trips:          b.Trips,
+			OpenDurationMs: b.OpenDuration.Milliseconds(),
+			LongestOpenMs:  b.LongestOpen.Milliseconds(),
+		}
+		for _, e := range b.TopErrors {
+			entry.TopErrors = append(entry.TopErrors, errorCount(e))
+		}
+		out.Breakers = append(out.Breakers, entry)
+	}
+	return json.Marshal(out)
+}
+
+// JSONSummarySink 每个周期向 w 写入一行 JSON
+func JSONSummarySink(w io.Writer) SummarySink {
+	var mu sync.Mutex
+	return func(_ context.Context, s Summary) error {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func tripOnce(t *testing.T, cb *CircuitBreaker, err error) {
+	t.Helper()
+	cb.Execute(func() (interface{}, error) { return nil, err })
+	if cb.State() != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want open", cb.State())
+	}
+}
+
+func TestSummarizer_Flush(t *testing.T) {
+	r := NewRegistry()
+	settings := DefaultSettings()
+	settings.ReadyToTrip = ConsecutiveFailures(1)
+	settings.ErrorSamples = 5
+	settings.Timeout = time.Hour
+	payments := r.GetOrCreate("payments", settings)
+	r.GetOrCreate("inventory", settings)
+
+	s := NewSummarizer(r, SummarySettings{})
+	defer s.Close()
+	start := time.Now()
+	tripOnce(t, payments, errors.New("connection refused"))
+	time.Sleep(20 * time.Millisecond)
+	// Run 在状态变更后异步收集错误样本；Reset 开始新的熔断周期并清空样本，先模拟一次收集
+	s.collectErrors()
+	payments.Reset()
+	tripOnce(t, payments, errors.New("connection refused"))
+
+	summary := s.Flush(time.Now())
+	if len(summary.Breakers) != 2 || summary.Breakers[0].Name != "inventory" {
+		t.Fatalf("Breakers = %+v, want inventory and payments", summary.Breakers)
+	}
+	if got := summary.Breakers[0]; got.Uptime != 1 || got.Trips != 0 {
+		t.Errorf("inventory = %+v, want full uptime and no trips", got)
+	}
+	got := summary.Breakers[1]
+	if got.Trips != 2 {
+		t.Errorf("Trips = %v, want 2", got.Trips)
+	}
+	if got.LongestOpen < 20*time.Millisecond || got.OpenDuration < got.LongestOpen {
+		t.Errorf("LongestOpen = %v, OpenDuration = %v, want >= 20ms", got.LongestOpen, got.OpenDuration)
+	}
+	if got.Uptime >= 1 || got.Uptime < 0 {
+		t.Errorf("Uptime = %v, want in [0, 1)", got.Uptime)
+	}
+	if len(got.TopErrors) != 1 || got.TopErrors[0] != (ErrorCount{Error: "connection refused", Count: 2}) {
+		t.Errorf("TopErrors = %+v, want connection refused x2", got.TopErrors)
+	}
+	if summary.From.Before(start.Add(-time.Second)) || !summary.To.After(summary.From) {
+		t.Errorf("period = %v ~ %v", summary.From, summary.To)
+	}
+
+	// 仍处于打开状态的熔断器延续到下一周期，但不重复计数
+	time.Sleep(10 * time.Millisecond)
+	next := s.Flush(time.Now()).Breakers[1]
+	if next.Trips != 0 || next.OpenDuration < 10*time.Millisecond || next.Uptime != 0 {
+		t.Errorf("next period = %+v, want open the whole period without new trips", next)
+	}
+	if len(next.TopErrors) != 0 {
+		t.Errorf("next TopErrors = %+v, want none", next.TopErrors)
+	}
+}
+
+func TestSummarizer_RunWritesSink(t *testing.T) {
+	r := NewRegistry()
+	r.GetOrCreate("payments", DefaultSettings())
+	summaries := make(chan Summary, 1)
+	s := NewSummarizer(r, SummarySettings{
+		Period: 20 * time.Millisecond,
+		Sink: func(_ context.Context, sum Summary) error {
+			select {
+			case summaries <- sum:
+			default:
+			}
+			return nil
+		},
+	})
+	defer s.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	select {
+	case sum := <-summaries:
+		if len(sum.Breakers) != 1 || sum.Breakers[0].Name != "payments" {
+			t.Errorf("Breakers = %+v", sum.Breakers)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not emit a summary")
+	}
+}
+
+func TestSummarySinks(t *testing.T) {
+	summary := Summary{
+		From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Breakers: []BreakerSummary{{
+			Name:         "payments",
+			Uptime:       0.99,
+			Trips:        3,
+			OpenDuration: 14 * time.Minute,
+			LongestOpen:  10 * time.Minute,
+			TopErrors:    []ErrorCount{{Error: "timeout", Count: 7}},
+		}},
+	}
+
+	var text bytes.Buffer
+	if err := TextSummarySink(&text)(context.Background(), summary); err != nil {
+		t.Fatalf("TextSummarySink() error = %v", err)
+	}
+	for _, want := range []string{"payments", "99.000%", "10m0s", "timeout (x7)"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text = %q, want %q", text.String(), want)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := JSONSummarySink(&out)(context.Background(), summary); err != nil {
+		t.Fatalf("JSONSummarySink() error = %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	b := decoded["breakers"].([]interface{})[0].(map[string]interface{})
+	if b["longest_open_ms"].(float64) != 600000 || b["trips"].(float64) != 3 {
+		t.Errorf("json = %s", out.Bytes())
+	}
+}
@@ -0,0 +1,57 @@
+//go:build !cbnotelemetry
+
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookSummarySink 以 JSON（格式同 JSONSummarySink）POST 到 url，非 2xx 响应返回错误；
+// client 为空时使用 http.DefaultClient
+func WebhookSummarySink(url string, client *http.Client) SummarySink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context, s Summary) error {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("circuitbreaker: summary webhook: %w", err)
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("circuitbreaker: summary webhook: status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
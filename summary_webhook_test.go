@@ -0,0 +1,40 @@
+//go:build !cbnotelemetry
+
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookSummarySink(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	sink := WebhookSummarySink(srv.URL, nil)
+	if err := sink(context.Background(), Summary{Breakers: []BreakerSummary{{Name: "payments", Uptime: 1}}}); err != nil {
+		t.Fatalf("sink() error = %v", err)
+	}
+	if len(got["breakers"].([]interface{})) != 1 {
+		t.Errorf("payload = %v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	if err := WebhookSummarySink(failing.URL, nil)(context.Background(), Summary{}); err == nil {
+		t.Error("sink() error = nil, want status error")
+	}
+}
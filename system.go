@@ -0,0 +1,272 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// ErrSystemOverload 本地资源过载，请求被系统熔断器拒绝
+var ErrSystemOverload = errors.New("circuitbreaker: system overloaded")
+
+// Executor 熔断执行接口，CircuitBreaker 与 SystemBreaker 均实现该接口；不需要熔断时可使用 Noop
+type Executor interface {
+	Name() string
+	Execute(fn func() (interface{}, error)) (interface{}, error)
+	ExecuteContext(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error)
+	State() gobreaker.State
+	Stats() Stats
+}
+
+var (
+	_ Executor = (*CircuitBreaker)(nil)
+	_ Executor = (*SystemBreaker)(nil)
+)
+
+// SystemSignals 本地资源信号采样
+type SystemSignals struct {
+	// CPU CPU 使用率（0~1），未配置 CPUFunc 时为 0
+	CPU float64
+	// GCPause 自上次采样以来新发生的 GC 中最长的停顿（按 runtime/metrics 直方图的桶上界估算），没有新 GC 时为 0
+	GCPause time.Duration
+	// Goroutines 当前协程数
+	Goroutines int
+	// SampledAt 采样时间
+	SampledAt time.Time
+}
+
+// SystemSettings 系统熔断器配置，阈值为 0 表示不启用该信号
+type SystemSettings struct {
+	// MaxCPU CPU 使用率阈值（0~1）
+	MaxCPU float64
+	// MaxGCPause 单次 GC 停顿阈值
+	MaxGCPause time.Duration
+	// MaxGoroutines 协程数阈值
+	MaxGoroutines int
+	// CoolDown 打开后的最短保持时间，之后信号恢复即关闭
+	CoolDown time.Duration
+	// SampleInterval 采样间隔，在调用路径上按需采样，不启动后台协程
+	SampleInterval time.Duration
+	// CPUFunc CPU 使用率来源；标准库无跨平台的 CPU 使用率接口，需由调用方提供
+	CPUFunc func() float64
+	// OnStateChange 状态变更回调
+	OnStateChange func(name string, from, to gobreaker.State)
+	// Description 说明，见 Settings.Description
+	Description string
+	// RunbookURL 处置手册地址，见 Settings.RunbookURL
+	RunbookURL string
+	// Tier 严重级别，见 Settings.Tier
+	Tier Tier
+}
+
+// DefaultSystemSettings 返回默认系统熔断器配置
+func DefaultSystemSettings() SystemSettings {
+	return SystemSettings{
+		MaxGCPause:     100 * time.Millisecond,
+		MaxGoroutines:  100000,
+		CoolDown:       5 * time.Second,
+		SampleInterval: time.Second,
+	}
+}
+
+// SystemBreaker 基于本地资源信号（CPU、GC 停顿、协程数）而非调用结果熔断，用于保护服务自身
+type SystemBreaker struct {
+	inner    *CircuitBreaker
+	settings SystemSettings
+
+	mu       sync.Mutex
+	state    gobreaker.State
+	openedAt time.Time
+	signals  SystemSignals
+	gc       gcPauses
+}
+
+// NewSystemBreaker 创建系统熔断器
+func NewSystemBreaker(name string, settings SystemSettings) *SystemBreaker {
+	inner := DefaultSettings()
+	// 调用结果不参与熔断判定
+	inner.ReadyToTrip = func(gobreaker.Counts) bool { return false }
+	inner.Description = settings.Description
+	inner.RunbookURL = settings.RunbookURL
+	inner.Tier = settings.Tier
+	sb := &SystemBreaker{
+		inner:    NewCircuitBreaker(name, inner),
+		settings: settings,
+		state:    gobreaker.StateClosed,
+	}
+	if settings.MaxGCPause > 0 {
+		// 以创建时的 GC 统计为基线，创建之前的停顿不参与判定
+		sb.gc.read()
+	}
+	return sb
+}
+
+// Name 返回熔断器名称
+func (sb *SystemBreaker) Name() string {
+	return sb.inner.Name()
+}
+
+// Execute 执行函数，本地资源过载时返回 ErrSystemOverload
+func (sb *SystemBreaker) Execute(fn func() (interface{}, error)) (interface{}, error) {
+	if sb.State() == gobreaker.StateOpen {
+		return nil, sb.rejectOverload()
+	}
+	return sb.inner.Execute(fn)
+}
+
+// ExecuteContext 执行函数，本地资源过载时返回 ErrSystemOverload
+func (sb *SystemBreaker) ExecuteContext(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if sb.State() == gobreaker.StateOpen {
+		return nil, sb.rejectOverload()
+	}
+	return sb.inner.ExecuteContext(ctx, fn)
+}
+
+// rejectOverload 返回过载拒绝错误，并像内部熔断器自身的拒绝一样计入 Stats 与 OnOutcome
+func (sb *SystemBreaker) rejectOverload() error {
+	err := reject(sb.Name(), ErrSystemOverload)
+	sb.inner.rejections.record(ReasonOverload)
+	sb.inner.emitRejection(err)
+	return err
+}
+
+// State 获取当前状态，必要时重新采样
+func (sb *SystemBreaker) State() gobreaker.State {
+	now := time.Now()
+
+	sb.mu.Lock()
+	from := sb.state
+	to := sb.evaluate(now)
+	sb.mu.Unlock()
+
+	if from != to && sb.settings.OnStateChange != nil {
+		sb.settings.OnStateChange(sb.Name(), from, to)
+	}
+	return to
+}
+
+// Signals 返回最近一次采样的资源信号
+func (sb *SystemBreaker) Signals() SystemSignals {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.signals
+}
+
+// Stats 获取运行时统计快照
+func (sb *SystemBreaker) Stats() Stats {
+	stats := sb.inner.Stats()
+	stats.State = sb.State()
+	return stats
+}
+
+// evaluate 按采样间隔刷新信号并更新状态，调用方需持有锁
+func (sb *SystemBreaker) evaluate(now time.Time) gobreaker.State {
+	if !sb.signals.SampledAt.IsZero() && now.Sub(sb.signals.SampledAt) < sb.settings.SampleInterval {
+		return sb.state
+	}
+
+	sb.signals = sb.sample(now)
+	overloaded := sb.overloaded(sb.signals)
+
+	switch {
+	case overloaded && sb.state != gobreaker.StateOpen:
+		sb.state = gobreaker.StateOpen
+		sb.openedAt = now
+	case overloaded:
+		// 持续过载时延长冷却期
+		sb.openedAt = now
+	case sb.state == gobreaker.StateOpen && now.Sub(sb.openedAt) >= sb.settings.CoolDown:
+		sb.state = gobreaker.StateClosed
+	}
+	return sb.state
+}
+
+func (sb *SystemBreaker) sample(now time.Time) SystemSignals {
+	signals := SystemSignals{
+		Goroutines: runtime.NumGoroutine(),
+		SampledAt:  now,
+	}
+	if sb.settings.CPUFunc != nil {
+		signals.CPU = sb.settings.CPUFunc()
+	}
+	if sb.settings.MaxGCPause > 0 {
+		signals.GCPause = sb.gc.read()
+	}
+	return signals
+}
+
+const (
+	gcCyclesMetric = "/gc/cycles/total:gc-cycles"
+	gcPausesMetric = "/gc/pauses:seconds"
+)
+
+// gcPauses 上次采样时的 GC 次数与停顿直方图，用于只统计两次采样之间新发生的停顿；
+// runtime.ReadMemStats 会暂停整个程序，不适合在调用路径上采样，因此读取 runtime/metrics
+type gcPauses struct {
+	cycles uint64
+	counts []uint64
+}
+
+// read 读取 GC 统计，返回自上次读取以来新发生的 GC 中最长的停顿
+func (g *gcPauses) read() time.Duration {
+	samples := []metrics.Sample{{Name: gcCyclesMetric}, {Name: gcPausesMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindUint64 || samples[1].Value.Kind() != metrics.KindFloat64Histogram {
+		return 0
+	}
+	return g.observe(samples[0].Value.Uint64(), samples[1].Value.Float64Histogram())
+}
+
+// observe 与上次记录的直方图比较，返回新增计数所在最高桶的上界（上界为 +Inf 时取下界）；
+// GC 次数没有增长时返回 0，不会重复报告已判定过的停顿
+func (g *gcPauses) observe(cycles uint64, h *metrics.Float64Histogram) time.Duration {
+	var pause time.Duration
+	if cycles > g.cycles {
+		for i := len(h.Counts) - 1; i >= 0; i-- {
+			var before uint64
+			if i < len(g.counts) {
+				before = g.counts[i]
+			}
+			if h.Counts[i] <= before {
+				continue
+			}
+			bound := h.Buckets[i+1]
+			if math.IsInf(bound, 1) {
+				bound = h.Buckets[i]
+			}
+			pause = time.Duration(bound * float64(time.Second))
+			break
+		}
+	}
+	g.cycles = cycles
+	g.counts = append(g.counts[:0], h.Counts...)
+	return pause
+}
+
+func (sb *SystemBreaker) overloaded(s SystemSignals) bool {
+	return (sb.settings.MaxCPU > 0 && s.CPU >= sb.settings.MaxCPU) ||
+		(sb.settings.MaxGCPause > 0 && s.GCPause >= sb.settings.MaxGCPause) ||
+		(sb.settings.MaxGoroutines > 0 && s.Goroutines >= sb.settings.MaxGoroutines)
+}
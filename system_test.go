@@ -0,0 +1,111 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestSystemBreaker_TripsOnCPU(t *testing.T) {
+	cpu := 0.2
+	var transitions []gobreaker.State
+	sb := NewSystemBreaker("system", SystemSettings{
+		MaxCPU:   0.9,
+		CoolDown: 20 * time.Millisecond,
+		CPUFunc:  func() float64 { return cpu },
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			transitions = append(transitions, to)
+		},
+	})
+
+	if _, err := sb.Execute(func() (interface{}, error) { return "ok", nil }); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	cpu = 0.95
+	if _, err := sb.Execute(func() (interface{}, error) { return "ok", nil }); !errors.Is(err, ErrSystemOverload) {
+		t.Errorf("Execute() error = %v, want %v", err, ErrSystemOverload)
+	}
+	if stats := sb.Stats(); stats.Rejections != 1 || stats.RejectionsByReason[ReasonOverload] != 1 {
+		t.Errorf("Rejections = %v, RejectionsByReason = %v, want 1 overload rejection", stats.Rejections, stats.RejectionsByReason)
+	}
+	if got := sb.Signals().CPU; got != 0.95 {
+		t.Errorf("Signals().CPU = %v, want %v", got, 0.95)
+	}
+
+	cpu = 0.2
+	if state := sb.State(); state != gobreaker.StateOpen {
+		t.Errorf("State within cool down = %v, want %v", state, gobreaker.StateOpen)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if state := sb.State(); state != gobreaker.StateClosed {
+		t.Errorf("State after cool down = %v, want %v", state, gobreaker.StateClosed)
+	}
+
+	if len(transitions) != 2 || transitions[0] != gobreaker.StateOpen || transitions[1] != gobreaker.StateClosed {
+		t.Errorf("transitions = %v, want [open closed]", transitions)
+	}
+}
+
+func TestSystemBreaker_IgnoresCallOutcomes(t *testing.T) {
+	sb := NewSystemBreaker("system", SystemSettings{})
+
+	for i := 0; i < 20; i++ {
+		sb.Execute(func() (interface{}, error) {
+			return nil, errors.New("fail")
+		})
+	}
+
+	stats := sb.Stats()
+	if stats.State != gobreaker.StateClosed {
+		t.Errorf("State = %v, want %v", stats.State, gobreaker.StateClosed)
+	}
+	if stats.Counts.TotalFailures != 20 {
+		t.Errorf("TotalFailures = %v, want %v", stats.Counts.TotalFailures, 20)
+	}
+}
+
+func TestGCPauses_OnlyNewCycles(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{0, 0, 0},
+		Buckets: []float64{0, 0.001, 0.1, math.Inf(1)},
+	}
+	var g gcPauses
+	if got := g.observe(0, h); got != 0 {
+		t.Errorf("observe() before any GC = %v, want 0", got)
+	}
+
+	h.Counts = []uint64{3, 1, 0}
+	if got := g.observe(4, h); got != 100*time.Millisecond {
+		t.Errorf("observe() = %v, want upper bound of the highest new bucket 100ms", got)
+	}
+	// 没有新的 GC 时不再报告之前的停顿
+	if got := g.observe(4, h); got != 0 {
+		t.Errorf("observe() without new GC = %v, want 0", got)
+	}
+
+	h.Counts = []uint64{4, 1, 0}
+	if got := g.observe(5, h); got != time.Millisecond {
+		t.Errorf("observe() = %v, want 1ms for the short new pause", got)
+	}
+	h.Counts = []uint64{4, 1, 1}
+	if got := g.observe(6, h); got != 100*time.Millisecond {
+		t.Errorf("observe() in the overflow bucket = %v, want its lower bound 100ms", got)
+	}
+}
+
+func TestGCPauses_Read(t *testing.T) {
+	var g gcPauses
+	g.read()
+	runtime.GC()
+	if g.read() <= 0 {
+		t.Error("read() after runtime.GC() = 0, want the new pause")
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"math"
+	"sort"
+)
+
+// centroid t-digest 中的质心
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// tdigest 合并式 t-digest（Dunning），以有限个质心近似任意分布，尾部分位数精度高于中部；
+// 非并发安全，由调用方加锁
+type tdigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	total       float64
+	min, max    float64
+}
+
+// newTDigest 创建 t-digest，compression 越大质心越多、精度越高，常用 100
+func newTDigest(compression float64) *tdigest {
+	return &tdigest{
+		compression: compression,
+		buffer:      make([]centroid, 0, int(compression)*5),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// add 加入一个观测值
+func (d *tdigest) add(x float64) {
+	d.buffer = append(d.buffer, centroid{mean: x, weight: 1})
+	d.min = math.Min(d.min, x)
+	d.max = math.Max(d.max, x)
+	if len(d.buffer) == cap(d.buffer) {
+		d.flush()
+	}
+}
+
+// count 返回观测值总权重
+func (d *tdigest) count() float64 {
+	var buffered float64
+	for _, c := range d.buffer {
+		buffered += c.weight
+	}
+	return d.total + buffered
+}
+
+// flush 将缓冲区合并进质心：按均值排序后从左到右合并，质心大小受 q(1-q) 约束，使尾部质心更小
+func (d *tdigest) flush() {
+	if len(d.buffer) == 0 {
+		return
+	}
+	points := append(d.centroids, d.buffer...)
+	sort.Slice(points, func(i, j int) bool { return points[i].mean < points[j].mean })
+	var total float64
+	for _, p := range points {
+		total += p.weight
+	}
+
+	merged := make([]centroid, 0, len(d.centroids)+1)
+	cur, soFar := points[0], 0.0
+	for _, p := range points[1:] {
+		proposed := cur.weight + p.weight
+		q0, q2 := soFar/total, (soFar+proposed)/total
+		limit := 4 * total * math.Min(q0*(1-q0), q2*(1-q2)) / d.compression
+		if proposed <= limit {
+			cur.mean += (p.mean - cur.mean) * p.weight / proposed
+			cur.weight = proposed
+			continue
+		}
+		merged = append(merged, cur)
+		soFar += cur.weight
+		cur = p
+	}
+	d.centroids = append(merged, cur)
+	d.total = total
+	d.buffer = d.buffer[:0]
+}
+
+// quantile 返回分位数 q（0~1）的估计值，相邻质心之间线性插值；无数据时返回 0
+func (d *tdigest) quantile(q float64) float64 {
+	d.flush()
+	cs := d.centroids
+	if len(cs) == 0 {
+		return 0
+	}
+	if len(cs) == 1 || q <= 0 {
+		if q <= 0 {
+			return d.min
+		}
+		return cs[0].mean
+	}
+	if q >= 1 {
+		return d.max
+	}
+
+	target := q * d.total
+	var cum float64
+	for i, c := range cs {
+		mid := cum + c.weight/2
+		if target < mid {
+			if i == 0 {
+				return d.min + (c.mean-d.min)*target/mid
+			}
+			prev := cs[i-1]
+			prevMid := cum - prev.weight/2
+			return prev.mean + (c.mean-prev.mean)*(target-prevMid)/(mid-prevMid)
+		}
+		cum += c.weight
+	}
+	last := cs[len(cs)-1]
+	lastMid := d.total - last.weight/2
+	return last.mean + (d.max-last.mean)*(target-lastMid)/(d.total-lastMid)
+}
+
+// decay 将所有权重减半，使旧观测逐步淡出
+func (d *tdigest) decay() {
+	d.flush()
+	for i := range d.centroids {
+		d.centroids[i].weight /= 2
+	}
+	d.total /= 2
+}
+
+// reset 清空所有观测
+func (d *tdigest) reset() {
+	d.centroids = d.centroids[:0]
+	d.buffer = d.buffer[:0]
+	d.total = 0
+	d.min, d.max = math.Inf(1), math.Inf(-1)
+}
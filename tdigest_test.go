@@ -0,0 +1,61 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestTDigest_Quantiles(t *testing.T) {
+	d := newTDigest(100)
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, v := range r.Perm(100000) {
+		d.add(float64(v))
+	}
+
+	for _, tc := range []struct {
+		q, want, tolerance float64
+	}{
+		{0.5, 50000, 1000},
+		{0.9, 90000, 500},
+		{0.99, 99000, 100},
+		{0.999, 99900, 30},
+	} {
+		if got := d.quantile(tc.q); math.Abs(got-tc.want) > tc.tolerance {
+			t.Errorf("quantile(%v) = %.0f, want %.0f ± %.0f", tc.q, got, tc.want, tc.tolerance)
+		}
+	}
+	if got := d.quantile(0); got != 0 {
+		t.Errorf("quantile(0) = %v, want min 0", got)
+	}
+	if got := d.quantile(1); got != 99999 {
+		t.Errorf("quantile(1) = %v, want max 99999", got)
+	}
+	if n := len(d.centroids); n > 1000 {
+		t.Errorf("len(centroids) = %d, want bounded by compression", n)
+	}
+}
+
+func TestTDigest_DecayAndReset(t *testing.T) {
+	d := newTDigest(100)
+	for i := 0; i < 1000; i++ {
+		d.add(10)
+	}
+	d.decay()
+	if got := d.count(); got != 500 {
+		t.Errorf("count() after decay = %v, want 500", got)
+	}
+	for i := 0; i < 1500; i++ {
+		d.add(20)
+	}
+	if got := d.quantile(0.5); got != 20 {
+		t.Errorf("quantile(0.5) = %v, want recent observations to dominate", got)
+	}
+
+	d.reset()
+	if got := d.count(); got != 0 || d.quantile(0.5) != 0 {
+		t.Errorf("after reset count() = %v, quantile(0.5) = %v, want empty", got, d.quantile(0.5))
+	}
+}
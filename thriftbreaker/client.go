@@ -0,0 +1,121 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package thriftbreaker 提供 Apache Thrift 客户端的熔断包装
+//
+// Client 以类型参数适配 thrift.TClient，无需直接依赖 Thrift 库：
+//
+//	var c thrift.TClient = thriftbreaker.Wrap[thrift.TStruct, thrift.TStruct, thrift.ResponseMeta](
+//		thrift.NewTStandardClient(in, out), "users")
+//	users := gen.NewUserServiceClient(c)
+package thriftbreaker
+
+import (
+	"context"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Caller thrift.TClient 的形状
+type Caller[A, R, M any] interface {
+	Call(ctx context.Context, method string, args A, result R) (M, error)
+}
+
+// IsFailure 默认失败判定：Call 返回的错误均为传输、协议或 TApplicationException，
+// 均计为失败；IDL 声明的业务异常写入 result 而非返回错误，不影响熔断
+func IsFailure(err error) bool {
+	return err != nil
+}
+
+// Option 配置项
+type Option func(*config)
+
+type config struct {
+	registry  *circuitbreaker.Registry
+	settings  circuitbreaker.Settings
+	perMethod bool
+	isFailure func(error) bool
+}
+
+// WithRegistry 设置熔断器注册表，便于与健康检查、管理接口共享
+func WithRegistry(r *circuitbreaker.Registry) Option {
+	return func(c *config) { c.registry = r }
+}
+
+// WithSettings 设置熔断器配置
+func WithSettings(settings circuitbreaker.Settings) Option {
+	return func(c *config) { c.settings = settings }
+}
+
+// WithPerMethod 按方法分别熔断（"thrift:<服务>.<方法>"），默认整个服务共用一个熔断器
+func WithPerMethod() Option {
+	return func(c *config) { c.perMethod = true }
+}
+
+// WithIsFailure 设置失败判定，如仅将 TTransportException 计为失败
+func WithIsFailure(fn func(error) bool) Option {
+	return func(c *config) { c.isFailure = fn }
+}
+
+// Client 带熔断保护的 Thrift 客户端，满足 thrift.TClient
+type Client[A, R, M any] struct {
+	next    Caller[A, R, M]
+	service string
+	config
+}
+
+// Wrap 包装 Thrift 客户端，service 为服务名，熔断器在注册表中命名为 "thrift:<服务>"
+func Wrap[A, R, M any](next Caller[A, R, M], service string, opts ...Option) *Client[A, R, M] {
+	c := &Client[A, R, M]{
+		next:    next,
+		service: service,
+		config: config{
+			registry:  circuitbreaker.NewRegistry(),
+			settings:  circuitbreaker.DefaultSettings(),
+			isFailure: IsFailure,
+		},
+	}
+	for _, opt := range opts {
+		opt(&c.config)
+	}
+	return c
+}
+
+// Breaker 返回方法对应的熔断器，未启用 WithPerMethod 时忽略 method
+func (c *Client[A, R, M]) Breaker(method string) *circuitbreaker.CircuitBreaker {
+	name := "thrift:" + c.service
+	if c.perMethod {
+		name += "." + method
+	}
+	return c.registry.GetOrCreate(name, c.settings)
+}
+
+// Call 实现 thrift.TClient，熔断拒绝时返回的错误可用 circuitbreaker.ReasonOf 获取原因
+func (c *Client[A, R, M]) Call(ctx context.Context, method string, args A, result R) (M, error) {
+	done, err := c.Breaker(method).AllowContext(ctx)
+	if err != nil {
+		var zero M
+		return zero, err
+	}
+
+	meta, err := c.next.Call(ctx, method, args, result)
+	if c.isFailure(err) {
+		done(err)
+	} else {
+		done(nil)
+	}
+	return meta, err
+}
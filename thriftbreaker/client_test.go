@@ -0,0 +1,82 @@
+// Copyright 2025 zampo.
+
+package thriftbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// 与 thrift 生成代码形状一致的测试类型
+type (
+	tstruct      interface{}
+	responseMeta struct{ Headers map[string]string }
+)
+
+type tclient interface {
+	Call(ctx context.Context, method string, args, result tstruct) (responseMeta, error)
+}
+
+type fakeCaller struct {
+	err   error
+	calls int
+}
+
+func (f *fakeCaller) Call(ctx context.Context, method string, args, result tstruct) (responseMeta, error) {
+	f.calls++
+	return responseMeta{Headers: map[string]string{"method": method}}, f.err
+}
+
+func tripOnFirstFailure() circuitbreaker.Settings {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	return settings
+}
+
+func TestClient_SatisfiesTClientShape(t *testing.T) {
+	next := &fakeCaller{}
+	var c tclient = Wrap[tstruct, tstruct, responseMeta](next, "users")
+
+	meta, err := c.Call(context.Background(), "getUser", nil, nil)
+	if err != nil || meta.Headers["method"] != "getUser" {
+		t.Errorf("Call() = %+v, %v", meta, err)
+	}
+}
+
+func TestClient_TripsPerService(t *testing.T) {
+	next := &fakeCaller{err: errors.New("connection refused")}
+	c := Wrap[tstruct, tstruct, responseMeta](next, "users", WithSettings(tripOnFirstFailure()))
+	ctx := context.Background()
+
+	c.Call(ctx, "getUser", nil, nil)
+	_, err := c.Call(ctx, "listUsers", nil, nil)
+	if circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Errorf("Call() error = %v, want open rejection", err)
+	}
+	if next.calls != 1 {
+		t.Errorf("calls = %v, want 1", next.calls)
+	}
+}
+
+func TestClient_PerMethod(t *testing.T) {
+	next := &fakeCaller{err: errors.New("connection refused")}
+	r := circuitbreaker.NewRegistry()
+	c := Wrap[tstruct, tstruct, responseMeta](next, "users",
+		WithRegistry(r), WithSettings(tripOnFirstFailure()), WithPerMethod())
+
+	c.Call(context.Background(), "getUser", nil, nil)
+	if got := c.Breaker("getUser").State(); got != gobreaker.StateOpen {
+		t.Errorf("getUser State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+	if got := c.Breaker("listUsers").State(); got != gobreaker.StateClosed {
+		t.Errorf("listUsers State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+	if _, ok := r.Get("thrift:users.getUser"); !ok {
+		t.Error("breaker not registered as thrift:users.getUser")
+	}
+}
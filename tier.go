@@ -0,0 +1,56 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"github.com/sony/gobreaker"
+)
+
+// Tier 熔断器的严重级别，用于告警路由与指标标签
+type Tier string
+
+const (
+	// TierCritical 核心依赖，熔断即影响主流程，需要立即处理
+	TierCritical Tier = "critical"
+	// TierDegradedOK 有降级方案的依赖，熔断时服务降级运行
+	TierDegradedOK Tier = "degraded-ok"
+	// TierBestEffort 尽力而为的依赖，熔断通常无需人工介入
+	TierBestEffort Tier = "best-effort"
+)
+
+// orDefault 未设置级别时按 TierCritical 处理，避免漏报
+func (t Tier) orDefault() Tier {
+	if t == "" {
+		return TierCritical
+	}
+	return t
+}
+
+// Tier 返回熔断器的严重级别，未配置时为 TierCritical
+// 无锁读取，可在状态变更回调中安全调用
+func (cb *CircuitBreaker) Tier() Tier {
+	if t, ok := cb.tier.Load().(Tier); ok {
+		return t
+	}
+	return TierCritical
+}
+
+// SubscribeTier 仅订阅指定级别熔断器的状态变更，返回取消订阅函数
+// 可为不同级别配置不同的通知渠道（如 critical 呼叫值班、best-effort 仅记录）
+func (r *Registry) SubscribeTier(tier Tier, fn func(name string, from, to gobreaker.State)) func() {
+	return r.subscribe(tier.orDefault(), byName(fn))
+}
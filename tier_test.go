@@ -0,0 +1,56 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestCircuitBreaker_Tier(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+	if got := cb.Tier(); got != TierCritical {
+		t.Errorf("Tier() = %v, want %v", got, TierCritical)
+	}
+
+	settings := DefaultSettings()
+	settings.Tier = TierBestEffort
+	cb.UpdateSettings(settings)
+	if got := cb.Stats().Tier; got != TierBestEffort {
+		t.Errorf("Stats().Tier = %v, want %v", got, TierBestEffort)
+	}
+}
+
+func TestRegistry_SubscribeTier(t *testing.T) {
+	r := NewRegistry()
+	trip := func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 1
+	}
+
+	critical := DefaultSettings()
+	critical.ReadyToTrip = trip
+	bestEffort := DefaultSettings()
+	bestEffort.ReadyToTrip = trip
+	bestEffort.Tier = TierBestEffort
+
+	var paged, all []string
+	r.SubscribeTier(TierCritical, func(name string, from, to gobreaker.State) {
+		paged = append(paged, name)
+	})
+	r.Subscribe(func(name string, from, to gobreaker.State) {
+		all = append(all, name)
+	})
+
+	fail := func() (interface{}, error) { return nil, errors.New("fail") }
+	r.GetOrCreate("payments", critical).Execute(fail)
+	r.GetOrCreate("recommendations", bestEffort).Execute(fail)
+
+	if len(paged) != 1 || paged[0] != "payments" {
+		t.Errorf("critical notifications = %v, want [payments]", paged)
+	}
+	if len(all) != 2 {
+		t.Errorf("all notifications = %v, want 2", all)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCallTimeout 调用超过 CallTimeout 仍未返回
+var ErrCallTimeout = errors.New("circuitbreaker: call timeout")
+
+// ErrDeadlineBudget 调用方截止时间扣除 DeadlineOverhead 后的预算耗尽
+// 携带该原因的错误同时匹配 context.DeadlineExceeded
+var ErrDeadlineBudget = errors.New("circuitbreaker: deadline budget exhausted")
+
+// errDeadlineBudget 预算耗尽时派生 ctx 的 Cause
+var errDeadlineBudget = fmt.Errorf("%w: %w", ErrDeadlineBudget, context.DeadlineExceeded)
+
+// TimeoutBound 单次调用实际生效的超时上限
+type TimeoutBound int
+
+const (
+	// BoundNone 未超时
+	BoundNone TimeoutBound = iota
+	// BoundCallTimeout 熔断器 CallTimeout 先到期
+	BoundCallTimeout
+	// BoundDeadline 调用方 ctx 的截止时间先到期
+	BoundDeadline
+)
+
+// String 返回超时上限名称
+func (b TimeoutBound) String() string {
+	switch b {
+	case BoundCallTimeout:
+		return "call_timeout"
+	case BoundDeadline:
+		return "deadline"
+	default:
+		return "none"
+	}
+}
+
+// CallContext 派生单次调用的 ctx，超时取 min(CallTimeout, ctx 剩余时间 - DeadlineOverhead)
+// 返回的 TimeoutBound 表示哪个上限更早，CallTimeout 到期时 context.Cause 返回 ErrCallTimeout；
+// HTTP/gRPC 适配器统一使用此方法，保证整个弹性链路上的超时一致
+func (cb *CircuitBreaker) CallContext(ctx context.Context) (context.Context, context.CancelFunc, TimeoutBound) {
+	settings := cb.GetSettings()
+	return deriveCallContext(ctx, settings.CallTimeout, settings.DeadlineOverhead)
+}
+
+// deriveCallContext 按 CallTimeout 与扣除 overhead 后的调用方截止时间派生 ctx
+func deriveCallContext(ctx context.Context, timeout, overhead time.Duration) (context.Context, context.CancelFunc, TimeoutBound) {
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		deadline = deadline.Add(-overhead)
+	}
+
+	if hasDeadline && (timeout <= 0 || !deadline.After(time.Now().Add(timeout))) {
+		if overhead > 0 {
+			callCtx, cancel := context.WithDeadlineCause(ctx, deadline, errDeadlineBudget)
+			return callCtx, cancel, BoundDeadline
+		}
+		callCtx, cancel := context.WithCancel(ctx)
+		return callCtx, cancel, BoundDeadline
+	}
+	if timeout > 0 {
+		callCtx, cancel := context.WithTimeoutCause(ctx, timeout, ErrCallTimeout)
+		return callCtx, cancel, BoundCallTimeout
+	}
+	callCtx, cancel := context.WithCancel(ctx)
+	return callCtx, cancel, BoundNone
+}
+
+// TimeoutBoundOf 判断由 CallContext 派生的 ctx 因哪个上限超时，未超时返回 BoundNone
+func TimeoutBoundOf(callCtx context.Context) TimeoutBound {
+	switch {
+	case callCtx.Err() == nil:
+		return BoundNone
+	case errors.Is(context.Cause(callCtx), ErrCallTimeout):
+		return BoundCallTimeout
+	case errors.Is(callCtx.Err(), context.DeadlineExceeded):
+		return BoundDeadline
+	default:
+		return BoundNone
+	}
+}
+
+// ContextError 调用 ctx 已结束时为 err 附加结束原因（ErrCallTimeout、ErrDeadlineBudget 等），
+// 供适配器在上报结果前使用，使熔断分类能区分调用方超时与依赖超时
+func ContextError(callCtx context.Context, err error) error {
+	if err == nil || callCtx.Err() == nil {
+		return err
+	}
+	cause := context.Cause(callCtx)
+	if errors.Is(err, cause) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", cause, err)
+}
+
+// RecordTimeout 按超时上限记录一次超时，供适配器在调用超时后统计
+func (cb *CircuitBreaker) RecordTimeout(bound TimeoutBound) {
+	switch bound {
+	case BoundCallTimeout:
+		cb.callTimeouts.Add(1)
+	case BoundDeadline:
+		cb.deadlineTimeouts.Add(1)
+	}
+}
+
+// outcome 一次调用的执行结果
+type outcome struct {
+	result   interface{}
+	err      error
+	panicked interface{}
+	// start 调用开始时间，用于延迟基线采样
+	start time.Time
+}
+
+// ExecuteContext 执行函数，带熔断保护和超时控制
+// 超过 CallTimeout 或 ctx 结束时立即返回，fn 会在后台继续运行直至返回，
+// 其最终结果计入 Stats 的 LateSuccesses/LateFailures 而不会丢失，此时发生的 panic 计为失败并计入 LatePanics，不会重新抛出；
+// fn 应尊重传入的 ctx 尽快退出。
+// 调用方 ctx 取消或超时导致的错误默认不计为失败，见 Settings.CountCallerCancellation；
+// 剩余时间不足 DeadlineOverhead 时不发起调用，直接返回 ErrDeadlineTooShort。
+// 配置了 Settings.Interceptors 时经拦截器执行，拦截器传给 next 的 ctx 即 fn 收到的 ctx 的父级
+func (cb *CircuitBreaker) ExecuteContext(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if err := cb.checkCall("ExecuteContext", fn == nil); err != nil {
+		return nil, err
+	}
+	if chain, ctx := cb.interceptorChain(ctx); chain != nil {
+		return chain(func(ctx context.Context) (interface{}, error) { return cb.executeContext(ctx, fn) })(ctx)
+	}
+	return cb.executeContext(ctx, fn)
+}
+
+// executeContext 实现 ExecuteContext，不经过拦截器
+func (cb *CircuitBreaker) executeContext(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	a, err := cb.admit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done, settings := a.done, a.settings
+
+	start := cb.enter()
+	if settings.CallTimeout <= 0 && ctx.Done() == nil {
+		// 既无超时也无法取消，直接同步执行
+		defer cb.exit(start)
+		defer func() {
+			if e := recover(); e != nil {
+				done(false)
+				panic(e)
+			}
+		}()
+		var result interface{}
+		if err = cb.faults.inject(ctx); err == nil {
+			result, err = fn(ctx)
+		}
+		outcome, sampled := cb.guardResult(&settings, settings.classifyContext(ctx, err), err, result, start)
+		reportOutcome(done, a.neutral, outcome)
+		cb.sample(&settings, outcome, sampled)
+		cb.observeSuccess(&settings, outcome, start)
+		cb.emitOutcome(&a, outcome, sampled, start)
+		return result, err
+	}
+
+	callCtx, cancel, _ := deriveCallContext(ctx, settings.CallTimeout, settings.DeadlineOverhead)
+
+	var (
+		mu        sync.Mutex
+		finished  bool
+		abandoned bool
+		ch        = make(chan outcome, 1)
+	)
+
+	go func() {
+		defer cancel()
+
+		o := outcome{start: start}
+		func() {
+			defer func() {
+				o.panicked = recover()
+			}()
+			if o.err = cb.faults.inject(callCtx); o.err == nil {
+				o.result, o.err = fn(callCtx)
+			}
+		}()
+		cb.exit(start)
+
+		mu.Lock()
+		if !abandoned {
+			finished = true
+			mu.Unlock()
+			ch <- o
+			return
+		}
+		mu.Unlock()
+
+		// 调用方已因超时放弃，补记最终结果
+		cb.leaked.Add(-1)
+		err := o.err
+		outcome := settings.classifyContext(ctx, err)
+		if o.panicked != nil {
+			// 调用方已返回，无人能够 recover，重新抛出会使整个进程退出；计为失败并计入 LatePanics
+			cb.latePanics.Add(1)
+			err = fmt.Errorf("circuitbreaker: panic: %v", o.panicked)
+			outcome = OutcomeFailure
+		}
+		switch outcome {
+		case OutcomeSuccess:
+			cb.lateSuccesses.Add(1)
+		case OutcomeFailure:
+			cb.lateFailures.Add(1)
+		}
+		if settings.DeferTimeoutOutcome {
+			cb.sample(&settings, reportOutcome(done, a.neutral, outcome), err)
+			cb.emitOutcome(&a, outcome, err, start)
+		}
+		// 超时后才成功的调用同样计入基线，否则基线会低估真实延迟
+		cb.observeSuccess(&settings, outcome, start)
+	}()
+
+	select {
+	case o := <-ch:
+		return cb.settle(ctx, callCtx, a, o)
+	case <-callCtx.Done():
+	}
+
+	mu.Lock()
+	if finished {
+		mu.Unlock()
+		return cb.settle(ctx, callCtx, a, <-ch)
+	}
+	abandoned = true
+	// 在锁内计数：协程看到 abandoned 后才会减一，LeakedCalls 不会短暂为负
+	cb.leaked.Add(1)
+	mu.Unlock()
+
+	bound := TimeoutBoundOf(callCtx)
+	cb.RecordTimeout(bound)
+
+	err = callCtx.Err()
+	if cause := context.Cause(callCtx); errors.Is(cause, ErrCallTimeout) || errors.Is(cause, ErrDeadlineBudget) {
+		err = cause
+	}
+	if !settings.DeferTimeoutOutcome {
+		outcome := cb.sample(&settings, reportOutcome(done, a.neutral, settings.classifyContext(ctx, err)), err)
+		cb.emitOutcome(&a, outcome, err, start)
+	}
+	return nil, err
+}
+
+// settle 按调用结果计数并返回，panic 会在调用方协程中重新抛出
+func (cb *CircuitBreaker) settle(ctx, callCtx context.Context, a admission, o outcome) (interface{}, error) {
+	if o.err != nil {
+		// fn 自行响应 ctx 到期返回的情况同样计入超时
+		cb.RecordTimeout(TimeoutBoundOf(callCtx))
+		if errors.Is(context.Cause(callCtx), ErrDeadlineBudget) {
+			// 向外层传递预算耗尽，避免链式熔断的外层将其计为失败
+			o.err = ContextError(callCtx, o.err)
+		}
+	}
+	if o.panicked != nil {
+		a.done(false)
+		panic(o.panicked)
+	}
+	outcome, sampled := cb.guardResult(&a.settings, a.settings.classifyContext(ctx, o.err), o.err, o.result, o.start)
+	reportOutcome(a.done, a.neutral, outcome)
+	cb.sample(&a.settings, outcome, sampled)
+	cb.observeSuccess(&a.settings, outcome, o.start)
+	cb.emitOutcome(&a, outcome, sampled, o.start)
+	return o.result, o.err
+}
@@ -0,0 +1,299 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteContext_Success(t *testing.T) {
+	settings := DefaultSettings()
+	settings.CallTimeout = time.Second
+	cb := NewCircuitBreaker("test", settings)
+
+	result, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	})
+
+	if err != nil || result != "ok" {
+		t.Errorf("ExecuteContext() = %v, %v, want ok, nil", result, err)
+	}
+	if got := cb.Counts().TotalSuccesses; got != 1 {
+		t.Errorf("TotalSuccesses = %v, want %v", got, 1)
+	}
+}
+
+func TestExecuteContext_TimeoutRecordsLateOutcome(t *testing.T) {
+	settings := DefaultSettings()
+	settings.CallTimeout = 20 * time.Millisecond
+	cb := NewCircuitBreaker("test", settings)
+
+	release := make(chan struct{})
+	_, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "late", nil
+	})
+
+	if !errors.Is(err, ErrCallTimeout) {
+		t.Fatalf("ExecuteContext() error = %v, want %v", err, ErrCallTimeout)
+	}
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %v, want %v", got, 1)
+	}
+	if got := cb.Stats().LeakedCalls; got != 1 {
+		t.Errorf("LeakedCalls = %v, want %v", got, 1)
+	}
+
+	close(release)
+	waitFor(t, func() bool { return cb.Stats().LeakedCalls == 0 })
+
+	stats := cb.Stats()
+	if stats.LateSuccesses != 1 {
+		t.Errorf("LateSuccesses = %v, want %v", stats.LateSuccesses, 1)
+	}
+	if stats.InFlight != 0 {
+		t.Errorf("InFlight = %v, want %v", stats.InFlight, 0)
+	}
+	if got := cb.Counts().TotalSuccesses; got != 0 {
+		t.Errorf("TotalSuccesses = %v, want %v (late outcome must not be double counted)", got, 0)
+	}
+}
+
+func TestExecuteContext_DeferTimeoutOutcome(t *testing.T) {
+	settings := DefaultSettings()
+	settings.CallTimeout = 20 * time.Millisecond
+	settings.DeferTimeoutOutcome = true
+	cb := NewCircuitBreaker("test", settings)
+
+	release := make(chan struct{})
+	_, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-release
+		return nil, errors.New("late failure")
+	})
+	if !errors.Is(err, ErrCallTimeout) {
+		t.Fatalf("ExecuteContext() error = %v, want %v", err, ErrCallTimeout)
+	}
+	if got := cb.Counts().TotalFailures; got != 0 {
+		t.Errorf("TotalFailures before late outcome = %v, want %v", got, 0)
+	}
+
+	close(release)
+	waitFor(t, func() bool { return cb.Counts().TotalFailures == 1 })
+
+	if got := cb.Stats().LateFailures; got != 1 {
+		t.Errorf("LateFailures = %v, want %v", got, 1)
+	}
+}
+
+func TestExecuteContext_ParentCanceled(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		t.Error("fn should not run with a canceled context")
+		return nil, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ExecuteContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestExecuteContext_Panic(t *testing.T) {
+	settings := DefaultSettings()
+	settings.CallTimeout = time.Second
+	cb := NewCircuitBreaker("test", settings)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("panic should propagate to the caller")
+		}
+		if got := cb.Counts().TotalFailures; got != 1 {
+			t.Errorf("TotalFailures = %v, want %v", got, 1)
+		}
+	}()
+
+	cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		panic("boom")
+	})
+}
+
+func TestExecuteContext_LatePanicIsCounted(t *testing.T) {
+	settings := DefaultSettings()
+	settings.CallTimeout = 10 * time.Millisecond
+	cb := NewCircuitBreaker("test", settings)
+
+	_, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(5 * time.Millisecond)
+		panic("late boom")
+	})
+	if !errors.Is(err, ErrCallTimeout) {
+		t.Fatalf("ExecuteContext() error = %v, want %v", err, ErrCallTimeout)
+	}
+
+	waitFor(t, func() bool { return cb.Stats().LatePanics == 1 })
+	if stats := cb.Stats(); stats.LateFailures != 1 || stats.LeakedCalls != 0 {
+		t.Errorf("LateFailures, LeakedCalls = %v, %v, want 1, 0", stats.LateFailures, stats.LeakedCalls)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCallContext(t *testing.T) {
+	settings := DefaultSettings()
+	settings.CallTimeout = time.Second
+	cb := NewCircuitBreaker("test", settings)
+
+	_, cancel, bound := cb.CallContext(context.Background())
+	cancel()
+	if bound != BoundCallTimeout {
+		t.Errorf("bound = %v, want %v", bound, BoundCallTimeout)
+	}
+
+	ctx, cancelParent := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancelParent()
+	callCtx, cancel, bound := cb.CallContext(ctx)
+	defer cancel()
+	if bound != BoundDeadline {
+		t.Errorf("bound = %v, want %v", bound, BoundDeadline)
+	}
+
+	<-callCtx.Done()
+	if got := TimeoutBoundOf(callCtx); got != BoundDeadline {
+		t.Errorf("TimeoutBoundOf() = %v, want %v", got, BoundDeadline)
+	}
+}
+
+func TestExecuteContext_RecordsTimeoutBound(t *testing.T) {
+	settings := DefaultSettings()
+	settings.CallTimeout = 10 * time.Millisecond
+	cb := NewCircuitBreaker("test", settings)
+
+	cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if got := cb.Stats().CallTimeouts; got != 1 {
+		t.Errorf("CallTimeouts = %v, want %v", got, 1)
+	}
+}
+
+func TestExecuteContext_CallerCancellationNotCounted(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		cancel()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ExecuteContext() error = %v, want %v", err, context.Canceled)
+	}
+	if got := cb.Counts().TotalFailures; got != 0 {
+		t.Errorf("TotalFailures = %v, want %v", got, 0)
+	}
+}
+
+func TestExecuteContext_CountCallerCancellation(t *testing.T) {
+	settings := DefaultSettings()
+	settings.CountCallerCancellation = true
+	cb := NewCircuitBreaker("test", settings)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		cancel()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %v, want %v", got, 1)
+	}
+}
+
+func TestAllowContext_CallerCancellationNotCounted(t *testing.T) {
+	cb := NewCircuitBreaker("test", DefaultSettings())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done, err := cb.AllowContext(ctx)
+	if err != nil {
+		t.Fatalf("AllowContext() error = %v", err)
+	}
+	cancel()
+	done(context.Canceled)
+
+	if got := cb.Counts().TotalFailures; got != 0 {
+		t.Errorf("TotalFailures = %v, want %v", got, 0)
+	}
+	if got := cb.Stats().InFlight; got != 0 {
+		t.Errorf("InFlight = %v, want %v", got, 0)
+	}
+}
+
+func TestCallContext_DeadlineOverhead(t *testing.T) {
+	settings := DefaultSettings()
+	settings.DeadlineOverhead = 50 * time.Millisecond
+	cb := NewCircuitBreaker("test", settings)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	callCtx, cancelCall, bound := cb.CallContext(ctx)
+	defer cancelCall()
+
+	if bound != BoundDeadline {
+		t.Errorf("bound = %v, want %v", bound, BoundDeadline)
+	}
+	outer, _ := ctx.Deadline()
+	inner, _ := callCtx.Deadline()
+	if got := outer.Sub(inner); got != settings.DeadlineOverhead {
+		t.Errorf("reserved = %v, want %v", got, settings.DeadlineOverhead)
+	}
+}
+
+func TestExecuteContext_ChainedBudget(t *testing.T) {
+	settings := DefaultSettings()
+	settings.DeadlineOverhead = 30 * time.Millisecond
+	endpoint := NewCircuitBreaker("endpoint", settings)
+	host := NewCircuitBreaker("host", settings)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := endpoint.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		return host.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	})
+
+	if !errors.Is(err, ErrDeadlineBudget) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ExecuteContext() error = %v, want %v", err, ErrDeadlineBudget)
+	}
+	if ctx.Err() != nil {
+		t.Error("caller ctx expired before the inner layers")
+	}
+	for _, cb := range []*CircuitBreaker{endpoint, host} {
+		if got := cb.Counts().TotalFailures; got != 0 {
+			t.Errorf("%s TotalFailures = %v, want %v", cb.Name(), got, 0)
+		}
+	}
+	if got := host.Stats().DeadlineTimeouts; got != 1 {
+		t.Errorf("host DeadlineTimeouts = %v, want %v", got, 1)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	"github.com/go-anyway/framework-circuitbreaker/internal/testhook"
+)
+
+func init() {
+	testhook.TransitionTo = func(cb any, state gobreaker.State) error {
+		return cb.(*CircuitBreaker).transitionTo(state)
+	}
+}
+
+// transitionTo 不经真实调用将熔断器切换到 state，见 circuitbreakertest.TransitionTo：
+// 先按 Reset 恢复为关闭，需要打开时向底层熔断器注入一次强制熔断的失败，需要半开时再清除按 Clock 计算的打开期，
+// 状态变更回调与监听者照常收到每一步变更。进入半开依赖 Settings.Clock，由调用方保证
+func (cb *CircuitBreaker) transitionTo(state gobreaker.State) error {
+	cb.Reset()
+	if state == gobreaker.StateClosed {
+		return nil
+	}
+
+	cb.mu.RLock()
+	backend := cb.cb
+	cb.mu.RUnlock()
+	cb.forcingTrip.Store(true)
+	done, err := backend.Allow()
+	if err == nil {
+		done(false)
+	}
+	cb.forcingTrip.Store(false)
+	if err != nil || state == gobreaker.StateOpen {
+		return err
+	}
+
+	// 设置 Clock 时底层熔断器的 Timeout 为 1ns，清除打开期后即可进入半开
+	cb.timeoutUntil.Store(0)
+	for {
+		cb.mu.RLock()
+		current := cb.cb.State()
+		cb.mu.RUnlock()
+		if current != gobreaker.StateOpen {
+			break
+		}
+		time.Sleep(time.Microsecond)
+	}
+	cb.flushHalfOpen()
+	return nil
+}
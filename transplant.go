@@ -0,0 +1,85 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"sync/atomic"
+
+	"github.com/sony/gobreaker"
+)
+
+// transplantLimit 单次移植回放的最大请求数，超过时按比例缩放，保持失败率不变
+const transplantLimit = 1 << 16
+
+// newBackendWithCounts 按新配置创建 gobreaker 实例，并通过合成的成功/失败回放移植 counts，
+// 使基于失败率的策略在配置重载后不必从零开始统计
+// 回放期间不评估 ReadyToTrip，移植本身不会打开熔断器，由下一次真实失败触发判定
+// 调用方需持有 cb.mu
+func (cb *CircuitBreaker) newBackendWithCounts(settings Settings, counts gobreaker.Counts) *gobreaker.TwoStepCircuitBreaker {
+	var replaying atomic.Bool
+	cbSettings := cb.buildSettings(settings)
+	readyToTrip := cbSettings.ReadyToTrip
+	if readyToTrip == nil {
+		readyToTrip = defaultReadyToTrip
+	}
+	cbSettings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return !replaying.Load() && readyToTrip(counts)
+	}
+	backend := gobreaker.NewTwoStepCircuitBreaker(cbSettings)
+
+	replaying.Store(true)
+	defer replaying.Store(false)
+	for _, success := range transplantSequence(counts) {
+		done, err := backend.Allow()
+		if err != nil {
+			break
+		}
+		done(success)
+	}
+	return backend
+}
+
+// transplantSequence 生成可复现 counts 的结果序列：先回放非连续部分，再以连续部分结尾，
+// 使回放后的总数与连续数均与原值一致（超过 transplantLimit 时总数按比例缩放）
+// 未完成的请求无法回放，不计入
+func transplantSequence(counts gobreaker.Counts) []bool {
+	successes, failures := counts.TotalSuccesses, counts.TotalFailures
+	consecutiveSuccesses, consecutiveFailures := counts.ConsecutiveSuccesses, counts.ConsecutiveFailures
+	if total := uint64(successes) + uint64(failures); total > transplantLimit {
+		successes = uint32(uint64(successes) * transplantLimit / total)
+		failures = uint32(uint64(failures) * transplantLimit / total)
+		consecutiveSuccesses = min(consecutiveSuccesses, successes)
+		consecutiveFailures = min(consecutiveFailures, failures)
+	}
+
+	seq := make([]bool, 0, successes+failures)
+	appendN := func(success bool, n uint32) {
+		for i := uint32(0); i < n; i++ {
+			seq = append(seq, success)
+		}
+	}
+	if consecutiveFailures > 0 {
+		appendN(false, failures-consecutiveFailures)
+		appendN(true, successes)
+		appendN(false, consecutiveFailures)
+	} else {
+		appendN(true, successes-consecutiveSuccesses)
+		appendN(false, failures)
+		appendN(true, consecutiveSuccesses)
+	}
+	return seq
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestUpdateSettings_TransplantsCounts(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip = FailureRate(0.9)
+	cb := NewCircuitBreaker("transplant", settings)
+
+	outcomes := []error{nil, errors.New("x"), nil, errors.New("x"), errors.New("x")}
+	for _, err := range outcomes {
+		cb.Execute(func() (interface{}, error) { return nil, err })
+	}
+	before := cb.Counts()
+
+	// 新策略按移植后的失败率（60%）已满足，但移植本身不应打开熔断器
+	settings.ReadyToTrip = FailureRate(0.5)
+	settings.MaxRequests = 3
+	cb.UpdateSettings(settings)
+
+	if got := cb.Counts(); got != before {
+		t.Errorf("Counts() after UpdateSettings = %+v, want %+v", got, before)
+	}
+	if cb.State() != gobreaker.StateClosed {
+		t.Fatalf("State() = %v, want closed after transplant", cb.State())
+	}
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("x") })
+	if cb.State() != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want open on next failure with warm counts", cb.State())
+	}
+}
+
+func TestTransplantSequence(t *testing.T) {
+	for _, counts := range []gobreaker.Counts{
+		{},
+		{Requests: 4, TotalSuccesses: 4, ConsecutiveSuccesses: 4},
+		{Requests: 4, TotalFailures: 4, ConsecutiveFailures: 4},
+		{Requests: 7, TotalSuccesses: 3, TotalFailures: 4, ConsecutiveSuccesses: 2},
+		{Requests: 7, TotalSuccesses: 3, TotalFailures: 4, ConsecutiveFailures: 1},
+	} {
+		var got gobreaker.Counts
+		for _, success := range transplantSequence(counts) {
+			got.Requests++
+			if success {
+				got.TotalSuccesses++
+				got.ConsecutiveSuccesses++
+				got.ConsecutiveFailures = 0
+			} else {
+				got.TotalFailures++
+				got.ConsecutiveFailures++
+				got.ConsecutiveSuccesses = 0
+			}
+		}
+		if got != counts {
+			t.Errorf("replay of %+v = %+v", counts, got)
+		}
+	}
+
+	big := gobreaker.Counts{TotalSuccesses: 3 << 20, TotalFailures: 1 << 20, ConsecutiveFailures: 5}
+	seq := transplantSequence(big)
+	if len(seq) > transplantLimit {
+		t.Errorf("len = %d, want <= %d", len(seq), transplantLimit)
+	}
+	var failures int
+	for _, success := range seq {
+		if !success {
+			failures++
+		}
+	}
+	if rate := float64(failures) / float64(len(seq)); rate < 0.24 || rate > 0.26 {
+		t.Errorf("scaled failure rate = %v, want 0.25", rate)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// TripCause 中内置的触发来源
+const (
+	// TripByPressure 外部饱和度达到 MaxPressure
+	TripByPressure = "pressure"
+	// TripByHalfOpenProbe 半开探测失败后重新打开
+	TripByHalfOpenProbe = "half_open_probe"
+	// TripByReadyToTrip 自定义 ReadyToTrip 触发，且未配置 TripPolicyName
+	TripByReadyToTrip = "ready_to_trip"
+	// TripByDefault gobreaker 默认策略（连续失败超过 5 次）触发
+	TripByDefault = "default"
+)
+
+// TripCause 最近一次进入打开状态的原因
+type TripCause struct {
+	// At 打开时间
+	At time.Time
+	// From 打开前的状态（关闭或半开）
+	From gobreaker.State
+	// Policy 触发的策略：Settings.TripPolicyName 的返回值或 TripBy* 常量
+	Policy string
+	// Counts 触发时的窗口计数，半开探测失败时为零值
+	Counts gobreaker.Counts
+	// FailureRate 触发时的窗口失败率
+	FailureRate float64
+	// Pressure 触发时的外部饱和度，未配置 PressureFunc 时为 0
+	Pressure float64
+	// Error 打开时正在上报的失败调用的错误样本，尚未上报时为 nil
+	Error *ErrorSample
+
+	// pending 仍在等待关联触发错误，恢复关闭后不再关联
+	pending bool
+}
+
+// NamedPolicy 带名称的熔断策略，名称记录在 TripCause.Policy 中
+type NamedPolicy struct {
+	Name   string
+	Policy TripPolicy
+}
+
+// AnyOfNamed 与 AnyOf 相同，同时返回可赋值给 Settings.TripPolicyName 的函数，
+// 该函数返回 counts 下第一个满足的策略名称
+func AnyOfNamed(policies ...NamedPolicy) (TripPolicy, func(counts gobreaker.Counts) string) {
+	name := func(counts gobreaker.Counts) string {
+		for _, p := range policies {
+			if p.Policy(counts) {
+				return p.Name
+			}
+		}
+		return ""
+	}
+	return func(counts gobreaker.Counts) bool { return name(counts) != "" }, name
+}
+
+// LastTripCause 返回最近一次进入打开状态的原因，从未打开时返回 false；Reset 后仍保留
+func (cb *CircuitBreaker) LastTripCause() (TripCause, bool) {
+	if c := cb.lastTrip.Load(); c != nil {
+		cause := *c
+		cause.pending = false
+		return cause, true
+	}
+	return TripCause{}, false
+}
+
+// lastTripCause 返回最近一次打开原因的副本，从未打开时返回 nil
+func (cb *CircuitBreaker) lastTripCause() *TripCause {
+	if c, ok := cb.LastTripCause(); ok {
+		return &c
+	}
+	return nil
+}
+
+// withTripCause ReadyToTrip 满足时记录原因；在 gobreaker 内部锁中调用，只使用原子操作
+func (cb *CircuitBreaker) withTripCause(readyToTrip func(counts gobreaker.Counts) bool, settings Settings) func(counts gobreaker.Counts) bool {
+	return func(counts gobreaker.Counts) bool {
+		if !readyToTrip(counts) {
+			return false
+		}
+		cause := &TripCause{
+			At:          cb.now(),
+			From:        gobreaker.StateClosed,
+			Counts:      counts,
+			FailureRate: failureRate(counts),
+			pending:     true,
+		}
+		if settings.PressureFunc != nil && settings.MaxPressure > 0 {
+			cause.Pressure = settings.PressureFunc()
+		}
+		switch {
+		case settings.PressureFunc != nil && settings.MaxPressure > 0 && cause.Pressure >= settings.MaxPressure:
+			cause.Policy = TripByPressure
+		case settings.TripPolicyName != nil:
+			cause.Policy = settings.TripPolicyName(counts)
+		case settings.ReadyToTrip != nil:
+			cause.Policy = TripByReadyToTrip
+		default:
+			cause.Policy = TripByDefault
+		}
+		if cause.Policy == "" {
+			cause.Policy = TripByReadyToTrip
+		}
+		cb.lastTrip.Store(cause)
+		return true
+	}
+}
+
+// recordProbeTrip 记录半开探测失败导致的重新打开
+func (cb *CircuitBreaker) recordProbeTrip() {
+	cb.lastTrip.Store(&TripCause{At: cb.now(), From: gobreaker.StateHalfOpen, Policy: TripByHalfOpenProbe, pending: true})
+}
+
+// sealTrip 恢复关闭后停止为最近一次打开原因关联错误
+func (cb *CircuitBreaker) sealTrip() {
+	if c := cb.lastTrip.Load(); c != nil && c.pending {
+		cause := *c
+		cause.pending = false
+		cb.lastTrip.CompareAndSwap(c, &cause)
+	}
+}
+
+// attachTripError 为尚未关联错误的最近一次打开原因补充触发的错误样本
+func (cb *CircuitBreaker) attachTripError(sample ErrorSample) {
+	c := cb.lastTrip.Load()
+	if c == nil || !c.pending {
+		return
+	}
+	cause := *c
+	cause.pending = false
+	cause.Error = &sample
+	cb.lastTrip.CompareAndSwap(c, &cause)
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestLastTripCause_NamedPolicy(t *testing.T) {
+	settings := DefaultSettings()
+	settings.ReadyToTrip, settings.TripPolicyName = AnyOfNamed(
+		NamedPolicy{Name: "slow_burn", Policy: AllOf(MinRequests(10), FailureRate(0.5))},
+		NamedPolicy{Name: "hard_down", Policy: ConsecutiveFailures(3)},
+	)
+	cb := NewCircuitBreaker("svc", settings)
+	if _, ok := cb.LastTripCause(); ok {
+		t.Fatal("LastTripCause() before any trip should be empty")
+	}
+
+	for i := 0; i < 3; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, Annotate(errors.New("refused"), 503, "10.0.0.1:80") })
+	}
+	cause, ok := cb.LastTripCause()
+	if !ok || cause.Policy != "hard_down" || cause.From != gobreaker.StateClosed {
+		t.Fatalf("LastTripCause() = %+v, %v, want hard_down from closed", cause, ok)
+	}
+	if cause.Counts.ConsecutiveFailures != 3 || cause.FailureRate != 1 {
+		t.Errorf("Counts = %+v, FailureRate = %v, want 3 consecutive failures at 100%%", cause.Counts, cause.FailureRate)
+	}
+	if cause.Error == nil || cause.Error.Error != "refused" || cause.Error.StatusCode != 503 {
+		t.Errorf("Error = %+v, want triggering error sample", cause.Error)
+	}
+	if got := cb.Stats().LastTripCause; got == nil || got.Policy != "hard_down" {
+		t.Errorf("Stats().LastTripCause = %+v, want hard_down", got)
+	}
+
+	// 恢复后的失败不会被关联到已结束的故障周期
+	cb.Reset()
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("later") })
+	if cause, _ := cb.LastTripCause(); cause.Error == nil || cause.Error.Error != "refused" {
+		t.Errorf("Error after reset = %+v, want refused", cause.Error)
+	}
+}
+
+func TestLastTripCause_Sources(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		cb := NewCircuitBreaker("svc", DefaultSettings())
+		for i := 0; i < 6; i++ {
+			cb.Execute(func() (interface{}, error) { return nil, errors.New("fail") })
+		}
+		if cause, _ := cb.LastTripCause(); cause.Policy != TripByDefault {
+			t.Errorf("Policy = %q, want %q", cause.Policy, TripByDefault)
+		}
+	})
+
+	t.Run("pressure", func(t *testing.T) {
+		settings := DefaultSettings()
+		settings.PressureFunc = func() float64 { return 0.95 }
+		settings.MaxPressure = 0.9
+		cb := NewCircuitBreaker("svc", settings)
+		cb.RecordFailure()
+		if cause, _ := cb.LastTripCause(); cause.Policy != TripByPressure || cause.Pressure != 0.95 {
+			t.Errorf("cause = %+v, want pressure 0.95", cause)
+		}
+	})
+
+	t.Run("half-open probe", func(t *testing.T) {
+		settings := DefaultSettings()
+		settings.ReadyToTrip = ConsecutiveFailures(1)
+		settings.Timeout = 20 * time.Millisecond
+		cb := NewCircuitBreaker("svc", settings)
+		tripBreaker(cb)
+		waitFor(t, func() bool { return cb.State() == gobreaker.StateHalfOpen })
+		cb.Execute(func() (interface{}, error) { return nil, errors.New("still down") })
+
+		cause, _ := cb.LastTripCause()
+		if cause.Policy != TripByHalfOpenProbe || cause.From != gobreaker.StateHalfOpen {
+			t.Errorf("cause = %+v, want half-open probe", cause)
+		}
+		if cause.Error == nil || cause.Error.Error != "still down" {
+			t.Errorf("Error = %+v, want probe error", cause.Error)
+		}
+	})
+}
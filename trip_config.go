@@ -0,0 +1,224 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sony/gobreaker"
+)
+
+// tripFields 各预设类型可用的字段
+var tripFields = map[string][]string{
+	PolicyConsecutiveFailures: {"failures"},
+	PolicyFailureRate:         {"rate", "min_requests"},
+	PolicySLOBurnRate:         {"target", "burn_rate", "min_requests"},
+}
+
+// TripConfig 声明式的内置熔断预设，与 policies 二选一，字段按 Type 使用：
+//
+//	consecutive_failures  failures
+//	failure_rate          rate, min_requests
+//	slo_burn_rate         target, burn_rate, min_requests
+//
+// 未知字段、拼写错误与类型不适用的字段在校验时报告，并给出最接近的字段名
+type TripConfig struct {
+	Type        string  `json:"type"`
+	Failures    uint32  `json:"failures,omitempty"`
+	Rate        float64 `json:"rate,omitempty"`
+	MinRequests uint32  `json:"min_requests,omitempty"`
+	Target      float64 `json:"target,omitempty"`
+	BurnRate    float64 `json:"burn_rate,omitempty"`
+
+	// unknown 解析时遇到的未知字段，由 validate 报告以便附带路径与建议
+	unknown []string
+}
+
+// UnmarshalJSON 解析预设，记录未知字段而不是直接失败
+func (t *TripConfig) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("trip must be an object: %w", err)
+	}
+	type plain TripConfig
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("trip: %w", err)
+	}
+	*t = TripConfig(p)
+	known := tripFieldNames()
+	for name := range fields {
+		if name != "type" && !slices.Contains(known, name) {
+			t.unknown = append(t.unknown, name)
+		}
+	}
+	sort.Strings(t.unknown)
+	return nil
+}
+
+// validate 校验预设，path 为 trip 字段的路径
+func (t *TripConfig) validate(path string) []error {
+	var errs []error
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, &ConfigError{Path: path + "." + field, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range t.unknown {
+		candidates := append(tripFieldNames(), "type")
+		if s := suggest(name, tripFields[t.Type]); s != "" {
+			fail(name, "unknown field, did you mean %q?", s)
+		} else if s := suggest(name, candidates); s != "" {
+			fail(name, "unknown field, did you mean %q?", s)
+		} else {
+			fail(name, "unknown field")
+		}
+	}
+
+	allowed, ok := tripFields[t.Type]
+	if !ok {
+		types := make([]string, 0, len(tripFields))
+		for name := range tripFields {
+			types = append(types, name)
+		}
+		sort.Strings(types)
+		hint := ""
+		if s := suggest(t.Type, types); s != "" {
+			hint = fmt.Sprintf(", did you mean %q?", s)
+		}
+		fail("type", "unknown trip type %q%s (valid: %s)", t.Type, hint, strings.Join(types, ", "))
+		return errs
+	}
+
+	for name, set := range t.setFields() {
+		if set && !slices.Contains(allowed, name) {
+			fail(name, "not used by type %q (uses %s)", t.Type, strings.Join(allowed, ", "))
+		}
+	}
+	switch t.Type {
+	case PolicyConsecutiveFailures:
+		if t.Failures < 1 {
+			fail("failures", "must be a positive integer")
+		}
+	case PolicyFailureRate:
+		if t.Rate <= 0 || t.Rate > 1 {
+			fail("rate", "must be in (0, 1], got %v", t.Rate)
+		}
+	case PolicySLOBurnRate:
+		if t.Target <= 0 || t.Target >= 1 {
+			fail("target", "must be in (0, 1), got %v", t.Target)
+		}
+		if t.BurnRate <= 0 {
+			fail("burn_rate", "must be positive, got %v", t.BurnRate)
+		}
+	}
+	return errs
+}
+
+// setFields 返回各参数字段是否设置（非零）
+func (t *TripConfig) setFields() map[string]bool {
+	return map[string]bool{
+		"failures":     t.Failures != 0,
+		"rate":         t.Rate != 0,
+		"min_requests": t.MinRequests != 0,
+		"target":       t.Target != 0,
+		"burn_rate":    t.BurnRate != 0,
+	}
+}
+
+// policy 构造预设对应的熔断策略，调用前应已通过校验
+func (t *TripConfig) policy() TripPolicy {
+	switch t.Type {
+	case PolicyConsecutiveFailures:
+		return ConsecutiveFailures(t.Failures)
+	case PolicyFailureRate:
+		if t.MinRequests > 0 {
+			return AllOf(MinRequests(t.MinRequests), FailureRate(t.Rate))
+		}
+		return FailureRate(t.Rate)
+	case PolicySLOBurnRate:
+		return SLOBurnRate(SLO{Target: t.Target, BurnRate: t.BurnRate, MinRequests: t.MinRequests})
+	}
+	return nil
+}
+
+// apply 设置 ReadyToTrip 与 TripPolicyName
+func (t *TripConfig) apply(s *Settings) {
+	policy := t.policy()
+	if policy == nil {
+		return
+	}
+	name := t.Type
+	s.ReadyToTrip = policy
+	s.TripPolicyName = func(gobreaker.Counts) string { return name }
+}
+
+// tripFieldNames 返回所有预设参数字段名（有序、去重）
+func tripFieldNames() []string {
+	var names []string
+	for _, fields := range tripFields {
+		for _, f := range fields {
+			if !slices.Contains(names, f) {
+				names = append(names, f)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// suggest 返回与 name 最接近的候选：忽略大小写与分隔符后相同（如 minRequests 与 min_requests），
+// 或编辑距离不超过 2；没有足够接近的候选时返回空
+func suggest(name string, candidates []string) string {
+	normalize := func(s string) string {
+		return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+	}
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if normalize(c) == normalize(name) {
+			return c
+		}
+		if d := editDistance(normalize(name), normalize(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算 Levenshtein 距离
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
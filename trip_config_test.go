@@ -0,0 +1,93 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestConfig_Trip(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{"version":2,"breakers":{
+		"payments":{"trip":{"type":"failure_rate","rate":0.5,"min_requests":20}},
+		"search":{"trip":{"type":"consecutive_failures","failures":3}},
+		"checkout":{"trip":{"type":"slo_burn_rate","target":0.999,"burn_rate":14.4,"min_requests":100}}
+	}}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	payments := cfg.Settings("payments")
+	if payments.ReadyToTrip(gobreaker.Counts{Requests: 10, TotalFailures: 10}) {
+		t.Error("payments tripped below min_requests")
+	}
+	if !payments.ReadyToTrip(gobreaker.Counts{Requests: 20, TotalFailures: 10}) {
+		t.Error("payments did not trip at 50% of 20 requests")
+	}
+	if got := payments.TripPolicyName(gobreaker.Counts{}); got != PolicyFailureRate {
+		t.Errorf("TripPolicyName() = %q, want %q", got, PolicyFailureRate)
+	}
+	if !cfg.Settings("search").ReadyToTrip(gobreaker.Counts{ConsecutiveFailures: 3}) {
+		t.Error("search did not trip after 3 consecutive failures")
+	}
+	if !cfg.Settings("checkout").ReadyToTrip(gobreaker.Counts{Requests: 100, TotalFailures: 2}) {
+		t.Error("checkout did not trip at burn rate 20")
+	}
+}
+
+func TestConfig_TripValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		trip string
+		want string
+	}{
+		{
+			name: "camel case field",
+			trip: `{"type":"failure_rate","rate":0.5,"minRequests":20}`,
+			want: `breakers.svc.trip.minRequests: unknown field, did you mean "min_requests"?`,
+		},
+		{
+			name: "misspelled field",
+			trip: `{"type":"consecutive_failures","failurs":3}`,
+			want: `breakers.svc.trip.failurs: unknown field, did you mean "failures"?`,
+		},
+		{
+			name: "camel case type",
+			trip: `{"type":"failureRate","rate":0.5}`,
+			want: `breakers.svc.trip.type: unknown trip type "failureRate", did you mean "failure_rate"?`,
+		},
+		{
+			name: "field of another type",
+			trip: `{"type":"consecutive_failures","failures":3,"rate":0.5}`,
+			want: `breakers.svc.trip.rate: not used by type "consecutive_failures" (uses failures)`,
+		},
+		{
+			name: "out of range",
+			trip: `{"type":"failure_rate","rate":1.5}`,
+			want: `breakers.svc.trip.rate: must be in (0, 1], got 1.5`,
+		},
+		{
+			name: "missing required",
+			trip: `{"type":"slo_burn_rate","target":0.99}`,
+			want: `breakers.svc.trip.burn_rate: must be positive, got 0`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(`{"version":2,"breakers":{"svc":{"trip":` + tt.trip + `}}}`))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseConfig() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	_, err := ParseConfig([]byte(`{"version":2,"breakers":{"svc":{
+		"trip":{"type":"consecutive_failures","failures":3},
+		"policies":[{"type":"failure_rate","threshold":0.5}]
+	}}}`))
+	if err == nil || !strings.Contains(err.Error(), "breakers.svc.trip: cannot be combined with policies") {
+		t.Errorf("ParseConfig() with trip and policies error = %v", err)
+	}
+}
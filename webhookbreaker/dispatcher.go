@@ -0,0 +1,264 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package webhookbreaker 为 webhook 投递子系统提供按目标隔离的熔断、持久化重试队列，
+// 以及目标熔断打开期间的投递暂停
+package webhookbreaker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// Result 投递结果
+type Result int
+
+const (
+	// Delivered 投递成功
+	Delivered Result = iota
+	// Queued 目标暂停或投递失败，已进入重试队列
+	Queued
+	// Rejected 目标拒绝（4xx）或超过最大重试次数，已放弃
+	Rejected
+)
+
+// String 返回投递结果名称
+func (r Result) String() string {
+	switch r {
+	case Delivered:
+		return "delivered"
+	case Queued:
+		return "queued"
+	default:
+		return "rejected"
+	}
+}
+
+// Dispatcher webhook 投递器，每个目标使用独立熔断器
+type Dispatcher struct {
+	client      *http.Client
+	queue       Queue
+	registry    *circuitbreaker.Registry
+	settings    circuitbreaker.Settings
+	keyFunc     func(u *url.URL) string
+	backoff     func(attempts int) time.Duration
+	maxAttempts int
+	onGiveUp    func(d Delivery)
+}
+
+// Option 投递器配置项
+type Option func(*Dispatcher)
+
+// WithClient 设置 HTTP 客户端，默认 http.DefaultClient
+func WithClient(client *http.Client) Option {
+	return func(d *Dispatcher) { d.client = client }
+}
+
+// WithRegistry 设置熔断器注册表，便于与健康检查、管理接口共享
+func WithRegistry(r *circuitbreaker.Registry) Option {
+	return func(d *Dispatcher) { d.registry = r }
+}
+
+// WithSettings 设置每个目标熔断器的配置
+func WithSettings(settings circuitbreaker.Settings) Option {
+	return func(d *Dispatcher) { d.settings = settings }
+}
+
+// WithKeyFunc 设置目标划分方式，默认 scheme://host/path（忽略查询参数）
+func WithKeyFunc(fn func(u *url.URL) string) Option {
+	return func(d *Dispatcher) { d.keyFunc = fn }
+}
+
+// WithBackoff 设置重试退避，默认从 1 秒开始指数退避，最长 1 小时
+func WithBackoff(fn func(attempts int) time.Duration) Option {
+	return func(d *Dispatcher) { d.backoff = fn }
+}
+
+// WithMaxAttempts 设置最大尝试次数，默认 10
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) { d.maxAttempts = n }
+}
+
+// WithGiveUp 设置放弃投递时的回调（如写入死信表）
+func WithGiveUp(fn func(d Delivery)) Option {
+	return func(d *Dispatcher) { d.onGiveUp = fn }
+}
+
+// New 创建投递器
+func New(queue Queue, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		client:      http.DefaultClient,
+		queue:       queue,
+		registry:    circuitbreaker.NewRegistry(),
+		settings:    circuitbreaker.DefaultSettings(),
+		keyFunc:     DestinationKey,
+		backoff:     ExponentialBackoff(time.Second, time.Hour),
+		maxAttempts: 10,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// DestinationKey 默认目标划分：scheme://host/path
+func DestinationKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+// ExponentialBackoff 返回指数退避函数
+func ExponentialBackoff(base, max time.Duration) func(attempts int) time.Duration {
+	return func(attempts int) time.Duration {
+		d := base
+		for i := 1; i < attempts && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// Breaker 返回目标对应的熔断器
+func (d *Dispatcher) Breaker(rawURL string) (*circuitbreaker.CircuitBreaker, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return d.registry.GetOrCreate("webhook:"+d.keyFunc(u), d.settings), nil
+}
+
+// Send 投递 webhook：目标熔断打开时直接入队暂停投递，失败时按退避入队重试
+func (d *Dispatcher) Send(ctx context.Context, del Delivery) (Result, error) {
+	return d.deliver(ctx, del)
+}
+
+// Flush 投递队列中已到期的记录，熔断打开的目标保持暂停，返回成功投递数
+// 应由调用方定时调用（如每秒一次）
+func (d *Dispatcher) Flush(ctx context.Context, limit int) (int, error) {
+	due, err := d.queue.Due(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, del := range due {
+		if err := ctx.Err(); err != nil {
+			return delivered, err
+		}
+		res, err := d.deliver(ctx, del)
+		if err != nil {
+			return delivered, err
+		}
+		if res == Delivered {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, del Delivery) (Result, error) {
+	cb, err := d.Breaker(del.URL)
+	if err != nil {
+		return d.giveUp(ctx, del, err)
+	}
+
+	done, err := cb.AllowContext(ctx)
+	if err != nil {
+		// 目标熔断打开，暂停到可再次探测时投递但不消耗重试次数，
+		// 避免暂停的记录一直排在队首使其他目标的投递饥饿
+		del.LastError = err.Error()
+		del.NextAttempt = cb.NextProbeAt()
+		if !del.NextAttempt.After(time.Now()) {
+			del.NextAttempt = time.Now().Add(d.backoff(max(del.Attempts, 1)))
+		}
+		return Queued, d.queue.Push(ctx, del)
+	}
+
+	del.Attempts++
+	status, err := d.post(ctx, del)
+	switch {
+	case err == nil && status < 300:
+		done(nil)
+		return Delivered, d.queue.Remove(ctx, del.ID)
+	case err == nil && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests:
+		// 目标拒绝请求内容，与目标健康无关，不计入熔断也不重试
+		done(nil)
+		return d.giveUp(ctx, del, fmt.Errorf("webhookbreaker: destination rejected with status %d", status))
+	case err == nil:
+		err = fmt.Errorf("webhookbreaker: destination responded with status %d", status)
+	}
+
+	done(err)
+	if ctx.Err() != nil {
+		// 调用方取消，保留记录待下次投递
+		del.Attempts--
+		return Queued, d.queue.Push(context.WithoutCancel(ctx), del)
+	}
+	if del.Attempts >= d.maxAttempts {
+		return d.giveUp(ctx, del, err)
+	}
+	del.LastError = err.Error()
+	del.NextAttempt = time.Now().Add(d.backoff(del.Attempts))
+	return Queued, d.queue.Push(ctx, del)
+}
+
+func (d *Dispatcher) post(ctx context.Context, del Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.URL, bytes.NewReader(del.Body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range del.Header {
+		req.Header[k] = v
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) giveUp(ctx context.Context, del Delivery, cause error) (Result, error) {
+	del.LastError = cause.Error()
+	if d.onGiveUp != nil {
+		d.onGiveUp(del)
+	}
+	return Rejected, d.queue.Remove(ctx, del.ID)
+}
+
+// Suspended 返回当前熔断打开（暂停投递）的目标
+func (d *Dispatcher) Suspended() []string {
+	var names []string
+	for _, stats := range d.registry.Stats() {
+		if stats.State == gobreaker.StateOpen {
+			names = append(names, stats.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 zampo.
+
+package webhookbreaker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+func tripAfter(n uint32) circuitbreaker.Settings {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= n
+	}
+	return settings
+}
+
+func TestDispatcher_SuspendsOpenDestination(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	queue := NewMemoryQueue()
+	d := New(queue, WithSettings(tripAfter(2)), WithBackoff(func(int) time.Duration { return 0 }))
+
+	for i, id := range []string{"a", "b", "c"} {
+		res, err := d.Send(context.Background(), Delivery{ID: id, URL: srv.URL + "/hook"})
+		if err != nil {
+			t.Fatalf("Send(%d) error = %v", i, err)
+		}
+		if res != Queued {
+			t.Errorf("Send(%d) = %v, want %v", i, res, Queued)
+		}
+	}
+
+	if got := hits.Load(); got != 2 {
+		t.Errorf("requests to destination = %v, want %v (third delivery should be suspended)", got, 2)
+	}
+	if got := queue.Len(); got != 3 {
+		t.Errorf("queue length = %v, want %v", got, 3)
+	}
+	if got := d.Suspended(); len(got) != 1 {
+		t.Errorf("Suspended() = %v, want one destination", got)
+	}
+}
+
+func TestDispatcher_FlushDelivers(t *testing.T) {
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	queue := NewMemoryQueue()
+	d := New(queue, WithBackoff(func(int) time.Duration { return 0 }))
+
+	if res, _ := d.Send(context.Background(), Delivery{ID: "a", URL: srv.URL}); res != Queued {
+		t.Fatalf("Send() = %v, want %v", res, Queued)
+	}
+
+	healthy.Store(true)
+	n, err := d.Flush(context.Background(), 10)
+	if err != nil || n != 1 {
+		t.Errorf("Flush() = %v, %v, want 1, nil", n, err)
+	}
+	if got := queue.Len(); got != 0 {
+		t.Errorf("queue length = %v, want %v", got, 0)
+	}
+}
+
+func TestDispatcher_FlushDoesNotStarveHealthyDestinations(t *testing.T) {
+	var down, up atomic.Int32
+	open := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { down.Add(1) }))
+	defer open.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { up.Add(1) }))
+	defer healthy.Close()
+
+	queue := NewMemoryQueue()
+	d := New(queue, WithBackoff(func(int) time.Duration { return time.Minute }))
+	cb, err := d.Breaker(open.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb.OpenFor(time.Hour)
+
+	ctx := context.Background()
+	past := time.Now().Add(-time.Minute)
+	for i, id := range []string{"a", "b", "c"} {
+		queue.Push(ctx, Delivery{ID: id, URL: open.URL, NextAttempt: past.Add(time.Duration(i) * time.Millisecond)})
+	}
+	queue.Push(ctx, Delivery{ID: "healthy", URL: healthy.URL, NextAttempt: past.Add(time.Second)})
+
+	delivered := 0
+	for i := 0; i < 2; i++ {
+		n, err := d.Flush(ctx, 2)
+		if err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+		delivered += n
+	}
+	if delivered != 1 || up.Load() != 1 {
+		t.Errorf("delivered = %d, healthy requests = %d, want the healthy delivery sent", delivered, up.Load())
+	}
+	if down.Load() != 0 {
+		t.Errorf("requests to open destination = %d, want 0", down.Load())
+	}
+
+	due, _ := queue.Due(ctx, time.Now(), 0)
+	if len(due) != 0 {
+		t.Errorf("due after flush = %+v, want suspended deliveries deferred", due)
+	}
+}
+
+func TestDispatcher_RejectsClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var gaveUp []Delivery
+	queue := NewMemoryQueue()
+	d := New(queue, WithGiveUp(func(del Delivery) { gaveUp = append(gaveUp, del) }))
+
+	res, err := d.Send(context.Background(), Delivery{ID: "a", URL: srv.URL})
+	if err != nil || res != Rejected {
+		t.Fatalf("Send() = %v, %v, want %v, nil", res, err, Rejected)
+	}
+	if len(gaveUp) != 1 || queue.Len() != 0 {
+		t.Errorf("gaveUp = %v, queue length = %v, want 1, 0", len(gaveUp), queue.Len())
+	}
+
+	cb, _ := d.Breaker(srv.URL)
+	if got := cb.Counts().TotalFailures; got != 0 {
+		t.Errorf("TotalFailures = %v, want %v", got, 0)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
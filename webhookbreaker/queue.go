@@ -0,0 +1,104 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package webhookbreaker
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Delivery 一次 webhook 投递
+type Delivery struct {
+	// ID 投递唯一标识，队列按 ID 去重
+	ID     string
+	URL    string
+	Header http.Header
+	Body   []byte
+	// Attempts 已尝试次数
+	Attempts int
+	// NextAttempt 下次可投递时间
+	NextAttempt time.Time
+	// LastError 最近一次失败原因
+	LastError string
+}
+
+// Queue 持久化重试队列，生产环境应基于数据库或消息队列实现以便重启后继续投递
+type Queue interface {
+	// Push 写入或更新（按 ID）一条待投递记录
+	Push(ctx context.Context, d Delivery) error
+	// Due 返回 NextAttempt 不晚于 now 的记录，最多 limit 条
+	Due(ctx context.Context, now time.Time, limit int) ([]Delivery, error)
+	// Remove 删除记录（投递成功或放弃）
+	Remove(ctx context.Context, id string) error
+}
+
+// MemoryQueue 内存队列，仅适用于测试或可接受重启丢失的场景
+type MemoryQueue struct {
+	mu    sync.Mutex
+	items map[string]Delivery
+}
+
+// NewMemoryQueue 创建内存队列
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{items: make(map[string]Delivery)}
+}
+
+// Push 实现 Queue
+func (q *MemoryQueue) Push(ctx context.Context, d Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items[d.ID] = d
+	return nil
+}
+
+// Due 实现 Queue，按 NextAttempt 升序返回
+func (q *MemoryQueue) Due(ctx context.Context, now time.Time, limit int) ([]Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	due := make([]Delivery, 0)
+	for _, d := range q.items {
+		if !d.NextAttempt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttempt.Before(due[j].NextAttempt)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// Remove 实现 Queue
+func (q *MemoryQueue) Remove(ctx context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.items, id)
+	return nil
+}
+
+// Len 返回队列长度
+func (q *MemoryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
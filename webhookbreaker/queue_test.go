@@ -0,0 +1,32 @@
+// Copyright 2025 zampo.
+
+package webhookbreaker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryQueue_Due(t *testing.T) {
+	q := NewMemoryQueue()
+	ctx := context.Background()
+	now := time.Now()
+
+	q.Push(ctx, Delivery{ID: "later", NextAttempt: now.Add(time.Minute)})
+	q.Push(ctx, Delivery{ID: "second", NextAttempt: now.Add(-time.Second)})
+	q.Push(ctx, Delivery{ID: "first", NextAttempt: now.Add(-time.Minute)})
+
+	due, err := q.Due(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("Due() error = %v", err)
+	}
+	if len(due) != 2 || due[0].ID != "first" || due[1].ID != "second" {
+		t.Errorf("Due() = %+v, want first, second", due)
+	}
+
+	q.Remove(ctx, "first")
+	if due, _ := q.Due(ctx, now, 1); len(due) != 1 || due[0].ID != "second" {
+		t.Errorf("Due() after Remove = %+v, want second", due)
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package circuitbreaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// WindowMode 关闭状态下的计数窗口模式
+type WindowMode string
+
+// 计数窗口模式
+const (
+	// WindowDefault 沿用 gobreaker 行为：Interval > 0 时每隔 Interval 整体清零（固定窗口），
+	// Interval 为 0 时从不清零
+	WindowDefault WindowMode = ""
+	// WindowCumulative 关闭状态下从不清零，仅在状态变更时重置，忽略 Interval
+	WindowCumulative WindowMode = "cumulative"
+	// WindowRolling 最近 Interval 内的结果，按 WindowBuckets 个桶滑动淘汰，不会在窗口边界整体清零
+	WindowRolling WindowMode = "rolling"
+	// WindowCountBased 最近 WindowSize 次调用的结果，忽略 Interval
+	WindowCountBased WindowMode = "count_based"
+)
+
+// 窗口默认参数
+const (
+	// DefaultWindowBuckets WindowRolling 的默认桶数
+	DefaultWindowBuckets = 10
+	// DefaultWindowSize WindowCountBased 的默认调用数
+	DefaultWindowSize = 100
+)
+
+// valid 是否为已知模式
+func (m WindowMode) valid() bool {
+	switch m {
+	case WindowDefault, WindowCumulative, WindowRolling, WindowCountBased:
+		return true
+	}
+	return false
+}
+
+// Warnings 返回配置中合法但容易误解的组合，如 Interval 为 0 的默认模式（计数永不清零）、
+// 缺少 Interval 的滑动窗口，或无法满足的 MinimumRequests；没有问题时返回空。
+// 累计与按调用数模式总是忽略 Interval（包括 DefaultSettings 的默认值），不视为问题
+func (s Settings) Warnings() []string {
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	switch s.WindowMode {
+	case WindowDefault:
+		if s.Interval <= 0 {
+			warn("Interval is 0: counts are never cleared while closed; set WindowMode to %q to make this explicit", WindowCumulative)
+		} else if s.WindowBuckets > 0 || s.WindowSize > 0 {
+			warn("WindowBuckets and WindowSize are ignored without WindowMode")
+		}
+	case WindowCumulative:
+	case WindowRolling:
+		if s.Interval <= 0 {
+			warn("%q mode requires Interval > 0; counts are never cleared", s.WindowMode)
+		} else if buckets := s.windowBuckets(); s.Interval/time.Duration(buckets) < time.Millisecond {
+			warn("Interval %v split into %d buckets is below 1ms per bucket", s.Interval, buckets)
+		}
+		if s.WindowSize > 0 {
+			warn("WindowSize is ignored in %q mode", s.WindowMode)
+		}
+	case WindowCountBased:
+		if s.WindowBuckets > 0 {
+			warn("WindowBuckets is ignored in %q mode", s.WindowMode)
+		}
+		if size := s.windowSize(); s.MinimumRequests > uint32(size) {
+			warn("MinimumRequests %d exceeds WindowSize %d: the breaker can never trip on counts", s.MinimumRequests, size)
+		}
+	default:
+		warn("unknown WindowMode %q, treated as the default", s.WindowMode)
+	}
+	return warnings
+}
+
+// windowBuckets 返回生效的桶数
+func (s Settings) windowBuckets() int {
+	if s.WindowBuckets > 0 {
+		return s.WindowBuckets
+	}
+	return DefaultWindowBuckets
+}
+
+// windowSize 返回生效的窗口调用数
+func (s Settings) windowSize() int {
+	if s.WindowSize > 0 {
+		return s.WindowSize
+	}
+	return DefaultWindowSize
+}
+
+// backendInterval 返回交给 gobreaker 的 Interval：显式窗口模式或设置 Clock 时由本包统计，gobreaker 不再清零
+func (s Settings) backendInterval() time.Duration {
+	if s.Clock == nil && (s.WindowMode == WindowDefault || !s.WindowMode.valid()) {
+		return s.Interval
+	}
+	return 0
+}
+
+// countingWindow 关闭状态下的滑动计数窗口，替代 gobreaker 计数中的请求、成功与失败总数；
+// 连续成功/失败次数仍取自 gobreaker
+type countingWindow struct {
+	mode    WindowMode
+	width   time.Duration
+	size    int
+	mu      sync.Mutex
+	gen     uint64
+	buckets []windowBucket
+	ring    []bool
+	next    int
+	filled  int
+	fails   int
+}
+
+// windowBucket 滑动窗口中的一个时间桶
+type windowBucket struct {
+	index     int64
+	successes uint32
+	failures  uint32
+}
+
+// newCountingWindow 按配置创建计数窗口，默认与累计模式无需窗口时返回 nil；
+// 设置 Clock 时默认模式的固定窗口也由本包按 Clock 统计（单桶、宽度为 Interval）
+func newCountingWindow(s Settings) *countingWindow {
+	switch s.WindowMode {
+	case WindowDefault:
+		if s.Clock != nil && s.Interval > 0 {
+			return &countingWindow{mode: WindowRolling, width: s.Interval, size: 1, buckets: make([]windowBucket, 1)}
+		}
+	case WindowRolling:
+		if s.Interval <= 0 {
+			return nil
+		}
+		buckets := s.windowBuckets()
+		width := s.Interval / time.Duration(buckets)
+		if width <= 0 {
+			width = 1
+		}
+		return &countingWindow{mode: s.WindowMode, width: width, size: buckets, buckets: make([]windowBucket, buckets)}
+	case WindowCountBased:
+		size := s.windowSize()
+		return &countingWindow{mode: s.WindowMode, size: size, ring: make([]bool, size)}
+	}
+	return nil
+}
+
+// sameShape 配置变更后窗口是否可以继续沿用
+func (w *countingWindow) sameShape(other *countingWindow) bool {
+	return w != nil && other != nil && w.mode == other.mode && w.width == other.width && w.size == other.size
+}
+
+// generation 返回当前代数，放行时记录，结果上报时代数已变化（状态变更）则丢弃
+func (w *countingWindow) generation() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.gen
+}
+
+// record 记录一次结果，应在上报 gobreaker 之前调用，使 ReadyToTrip 能看到本次结果
+func (w *countingWindow) record(gen uint64, success bool, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if gen != w.gen {
+		return
+	}
+	if w.mode == WindowRolling {
+		b := w.bucket(now.UnixNano() / int64(w.width))
+		if success {
+			b.successes++
+		} else {
+			b.failures++
+		}
+		return
+	}
+	if w.filled == w.size {
+		if w.ring[w.next] {
+			w.fails--
+		}
+	} else {
+		w.filled++
+	}
+	w.ring[w.next] = !success
+	if !success {
+		w.fails++
+	}
+	w.next = (w.next + 1) % w.size
+}
+
+// bucket 返回 index 对应的桶，复用过期的桶
+func (w *countingWindow) bucket(index int64) *windowBucket {
+	b := &w.buckets[index%int64(len(w.buckets))]
+	if b.index != index {
+		*b = windowBucket{index: index}
+	}
+	return b
+}
+
+// counts 以窗口内的总数替换 counts 中的请求、成功与失败总数
+func (w *countingWindow) counts(counts gobreaker.Counts, now time.Time) gobreaker.Counts {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var successes, failures uint32
+	if w.mode == WindowRolling {
+		current := now.UnixNano() / int64(w.width)
+		for _, b := range w.buckets {
+			if b.index > current-int64(len(w.buckets)) && b.index <= current {
+				successes += b.successes
+				failures += b.failures
+			}
+		}
+	} else {
+		failures = uint32(w.fails)
+		successes = uint32(w.filled - w.fails)
+	}
+	counts.Requests = successes + failures
+	counts.TotalSuccesses = successes
+	counts.TotalFailures = failures
+	return counts
+}
+
+// reset 清空窗口并推进代数，在状态变更时调用
+func (w *countingWindow) reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.gen++
+	for i := range w.buckets {
+		w.buckets[i] = windowBucket{}
+	}
+	clear(w.ring)
+	w.next, w.filled, w.fails = 0, 0, 0
+}
+
+// configureWindow 按配置更新计数窗口，形状不变时沿用已有窗口；调用方需持有 cb.mu 或处于构造阶段
+func (cb *CircuitBreaker) configureWindow(settings Settings) *countingWindow {
+	w := newCountingWindow(settings)
+	if current := cb.window.Load(); current.sameShape(w) {
+		return current
+	}
+	cb.window.Store(w)
+	return w
+}
+
+// windowed 返回按计数窗口修正后的 counts
+func (cb *CircuitBreaker) windowed(counts gobreaker.Counts) gobreaker.Counts {
+	if w := cb.window.Load(); w != nil {
+		return w.counts(counts, cb.now())
+	}
+	return counts
+}
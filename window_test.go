@@ -0,0 +1,156 @@
+// Copyright 2025 zampo.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestWindowCountBased_EvictsOldOutcomes(t *testing.T) {
+	settings := DefaultSettings()
+	settings.WindowMode = WindowCountBased
+	settings.WindowSize = 4
+	settings.MinimumRequests = 4
+	settings.ReadyToTrip = FailureRate(0.75)
+	cb := NewCircuitBreaker("count-based", settings)
+
+	fail := func() (interface{}, error) { return nil, errors.New("x") }
+	ok := func() (interface{}, error) { return nil, nil }
+	for _, fn := range []func() (interface{}, error){fail, fail, ok, ok, ok, ok} {
+		cb.Execute(fn)
+	}
+	if got := cb.Counts(); got.Requests != 4 || got.TotalFailures != 0 || got.TotalSuccesses != 4 {
+		t.Errorf("Counts() = %+v, want the last 4 calls, all successes", got)
+	}
+
+	cb.Execute(fail)
+	cb.Execute(fail)
+	if cb.State() != gobreaker.StateClosed {
+		t.Fatalf("State() = %v, want closed at 50%% of the window", cb.State())
+	}
+	cb.Execute(fail)
+	if cb.State() != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want open at 75%% of the window", cb.State())
+	}
+	if got := cb.Counts(); got.Requests != 0 {
+		t.Errorf("Counts() after trip = %+v, want cleared window", got)
+	}
+}
+
+func TestWindowRolling_SlidesBuckets(t *testing.T) {
+	w := newCountingWindow(Settings{WindowMode: WindowRolling, Interval: time.Second, WindowBuckets: 4})
+	start := time.Unix(0, 0)
+	gen := w.generation()
+
+	w.record(gen, false, start)
+	w.record(gen, true, start.Add(500*time.Millisecond))
+	if got := w.counts(gobreaker.Counts{}, start.Add(900*time.Millisecond)); got.Requests != 2 || got.TotalFailures != 1 {
+		t.Errorf("counts within window = %+v, want 2 requests, 1 failure", got)
+	}
+	// 第一个桶滑出窗口，第二个仍在
+	if got := w.counts(gobreaker.Counts{}, start.Add(1100*time.Millisecond)); got.Requests != 1 || got.TotalFailures != 0 {
+		t.Errorf("counts after first bucket expired = %+v, want 1 success", got)
+	}
+	if got := w.counts(gobreaker.Counts{}, start.Add(2*time.Second)); got.Requests != 0 {
+		t.Errorf("counts after window = %+v, want empty", got)
+	}
+}
+
+func TestWindow_ResetDropsStaleOutcomes(t *testing.T) {
+	w := newCountingWindow(Settings{WindowMode: WindowCountBased, WindowSize: 2})
+	gen := w.generation()
+	w.reset()
+	w.record(gen, false, time.Now())
+	if got := w.counts(gobreaker.Counts{ConsecutiveFailures: 1}, time.Now()); got.Requests != 0 || got.ConsecutiveFailures != 1 {
+		t.Errorf("counts() = %+v, want stale outcome dropped and consecutive counts kept", got)
+	}
+}
+
+func TestWindowCumulative_IgnoresInterval(t *testing.T) {
+	settings := DefaultSettings()
+	settings.WindowMode = WindowCumulative
+	settings.Interval = time.Millisecond
+	if got := settings.backendInterval(); got != 0 {
+		t.Errorf("backendInterval() = %v, want 0", got)
+	}
+	cb := NewCircuitBreaker("cumulative", settings)
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	time.Sleep(5 * time.Millisecond)
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+	if got := cb.Counts().Requests; got != 2 {
+		t.Errorf("Requests = %d, want 2 across intervals", got)
+	}
+}
+
+func TestUpdateSettings_KeepsWindowOfSameShape(t *testing.T) {
+	settings := DefaultSettings()
+	settings.WindowMode = WindowCountBased
+	settings.WindowSize = 10
+	cb := NewCircuitBreaker("reload", settings)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("x") })
+
+	settings.Timeout = time.Minute
+	cb.UpdateSettings(settings)
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures after reload = %d, want 1", got)
+	}
+
+	settings.WindowSize = 20
+	cb.UpdateSettings(settings)
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Requests after resizing window = %d, want 0", got)
+	}
+}
+
+func TestSettings_Warnings(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		settings Settings
+		want     string
+	}{
+		{"implicit cumulative", Settings{}, "never cleared"},
+		{"count based buckets", Settings{WindowMode: WindowCountBased, WindowBuckets: 4}, "ignored"},
+		{"rolling without interval", Settings{WindowMode: WindowRolling}, "requires Interval"},
+		{"count based minimum", Settings{WindowMode: WindowCountBased, WindowSize: 10, MinimumRequests: 20}, "can never trip"},
+		{"unknown", Settings{WindowMode: "sliding"}, "unknown WindowMode"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := strings.Join(tc.settings.Warnings(), "\n")
+			if !strings.Contains(got, tc.want) {
+				t.Errorf("Warnings() = %q, want mention of %q", got, tc.want)
+			}
+		})
+	}
+	if got := DefaultSettings().Warnings(); len(got) != 0 {
+		t.Errorf("DefaultSettings().Warnings() = %v, want none", got)
+	}
+}
+
+func TestConfig_WindowMode(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{"version":2,"breakers":{
+		"payments":{"window_mode":"count_based","window_size":50,"minimum_requests":100},
+		"search":{"window_mode":"rolling","interval":"30s","window_buckets":6}
+	}}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if s := cfg.Settings("search"); s.WindowMode != WindowRolling || s.WindowBuckets != 6 || s.Interval != 30*time.Second {
+		t.Errorf("search = %+v", s)
+	}
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "breakers.payments: ") {
+		t.Errorf("Warnings() = %q, want one payments warning", warnings)
+	}
+
+	_, err = ParseConfig([]byte(`{"version":2,"breakers":{"x":{"window_mode":"sliding","window_size":-1}}}`))
+	for _, path := range []string{"breakers.x.window_mode:", "breakers.x.window_size:"} {
+		if err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("ParseConfig() error = %v, want mention of %s", err, path)
+		}
+	}
+}
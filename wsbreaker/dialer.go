@@ -0,0 +1,196 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package wsbreaker 提供按端点熔断的 WebSocket 拨号包装，
+// 适配任意 WebSocket 库（gorilla/websocket、nhooyr.io/websocket 等）的拨号函数
+package wsbreaker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+// DialFunc 底层拨号函数，通常包装所用库的 Dial，如
+//
+//	func(ctx context.Context, url string) (*websocket.Conn, error) {
+//		c, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+//		return c, err
+//	}
+type DialFunc[C any] func(ctx context.Context, endpoint string) (C, error)
+
+// EventType 连接事件类型
+type EventType int
+
+const (
+	// EventConnected 连接建立
+	EventConnected EventType = iota
+	// EventStable 连接存活超过 MinStable，计为一次成功
+	EventStable
+	// EventFlap 连接在 MinStable 内断开，计为一次失败
+	EventFlap
+	// EventDisconnected 已稳定的连接断开
+	EventDisconnected
+	// EventDialFailed 握手失败
+	EventDialFailed
+	// EventRejected 熔断器拒绝重连
+	EventRejected
+)
+
+// Event 连接事件
+type Event struct {
+	Endpoint string
+	Type     EventType
+	// Err 握手失败、断开或拒绝的原因
+	Err error
+	// Uptime 断开时的连接时长
+	Uptime time.Duration
+	At     time.Time
+}
+
+// config 拨号器配置
+type config struct {
+	registry  *circuitbreaker.Registry
+	settings  circuitbreaker.Settings
+	minStable time.Duration
+	onEvent   func(Event)
+}
+
+// Option 拨号器配置项
+type Option func(*config)
+
+// WithRegistry 设置熔断器注册表，便于与健康检查、管理接口共享
+func WithRegistry(r *circuitbreaker.Registry) Option {
+	return func(c *config) { c.registry = r }
+}
+
+// WithSettings 设置每个端点熔断器的配置
+func WithSettings(settings circuitbreaker.Settings) Option {
+	return func(c *config) { c.settings = settings }
+}
+
+// WithMinStable 设置连接被视为稳定所需的存活时长，默认 10 秒
+// 握手成功但在该时长内断开的连接计为失败，使频繁抖动的端点也能触发熔断
+func WithMinStable(d time.Duration) Option {
+	return func(c *config) { c.minStable = d }
+}
+
+// WithEventHandler 设置连接事件回调，同步调用，不可阻塞
+func WithEventHandler(fn func(Event)) Option {
+	return func(c *config) { c.onEvent = fn }
+}
+
+// Dialer 按端点熔断的 WebSocket 拨号器
+// 熔断打开期间重连请求直接被拒绝，避免大量客户端同时重连冲击恢复中的服务（重连风暴）；
+// 半开状态下仅 MaxRequests 个连接可用于验证恢复
+type Dialer[C any] struct {
+	dial DialFunc[C]
+	config
+}
+
+// New 创建拨号器
+func New[C any](dial DialFunc[C], opts ...Option) *Dialer[C] {
+	d := &Dialer[C]{
+		dial: dial,
+		config: config{
+			registry:  circuitbreaker.NewRegistry(),
+			settings:  circuitbreaker.DefaultSettings(),
+			minStable: 10 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(&d.config)
+	}
+	return d
+}
+
+// Breaker 返回指定端点的熔断器，注册表中命名为 "ws:<端点>"
+func (d *Dialer[C]) Breaker(endpoint string) *circuitbreaker.CircuitBreaker {
+	return d.registry.GetOrCreate("ws:"+endpoint, d.settings)
+}
+
+// DialContext 连接端点，返回连接及其关闭回调；连接结束时须调用 closed（读写出错时传入错误，主动关闭时传入 nil），
+// 连接的结果在存活超过 MinStable 或 closed 被调用时才上报熔断器；
+// 熔断器拒绝时返回的错误可用 circuitbreaker.ReasonOf 获取原因
+func (d *Dialer[C]) DialContext(ctx context.Context, endpoint string) (conn C, closed func(err error), err error) {
+	done, err := d.Breaker(endpoint).AllowContext(ctx)
+	if err != nil {
+		d.emit(Event{Endpoint: endpoint, Type: EventRejected, Err: err})
+		return conn, nil, err
+	}
+
+	conn, err = d.dial(ctx, endpoint)
+	if err != nil {
+		done(err)
+		d.emit(Event{Endpoint: endpoint, Type: EventDialFailed, Err: err})
+		return conn, nil, err
+	}
+	d.emit(Event{Endpoint: endpoint, Type: EventConnected})
+
+	s := &session{dialer: &d.config, endpoint: endpoint, done: done, start: time.Now()}
+	s.timer = time.AfterFunc(d.minStable, s.stable)
+	return conn, s.closed, nil
+}
+
+// emit 分发事件
+func (c *config) emit(e Event) {
+	if c.onEvent == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	c.onEvent(e)
+}
+
+// session 单个连接的生命周期跟踪
+type session struct {
+	dialer   *config
+	endpoint string
+	done     func(err error)
+	start    time.Time
+	timer    *time.Timer
+
+	reported  atomic.Bool
+	closeOnce sync.Once
+}
+
+// stable 连接存活超过 MinStable，计为成功
+func (s *session) stable() {
+	if s.reported.CompareAndSwap(false, true) {
+		s.done(nil)
+		s.dialer.emit(Event{Endpoint: s.endpoint, Type: EventStable})
+	}
+}
+
+// closed 连接结束，仅第一次调用生效；err 为空表示主动关闭，不计为失败
+func (s *session) closed(err error) {
+	s.closeOnce.Do(func() {
+		uptime := time.Since(s.start)
+		typ := EventDisconnected
+		if s.reported.CompareAndSwap(false, true) {
+			s.timer.Stop()
+			s.done(err)
+			if err != nil {
+				typ = EventFlap
+			}
+		}
+		s.dialer.emit(Event{Endpoint: s.endpoint, Type: typ, Err: err, Uptime: uptime})
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 zampo.
+
+package wsbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	circuitbreaker "github.com/go-anyway/framework-circuitbreaker"
+)
+
+type fakeConn struct{ endpoint string }
+
+func tripOnFirstFailure() circuitbreaker.Settings {
+	settings := circuitbreaker.DefaultSettings()
+	settings.ReadyToTrip = circuitbreaker.ConsecutiveFailures(1)
+	return settings
+}
+
+type recorder struct {
+	mu     sync.Mutex
+	events []EventType
+}
+
+func (r *recorder) handle(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e.Type)
+}
+
+func (r *recorder) types() []EventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]EventType(nil), r.events...)
+}
+
+func TestDialer_FlapTripsAndGatesReconnect(t *testing.T) {
+	var rec recorder
+	dials := 0
+	d := New(func(ctx context.Context, endpoint string) (*fakeConn, error) {
+		dials++
+		return &fakeConn{endpoint: endpoint}, nil
+	}, WithSettings(tripOnFirstFailure()), WithMinStable(time.Hour), WithEventHandler(rec.handle))
+
+	conn, closed, err := d.DialContext(context.Background(), "wss://feed.example.com")
+	if err != nil || conn.endpoint != "wss://feed.example.com" {
+		t.Fatalf("DialContext() = %v, %v", conn, err)
+	}
+	closed(errors.New("connection reset"))
+	closed(errors.New("ignored"))
+
+	if got := d.Breaker("wss://feed.example.com").State(); got != gobreaker.StateOpen {
+		t.Fatalf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+
+	_, _, err = d.DialContext(context.Background(), "wss://feed.example.com")
+	if circuitbreaker.ReasonOf(err) != circuitbreaker.ReasonOpen {
+		t.Errorf("reconnect error = %v, want open rejection", err)
+	}
+	if dials != 1 {
+		t.Errorf("dials = %v, want 1", dials)
+	}
+
+	want := []EventType{EventConnected, EventFlap, EventRejected}
+	if got := rec.types(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func TestDialer_StableConnection(t *testing.T) {
+	var rec recorder
+	d := New(func(ctx context.Context, endpoint string) (*fakeConn, error) {
+		return &fakeConn{}, nil
+	}, WithSettings(tripOnFirstFailure()), WithMinStable(5*time.Millisecond), WithEventHandler(rec.handle))
+
+	_, closed, err := d.DialContext(context.Background(), "wss://chat")
+	if err != nil {
+		t.Fatalf("DialContext() error = %v", err)
+	}
+	cb := d.Breaker("wss://chat")
+	deadline := time.Now().Add(time.Second)
+	for cb.Counts().TotalSuccesses != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("connection never reported stable")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	closed(errors.New("server going away"))
+	if got := cb.State(); got != gobreaker.StateClosed {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+	if got := rec.types(); got[len(got)-1] != EventDisconnected {
+		t.Errorf("last event = %v, want %v", got[len(got)-1], EventDisconnected)
+	}
+}
+
+func TestDialer_DialFailure(t *testing.T) {
+	d := New(func(ctx context.Context, endpoint string) (*fakeConn, error) {
+		return nil, errors.New("handshake failed")
+	}, WithSettings(tripOnFirstFailure()))
+
+	if _, closed, err := d.DialContext(context.Background(), "wss://a"); err == nil || closed != nil {
+		t.Fatalf("DialContext() error = %v, closed nil = %v, want error and nil closed", err, closed == nil)
+	}
+	if got := d.Breaker("wss://a").State(); got != gobreaker.StateOpen {
+		t.Errorf("State() = %v, want %v", got, gobreaker.StateOpen)
+	}
+	if got := d.Breaker("wss://b").State(); got != gobreaker.StateClosed {
+		t.Errorf("other endpoint State() = %v, want %v", got, gobreaker.StateClosed)
+	}
+}